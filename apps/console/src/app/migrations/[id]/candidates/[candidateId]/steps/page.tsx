@@ -10,13 +10,20 @@ import {
   getMigration,
   completeStep,
   retryStep,
+  subscribeCandidateProgress,
   updateInputs,
   type Candidate,
   type CandidateStepsResponse,
   type Migration,
 } from "@/lib/api";
 import { ROUTES } from "@/lib/routes";
-import { buildStepDescriptionMap, calculateStepProgress, getApplicableSteps } from "@/lib/steps";
+import {
+  buildStepDependencyMap,
+  buildStepDescriptionMap,
+  calculateStepProgress,
+  getApplicableSteps,
+  hasStepGraph,
+} from "@/lib/steps";
 import { prefillInputs } from "@/lib/inputs";
 import { StepTimeline } from "@/components/step-timeline";
 import {
@@ -82,12 +89,28 @@ export default function CandidateStepsPage() {
   useEffect(() => {
     void poll();
     fetchCandidate();
-    intervalRef.current = setInterval(() => {
-      void poll();
-      fetchCandidate();
-    }, 2000);
-    return stopPolling;
-  }, [poll, fetchCandidate, stopPolling]);
+    const startPolling = () => {
+      if (intervalRef.current) return;
+      intervalRef.current = setInterval(() => {
+        void poll();
+        fetchCandidate();
+      }, 2000);
+    };
+    // Refresh on each progress event while the stream is up; poll while it is not.
+    startPolling();
+    const unsubscribe = subscribeCandidateProgress(id, candidateId, {
+      onEvent: () => {
+        void poll();
+        fetchCandidate();
+      },
+      onOpen: stopPolling,
+      onError: startPolling,
+    });
+    return () => {
+      unsubscribe();
+      stopPolling();
+    };
+  }, [id, candidateId, poll, fetchCandidate, stopPolling]);
 
   useEffect(() => {
     getMigration(id).then(setMigration).catch(() => {});
@@ -121,6 +144,13 @@ export default function CandidateStepsPage() {
     [candidate, migration],
   );
 
+  // Only label dependencies when the migration declares a graph; a plain
+  // sequence reads fine from the step numbers alone.
+  const stepDependencies = useMemo(() => {
+    const steps = getApplicableSteps(candidate, migration);
+    return hasStepGraph(steps) ? buildStepDependencyMap(steps) : undefined;
+  }, [candidate, migration]);
+
   const progress = useMemo(
     () =>
       stepsData && migration
@@ -129,9 +159,6 @@ export default function CandidateStepsPage() {
     [stepsData, migration, candidate],
   );
 
-  // Temporal workflow ID used for event callbacks — derived from migration + candidate IDs
-  const runId = `${id}__${candidateId}`;
-
   return (
     <div className="space-y-8 animate-fade-in-up">
       {notFound ? (
@@ -255,9 +282,10 @@ export default function CandidateStepsPage() {
               <StepTimeline
                 results={stepsData.steps}
                 stepDescriptions={stepDescriptions}
+                stepDependencies={stepDependencies}
                 onComplete={(stepName, candidateId, status) => {
                   void (async () => {
-                    await completeStep(runId, stepName, candidateId, status);
+                    await completeStep(id, candidateId, stepName, status);
                     void poll();
                   })();
                 }}
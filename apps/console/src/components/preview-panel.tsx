@@ -12,7 +12,7 @@ import {
   type DryRunResult,
 } from "@/lib/api";
 import { ROUTES } from "@/lib/routes";
-import { getApplicableSteps } from "@/lib/steps";
+import { getApplicableSteps, groupStepsIntoStages, hasStepGraph } from "@/lib/steps";
 import { prefillInputs, mergeInputsIntoCandidate } from "@/lib/inputs";
 import { Button, Input, Sheet, SheetContent, SheetHeader, SheetFooter, SheetTitle } from "@/components/ui";
 import { DryRunStepResult } from "@/components/file-diff-view";
@@ -94,6 +94,14 @@ export function PreviewPanel({ open, migrationId, migration, candidate, onClose
 
   const steps = getApplicableSteps(candidate, migration);
 
+  // Steps in the same stage run concurrently; only shown when the migration
+  // declares dependsOn.
+  const stageByStep = useMemo(() => {
+    if (!hasStepGraph(steps)) return undefined;
+    const stages = groupStepsIntoStages(steps);
+    return new Map(stages.flatMap((names, i) => names.map((name) => [name, i + 1] as const)));
+  }, [steps]);
+
   const dryRunByStep = useMemo(() => {
     if (!dryRunResult) return new Map<string, DryRunResult["steps"][number]>();
     return new Map(dryRunResult.steps.map((s) => [s.stepName, s]));
@@ -216,6 +224,11 @@ export function PreviewPanel({ open, migrationId, migration, candidate, onClose
                             {String(i + 1).padStart(2, "0")}.
                           </span>
                           <span className="text-sm font-medium font-mono text-foreground">{step.name}</span>
+                          {stageByStep?.has(step.name) ? (
+                            <span className="text-xs font-mono text-muted-foreground/70 bg-muted/40 px-1.5 py-0.5 rounded">
+                              stage {stageByStep.get(step.name)}
+                            </span>
+                          ) : null}
                         </div>
                         <span className="text-xs font-mono text-muted-foreground bg-muted px-2 py-0.5 rounded shrink-0">
                           {step.migratorApp}
@@ -226,6 +239,18 @@ export function PreviewPanel({ open, migrationId, migration, candidate, onClose
                         <p className="text-sm text-muted-foreground ml-7">{step.description}</p>
                       )}
 
+                      {step.when ? (
+                        <p className="text-xs font-mono text-muted-foreground/70 ml-7">
+                          runs when {step.when}
+                        </p>
+                      ) : null}
+
+                      {stageByStep && step.dependsOn?.length ? (
+                        <p className="text-xs font-mono text-muted-foreground/70 ml-7">
+                          after {step.dependsOn.join(", ")}
+                        </p>
+                      ) : null}
+
                       {instructions ? (
                         <div className="ml-7 bg-pending/5 border border-pending/15 rounded-md px-3 py-2.5">
                           <div className="text-xs font-medium text-pending/70 uppercase tracking-widest mb-2">
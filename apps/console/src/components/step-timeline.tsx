@@ -15,17 +15,19 @@ import {
 export function StepTimeline({
   results,
   stepDescriptions,
+  stepDependencies,
   onComplete,
   onRetry,
 }: {
   results: StepState[];
   stepDescriptions?: Map<string, string>;
+  stepDependencies?: Map<string, string[]>;
   onComplete?: (stepName: string, candidateId: string, status: string) => void;
   onRetry?: (stepName: string, candidateId: string) => void;
 }) {
   const lastActiveIndex = results.reduce((acc, r, idx) => {
     const p = r.status;
-    return p === "pending" || p === "in_progress" ? idx : acc;
+    return p === "pending" || p === "in_progress" || p === "waiting_for_window" || p === "queued" ? idx : acc;
   }, -1);
 
   const activeRef = useRef<HTMLDivElement>(null);
@@ -36,7 +38,7 @@ export function StepTimeline({
     const forced: string[] = [];
     for (let i = 0; i < results.length; i++) {
       const s = results[i].status;
-      if (s !== "succeeded" && s !== "merged") {
+      if (s !== "succeeded" && s !== "merged" && s !== "skipped") {
         forced.push(String(i));
       }
     }
@@ -116,11 +118,13 @@ export function StepTimeline({
         const phase = r.status;
         const meta = r.metadata ?? {};
         const description = stepDescriptions?.get(r.stepName);
+        const dependsOn = stepDependencies?.get(r.stepName) ?? [];
         const isLast = i === results.length - 1;
-        const isActive = phase === "pending" || phase === "in_progress";
+        const isActive =
+          phase === "pending" || phase === "in_progress" || phase === "waiting_for_window" || phase === "queued";
         const hasPR = phase === "pending" && Boolean(meta.prUrl);
         const hasReview = phase === "pending" && Boolean(meta.instructions);
-        const isDone = phase === "succeeded" || phase === "merged";
+        const isDone = phase === "succeeded" || phase === "merged" || phase === "skipped";
         const isCollapsible = isDone;
         const isOpen = value.includes(String(i));
 
@@ -193,6 +197,11 @@ export function StepTimeline({
                         {description}
                       </span>
                     )}
+                    {dependsOn.length > 0 && (
+                      <span className="text-xs text-muted-foreground/70 mt-0.5 block font-mono">
+                        after {dependsOn.join(", ")}
+                      </span>
+                    )}
                   </div>
 
                   <div className="flex flex-col items-end gap-1.5 shrink-0">
@@ -221,6 +230,16 @@ export function StepTimeline({
                     </div>
                   ) : null}
 
+                  {/* Skipped step: operator reason or unmet when condition */}
+                  {phase === "skipped" && meta.skipReason ? (
+                    <p className="mb-2 text-xs text-muted-foreground font-mono">{meta.skipReason}</p>
+                  ) : null}
+
+                  {/* Failed step: the migrator refused or never took the dispatch */}
+                  {phase === "failed" && meta.dispatchError ? (
+                    <p className="mb-2 text-xs text-destructive font-mono">{meta.dispatchError}</p>
+                  ) : null}
+
                   {/* Pending with PR: manual merge action */}
                   {hasPR && onComplete ? (
                     <div className="mt-2">
@@ -336,6 +355,14 @@ function TimelineDot({ phase, meta }: { phase: StepState["status"]; meta: Record
           <circle cx="8" cy="8" r="6.5" strokeDasharray="28" strokeDashoffset="8" />
         </svg>
       );
+    case "waiting_for_window":
+    case "queued":
+      return (
+        <svg className={cn(cls, "text-pending")} viewBox="0 0 16 16" fill="none" stroke="currentColor" strokeWidth="1.5" strokeLinecap="round" strokeLinejoin="round">
+          <circle cx="8" cy="8" r="6.5" />
+          <path d="M8 4.5V8l2.5 1.5" />
+        </svg>
+      );
     case "failed":
       return (
         <svg className={cn(cls, "text-destructive")} viewBox="0 0 16 16" fill="none" stroke="currentColor" strokeWidth="1.5" strokeLinecap="round">
@@ -367,10 +394,26 @@ function PhaseLabel({ phase, meta }: { phase: StepState["status"]; meta: Record<
       return <span className={cn(base, "text-muted-foreground bg-muted border-border")}>Pending</span>;
     case "in_progress":
       return <span className={cn(base, "text-running bg-running/10 border-running/20")}>Running</span>;
+    case "waiting_for_window":
+      return (
+        <span className={cn(base, "text-pending bg-pending/10 border-pending/20")} title={meta.freezeReason}>
+          {meta.waitingUntil ? `Frozen until ${new Date(meta.waitingUntil).toLocaleString()}` : "Frozen"}
+        </span>
+      );
+    case "queued":
+      return (
+        <span className={cn(base, "text-pending bg-pending/10 border-pending/20")} title={meta.queuedReason}>
+          Queued
+        </span>
+      );
     case "merged":
       return <span className={cn(base, "text-merged bg-merged/10 border-merged/20")}>Merged</span>;
     case "failed":
       return <span className={cn(base, "text-destructive bg-destructive/10 border-destructive/20")}>Failed</span>;
+    case "skipped":
+      return <span className={cn(base, "text-muted-foreground bg-muted border-border")}>Skipped</span>;
+    case "timed_out":
+      return <span className={cn(base, "text-destructive bg-destructive/10 border-destructive/20")}>Timed out</span>;
     default:
       return <span className={cn(base, "text-completed bg-completed/10 border-completed/20")}>Done</span>;
   }
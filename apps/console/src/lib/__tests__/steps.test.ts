@@ -1,7 +1,14 @@
 import { describe, expect, it } from "vitest";
 import type { Candidate, CandidateStepsResponse, Migration } from "@/lib/api";
 import type { components } from "@/lib/api.gen";
-import { getApplicableSteps, buildStepDescriptionMap, calculateStepProgress } from "../steps";
+import {
+  getApplicableSteps,
+  buildStepDescriptionMap,
+  buildStepDependencyMap,
+  calculateStepProgress,
+  groupStepsIntoStages,
+  hasStepGraph,
+} from "../steps";
 
 type StepDefinition = components["schemas"]["StepDefinition"];
 type StepState = components["schemas"]["StepState"];
@@ -73,6 +80,56 @@ describe("buildStepDescriptionMap", () => {
   });
 });
 
+const dependent = (name: string, dependsOn: string[]): StepDefinition => ({
+  ...step(name),
+  dependsOn,
+});
+
+describe("buildStepDependencyMap", () => {
+  it("chains steps in order when none declares dependsOn", () => {
+    const steps = [step("a"), step("b"), step("c")];
+    expect(hasStepGraph(steps)).toBe(false);
+    expect(buildStepDependencyMap(steps)).toEqual(
+      new Map([
+        ["a", []],
+        ["b", ["a"]],
+        ["c", ["b"]],
+      ]),
+    );
+  });
+
+  it("treats steps without dependsOn as roots once any step declares it", () => {
+    const steps = [step("a"), step("b"), dependent("c", ["a", "b"])];
+    expect(hasStepGraph(steps)).toBe(true);
+    expect(buildStepDependencyMap(steps)).toEqual(
+      new Map([
+        ["a", []],
+        ["b", []],
+        ["c", ["a", "b"]],
+      ]),
+    );
+  });
+});
+
+describe("groupStepsIntoStages", () => {
+  it("puts each step in its own stage for a plain sequence", () => {
+    expect(groupStepsIntoStages([step("a"), step("b")])).toEqual([["a"], ["b"]]);
+  });
+
+  it("groups independent steps into the same stage", () => {
+    const steps = [step("a"), step("b"), dependent("c", ["a", "b"]), dependent("d", ["a"])];
+    expect(groupStepsIntoStages(steps)).toEqual([
+      ["a", "b"],
+      ["c", "d"],
+    ]);
+  });
+
+  it("leaves out steps on a dependency cycle", () => {
+    const steps = [step("a"), dependent("b", ["c"]), dependent("c", ["b"])];
+    expect(groupStepsIntoStages(steps)).toEqual([["a"]]);
+  });
+});
+
 describe("calculateStepProgress", () => {
   const makeState = (stepName: string, status: StepState["status"]): StepState => ({
     stepName,
@@ -96,6 +153,11 @@ describe("calculateStepProgress", () => {
     expect(result.total).toBe(5);
   });
 
+  it("counts skipped steps as done", () => {
+    const data = stepsData([makeState("a", "skipped"), makeState("b", "pending")]);
+    expect(calculateStepProgress(data, 2).done).toBe(1);
+  });
+
   it("prefers in_progress step as active", () => {
     const data = stepsData([
       makeState("a", "succeeded"),
@@ -7,6 +7,9 @@ export type CandidateStatus = components["schemas"]["CandidateStatus"];
 export type CandidateStepsResponse = components["schemas"]["CandidateStepsResponse"];
 export type DryRunResult = components["schemas"]["DryRunResult"];
 export type FileDiff = components["schemas"]["FileDiff"];
+export type MigrationVersion = components["schemas"]["MigrationVersion"];
+export type MigrationVersionDiff = components["schemas"]["MigrationVersionDiff"];
+export type RunAttempt = components["schemas"]["RunAttempt"];
 export interface MetricsOverview {
   totalRuns: number;
   completedRuns: number;
@@ -43,6 +46,17 @@ export interface StepEventRecord {
   createdAt: string;
 }
 
+export interface ProgressEvent {
+  id: number;
+  migrationId: string;
+  candidateId: string;
+  eventType: string;
+  stepName?: string;
+  status?: string;
+  metadata?: Record<string, string>;
+  createdAt: string;
+}
+
 const BASE = "/api";
 
 export async function listMigrations(): Promise<{ migrations: Migration[] }> {
@@ -57,6 +71,18 @@ export async function getMigration(id: string): Promise<Migration> {
   return res.json();
 }
 
+export async function listMigrationVersions(id: string): Promise<{ versions: MigrationVersion[] }> {
+  const res = await fetch(`${BASE}/migrations/${id}/versions`);
+  if (!res.ok) throw new Error(await res.text());
+  return res.json();
+}
+
+export async function diffMigrationVersions(id: string, from: number, to: number): Promise<MigrationVersionDiff> {
+  const res = await fetch(`${BASE}/migrations/${id}/versions/diff?from=${from}&to=${to}`);
+  if (!res.ok) throw new Error(await res.text());
+  return res.json();
+}
+
 export class ConflictError extends Error {
   constructor(message: string) {
     super(message);
@@ -65,16 +91,19 @@ export class ConflictError extends Error {
 }
 
 export async function completeStep(
-  runId: string,
-  stepName: string,
+  migrationId: string,
   candidateId: string,
+  stepName: string,
   status: string,
 ): Promise<void> {
-  const res = await fetch(`${BASE}/event/${runId}`, {
-    method: "POST",
-    headers: { "Content-Type": "application/json" },
-    body: JSON.stringify({ stepName, candidateId, status }),
-  });
+  const res = await fetch(
+    `${BASE}/migrations/${migrationId}/candidates/${candidateId}/complete-step`,
+    {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ stepName, status }),
+    },
+  );
   if (!res.ok) throw new Error(await res.text());
 }
 
@@ -94,6 +123,29 @@ export async function getCandidateSteps(
   return res.json();
 }
 
+/**
+ * Streams a candidate's step transitions and status changes. onOpen and onError
+ * follow the connection: EventSource reconnects on its own, resuming after the
+ * last event it received. Returns a function that closes the stream.
+ */
+export function subscribeCandidateProgress(
+  migrationId: string,
+  candidateId: string,
+  handlers: {
+    onEvent: (event: ProgressEvent) => void;
+    onOpen?: () => void;
+    onError?: () => void;
+  },
+): () => void {
+  const source = new EventSource(`${BASE}/migrations/${migrationId}/candidates/${candidateId}/stream`);
+  source.addEventListener("progress", (e) => {
+    handlers.onEvent(JSON.parse((e as MessageEvent<string>).data) as ProgressEvent);
+  });
+  source.onopen = () => handlers.onOpen?.();
+  source.onerror = () => handlers.onError?.();
+  return () => source.close();
+}
+
 export async function startRun(
   migrationId: string,
   candidateId: string,
@@ -116,6 +168,42 @@ export async function cancelRun(migrationId: string, candidateId: string): Promi
   if (!res.ok) throw new Error(await res.text());
 }
 
+export async function pauseRun(migrationId: string, candidateId: string): Promise<void> {
+  const res = await fetch(`${BASE}/migrations/${migrationId}/candidates/${candidateId}/pause`, {
+    method: "POST",
+  });
+  if (!res.ok) throw new Error(await res.text());
+}
+
+export async function resumeRun(migrationId: string, candidateId: string): Promise<void> {
+  const res = await fetch(`${BASE}/migrations/${migrationId}/candidates/${candidateId}/resume`, {
+    method: "POST",
+  });
+  if (!res.ok) throw new Error(await res.text());
+}
+
+export async function rollbackRun(migrationId: string, candidateId: string): Promise<void> {
+  const res = await fetch(`${BASE}/migrations/${migrationId}/candidates/${candidateId}/rollback`, {
+    method: "POST",
+  });
+  if (res.status === 409) throw new ConflictError(await res.text());
+  if (!res.ok) throw new Error(await res.text());
+}
+
+export async function resetCandidate(migrationId: string, candidateId: string): Promise<void> {
+  const res = await fetch(`${BASE}/migrations/${migrationId}/candidates/${candidateId}/reset`, {
+    method: "POST",
+  });
+  if (res.status === 409) throw new ConflictError(await res.text());
+  if (!res.ok) throw new Error(await res.text());
+}
+
+export async function listRuns(migrationId: string, candidateId: string): Promise<{ runs: RunAttempt[] }> {
+  const res = await fetch(`${BASE}/migrations/${migrationId}/candidates/${candidateId}/runs`);
+  if (!res.ok) throw new Error(await res.text());
+  return res.json();
+}
+
 export async function retryStep(
   migrationId: string,
   candidateId: string,
@@ -132,6 +220,23 @@ export async function retryStep(
   if (!res.ok) throw new Error(await res.text());
 }
 
+export async function skipStep(
+  migrationId: string,
+  candidateId: string,
+  stepName: string,
+  reason: string,
+): Promise<void> {
+  const res = await fetch(
+    `${BASE}/migrations/${migrationId}/candidates/${candidateId}/skip-step`,
+    {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ stepName, reason }),
+    },
+  );
+  if (!res.ok) throw new Error(await res.text());
+}
+
 export async function updateInputs(
   migrationId: string,
   candidateId: string,
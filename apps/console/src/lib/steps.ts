@@ -14,15 +14,53 @@ export function buildStepDescriptionMap(steps: StepDefinition[]): Map<string, st
   return new Map(steps.filter((s) => s.description).map((s) => [s.name, s.description ?? ""]));
 }
 
+// Whether any step declares dependsOn; otherwise steps run one after another.
+export function hasStepGraph(steps: StepDefinition[]): boolean {
+  return steps.some((s) => s.dependsOn !== undefined);
+}
+
+// Mirrors the server's StepDependencies: without dependsOn anywhere, each step
+// depends on the one before it.
+export function buildStepDependencyMap(steps: StepDefinition[]): Map<string, string[]> {
+  const explicit = hasStepGraph(steps);
+  return new Map(
+    steps.map((s, i) => {
+      if (explicit) return [s.name, s.dependsOn ?? []];
+      return [s.name, i > 0 ? [steps[i - 1].name] : []];
+    }),
+  );
+}
+
+// Groups steps into stages that run one after another; steps in the same stage
+// only depend on earlier stages, so they run concurrently. Steps on a dependency
+// cycle (rejected by the server) are left out.
+export function groupStepsIntoStages(steps: StepDefinition[]): string[][] {
+  const deps = buildStepDependencyMap(steps);
+  const placed = new Set<string>();
+  const stages: string[][] = [];
+  for (;;) {
+    const stage = steps
+      .map((s) => s.name)
+      .filter((name) => !placed.has(name) && (deps.get(name) ?? []).every((d) => placed.has(d)));
+    if (stage.length === 0) return stages;
+    stage.forEach((name) => placed.add(name));
+    stages.push(stage);
+  }
+}
+
 export function calculateStepProgress(
   stepsData: CandidateStepsResponse,
   totalSteps: number,
 ): { done: number; total: number; activeStepName: string | undefined } {
   const reported = stepsData.steps;
-  const done = reported.filter((s) => s.status === "succeeded" || s.status === "merged").length;
+  const done = reported.filter(
+    (s) => s.status === "succeeded" || s.status === "merged" || s.status === "skipped",
+  ).length;
   const active =
     reported.find((s) => s.status === "in_progress") ??
-    reported.find((s) => s.status === "failed") ??
-    reported.find((s) => s.status === "pending");
+    reported.find((s) => s.status === "failed" || s.status === "timed_out") ??
+    reported.find(
+      (s) => s.status === "pending" || s.status === "waiting_for_window" || s.status === "queued",
+    );
   return { done, total: totalSteps, activeStepName: active?.stepName };
 }
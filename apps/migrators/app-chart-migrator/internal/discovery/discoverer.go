@@ -24,6 +24,7 @@ type Runner struct {
 	MigrationID string
 	Discoverer  Discoverer
 	ServerURL   string
+	Token       string // bearer token for the server's API; empty when auth is disabled
 	Log         *slog.Logger
 }
 
@@ -61,6 +62,9 @@ func (r *Runner) Run(ctx context.Context) {
 			return
 		}
 		httpReq.Header.Set("Content-Type", "application/json")
+		if r.Token != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+r.Token)
+		}
 
 		resp, err := http.DefaultClient.Do(httpReq)
 		if err != nil {
@@ -36,35 +36,57 @@ func NewDispatch(
 	return &Dispatch{gr: gr, pending: store, loom: loomClient, log: log, stepCfg: stepCfg}
 }
 
+// RejectedError is returned by Run for a step it cannot act on. The server
+// fails such a step without retrying the dispatch.
+type RejectedError struct {
+	Reason string
+}
+
+func (e RejectedError) Error() string {
+	return e.Reason
+}
+
 // Handle processes a DispatchStepRequest posted directly by the server.
 func (d *Dispatch) Handle(c *gin.Context) {
 	var req api.DispatchStepRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		msg := err.Error()
+		c.JSON(http.StatusBadRequest, api.DispatchError{Code: "invalid_request", Message: &msg})
+		return
+	}
+
+	if err := d.Run(c.Request.Context(), req); err != nil {
+		msg := err.Error()
+		c.JSON(http.StatusBadRequest, api.DispatchError{Code: "invalid_request", Message: &msg})
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{"status": "SUCCESS"})
+}
 
+// Run takes a dispatched step. Outcomes are reported to the server through
+// callbacks; the only error is a RejectedError for a step it cannot act on.
+func (d *Dispatch) Run(ctx context.Context, req api.DispatchStepRequest) error {
 	d.log.Info("received dispatch", "step", req.StepName, "target", req.Candidate.Id, "callbackId", req.CallbackId)
 
 	// Route to the registered step handler (by req.Type).
-	result, handled, err := d.routeToHandler(c.Request.Context(), req)
+	result, handled, err := d.routeToHandler(ctx, req)
 	if err != nil {
 		// Signal the workflow that this step failed so it can surface in the UI.
-		_ = d.loom.SendCallback(c.Request.Context(), req.CallbackId, api.StepStatusEvent{
+		_ = d.loom.SendCallback(ctx, req.CallbackId, api.StepStatusEvent{
 			StepName:    req.StepName,
 			CandidateId: req.Candidate.Id,
 			Status:      api.StepStatusEventStatusFailed,
+			Attempt:     req.Attempt,
 		})
-		c.JSON(http.StatusOK, gin.H{"status": "SUCCESS"})
-		return
+		return nil
 	}
 
 	// Handler acknowledged the step but requires no PR (e.g. manual-review).
 	// Signal "pending" with instructions so the UI shows the Approve/Reject buttons.
 	if handled && result.Owner == "" {
-		d.loom.SendUpdate(c.Request.Context(), req.CallbackId, req.StepName, req.Candidate.Id, map[string]string{"instructions": result.Instructions})
-		c.JSON(http.StatusOK, gin.H{"status": "SUCCESS"})
-		return
+		d.loom.SendUpdate(ctx, req.CallbackId, req.StepName, req.Candidate.Id, req.Attempt,
+			map[string]string{"instructions": result.Instructions})
+		return nil
 	}
 
 	var owner, repo, title, body, branch string
@@ -89,8 +111,7 @@ func (d *Dispatch) Handle(c *gin.Context) {
 		parts := strings.SplitN(repoName, "/", 2)
 		if len(parts) != 2 {
 			d.log.Error("invalid target format (expected owner/repo in repoName metadata)", "candidate", req.Candidate.Id)
-			c.JSON(http.StatusOK, gin.H{"status": "SUCCESS"})
-			return
+			return RejectedError{Reason: fmt.Sprintf("candidate %q has no owner/repo repoName", req.Candidate.Id)}
 		}
 		owner, repo = parts[0], parts[1]
 		title = fmt.Sprintf("[%s] %s — %s", req.MigrationId, req.StepName, req.Candidate.Id)
@@ -99,7 +120,7 @@ func (d *Dispatch) Handle(c *gin.Context) {
 	}
 
 	// Create PR on GitHub.
-	pr, err := d.gr.CreatePR(c.Request.Context(), owner, repo, gitrepo.CreatePRRequest{
+	pr, err := d.gr.CreatePR(ctx, owner, repo, gitrepo.CreatePRRequest{
 		Title: title,
 		Body:  body,
 		Head:  branch,
@@ -108,13 +129,13 @@ func (d *Dispatch) Handle(c *gin.Context) {
 	})
 	if err != nil {
 		d.log.Error("failed to create PR", "error", err, "target", req.Candidate.Id)
-		_ = d.loom.SendCallback(c.Request.Context(), req.CallbackId, api.StepStatusEvent{
+		_ = d.loom.SendCallback(ctx, req.CallbackId, api.StepStatusEvent{
 			StepName:    req.StepName,
 			CandidateId: req.Candidate.Id,
 			Status:      api.StepStatusEventStatusFailed,
+			Attempt:     req.Attempt,
 		})
-		c.JSON(http.StatusOK, gin.H{"status": "SUCCESS"})
-		return
+		return nil
 	}
 
 	d.log.Info("PR created", "target", req.Candidate.Id, "repo", owner+"/"+repo, "pr", pr.HTMLURL)
@@ -127,13 +148,14 @@ func (d *Dispatch) Handle(c *gin.Context) {
 		StepName:    req.StepName,
 		CandidateId: req.Candidate.Id,
 		PRURL:       pr.HTMLURL,
+		Attempt:     req.Attempt,
 	})
 
 	// Notify the workflow that a PR is open so the UI can show the link
 	// and the "Mark as merged" button while waiting for the webhook.
-	d.loom.SendUpdate(c.Request.Context(), req.CallbackId, req.StepName, req.Candidate.Id, map[string]string{"prUrl": pr.HTMLURL})
-
-	c.JSON(http.StatusOK, gin.H{"status": "SUCCESS"})
+	d.loom.SendUpdate(ctx, req.CallbackId, req.StepName, req.Candidate.Id, req.Attempt,
+		map[string]string{"prUrl": pr.HTMLURL})
+	return nil
 }
 
 // routeToHandler looks up the registered step handler for req.Type and executes it.
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 
@@ -30,14 +31,23 @@ func (h *DryRun) Handle(c *gin.Context) {
 		return
 	}
 
-	h.log.Info("dry run started", "migrationId", req.MigrationId, "candidate", req.Candidate.Id, "steps", len(req.Steps))
-
-	result, err := h.runner.Run(c.Request.Context(), req)
+	result, err := h.Run(c.Request.Context(), req)
 	if err != nil {
-		h.log.Error("dry run failed", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, result)
 }
+
+// Run simulates the steps of req and returns per-step file diffs.
+func (h *DryRun) Run(ctx context.Context, req api.DryRunRequest) (*api.DryRunResult, error) {
+	h.log.Info("dry run started", "migrationId", req.MigrationId, "candidate", req.Candidate.Id, "steps", len(req.Steps))
+
+	result, err := h.runner.Run(ctx, req)
+	if err != nil {
+		h.log.Error("dry run failed", "error", err)
+		return nil, err
+	}
+	return result, nil
+}
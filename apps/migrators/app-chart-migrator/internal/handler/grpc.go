@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tilsley/loom/pkg/migratorpb"
+)
+
+// GRPC serves the Migrator gRPC service with the same handlers as the
+// /dispatch-step and /dry-run routes.
+type GRPC struct {
+	migratorpb.UnimplementedMigratorServer
+	dispatch *Dispatch
+	dryRun   *DryRun
+}
+
+// NewGRPC creates a GRPC server.
+func NewGRPC(dispatch *Dispatch, dryRun *DryRun) *GRPC {
+	return &GRPC{dispatch: dispatch, dryRun: dryRun}
+}
+
+// DispatchStep takes a dispatched step. A step it cannot act on is refused
+// with INVALID_ARGUMENT, so the server fails it without retrying.
+func (g *GRPC) DispatchStep(
+	ctx context.Context,
+	req *migratorpb.DispatchStepRequest,
+) (*migratorpb.DispatchStepResponse, error) {
+	if err := g.dispatch.Run(ctx, req.API()); err != nil {
+		var rejected RejectedError
+		if errors.As(err, &rejected) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &migratorpb.DispatchStepResponse{}, nil
+}
+
+// DryRun simulates the steps of a candidate and returns per-step file diffs.
+func (g *GRPC) DryRun(ctx context.Context, req *migratorpb.DryRunRequest) (*migratorpb.DryRunResult, error) {
+	result, err := g.dryRun.Run(ctx, req.API())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return migratorpb.NewDryRunResult(*result), nil
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// Consume takes a step read from the dispatch stream (see pkg/dispatchqueue).
+// With no dispatch call to refuse, a step Run rejects is reported as failed
+// through a callback carrying the reason, as the server does for a step it
+// could not dispatch. The entry stays pending while that callback cannot be
+// delivered.
+func (d *Dispatch) Consume(ctx context.Context, req api.DispatchStepRequest) error {
+	err := d.Run(ctx, req)
+	var rejected RejectedError
+	if !errors.As(err, &rejected) {
+		return err
+	}
+	return d.loom.SendCallback(ctx, req.CallbackId, api.StepStatusEvent{
+		StepName:    req.StepName,
+		CandidateId: req.Candidate.Id,
+		Status:      api.StepStatusEventStatusFailed,
+		Attempt:     req.Attempt,
+		Metadata:    &map[string]string{"dispatchError": rejected.Reason},
+	})
+}
@@ -70,6 +70,7 @@ func (w *Webhook) Handle(c *gin.Context) {
 		CandidateId: cb.CandidateId,
 		Status:      api.StepStatusEventStatusMerged,
 		Metadata:    &meta,
+		Attempt:     cb.Attempt,
 	}
 
 	if err := w.loom.SendCallback(c.Request.Context(), cb.CallbackID, event); err != nil {
@@ -3,39 +3,95 @@ package loom
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/tilsley/loom/pkg/api"
+	"github.com/tilsley/loom/pkg/migratorpb"
+	"github.com/tilsley/loom/pkg/signing"
 )
 
 // Client sends progress and callback events to the Loom server.
 type Client struct {
 	BaseURL string
+	Token   string // bearer token for the server's API; empty when auth is disabled
+	Secret  string // shared secret callbacks are signed with; empty sends them unsigned
 	Log     *slog.Logger
+	// GRPC, when set, sends callbacks through the server's Loom gRPC service
+	// instead of POSTing them to BaseURL.
+	GRPC migratorpb.LoomClient
 }
 
 // NewClient creates a Loom HTTP client.
-func NewClient(baseURL string, log *slog.Logger) *Client {
-	return &Client{BaseURL: baseURL, Log: log}
+func NewClient(baseURL, token, secret string, log *slog.Logger) *Client {
+	return &Client{BaseURL: baseURL, Token: token, Secret: secret, Log: log}
 }
 
-// SendCallback posts a completion event to /event/{callbackID}.
+// callbackAttempts is how many times a callback is sent before giving up.
+// Every resend carries the same event ID, so the server applies it at most once.
+const callbackAttempts = 3
+
+// callbackRetryDelay is the wait before the first resend; it doubles after each.
+var callbackRetryDelay = 500 * time.Millisecond
+
+// SendCallback posts a completion event to /event/{callbackID}, signed with
+// the client's secret. An event without an ID is given one. Network errors and
+// 5xx responses are retried with the same event ID.
 func (c *Client) SendCallback(ctx context.Context, callbackID string, event api.StepStatusEvent) error {
+	if event.EventId == nil {
+		id := newEventID()
+		event.EventId = &id
+	}
 	url := fmt.Sprintf("%s/event/%s", c.BaseURL, callbackID)
-	return c.post(ctx, url, event)
+
+	delay := callbackRetryDelay
+	var err error
+	for attempt := 1; ; attempt++ {
+		var retryable bool
+		if c.GRPC != nil {
+			retryable, err = c.call(ctx, callbackID, event)
+		} else {
+			retryable, err = c.post(ctx, url, callbackID, event)
+		}
+		if err == nil || !retryable || attempt == callbackAttempts {
+			return err
+		}
+		c.Log.Warn("callback failed, resending", "error", err, "callbackId", callbackID, "eventId", *event.EventId)
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
 }
 
 // SendUpdate signals the workflow that a step is still in progress, carrying
 // arbitrary metadata for UI rendering (e.g. prUrl, instructions).
 // The workflow continues waiting for a subsequent terminal signal.
-func (c *Client) SendUpdate(ctx context.Context, callbackID, stepName, candidateID string, metadata map[string]string) {
+// attempt is the DispatchStepRequest.attempt being answered.
+func (c *Client) SendUpdate(
+	ctx context.Context,
+	callbackID, stepName, candidateID string,
+	attempt *int,
+	metadata map[string]string,
+) {
 	event := api.StepStatusEvent{
 		StepName:    stepName,
 		CandidateId: candidateID,
 		Status:      api.StepStatusEventStatusPending,
+		Attempt:     attempt,
 	}
 	if len(metadata) > 0 {
 		event.Metadata = &metadata
@@ -45,25 +101,75 @@ func (c *Client) SendUpdate(ctx context.Context, callbackID, stepName, candidate
 	}
 }
 
-func (c *Client) post(ctx context.Context, url string, event api.StepStatusEvent) error {
+// post sends event once and reports whether a failure is worth retrying.
+func (c *Client) post(ctx context.Context, url, callbackID string, event api.StepStatusEvent) (bool, error) {
 	body, err := json.Marshal(event)
 	if err != nil {
-		return fmt.Errorf("marshal event: %w", err)
+		return false, fmt.Errorf("marshal event: %w", err)
 	}
 
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+		return false, fmt.Errorf("create request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	if c.Token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	if c.Secret != "" {
+		signing.SignRequest(httpReq, c.Secret, callbackID, body, time.Now())
+	}
 
 	resp, err := http.DefaultClient.Do(httpReq)
 	if err != nil {
-		return fmt.Errorf("POST %s: %w", url, err)
+		return true, fmt.Errorf("POST %s: %w", url, err)
 	}
 	defer func() { //nolint:errcheck // response body close errors are non-actionable after reading
 		_ = resp.Body.Close()
 	}()
 
-	return nil
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode >= http.StatusInternalServerError, fmt.Errorf("POST %s: %s", url, resp.Status)
+	}
+	return false, nil
+}
+
+// call sends event once over gRPC and reports whether a failure is worth
+// retrying. The metadata carries the same token and signature as post's headers.
+func (c *Client) call(ctx context.Context, callbackID string, event api.StepStatusEvent) (bool, error) {
+	msg := migratorpb.NewStepStatusEvent(event)
+	payload, err := migratorpb.CallbackPayload(msg)
+	if err != nil {
+		return false, fmt.Errorf("marshal event: %w", err)
+	}
+
+	var md []string
+	if c.Token != "" {
+		md = append(md, "authorization", "Bearer "+c.Token)
+	}
+	if c.Secret != "" {
+		now := time.Now()
+		md = append(md,
+			signing.TimestampHeader, strconv.FormatInt(now.Unix(), 10),
+			signing.SignatureHeader, signing.Sign(c.Secret, now, callbackID, payload),
+		)
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, md...)
+
+	_, err = c.GRPC.SendCallback(ctx, &migratorpb.SendCallbackRequest{CallbackId: callbackID, Event: msg})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown:
+			return true, fmt.Errorf("SendCallback %s: %w", callbackID, err)
+		}
+		return false, fmt.Errorf("SendCallback %s: %w", callbackID, err)
+	}
+	return false, nil
+}
+
+// newEventID returns a random ID for a callback event.
+func newEventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // crypto/rand.Read never returns an error
+	return hex.EncodeToString(b)
 }
@@ -0,0 +1,123 @@
+package loom_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/tilsley/loom/apps/migrators/app-chart-migrator/internal/platform/loom"
+	"github.com/tilsley/loom/pkg/api"
+	"github.com/tilsley/loom/pkg/migratorpb"
+	"github.com/tilsley/loom/pkg/signing"
+)
+
+// TestSendCallback_ResendsWithSameEventID verifies that a callback the server
+// failed to handle is resent carrying the event ID of the first delivery.
+func TestSendCallback_ResendsWithSameEventID(t *testing.T) {
+	var received []api.StepStatusEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event api.StepStatusEvent
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		received = append(received, event)
+		if len(received) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	attempt := 2
+	client := loom.NewClient(srv.URL, "", "", slog.Default())
+	err := client.SendCallback(context.Background(), "mig__billing-api", api.StepStatusEvent{
+		StepName:    "update-chart",
+		CandidateId: "billing-api",
+		Status:      api.StepStatusEventStatusMerged,
+		Attempt:     &attempt,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, received, 2)
+	require.NotNil(t, received[0].EventId)
+	assert.Equal(t, *received[0].EventId, *received[1].EventId)
+	assert.Equal(t, 2, *received[1].Attempt)
+}
+
+// TestSendCallback_DoesNotRetryClientErrors verifies that a rejected callback
+// is reported without being resent.
+func TestSendCallback_DoesNotRetryClientErrors(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	client := loom.NewClient(srv.URL, "", "", slog.Default())
+	err := client.SendCallback(context.Background(), "mig__billing-api", api.StepStatusEvent{})
+
+	require.ErrorContains(t, err, "401")
+	assert.Equal(t, 1, calls)
+}
+
+// fakeLoom records the callbacks sent over gRPC and fails the first with fail.
+type fakeLoom struct {
+	migratorpb.LoomClient
+	reqs []*migratorpb.SendCallbackRequest
+	mds  []metadata.MD
+	fail error
+}
+
+func (f *fakeLoom) SendCallback(
+	ctx context.Context,
+	req *migratorpb.SendCallbackRequest,
+	_ ...grpc.CallOption,
+) (*migratorpb.SendCallbackResponse, error) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	f.reqs = append(f.reqs, req)
+	f.mds = append(f.mds, md)
+	if len(f.reqs) == 1 && f.fail != nil {
+		return nil, f.fail
+	}
+	return &migratorpb.SendCallbackResponse{}, nil
+}
+
+// TestSendCallback_GRPC_SignsAndResends verifies that callbacks sent over gRPC
+// carry the token and a valid signature, and are resent when the server is
+// unavailable.
+func TestSendCallback_GRPC_SignsAndResends(t *testing.T) {
+	fake := &fakeLoom{fail: status.Error(codes.Unavailable, "restarting")}
+	client := loom.NewClient("", "tok", "s3cret", slog.Default())
+	client.GRPC = fake
+
+	err := client.SendCallback(context.Background(), "mig__billing-api", api.StepStatusEvent{
+		StepName:    "update-chart",
+		CandidateId: "billing-api",
+		Status:      api.StepStatusEventStatusSucceeded,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, fake.reqs, 2)
+	assert.Equal(t, "mig__billing-api", fake.reqs[1].GetCallbackId())
+	event := fake.reqs[1].GetEvent()
+	assert.Equal(t, fake.reqs[0].GetEvent().GetEventId(), event.GetEventId(), "resent with the same event ID")
+	assert.Equal(t, migratorpb.StepStatus_STEP_STATUS_SUCCEEDED, event.GetStatus())
+
+	md := fake.mds[1]
+	assert.Equal(t, []string{"Bearer tok"}, md.Get("authorization"))
+	payload, err := migratorpb.CallbackPayload(event)
+	require.NoError(t, err)
+	require.NoError(t, signing.Verify("s3cret", "mig__billing-api", payload,
+		md.Get(signing.TimestampHeader)[0], md.Get(signing.SignatureHeader)[0], time.Now(), signing.DefaultTolerance))
+}
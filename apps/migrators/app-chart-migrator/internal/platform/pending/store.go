@@ -17,6 +17,7 @@ type Callback struct {
 	StepName    string `json:"stepName"`
 	CandidateId string `json:"candidateId"`
 	PRURL       string `json:"prUrl"`
+	Attempt     *int   `json:"attempt,omitempty"` // dispatch attempt the PR was raised for
 }
 
 // Store persists pending PR-to-workflow callback mappings in Redis
@@ -6,12 +6,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	githubadapter "github.com/tilsley/loom/apps/migrators/app-chart-migrator/internal/adapters/github"
 	"github.com/tilsley/loom/apps/migrators/app-chart-migrator/internal/discovery"
@@ -22,7 +28,9 @@ import (
 	"github.com/tilsley/loom/apps/migrators/app-chart-migrator/internal/platform/pending"
 	"github.com/tilsley/loom/apps/migrators/app-chart-migrator/internal/steps"
 	"github.com/tilsley/loom/pkg/api"
+	"github.com/tilsley/loom/pkg/dispatchqueue"
 	"github.com/tilsley/loom/pkg/logging"
+	"github.com/tilsley/loom/pkg/migratorpb"
 )
 
 func main() {
@@ -30,8 +38,12 @@ func main() {
 
 	githubURL := envOr("GITHUB_API_URL", "http://localhost:9090")
 	loomURL := envOr("LOOM_URL", "http://localhost:8080")
+	loomToken := os.Getenv("LOOM_TOKEN")
+	callbackSecret := os.Getenv("LOOM_CALLBACK_SECRET")
 	workerURL := envOr("WORKER_URL", "http://localhost:8082")
 	port := envOr("PORT", "8082")
+	grpcPort := os.Getenv("GRPC_PORT")
+	loomGRPCAddr := os.Getenv("LOOM_GRPC_ADDR")
 
 	// Parse gitops repo from env
 	gitopsRepo := envOr("GITOPS_REPO", "tilsley/gitops")
@@ -85,7 +97,16 @@ func main() {
 	}
 
 	store := pending.NewStore(redisAddr, log)
-	loomClient := loom.NewClient(loomURL, log)
+	loomClient := loom.NewClient(loomURL, loomToken, callbackSecret, log)
+	if loomGRPCAddr != "" {
+		cc, err := grpc.NewClient(loomGRPCAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			log.Error("loom grpc client init failed", "error", err)
+			os.Exit(1)
+		}
+		loomClient.GRPC = migratorpb.NewLoomClient(cc)
+		log.Info("sending callbacks over grpc", "addr", loomGRPCAddr)
+	}
 	dispatch := handler.NewDispatch(ghAdapter, store, loomClient, log, stepCfg)
 	webhook := handler.NewWebhook(store, loomClient, log)
 	dryRunRunner := &dryrun.Runner{RealClient: ghAdapter, StepCfg: stepCfg}
@@ -107,8 +128,54 @@ func main() {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
 
+	// gRPC — the same dispatch and dry run, for a grpc:// WORKER_URL. The
+	// health service answers the server's default gRPC health check.
+	if grpcPort != "" {
+		lis, err := net.Listen("tcp", ":"+grpcPort)
+		if err != nil {
+			log.Error("grpc listen failed", "error", err)
+			os.Exit(1)
+		}
+		grpcServer := grpc.NewServer()
+		migratorpb.RegisterMigratorServer(grpcServer, handler.NewGRPC(dispatch, dryRunHandler))
+		healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+		go func() {
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Error("grpc server failed", "error", err)
+				os.Exit(1)
+			}
+		}()
+		log.Info("grpc server starting", "port", grpcPort)
+	}
+
 	ctx := context.Background()
 
+	// Dispatch stream — pull steps queued by a server with DISPATCH_REDIS_ADDR.
+	// Replicas share the stream through one consumer group; each needs a
+	// consumer name that survives its restarts so its pending steps are
+	// picked up again.
+	if os.Getenv("DISPATCH_FROM_STREAM") == "true" {
+		consumerName := os.Getenv("DISPATCH_CONSUMER")
+		if consumerName == "" {
+			consumerName, _ = os.Hostname()
+		}
+		consumer := &dispatchqueue.Consumer{
+			RDB:     redis.NewClient(&redis.Options{Addr: envOr("DISPATCH_REDIS_ADDR", redisAddr)}),
+			App:     "app-chart-migrator",
+			Group:   "app-chart-migrator",
+			Name:    consumerName,
+			Handler: dispatch.Consume,
+			Log:     log,
+		}
+		go func() {
+			if err := consumer.Run(ctx); err != nil {
+				log.Error("dispatch stream consumer failed", "error", err)
+				os.Exit(1)
+			}
+		}()
+		log.Info("consuming dispatch stream", "stream", dispatchqueue.Stream(consumer.App), "consumer", consumerName)
+	}
+
 	// Announce migration on startup, then discover candidates.
 	discoverer := &discovery.AppChartDiscoverer{
 		Reader:      ghAdapter,
@@ -122,10 +189,11 @@ func main() {
 		MigrationID: "app-chart-migration",
 		Discoverer:  discoverer,
 		ServerURL:   loomURL,
+		Token:       loomToken,
 		Log:         log,
 	}
 	go func() {
-		if !announceOnStartup(log, loomURL, workerURL, gitopsOwner, gitopsRepoName, envs) {
+		if !announceOnStartup(log, loomURL, loomToken, workerURL, gitopsOwner, gitopsRepoName, envs) {
 			return
 		}
 		discoveryRunner.Run(ctx)
@@ -246,44 +314,64 @@ func buildAnnouncement(workerURL, gitopsOwner, gitopsRepoName string, envs []str
 	}
 }
 
-func announceOnStartup(log *slog.Logger, loomURL, workerURL, gitopsOwner, gitopsRepoName string, envs []string) bool {
+func announceOnStartup(
+	log *slog.Logger,
+	loomURL, loomToken, workerURL, gitopsOwner, gitopsRepoName string,
+	envs []string,
+) bool {
 	// Small pause to let the server start accepting connections.
 	time.Sleep(2 * time.Second)
 
+	// Register this migrator's URL first, so steps that name it are dispatched
+	// here even when a migration's migratorUrl points elsewhere.
+	registration := api.MigratorRegistration{MigratorApp: "app-chart-migrator", Url: workerURL}
+	if !postToLoom(log, loomURL+"/registry/migrators", loomToken, registration) {
+		log.Error("failed to register migrator after retries")
+		return false
+	}
+	log.Info("migrator registered", "migratorApp", registration.MigratorApp, "url", workerURL)
+
 	announcement := buildAnnouncement(workerURL, gitopsOwner, gitopsRepoName, envs)
+	if !postToLoom(log, loomURL+"/registry/announce", loomToken, announcement) {
+		log.Error("failed to announce migration after retries")
+		return false
+	}
+	log.Info("migration announced", "id", announcement.Id, "steps", len(announcement.Steps))
+	return true
+}
 
-	body, err := json.Marshal(announcement)
+// postToLoom POSTs payload as JSON to url, retrying up to 10 times while the
+// server is not ready yet. It reports whether the server accepted it.
+func postToLoom(log *slog.Logger, url, loomToken string, payload any) bool {
+	body, err := json.Marshal(payload)
 	if err != nil {
-		log.Error("failed to marshal announcement", "error", err)
+		log.Error("failed to marshal request", "url", url, "error", err)
 		return false
 	}
 
-	url := loomURL + "/registry/announce"
-
-	// Retry up to 10 times (server may not be ready yet).
 	for i := range 10 {
 		httpReq, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, bytes.NewReader(body))
 		if err != nil {
-			log.Warn("announce: failed to build request", "error", err)
-			break
+			log.Warn("failed to build request", "url", url, "error", err)
+			return false
 		}
 		httpReq.Header.Set("Content-Type", "application/json")
+		if loomToken != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+loomToken)
+		}
 
 		resp, err := http.DefaultClient.Do(httpReq)
 		if err == nil {
 			_ = resp.Body.Close() //nolint:errcheck // response body close errors are non-actionable
 			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-				log.Info("migration announced", "id", announcement.Id, "steps", len(announcement.Steps))
 				return true
 			}
-			log.Warn("announce returned non-2xx", "status", resp.StatusCode, "attempt", i+1)
+			log.Warn("loom returned non-2xx", "url", url, "status", resp.StatusCode, "attempt", i+1)
 		} else {
-			log.Warn("announce failed", "error", err, "attempt", i+1)
+			log.Warn("request to loom failed", "url", url, "error", err, "attempt", i+1)
 		}
 		time.Sleep(2 * time.Second)
 	}
-
-	log.Error("failed to announce migration after retries")
 	return false
 }
 
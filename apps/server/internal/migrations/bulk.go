@@ -0,0 +1,84 @@
+package migrations
+
+import (
+	"slices"
+
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// BulkStartRunType is the engine run type that rolls a set of candidates out in waves.
+const BulkStartRunType = "BulkStartOrchestrator"
+
+// BulkStartInput is the input passed to a bulk start run.
+// Only candidate IDs are carried; each candidate's MigrationManifest is built
+// when its turn comes, so the run always starts from the current definition.
+type BulkStartInput struct {
+	MigrationID  string            `json:"migrationId"`
+	CandidateIDs []string          `json:"candidateIds"`
+	MaxInFlight  int               `json:"maxInFlight"`
+	Inputs       map[string]string `json:"inputs,omitempty"`
+}
+
+// SelectCandidates returns the candidates that match every criterion in sel,
+// preserving the order of candidates.
+func SelectCandidates(candidates []api.Candidate, sel api.CandidateSelector) []api.Candidate {
+	var out []api.Candidate
+	for _, c := range candidates {
+		if matchesSelector(c, sel) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func matchesSelector(c api.Candidate, sel api.CandidateSelector) bool {
+	if sel.Ids != nil && !slices.Contains(*sel.Ids, c.Id) {
+		return false
+	}
+	if sel.Kind != nil && *sel.Kind != "" && c.Kind != *sel.Kind {
+		return false
+	}
+	if sel.Metadata != nil {
+		for k, want := range *sel.Metadata {
+			if c.Metadata == nil {
+				return false
+			}
+			if got, ok := (*c.Metadata)[k]; !ok || got != want {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// BuildManifest snapshots the migration definition for a single candidate,
+// merging operator-supplied inputs into the candidate's metadata. The
+// candidate's own step list wins over the migration-level steps when present.
+// The manifest records the definition version it was built from.
+func BuildManifest(m api.Migration, candidate api.Candidate, inputs map[string]string) api.MigrationManifest {
+	if len(inputs) > 0 {
+		md := make(map[string]string)
+		if candidate.Metadata != nil {
+			for k, v := range *candidate.Metadata {
+				md[k] = v
+			}
+		}
+		for k, v := range inputs {
+			md[k] = v
+		}
+		candidate.Metadata = &md
+	}
+
+	steps := m.Steps
+	if candidate.Steps != nil && len(*candidate.Steps) > 0 {
+		steps = *candidate.Steps
+	}
+
+	return api.MigrationManifest{
+		MigrationId: m.Id,
+		Candidates:  []api.Candidate{candidate},
+		Steps:       steps,
+		MigratorUrl: m.MigratorUrl,
+		Version:     m.Version,
+	}
+}
@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"strconv"
+
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// Reasons a step callback is discarded, recorded in the "reason" metadata of a
+// callback_ignored event.
+const (
+	IgnoredDuplicate    = "duplicate"
+	IgnoredStaleAttempt = "stale attempt"
+)
+
+// IgnoredCallbackEvent builds the callback_ignored event recorded when a step
+// callback is discarded, keeping its event ID, attempt and status so that
+// redelivered or late callbacks can be traced back to the migrator.
+func IgnoredCallbackEvent(migrationID string, event api.StepStatusEvent, reason string) StepEvent {
+	md := map[string]string{"reason": reason}
+	if event.EventId != nil {
+		md["eventId"] = *event.EventId
+	}
+	if event.Attempt != nil {
+		md["attempt"] = strconv.Itoa(*event.Attempt)
+	}
+	return StepEvent{
+		MigrationID: migrationID,
+		CandidateID: event.CandidateId,
+		StepName:    event.StepName,
+		EventType:   EventCallbackIgnored,
+		Status:      string(event.Status),
+		Metadata:    md,
+	}
+}
+
+// IsStaleAttempt reports whether event answers an earlier dispatch than the
+// step's current attempt. Events that do not carry an attempt are never stale.
+func IsStaleAttempt(event api.StepStatusEvent, attempt int) bool {
+	return event.Attempt != nil && *event.Attempt < attempt
+}
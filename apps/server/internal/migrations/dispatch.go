@@ -0,0 +1,125 @@
+package migrations
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tilsley/loom/pkg/api"
+)
+
+const (
+	// DispatchRateWindow is the window a DispatchLimit's perMinute counts over.
+	DispatchRateWindow = time.Minute
+
+	// DispatchSlotLease is how long a dispatch slot counts as in flight when
+	// its run stops renewing it without releasing it, e.g. because the run was
+	// terminated. A run renews its slots every DispatchSlotRenewInterval for
+	// as long as their steps are outstanding.
+	DispatchSlotLease = 5 * time.Minute
+
+	// DispatchSlotRenewInterval is how often a run renews the dispatch slots
+	// it holds; well within DispatchSlotLease so a slow renewal does not let
+	// the slot lapse.
+	DispatchSlotRenewInterval = 2 * time.Minute
+
+	// dispatchCapacityPoll is how long a dispatch held back by maxInFlight
+	// waits before asking again; when a slot frees up is not known.
+	dispatchCapacityPoll = 10 * time.Second
+)
+
+// DispatchUsage is a migrator app's current use of its dispatch limits.
+type DispatchUsage struct {
+	// InFlight counts slots that are neither released nor past their lease.
+	InFlight int
+	// Recent counts dispatches within the last DispatchRateWindow, and
+	// OldestRecent is the earliest of them.
+	Recent       int
+	OldestRecent time.Time
+}
+
+// DispatchSlotGrant is the answer to a request for a dispatch slot.
+type DispatchSlotGrant struct {
+	// Acquired reports whether the step may be dispatched now.
+	Acquired bool `json:"acquired"`
+	// Held reports whether a slot was recorded that must be released once
+	// the migrator reports back. It is false for apps without limits.
+	Held bool `json:"held,omitempty"`
+	// RetryAfter is how long to wait before asking again when not acquired,
+	// and Reason says which limit was reached.
+	RetryAfter time.Duration `json:"retryAfter,omitempty"`
+	Reason     string        `json:"reason,omitempty"`
+}
+
+// CheckDispatchLimit decides whether one more dispatch fits within limit given
+// the app's usage at now.
+func CheckDispatchLimit(limit api.DispatchLimit, usage DispatchUsage, now time.Time) DispatchSlotGrant {
+	if limit.MaxInFlight != nil && usage.InFlight >= *limit.MaxInFlight {
+		return DispatchSlotGrant{
+			RetryAfter: dispatchCapacityPoll,
+			Reason:     fmt.Sprintf("%d dispatches in flight (max %d)", usage.InFlight, *limit.MaxInFlight),
+		}
+	}
+	if limit.PerMinute != nil && usage.Recent >= *limit.PerMinute {
+		// The oldest dispatch in the window is the next to leave it.
+		return DispatchSlotGrant{
+			RetryAfter: max(usage.OldestRecent.Add(DispatchRateWindow).Sub(now), time.Second),
+			Reason:     fmt.Sprintf("%d dispatches in the last minute (max %d)", usage.Recent, *limit.PerMinute),
+		}
+	}
+	return DispatchSlotGrant{Acquired: true, Held: true}
+}
+
+// DispatchSlotHolder identifies the dispatch of one attempt of a step in a
+// run; it is the holder of that dispatch's slot.
+func DispatchSlotHolder(runID, stepName, candidateID string, attempt int) string {
+	return runID + "/" + stepName + "/" + candidateID + "/" + strconv.Itoa(attempt)
+}
+
+// validateDispatchLimits checks the dispatch limits of an announcement.
+func validateDispatchLimits(limits []api.DispatchLimit) error {
+	seen := make(map[string]bool, len(limits))
+	for _, l := range limits {
+		switch {
+		case l.MigratorApp == "":
+			return InvalidDispatchLimitError{Reason: "migratorApp is required"}
+		case seen[l.MigratorApp]:
+			return InvalidDispatchLimitError{MigratorApp: l.MigratorApp, Reason: "declared more than once"}
+		case l.MaxInFlight != nil && *l.MaxInFlight < 1:
+			return InvalidDispatchLimitError{MigratorApp: l.MigratorApp, Reason: "maxInFlight must be at least 1"}
+		case l.PerMinute != nil && *l.PerMinute < 1:
+			return InvalidDispatchLimitError{MigratorApp: l.MigratorApp, Reason: "perMinute must be at least 1"}
+		}
+		seen[l.MigratorApp] = true
+	}
+	return nil
+}
+
+// Codes a migrator may return in a DispatchError body.
+const (
+	// DispatchErrorTransient asks the server to retry the dispatch.
+	DispatchErrorTransient = "transient"
+	// DispatchErrorInvalidRequest and DispatchErrorUnsupportedStep fail the
+	// step without retrying it.
+	DispatchErrorInvalidRequest  = "invalid_request"
+	DispatchErrorUnsupportedStep = "unsupported_step"
+)
+
+// RetryableDispatch reports whether a dispatch the migrator answered with
+// statusCode and the DispatchError code is worth retrying. A known code
+// decides; otherwise 408, 425, 429 and 5xx are retryable and every other
+// status is permanent.
+func RetryableDispatch(statusCode int, code string) bool {
+	switch code {
+	case DispatchErrorTransient:
+		return true
+	case DispatchErrorInvalidRequest, DispatchErrorUnsupportedStep:
+		return false
+	}
+	switch statusCode {
+	case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests:
+		return true
+	}
+	return statusCode >= http.StatusInternalServerError
+}
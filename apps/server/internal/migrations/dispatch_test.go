@@ -0,0 +1,50 @@
+package migrations_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+func TestCheckDispatchLimit(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	two, ten := 2, 10
+	limit := api.DispatchLimit{MigratorApp: "app", MaxInFlight: &two, PerMinute: &ten}
+
+	grant := migrations.CheckDispatchLimit(limit, migrations.DispatchUsage{InFlight: 1, Recent: 9}, now)
+	assert.Equal(t, migrations.DispatchSlotGrant{Acquired: true, Held: true}, grant)
+
+	grant = migrations.CheckDispatchLimit(limit, migrations.DispatchUsage{InFlight: 2}, now)
+	assert.False(t, grant.Acquired)
+	assert.Equal(t, "2 dispatches in flight (max 2)", grant.Reason)
+	assert.Positive(t, grant.RetryAfter)
+
+	grant = migrations.CheckDispatchLimit(limit, migrations.DispatchUsage{
+		InFlight: 1, Recent: 10, OldestRecent: now.Add(-45 * time.Second),
+	}, now)
+	assert.False(t, grant.Acquired)
+	assert.Equal(t, "10 dispatches in the last minute (max 10)", grant.Reason)
+	assert.Equal(t, 15*time.Second, grant.RetryAfter, "until the oldest dispatch leaves the window")
+
+	unlimited := api.DispatchLimit{MigratorApp: "app"}
+	grant = migrations.CheckDispatchLimit(unlimited, migrations.DispatchUsage{InFlight: 100, Recent: 100}, now)
+	assert.True(t, grant.Acquired)
+}
+
+func TestDispatchSlotHolder(t *testing.T) {
+	assert.Equal(t, "mig__repo-a/open-pr/repo-a/2", migrations.DispatchSlotHolder("mig__repo-a", "open-pr", "repo-a", 2))
+}
+
+func TestRetryableDispatch(t *testing.T) {
+	assert.True(t, migrations.RetryableDispatch(503, ""))
+	assert.True(t, migrations.RetryableDispatch(429, ""))
+	assert.False(t, migrations.RetryableDispatch(400, ""))
+	assert.True(t, migrations.RetryableDispatch(400, migrations.DispatchErrorTransient))
+	assert.False(t, migrations.RetryableDispatch(500, migrations.DispatchErrorInvalidRequest))
+	assert.False(t, migrations.RetryableDispatch(500, migrations.DispatchErrorUnsupportedStep))
+	assert.True(t, migrations.RetryableDispatch(502, "unknown-code"), "unknown codes fall back to the status")
+}
@@ -44,6 +44,17 @@ func (e CandidateNotRunningError) Error() string {
 	return fmt.Sprintf("candidate %q is not running", e.ID)
 }
 
+// CandidateNotCompletedError is returned when a reset is requested for a candidate that is not completed.
+type CandidateNotCompletedError struct {
+	ID     string
+	Status string
+}
+
+// Error implements the error interface.
+func (e CandidateNotCompletedError) Error() string {
+	return fmt.Sprintf("candidate %q is %q, only a completed candidate can be reset", e.ID, e.Status)
+}
+
 // RunNotFoundError is returned by the ExecutionEngine when the run instance
 // does not exist — typically after the engine is restarted in development.
 type RunNotFoundError struct {
@@ -65,3 +76,285 @@ type InvalidInputKeyError struct {
 func (e InvalidInputKeyError) Error() string {
 	return fmt.Sprintf("input key %q is not in requiredInputs", e.Key)
 }
+
+// NoCandidatesSelectedError is returned when a bulk start selector matches no
+// candidate that can be started.
+type NoCandidatesSelectedError struct {
+	MigrationID string
+}
+
+// Error implements the error interface.
+func (e NoCandidatesSelectedError) Error() string {
+	return fmt.Sprintf("no startable candidates in migration %q match the selector", e.MigrationID)
+}
+
+// StepNotSkippableError is returned when a skip is requested for a step that
+// is not currently awaiting a callback or a retry.
+type StepNotSkippableError struct {
+	StepName string
+	Status   string
+}
+
+// Error implements the error interface.
+func (e StepNotSkippableError) Error() string {
+	if e.Status == "" {
+		return fmt.Sprintf("step %q has not been reached and cannot be skipped", e.StepName)
+	}
+	return fmt.Sprintf("step %q has status %q and cannot be skipped", e.StepName, e.Status)
+}
+
+// InvalidStepGraphError is returned when a step list cannot be run as a
+// dependency graph: duplicate step names, a dependsOn naming an unknown step,
+// or a dependency cycle.
+type InvalidStepGraphError struct {
+	Reason string
+}
+
+// Error implements the error interface.
+func (e InvalidStepGraphError) Error() string {
+	return "invalid step graph: " + e.Reason
+}
+
+// InvalidStepConditionError is returned when a step's when expression does not
+// parse or reads from a step that is not guaranteed to run before it.
+type InvalidStepConditionError struct {
+	StepName string
+	Reason   string
+}
+
+// Error implements the error interface.
+func (e InvalidStepConditionError) Error() string {
+	return fmt.Sprintf("invalid when expression on step %q: %s", e.StepName, e.Reason)
+}
+
+// RollbackNotAllowedError is returned when a rollback is requested for a
+// candidate whose run is still in progress or left nothing to compensate.
+type RollbackNotAllowedError struct {
+	CandidateID string
+	Reason      string
+}
+
+// Error implements the error interface.
+func (e RollbackNotAllowedError) Error() string {
+	return fmt.Sprintf("cannot roll back candidate %q: %s", e.CandidateID, e.Reason)
+}
+
+// MigrationVersionNotFoundError is returned when a migration has no version with the requested number.
+type MigrationVersionNotFoundError struct {
+	MigrationID string
+	Version     int
+}
+
+// Error implements the error interface.
+func (e MigrationVersionNotFoundError) Error() string {
+	return fmt.Sprintf("version %d of migration %q not found", e.Version, e.MigrationID)
+}
+
+// DuplicateEventError is returned when a step callback carries an event ID the
+// run has already received. The event is discarded; callers should treat it as
+// accepted so the migrator stops resending it.
+type DuplicateEventError struct {
+	RunID   string
+	EventID string
+}
+
+// Error implements the error interface.
+func (e DuplicateEventError) Error() string {
+	return fmt.Sprintf("event %q already received for run %q", e.EventID, e.RunID)
+}
+
+// ProgressFeedUnavailableError is returned when progress is requested but no
+// progress feed is configured.
+type ProgressFeedUnavailableError struct{}
+
+// Error implements the error interface.
+func (ProgressFeedUnavailableError) Error() string {
+	return "progress streaming is not configured"
+}
+
+// InvalidWebhookError is returned when a webhook subscription is malformed.
+type InvalidWebhookError struct {
+	Reason string
+}
+
+// Error implements the error interface.
+func (e InvalidWebhookError) Error() string {
+	return "invalid webhook: " + e.Reason
+}
+
+// WebhookNotFoundError is returned when the requested webhook does not exist in the migration.
+type WebhookNotFoundError struct {
+	MigrationID string
+	ID          int64
+}
+
+// Error implements the error interface.
+func (e WebhookNotFoundError) Error() string {
+	return fmt.Sprintf("webhook %d not found in migration %q", e.ID, e.MigrationID)
+}
+
+// WebhooksUnavailableError is returned when webhooks are used but no webhook
+// store is configured.
+type WebhooksUnavailableError struct{}
+
+// Error implements the error interface.
+func (WebhooksUnavailableError) Error() string {
+	return "webhooks are not configured"
+}
+
+// InvalidFreezeWindowError is returned when a freeze window is malformed.
+type InvalidFreezeWindowError struct {
+	Reason string
+}
+
+// Error implements the error interface.
+func (e InvalidFreezeWindowError) Error() string {
+	return "invalid freeze window: " + e.Reason
+}
+
+// FreezeWindowNotFoundError is returned when the requested freeze window does not exist.
+type FreezeWindowNotFoundError struct {
+	ID int64
+}
+
+// Error implements the error interface.
+func (e FreezeWindowNotFoundError) Error() string {
+	return fmt.Sprintf("freeze window %d not found", e.ID)
+}
+
+// FreezeCalendarUnavailableError is returned when freeze windows are used but
+// no freeze calendar is configured.
+type FreezeCalendarUnavailableError struct{}
+
+// Error implements the error interface.
+func (FreezeCalendarUnavailableError) Error() string {
+	return "freeze windows are not configured"
+}
+
+// InvalidScheduleError is returned when a scheduled start is malformed.
+type InvalidScheduleError struct {
+	Reason string
+}
+
+// Error implements the error interface.
+func (e InvalidScheduleError) Error() string {
+	return "invalid scheduled start: " + e.Reason
+}
+
+// ScheduledStartNotFoundError is returned when the requested scheduled start
+// does not exist in the migration.
+type ScheduledStartNotFoundError struct {
+	MigrationID string
+	ID          string
+}
+
+// Error implements the error interface.
+func (e ScheduledStartNotFoundError) Error() string {
+	return fmt.Sprintf("scheduled start %q not found in migration %q", e.ID, e.MigrationID)
+}
+
+// ScheduledStartNotPendingError is returned when a scheduled start that has
+// already fired or been cancelled is cancelled.
+type ScheduledStartNotPendingError struct {
+	ID     string
+	Status string
+}
+
+// Error implements the error interface.
+func (e ScheduledStartNotPendingError) Error() string {
+	return fmt.Sprintf("scheduled start %q is already %s", e.ID, e.Status)
+}
+
+// SchedulesUnavailableError is returned when scheduled starts are used but no
+// schedule store is configured.
+type SchedulesUnavailableError struct{}
+
+// Error implements the error interface.
+func (SchedulesUnavailableError) Error() string {
+	return "scheduled starts are not configured"
+}
+
+// InvalidMigrationIDError is returned when an announcement names a migration
+// with an ID that cannot be used.
+type InvalidMigrationIDError struct {
+	ID     string
+	Reason string
+}
+
+// Error implements the error interface.
+func (e InvalidMigrationIDError) Error() string {
+	return fmt.Sprintf("invalid migration ID %q: %s", e.ID, e.Reason)
+}
+
+// InvalidDispatchLimitError is returned when an announcement declares dispatch
+// limits without a migrator app, with a limit below 1, or twice for one app.
+type InvalidDispatchLimitError struct {
+	MigratorApp string
+	Reason      string
+}
+
+// Error implements the error interface.
+func (e InvalidDispatchLimitError) Error() string {
+	return fmt.Sprintf("invalid dispatch limit for %q: %s", e.MigratorApp, e.Reason)
+}
+
+// DispatchLimitConflictError is returned when an announcement declares dispatch
+// limits for an app whose limits another migration declared differently.
+type DispatchLimitConflictError struct {
+	MigratorApp string
+	DeclaredBy  string
+}
+
+// Error implements the error interface.
+func (e DispatchLimitConflictError) Error() string {
+	return fmt.Sprintf("dispatch limits for %q are declared differently by migration %q", e.MigratorApp, e.DeclaredBy)
+}
+
+// InvalidMigratorRegistrationError is returned when a migrator registers
+// without an app-id or with a URL that is not an absolute http, https, grpc or
+// grpcs URL.
+type InvalidMigratorRegistrationError struct {
+	MigratorApp string
+	Reason      string
+}
+
+// Error implements the error interface.
+func (e InvalidMigratorRegistrationError) Error() string {
+	return fmt.Sprintf("invalid migrator registration for %q: %s", e.MigratorApp, e.Reason)
+}
+
+// MigratorRegistryUnavailableError is returned when migrators register but no
+// migrator registry is configured.
+type MigratorRegistryUnavailableError struct{}
+
+// Error implements the error interface.
+func (MigratorRegistryUnavailableError) Error() string {
+	return "the migrator registry is not configured"
+}
+
+// DispatchError is returned by a MigratorNotifier when a migrator could not be
+// given a step. Permanent errors are not retried; the step fails instead.
+type DispatchError struct {
+	MigratorApp string
+	// StatusCode is the migrator's HTTP status, or 0 when it did not answer.
+	StatusCode int
+	// Code is the DispatchError code from the migrator's answer, if any.
+	Code      string
+	Message   string
+	Permanent bool
+}
+
+// Error implements the error interface.
+func (e DispatchError) Error() string {
+	msg := fmt.Sprintf("dispatch to migrator %q failed", e.MigratorApp)
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(": HTTP %d", e.StatusCode)
+	}
+	if e.Code != "" {
+		msg += " (" + e.Code + ")"
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
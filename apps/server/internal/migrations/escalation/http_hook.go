@@ -0,0 +1,52 @@
+package escalation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+)
+
+// Compile-time check: *HTTPHookEscalator implements migrations.StepEscalator.
+var _ migrations.StepEscalator = (*HTTPHookEscalator)(nil)
+
+// HTTPHookEscalator implements StepEscalator by posting the StepEscalation as
+// JSON to a single configured webhook URL (e.g. a chat or paging integration).
+type HTTPHookEscalator struct {
+	client *http.Client
+	url    string
+}
+
+// NewHTTPHookEscalator creates a new HTTPHookEscalator that posts to url.
+func NewHTTPHookEscalator(client *http.Client, url string) *HTTPHookEscalator {
+	return &HTTPHookEscalator{client: client, url: url}
+}
+
+// Escalate posts e to the webhook. Any non-2xx response is returned as an error
+// so the calling activity can retry.
+func (h *HTTPHookEscalator) Escalate(ctx context.Context, e migrations.StepEscalation) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal escalation: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create escalation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post escalation for step %q: %w", e.StepName, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("escalation hook returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package escalation_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/migrations/escalation"
+)
+
+var baseEscalation = migrations.StepEscalation{
+	MigrationID:    "app-chart-migration",
+	CandidateID:    "billing-api",
+	StepName:       "open-pr",
+	RunID:          "app-chart-migration__billing-api",
+	TimeoutSeconds: 86400,
+	Metadata:       map[string]string{"prUrl": "https://github.com/org/repo/pull/1"},
+}
+
+func TestEscalate_PostsEscalationJSON(t *testing.T) {
+	var received migrations.StepEscalation
+	var contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	h := escalation.NewHTTPHookEscalator(http.DefaultClient, srv.URL)
+
+	require.NoError(t, h.Escalate(context.Background(), baseEscalation))
+	assert.Equal(t, "application/json", contentType)
+	assert.Equal(t, baseEscalation, received)
+}
+
+func TestEscalate_Non2xx_ReturnsError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	h := escalation.NewHTTPHookEscalator(http.DefaultClient, srv.URL)
+
+	err := h.Escalate(context.Background(), baseEscalation)
+	require.ErrorContains(t, err, "502")
+}
+
+func TestEscalate_Unreachable_ReturnsError(t *testing.T) {
+	h := escalation.NewHTTPHookEscalator(http.DefaultClient, "http://127.0.0.1:1")
+
+	require.Error(t, h.Escalate(context.Background(), baseEscalation))
+}
@@ -2,12 +2,16 @@ package execution
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+	"go.temporal.io/sdk/temporal"
 
 	"github.com/tilsley/loom/apps/server/internal/migrations"
 	"github.com/tilsley/loom/pkg/api"
@@ -22,19 +26,99 @@ type UpdateCandidateStatusInput struct {
 	Status      string `json:"status"`
 }
 
+// FinishRunInput is the input for the FinishRun activity.
+type FinishRunInput struct {
+	RunID  string               `json:"runId"`
+	Status api.RunAttemptStatus `json:"status"`
+	Steps  []api.StepState      `json:"steps,omitempty"`
+}
+
+// PrepareBulkRunInput is the input for the PrepareBulkRun activity.
+// StartedAt becomes the start time of the run attempt it records, and tells a
+// retried call the attempt it has already recorded.
+type PrepareBulkRunInput struct {
+	MigrationID string            `json:"migrationId"`
+	CandidateID string            `json:"candidateId"`
+	Inputs      map[string]string `json:"inputs,omitempty"`
+	StartedAt   time.Time         `json:"startedAt"`
+}
+
+// PrepareBulkRunResult is the output of the PrepareBulkRun activity.
+// SkipReason is set instead of Manifest and RunID when the candidate must not be started.
+type PrepareBulkRunResult struct {
+	Manifest   api.MigrationManifest `json:"manifest"`
+	RunID      string                `json:"runId,omitempty"`
+	SkipReason string                `json:"skipReason,omitempty"`
+}
+
+// CheckFreezeWindowsInput is the input for the CheckFreezeWindows activity.
+type CheckFreezeWindowsInput struct {
+	MigrationID string            `json:"migrationId"`
+	StepConfig  map[string]string `json:"stepConfig,omitempty"`
+	At          time.Time         `json:"at"`
+}
+
+// CheckFreezeWindowsResult is the output of the CheckFreezeWindows activity.
+// Window is nil when the step may be dispatched.
+type CheckFreezeWindowsResult struct {
+	Window *migrations.FreezeWindow `json:"window,omitempty"`
+}
+
+// DispatchSlotInput is the input for the AcquireDispatchSlot,
+// RenewDispatchSlot and ReleaseDispatchSlot activities.
+type DispatchSlotInput struct {
+	MigratorApp string `json:"migratorApp"`
+	Holder      string `json:"holder"`
+}
+
+// ScheduledStarter fires scheduled starts. *migrations.Service implements it;
+// starting through the service applies the same guards as an operator's start.
+type ScheduledStarter interface {
+	FireScheduledStart(ctx context.Context, id string) error
+}
+
 // Activities groups Temporal activity methods. The struct holds dependencies
 // injected at startup (idiomatic Temporal pattern).
 type Activities struct {
 	notifier   migrations.MigratorNotifier
 	store      migrations.MigrationStore
 	eventStore migrations.EventStore
+	escalator  migrations.StepEscalator
+	freezes    migrations.FreezeCalendar
+	limiter    migrations.DispatchLimiter
+	migrators  migrations.MigratorRegistry
+	starter    ScheduledStarter
 	log        *slog.Logger
 }
 
 // NewActivities creates a new Activities instance with the given dependencies.
-// eventStore may be nil — event recording is best-effort.
-func NewActivities(notifier migrations.MigratorNotifier, store migrations.MigrationStore, eventStore migrations.EventStore, log *slog.Logger) *Activities {
-	return &Activities{notifier: notifier, store: store, eventStore: eventStore, log: log}
+// eventStore may be nil — event recording is best-effort. escalator may be nil —
+// timed-out steps are then only recorded, not escalated. freezes may be nil —
+// steps are then never held back. limiter may be nil — dispatches are then
+// never queued. migrators may be nil — steps then always go to the manifest's
+// migratorUrl. starter may be nil when no start is ever scheduled.
+func NewActivities(
+	notifier migrations.MigratorNotifier,
+	store migrations.MigrationStore,
+	eventStore migrations.EventStore,
+	escalator migrations.StepEscalator,
+	freezes migrations.FreezeCalendar,
+	limiter migrations.DispatchLimiter,
+	migrators migrations.MigratorRegistry,
+	starter ScheduledStarter,
+	log *slog.Logger,
+) *Activities {
+	return &Activities{
+		notifier:   notifier,
+		store:      store,
+		eventStore: eventStore,
+		escalator:  escalator,
+		freezes:    freezes,
+		limiter:    limiter,
+		migrators:  migrators,
+		starter:    starter,
+		log:        log,
+	}
 }
 
 // RecordEvent persists a lifecycle event into the event store.
@@ -49,10 +133,15 @@ func (a *Activities) RecordEvent(ctx context.Context, event migrations.StepEvent
 	return nil
 }
 
+// PermanentDispatchErrorType is the application error type DispatchStep fails
+// with when the migrator refused the step for good; it is never retried.
+const PermanentDispatchErrorType = "PermanentDispatchError"
+
 // DispatchStep dispatches a step request to the migrator via MigratorNotifier.
+// The request goes to the URL req.MigratorApp registered, when it has, rather
+// than the manifest's migratorUrl; the lookup happens on every attempt, so a
+// migrator that registers a new URL gets the retries.
 func (a *Activities) DispatchStep(ctx context.Context, req api.DispatchStepRequest) error {
-	a.log.Info("DispatchStep activity called", "step", req.StepName, "candidate", req.Candidate.Id, "migratorUrl", req.MigratorUrl)
-
 	ctx, span := otel.Tracer(instrName).Start(ctx, "DispatchStep",
 		trace.WithAttributes(
 			attribute.String("step.name", req.StepName),
@@ -61,9 +150,26 @@ func (a *Activities) DispatchStep(ctx context.Context, req api.DispatchStepReque
 	)
 	defer span.End()
 
+	if a.migrators != nil {
+		registered, err := a.migrators.GetMigrator(ctx, req.MigratorApp)
+		if err != nil {
+			span.RecordError(err)
+			return fmt.Errorf("get migrator %q: %w", req.MigratorApp, err)
+		}
+		if registered != nil {
+			req.MigratorUrl = registered.Url
+		}
+	}
+	a.log.Info("DispatchStep activity called", "step", req.StepName, "candidate", req.Candidate.Id, "migratorUrl", req.MigratorUrl)
+
 	if err := a.notifier.Dispatch(ctx, req); err != nil {
 		span.RecordError(err)
-		return fmt.Errorf("dispatch step %q for %q: %w", req.StepName, req.Candidate.Id, err)
+		err = fmt.Errorf("dispatch step %q for %q: %w", req.StepName, req.Candidate.Id, err)
+		var dispatchErr migrations.DispatchError
+		if errors.As(err, &dispatchErr) && dispatchErr.Permanent {
+			return temporal.NewNonRetryableApplicationError(err.Error(), PermanentDispatchErrorType, err)
+		}
+		return err
 	}
 	return nil
 }
@@ -89,3 +195,166 @@ func (a *Activities) UpdateCandidateStatus(ctx context.Context, input UpdateCand
 	)
 	return nil
 }
+
+// FinishRun records the outcome and final step results of a run attempt.
+func (a *Activities) FinishRun(ctx context.Context, input FinishRunInput) error {
+	if err := a.store.FinishRun(ctx, input.RunID, input.Status, input.Steps); err != nil {
+		return fmt.Errorf("finish run %q: %w", input.RunID, err)
+	}
+	return nil
+}
+
+// PrepareBulkRun builds the MigrationManifest for the next candidate in a bulk
+// start, records the candidate's next run attempt and marks the candidate
+// running. The candidate is re-read from the store so that one started
+// elsewhere since the bulk start was queued is skipped. A retry finds the
+// attempt an earlier try recorded by its start time and prepares that attempt
+// again instead of recording another or skipping the candidate.
+func (a *Activities) PrepareBulkRun(ctx context.Context, input PrepareBulkRunInput) (PrepareBulkRunResult, error) {
+	ctx, span := otel.Tracer(instrName).Start(ctx, "PrepareBulkRun",
+		trace.WithAttributes(
+			attribute.String("migration.id", input.MigrationID),
+			attribute.String("candidate.id", input.CandidateID),
+		),
+	)
+	defer span.End()
+
+	m, err := a.store.Get(ctx, input.MigrationID)
+	if err != nil {
+		span.RecordError(err)
+		return PrepareBulkRunResult{}, fmt.Errorf("get migration %q: %w", input.MigrationID, err)
+	}
+	if m == nil {
+		return PrepareBulkRunResult{SkipReason: "migration not found"}, nil
+	}
+
+	idx := slices.IndexFunc(m.Candidates, func(c api.Candidate) bool { return c.Id == input.CandidateID })
+	if idx < 0 {
+		return PrepareBulkRunResult{SkipReason: "candidate not found"}, nil
+	}
+	candidate := m.Candidates[idx]
+
+	previous, err := a.store.ListRuns(ctx, input.MigrationID, input.CandidateID)
+	if err != nil {
+		span.RecordError(err)
+		return PrepareBulkRunResult{}, fmt.Errorf("list runs: %w", err)
+	}
+	recorded := len(previous) > 0 && !input.StartedAt.IsZero() &&
+		previous[0].Type == api.RunAttemptTypeMigration &&
+		previous[0].Status == api.RunAttemptStatusRunning &&
+		previous[0].StartedAt.Equal(input.StartedAt)
+	if !recorded && (candidate.Status == api.CandidateStatusRunning || candidate.Status == api.CandidateStatusCompleted) {
+		return PrepareBulkRunResult{SkipReason: "candidate is already " + string(candidate.Status)}, nil
+	}
+
+	manifest := migrations.BuildManifest(*m, candidate, input.Inputs)
+	var attempt api.RunAttempt
+	if recorded {
+		attempt = previous[0]
+	} else {
+		startedAt := input.StartedAt
+		if startedAt.IsZero() {
+			startedAt = time.Now().UTC()
+		}
+		attempt = migrations.NextRunAttempt(previous, input.MigrationID, input.CandidateID,
+			api.RunAttemptTypeMigration, manifest.Version, startedAt)
+		if err := a.store.CreateRun(ctx, attempt); err != nil {
+			span.RecordError(err)
+			return PrepareBulkRunResult{}, fmt.Errorf("record run attempt: %w", err)
+		}
+	}
+	if err := a.store.SetCandidateStatus(ctx, input.MigrationID, input.CandidateID, api.CandidateStatusRunning); err != nil {
+		span.RecordError(err)
+		return PrepareBulkRunResult{}, fmt.Errorf("set candidate status: %w", err)
+	}
+	return PrepareBulkRunResult{Manifest: manifest, RunID: attempt.RunId}, nil
+}
+
+// EscalateStep fires the escalation hook for a step that passed its deadline.
+// A no-op when no escalator is configured; hook errors are returned so Temporal retries.
+func (a *Activities) EscalateStep(ctx context.Context, e migrations.StepEscalation) error {
+	if a.escalator == nil {
+		return nil
+	}
+	if err := a.escalator.Escalate(ctx, e); err != nil {
+		return fmt.Errorf("escalate step %q for %q: %w", e.StepName, e.CandidateID, err)
+	}
+	a.log.Info("escalated timed-out step", "migrationId", e.MigrationID, "candidateId", e.CandidateID, "step", e.StepName)
+	return nil
+}
+
+// CheckFreezeWindows returns the freeze window that holds back a step of the
+// migration with the given config at input.At, if any. Calendar errors are
+// returned so Temporal retries: a step is never dispatched unchecked.
+func (a *Activities) CheckFreezeWindows(
+	ctx context.Context,
+	input CheckFreezeWindowsInput,
+) (CheckFreezeWindowsResult, error) {
+	if a.freezes == nil {
+		return CheckFreezeWindowsResult{}, nil
+	}
+	windows, err := a.freezes.ListFreezeWindows(ctx, migrations.FreezeWindowFilter{
+		MigrationID: input.MigrationID,
+		EndsAfter:   input.At,
+	})
+	if err != nil {
+		return CheckFreezeWindowsResult{}, fmt.Errorf("list freeze windows: %w", err)
+	}
+	return CheckFreezeWindowsResult{
+		Window: migrations.BlockingFreezeWindow(windows, input.MigrationID, input.StepConfig, input.At),
+	}, nil
+}
+
+// AcquireDispatchSlot asks for a slot to dispatch a step to input.MigratorApp
+// within the app's dispatch limits. Limiter errors are returned so Temporal
+// retries: a step is never dispatched past its limits unchecked.
+func (a *Activities) AcquireDispatchSlot(
+	ctx context.Context,
+	input DispatchSlotInput,
+) (migrations.DispatchSlotGrant, error) {
+	if a.limiter == nil {
+		return migrations.DispatchSlotGrant{Acquired: true}, nil
+	}
+	grant, err := a.limiter.AcquireDispatchSlot(ctx, input.MigratorApp, input.Holder)
+	if err != nil {
+		return migrations.DispatchSlotGrant{}, fmt.Errorf("acquire dispatch slot for %q: %w", input.MigratorApp, err)
+	}
+	return grant, nil
+}
+
+// RenewDispatchSlot keeps a slot taken by AcquireDispatchSlot from lapsing
+// while its step is outstanding.
+func (a *Activities) RenewDispatchSlot(ctx context.Context, input DispatchSlotInput) error {
+	if a.limiter == nil {
+		return nil
+	}
+	if err := a.limiter.RenewDispatchSlot(ctx, input.MigratorApp, input.Holder); err != nil {
+		return fmt.Errorf("renew dispatch slot for %q: %w", input.MigratorApp, err)
+	}
+	return nil
+}
+
+// ReleaseDispatchSlot frees a slot taken by AcquireDispatchSlot.
+func (a *Activities) ReleaseDispatchSlot(ctx context.Context, input DispatchSlotInput) error {
+	if a.limiter == nil {
+		return nil
+	}
+	if err := a.limiter.ReleaseDispatchSlot(ctx, input.MigratorApp, input.Holder); err != nil {
+		return fmt.Errorf("release dispatch slot for %q: %w", input.MigratorApp, err)
+	}
+	return nil
+}
+
+// FireScheduledStart fires a scheduled start once its time has come. Store
+// errors are returned so Temporal retries; a start that is refused is recorded
+// on the scheduled start instead.
+func (a *Activities) FireScheduledStart(ctx context.Context, id string) error {
+	if a.starter == nil {
+		return fmt.Errorf("fire scheduled start %q: no scheduled starter configured", id)
+	}
+	if err := a.starter.FireScheduledStart(ctx, id); err != nil {
+		return fmt.Errorf("fire scheduled start %q: %w", id, err)
+	}
+	a.log.Info("fired scheduled start", "id", id)
+	return nil
+}
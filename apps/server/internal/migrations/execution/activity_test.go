@@ -0,0 +1,166 @@
+package execution_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/migrations/execution"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// recordingNotifier records the requests it is asked to dispatch and answers
+// each with err.
+type recordingNotifier struct {
+	reqs []api.DispatchStepRequest
+	err  error
+}
+
+func (n *recordingNotifier) Dispatch(_ context.Context, req api.DispatchStepRequest) error {
+	n.reqs = append(n.reqs, req)
+	return n.err
+}
+
+// staticRegistry serves fixed migrator registrations.
+type staticRegistry map[string]api.RegisteredMigrator
+
+func (r staticRegistry) RegisterMigrator(context.Context, api.RegisteredMigrator) error { return nil }
+
+func (r staticRegistry) GetMigrator(_ context.Context, app string) (*api.RegisteredMigrator, error) {
+	m, ok := r[app]
+	if !ok {
+		return nil, nil //nolint:nilnil
+	}
+	return &m, nil
+}
+
+func (r staticRegistry) ListMigrators(context.Context) ([]api.RegisteredMigrator, error) {
+	return nil, nil
+}
+
+func TestDispatchStep_ResolvesRegisteredMigratorURL(t *testing.T) {
+	notifier := &recordingNotifier{}
+	registry := staticRegistry{"secrets-migrator": {MigratorApp: "secrets-migrator", Url: "http://secrets:3002"}}
+	acts := execution.NewActivities(notifier, nil, nil, nil, nil, nil, registry, nil, slog.Default())
+
+	for _, app := range []string{"secrets-migrator", "app-chart-migrator"} {
+		require.NoError(t, acts.DispatchStep(context.Background(), api.DispatchStepRequest{
+			StepName:    "step",
+			MigratorApp: app,
+			MigratorUrl: "http://charts:3001",
+		}))
+	}
+
+	require.Len(t, notifier.reqs, 2)
+	assert.Equal(t, "http://secrets:3002", notifier.reqs[0].MigratorUrl)
+	assert.Equal(t, "http://charts:3001", notifier.reqs[1].MigratorUrl, "unregistered apps keep the manifest URL")
+}
+
+func TestDispatchStep_PermanentErrorIsNotRetried(t *testing.T) {
+	req := api.DispatchStepRequest{StepName: "step", MigratorApp: "app-chart-migrator"}
+
+	notifier := &recordingNotifier{err: migrations.DispatchError{
+		MigratorApp: "app-chart-migrator",
+		StatusCode:  400,
+		Code:        "invalid_request",
+		Message:     "no chart",
+		Permanent:   true,
+	}}
+	err := execution.NewActivities(notifier, nil, nil, nil, nil, nil, nil, nil, slog.Default()).
+		DispatchStep(context.Background(), req)
+	var appErr *temporal.ApplicationError
+	require.ErrorAs(t, err, &appErr)
+	assert.True(t, appErr.NonRetryable())
+	assert.Equal(t, execution.PermanentDispatchErrorType, appErr.Type())
+	assert.Contains(t, appErr.Message(), "no chart")
+
+	notifier.err = migrations.DispatchError{MigratorApp: "app-chart-migrator", StatusCode: 503}
+	err = execution.NewActivities(notifier, nil, nil, nil, nil, nil, nil, nil, slog.Default()).
+		DispatchStep(context.Background(), req)
+	require.Error(t, err)
+	assert.False(t, errors.As(err, &appErr), "transient errors are left to the retry policy")
+}
+
+// bulkStore keeps one migration and the run attempts recorded for it. The rest
+// of MigrationStore is never called by PrepareBulkRun.
+type bulkStore struct {
+	migrations.MigrationStore
+
+	migration api.Migration
+	runs      []api.RunAttempt // newest first, as ListRuns returns them
+	statusErr error            // returned by the next SetCandidateStatus
+}
+
+func (s *bulkStore) Get(context.Context, string) (*api.Migration, error) { return &s.migration, nil }
+
+func (s *bulkStore) ListRuns(context.Context, string, string) ([]api.RunAttempt, error) {
+	return s.runs, nil
+}
+
+func (s *bulkStore) CreateRun(_ context.Context, r api.RunAttempt) error {
+	s.runs = append([]api.RunAttempt{r}, s.runs...)
+	return nil
+}
+
+func (s *bulkStore) SetCandidateStatus(_ context.Context, _, _ string, status api.CandidateStatus) error {
+	if err := s.statusErr; err != nil {
+		s.statusErr = nil
+		return err
+	}
+	s.migration.Candidates[0].Status = status
+	return nil
+}
+
+func TestPrepareBulkRun_RetryPreparesTheSameAttempt(t *testing.T) {
+	input := execution.PrepareBulkRunInput{
+		MigrationID: "mig-abc",
+		CandidateID: "billing-api",
+		StartedAt:   time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+	}
+
+	for name, statusErr := range map[string]error{
+		"first try failed after recording the attempt": errors.New("connection reset"),
+		"first try succeeded":                          nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := &bulkStore{
+				migration: api.Migration{
+					Id:         "mig-abc",
+					Candidates: []api.Candidate{{Id: "billing-api", Status: api.CandidateStatusNotStarted}},
+					Steps:      []api.StepDefinition{{Name: "update-chart", MigratorApp: "app-chart-migrator"}},
+				},
+				statusErr: statusErr,
+			}
+			acts := execution.NewActivities(nil, store, nil, nil, nil, nil, nil, nil, slog.Default())
+
+			first, err := acts.PrepareBulkRun(context.Background(), input)
+			require.Equal(t, statusErr, errors.Unwrap(err))
+			second, err := acts.PrepareBulkRun(context.Background(), input)
+			require.NoError(t, err)
+
+			require.Empty(t, second.SkipReason)
+			assert.Equal(t, migrations.RunID("mig-abc", "billing-api"), second.RunID)
+			if statusErr == nil {
+				assert.Equal(t, first.RunID, second.RunID)
+			}
+			require.Len(t, store.runs, 1)
+			assert.True(t, store.runs[0].StartedAt.Equal(input.StartedAt))
+			assert.Equal(t, api.CandidateStatusRunning, store.migration.Candidates[0].Status)
+
+			// Another bulk start reaching the candidate leaves it alone.
+			other := input
+			other.StartedAt = input.StartedAt.Add(time.Minute)
+			skipped, err := acts.PrepareBulkRun(context.Background(), other)
+			require.NoError(t, err)
+			assert.Equal(t, "candidate is already running", skipped.SkipReason)
+			require.Len(t, store.runs, 1)
+		})
+	}
+}
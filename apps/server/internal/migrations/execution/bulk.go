@@ -0,0 +1,140 @@
+package execution
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// BulkStartOrchestrator is the Temporal workflow that rolls a migration out to a
+// set of candidates in waves.
+//
+// It starts one MigrationOrchestrator child run per candidate, keeping at most
+// MaxInFlight of them running, and starts the next queued candidate as soon as
+// an earlier run finishes. Each child run is recorded as the candidate's next
+// run attempt and uses that attempt's run ID, so the rest of the API (steps,
+// retry, cancel) treats them exactly like runs started individually.
+//
+// A query handler ("progress") exposes how far the rollout has got.
+func BulkStartOrchestrator(ctx workflow.Context, input migrations.BulkStartInput) (api.BulkStartProgress, error) {
+	return bulkStart(temporalRuntime{ctx: ctx}, input)
+}
+
+// bulkChild is a child run of a bulk start, by the candidate it runs for.
+type bulkChild struct {
+	candidateID string
+	run         childRun
+}
+
+// bulkStart runs a BulkStartOrchestrator on rt.
+func bulkStart(rt runtime, input migrations.BulkStartInput) (api.BulkStartProgress, error) {
+	rt.Logger().Info("BulkStartOrchestrator started", "migrationId", input.MigrationID, "candidates", len(input.CandidateIDs), "maxInFlight", input.MaxInFlight)
+
+	maxInFlight := max(input.MaxInFlight, 1)
+	progress := api.BulkStartProgress{
+		Id:          rt.RunID(),
+		MigrationId: input.MigrationID,
+		Status:      api.BulkStartProgressStatusRunning,
+		MaxInFlight: maxInFlight,
+		Total:       len(input.CandidateIDs),
+		Pending:     slices.Clone(input.CandidateIDs),
+		Running:     []string{},
+		Succeeded:   []string{},
+		Failed:      []string{},
+		Skipped:     []string{},
+	}
+
+	if err := rt.SetQuery("progress", func() any { return progress }); err != nil {
+		return api.BulkStartProgress{}, fmt.Errorf("register query handler: %w", err)
+	}
+
+	var inFlight []bulkChild
+	for len(progress.Pending) > 0 || len(inFlight) > 0 {
+		for len(inFlight) < maxInFlight && len(progress.Pending) > 0 {
+			id := progress.Pending[0]
+			progress.Pending = progress.Pending[1:]
+
+			if run, ok := startBulkChild(rt, input, id, &progress); ok {
+				inFlight = append(inFlight, bulkChild{candidateID: id, run: run})
+			}
+		}
+		if len(inFlight) == 0 {
+			continue
+		}
+
+		// The bulk start is never cancelled on its own; it finishes once every
+		// child has, so this wait cannot fail.
+		_ = rt.Await(func() bool {
+			return slices.ContainsFunc(inFlight, func(c bulkChild) bool { return c.run.Done() })
+		})
+		i := slices.IndexFunc(inFlight, func(c bulkChild) bool { return c.run.Done() })
+		done := inFlight[i]
+		inFlight = slices.Delete(inFlight, i, i+1)
+		progress.Running = slices.DeleteFunc(progress.Running, func(c string) bool { return c == done.candidateID })
+
+		var result MigrationResult
+		if err := done.run.Get(&result); err != nil || result.Status != resultCompleted {
+			progress.Failed = append(progress.Failed, done.candidateID)
+			continue
+		}
+		progress.Succeeded = append(progress.Succeeded, done.candidateID)
+	}
+
+	progress.Status = api.BulkStartProgressStatusCompleted
+	return progress, nil
+}
+
+// startBulkChild prepares the candidate and starts its MigrationOrchestrator
+// child run. Returns false (after recording the candidate as skipped or failed
+// in progress) when no run was started.
+func startBulkChild(
+	rt runtime,
+	input migrations.BulkStartInput,
+	candidateID string,
+	progress *api.BulkStartProgress,
+) (childRun, bool) {
+	logger := rt.Logger()
+
+	var prep PrepareBulkRunResult
+	prepInput := PrepareBulkRunInput{
+		MigrationID: input.MigrationID,
+		CandidateID: candidateID,
+		Inputs:      input.Inputs,
+		// Truncated to the precision the store keeps, so that a retry can
+		// match it against the attempt it recorded.
+		StartedAt: rt.Now().UTC().Truncate(time.Microsecond),
+	}
+	if err := rt.ExecuteActivity("PrepareBulkRun", shortActivity, prepInput, &prep); err != nil {
+		logger.Warn("failed to prepare candidate", "candidate", candidateID, "error", err)
+		progress.Failed = append(progress.Failed, candidateID)
+		return nil, false
+	}
+	if prep.SkipReason != "" {
+		logger.Info("skipping candidate", "candidate", candidateID, "reason", prep.SkipReason)
+		progress.Skipped = append(progress.Skipped, candidateID)
+		return nil, false
+	}
+
+	// Child runs are abandoned when the bulk start closes: they belong to
+	// their candidate, not to the rollout. StartChild waits for the child to
+	// start so a run that could not be started (e.g. one with the same ID is
+	// already running) is reported as failed straight away.
+	run, err := rt.StartChild("MigrationOrchestrator", prep.RunID, prep.Manifest)
+	if err != nil {
+		logger.Warn("failed to start run", "candidate", candidateID, "error", err)
+		progress.Failed = append(progress.Failed, candidateID)
+		finish := FinishRunInput{RunID: prep.RunID, Status: api.RunAttemptStatusFailed}
+		if err := rt.ExecuteActivity("FinishRun", shortActivity, finish, nil); err != nil {
+			logger.Warn("failed to record run attempt as failed", "candidate", candidateID, "error", err)
+		}
+		return nil, false
+	}
+
+	progress.Running = append(progress.Running, candidateID)
+	return run, true
+}
@@ -0,0 +1,107 @@
+package execution_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/migrations/execution"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// prepareAll configures PrepareBulkRun to return a single-candidate manifest,
+// or a skip reason for the candidate IDs in skip.
+func prepareAll(env *testsuite.TestWorkflowEnvironment, acts *execution.Activities, skip ...string) {
+	env.OnActivity(acts.PrepareBulkRun, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, in execution.PrepareBulkRunInput) (execution.PrepareBulkRunResult, error) {
+			for _, s := range skip {
+				if s == in.CandidateID {
+					return execution.PrepareBulkRunResult{SkipReason: "candidate is already completed"}, nil
+				}
+			}
+			return execution.PrepareBulkRunResult{
+				Manifest: api.MigrationManifest{
+					MigrationId: in.MigrationID,
+					Candidates:  []api.Candidate{{Id: in.CandidateID}},
+				},
+				RunID: migrations.RunAttemptID(in.MigrationID, in.CandidateID, 2),
+			}, nil
+		})
+}
+
+func TestBulkStartOrchestrator_RespectsMaxInFlight(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+	prepareAll(env, acts)
+
+	// Stand-in child run that takes a while, tracking how many overlap.
+	inFlight, peak, started := 0, 0, 0
+	env.RegisterWorkflowWithOptions(func(ctx workflow.Context, m api.MigrationManifest) (execution.MigrationResult, error) {
+		started++
+		inFlight++
+		peak = max(peak, inFlight)
+		_ = workflow.Sleep(ctx, time.Hour)
+		inFlight--
+		return execution.MigrationResult{MigrationId: m.MigrationId, Status: "completed"}, nil
+	}, workflow.RegisterOptions{Name: "MigrationOrchestrator"})
+
+	env.ExecuteWorkflow(execution.BulkStartOrchestrator, migrations.BulkStartInput{
+		MigrationID:  "mig-abc",
+		CandidateIDs: []string{"a", "b", "c", "d", "e"},
+		MaxInFlight:  2,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, 5, started)
+	require.Equal(t, 2, peak)
+
+	var progress api.BulkStartProgress
+	require.NoError(t, env.GetWorkflowResult(&progress))
+	require.Equal(t, api.BulkStartProgressStatusCompleted, progress.Status)
+	require.Equal(t, 5, progress.Total)
+	require.Empty(t, progress.Pending)
+	require.Empty(t, progress.Running)
+	require.ElementsMatch(t, []string{"a", "b", "c", "d", "e"}, progress.Succeeded)
+}
+
+func TestBulkStartOrchestrator_RecordsSkippedAndFailed(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+	prepareAll(env, acts, "skipped")
+
+	env.RegisterWorkflowWithOptions(func(_ workflow.Context, m api.MigrationManifest) (execution.MigrationResult, error) {
+		status := "completed"
+		if m.Candidates[0].Id == "broken" {
+			status = "failed"
+		}
+		return execution.MigrationResult{MigrationId: m.MigrationId, Status: status}, nil
+	}, workflow.RegisterOptions{Name: "MigrationOrchestrator"})
+
+	env.ExecuteWorkflow(execution.BulkStartOrchestrator, migrations.BulkStartInput{
+		MigrationID:  "mig-abc",
+		CandidateIDs: []string{"ok", "skipped", "broken"},
+		MaxInFlight:  3,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	var progress api.BulkStartProgress
+	require.NoError(t, env.GetWorkflowResult(&progress))
+	require.Equal(t, []string{"ok"}, progress.Succeeded)
+	require.Equal(t, []string{"skipped"}, progress.Skipped)
+	require.Equal(t, []string{"broken"}, progress.Failed)
+}
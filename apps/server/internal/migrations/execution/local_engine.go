@@ -0,0 +1,445 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// Compile-time checks: the in-process engine and its memory store implement the ports.
+var (
+	_ migrations.ExecutionEngine = (*LocalEngine)(nil)
+	_ migrations.RunLister       = (*LocalEngine)(nil)
+	_ migrations.ClosedRunStore  = (*MemoryClosedRunStore)(nil)
+)
+
+// LocalEngine implements migrations.ExecutionEngine in-process, for local
+// development and tests that should not need a Temporal cluster. It runs the
+// same run types as the Temporal worker, with the same signal names, queries
+// and results, as goroutines that call the Activities directly.
+//
+// Runs are not durable: timers and waits live in memory, and a run that is in
+// flight when the process stops is gone, so the service treats it like any
+// run the engine no longer knows (see Service.GetCandidates). Closed runs are
+// kept in a ClosedRunStore so their status and results can still be read.
+type LocalEngine struct {
+	closed migrations.ClosedRunStore
+	log    *slog.Logger
+
+	mu   sync.Mutex
+	acts *Activities
+	runs map[string]*localRun // runs still in flight
+	wg   sync.WaitGroup
+}
+
+// NewLocalEngine creates a LocalEngine that keeps closed runs in closed.
+// RegisterActivities must be called before the first run is started.
+func NewLocalEngine(closed migrations.ClosedRunStore, log *slog.Logger) *LocalEngine {
+	return &LocalEngine{closed: closed, log: log, runs: make(map[string]*localRun)}
+}
+
+// RegisterActivities sets the activities runs call. It is separate from
+// NewLocalEngine because the activities fire scheduled starts through the
+// service, which in turn needs the engine.
+func (e *LocalEngine) RegisterActivities(acts *Activities) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.acts = acts
+}
+
+// activities returns the registered activities.
+func (e *LocalEngine) activities() *Activities {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.acts
+}
+
+// StartRun starts a run of runType with ID instanceID. Like a Temporal
+// workflow ID, instanceID cannot be reused while its run is in flight.
+func (e *LocalEngine) StartRun(ctx context.Context, runType, instanceID string, input any) (string, error) {
+	if _, err := e.start(runType, instanceID, input); err != nil {
+		return "", err
+	}
+	return instanceID, nil
+}
+
+// start launches a run and returns it.
+func (e *LocalEngine) start(runType, instanceID string, input any) (*localRun, error) {
+	fn, ok := localRunType(runType)
+	if !ok {
+		return nil, fmt.Errorf("start run %q: unknown run type", runType)
+	}
+	raw, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("start run %q: marshal input: %w", runType, err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.acts == nil {
+		return nil, fmt.Errorf("start run %q: no activities registered", runType)
+	}
+	if _, ok := e.runs[instanceID]; ok {
+		return nil, fmt.Errorf("start run %q: run %q is already running", runType, instanceID)
+	}
+	run := newLocalRun(instanceID, runType)
+	e.runs[instanceID] = run
+	e.wg.Add(1)
+	go e.execute(fn, run, raw)
+	return run, nil
+}
+
+// execute runs fn to completion, then moves the run to the closed runs.
+func (e *LocalEngine) execute(fn localRunFunc, run *localRun, input json.RawMessage) {
+	defer e.wg.Done()
+
+	run.turn.Lock()
+	result, err := fn(localRuntime{ctx: run.ctx, run: run, engine: e}, input)
+	run.turn.Unlock()
+
+	closed := migrations.ClosedRun{
+		ID:            run.id,
+		RunType:       run.runType,
+		RuntimeStatus: migrations.RuntimeStatusCompleted,
+		Queries:       run.answerAll(),
+		ClosedAt:      time.Now().UTC(),
+	}
+	if err != nil {
+		closed.RuntimeStatus = migrations.RuntimeStatusFailed
+		e.log.Warn(run.runType+" failed", "runId", run.id, "error", err)
+	} else if closed.Result, err = json.Marshal(result); err != nil {
+		closed.RuntimeStatus = migrations.RuntimeStatusFailed
+		e.log.Warn(run.runType+" result could not be encoded", "runId", run.id, "error", err)
+	}
+	// Stored before the run leaves e.runs, so a reader always finds it in one or the other.
+	if err := e.closed.SaveClosedRun(context.Background(), closed); err != nil {
+		e.log.Error("failed to save closed run", "runId", run.id, "error", err)
+	}
+
+	e.mu.Lock()
+	delete(e.runs, run.id)
+	e.mu.Unlock()
+	run.cancel()
+	run.result, run.err = closed.Result, err
+	close(run.done)
+}
+
+// live returns the in-flight run with the given ID, or nil.
+func (e *LocalEngine) live(instanceID string) *localRun {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.runs[instanceID]
+}
+
+// GetStatus returns the status of a run. A run in flight reports the steps
+// and pause state of its "progress" query; a completed run the steps of its
+// result, as the Temporal engine does.
+func (e *LocalEngine) GetStatus(ctx context.Context, instanceID string) (*migrations.RunStatus, error) {
+	if run := e.live(instanceID); run != nil {
+		ws := &migrations.RunStatus{RuntimeStatus: migrations.RuntimeStatusRunning}
+		if raw, ok := run.answer("progress"); ok {
+			ws.Steps = localStepResults(raw)
+			ws.Paused = localRunStatus(raw) == resultPaused
+		}
+		return ws, nil
+	}
+
+	closed, err := e.closed.GetClosedRun(ctx, instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("get closed run %q: %w", instanceID, err)
+	}
+	if closed == nil {
+		return nil, migrations.RunNotFoundError{InstanceID: instanceID}
+	}
+	return &migrations.RunStatus{
+		RuntimeStatus: closed.RuntimeStatus,
+		Steps:         localStepResults(closed.Result),
+	}, nil
+}
+
+// RaiseEvent delivers a signal to a run in flight. Signals are buffered per
+// name until the run receives them.
+func (e *LocalEngine) RaiseEvent(ctx context.Context, instanceID, eventName string, payload any) error {
+	run := e.live(instanceID)
+	if run == nil {
+		return fmt.Errorf("signal %q on %q: %w", eventName, instanceID, e.closedError(ctx, instanceID))
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("signal %q on %q: marshal payload: %w", eventName, instanceID, err)
+	}
+	run.signal(eventName).push(raw)
+	run.wake()
+	return nil
+}
+
+// CancelRun cancels the context of a run in flight. The run unwinds as a
+// cancelled Temporal workflow does, running its cleanup before it closes.
+func (e *LocalEngine) CancelRun(ctx context.Context, instanceID string) error {
+	run := e.live(instanceID)
+	if run == nil {
+		return fmt.Errorf("cancel run %q: %w", instanceID, e.closedError(ctx, instanceID))
+	}
+	run.cancel()
+	return nil
+}
+
+// closedError returns why a run that is not in flight cannot be signalled or
+// cancelled: RunNotFoundError when there never was such a run.
+func (e *LocalEngine) closedError(ctx context.Context, instanceID string) error {
+	closed, err := e.closed.GetClosedRun(ctx, instanceID)
+	if err != nil {
+		return fmt.Errorf("get closed run: %w", err)
+	}
+	if closed == nil {
+		return migrations.RunNotFoundError{InstanceID: instanceID}
+	}
+	return errors.New("run has already closed")
+}
+
+// QueryRun answers the named query of a run. A closed run answers with the
+// value the query had when the run closed.
+func (e *LocalEngine) QueryRun(ctx context.Context, instanceID, queryType string, out any) error {
+	var raw json.RawMessage
+	if run := e.live(instanceID); run != nil {
+		answer, ok := run.answer(queryType)
+		if !ok {
+			return fmt.Errorf("query %q on %q: unknown query type", queryType, instanceID)
+		}
+		raw = answer
+	} else {
+		closed, err := e.closed.GetClosedRun(ctx, instanceID)
+		if err != nil {
+			return fmt.Errorf("get closed run %q: %w", instanceID, err)
+		}
+		if closed == nil {
+			return migrations.RunNotFoundError{InstanceID: instanceID}
+		}
+		answer, ok := closed.Queries[queryType]
+		if !ok {
+			return fmt.Errorf("query %q on %q: unknown query type", queryType, instanceID)
+		}
+		raw = answer
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("decode %q result for %q: %w", queryType, instanceID, err)
+	}
+	return nil
+}
+
+// ListOpenRuns returns the IDs of the runs of the given types in flight.
+func (e *LocalEngine) ListOpenRuns(_ context.Context, runTypes ...string) ([]string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var ids []string
+	for id, run := range e.runs {
+		if slices.Contains(runTypes, run.runType) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// Wait blocks until every run in flight has closed. Runs only close by
+// themselves or through CancelRun, so callers cancel them first.
+func (e *LocalEngine) Wait() {
+	e.wg.Wait()
+}
+
+// localStepResults extracts the step results of a MigrationResult payload.
+// Returns nil on any parse failure, as the Temporal engine does.
+func localStepResults(raw json.RawMessage) []api.StepState {
+	var out struct {
+		Results []api.StepState `json:"results"`
+	}
+	if len(raw) == 0 || json.Unmarshal(raw, &out) != nil {
+		return nil
+	}
+	return out.Results
+}
+
+// localRunStatus extracts the status of a MigrationResult payload, or "".
+func localRunStatus(raw json.RawMessage) string {
+	var out struct {
+		Status string `json:"status"`
+	}
+	if json.Unmarshal(raw, &out) != nil {
+		return ""
+	}
+	return out.Status
+}
+
+// localRun is the state of one run in flight.
+type localRun struct {
+	id      string
+	runType string
+	ctx     context.Context
+	cancel  context.CancelFunc
+	done    chan struct{}   // closed once the run has closed
+	result  json.RawMessage // set before done is closed
+	err     error           // set before done is closed
+
+	// turn is held by whichever of the run's coroutines is executing, and by
+	// query handlers, which read run state (see localRuntime).
+	turn sync.Mutex
+
+	mu      sync.Mutex
+	woken   chan struct{} // closed and replaced by wake
+	signals map[string]*localSignal
+	queries map[string]func() any
+}
+
+func newLocalRun(id, runType string) *localRun {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &localRun{
+		id:      id,
+		runType: runType,
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		woken:   make(chan struct{}),
+		signals: make(map[string]*localSignal),
+		queries: make(map[string]func() any),
+	}
+}
+
+// wake tells the run's waiting coroutines that what they wait for may have
+// happened.
+func (r *localRun) wake() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	close(r.woken)
+	r.woken = make(chan struct{})
+}
+
+// wakeup returns the channel the next wake closes.
+func (r *localRun) wakeup() <-chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.woken
+}
+
+// signal returns the buffer of the named signal, creating it on first use.
+func (r *localRun) signal(name string) *localSignal {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.signals[name]
+	if !ok {
+		s = &localSignal{}
+		r.signals[name] = s
+	}
+	return s
+}
+
+// setQuery registers the handler of the named query.
+func (r *localRun) setQuery(name string, handler func() any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries[name] = handler
+}
+
+// answer returns the JSON encoding of the named query's answer.
+func (r *localRun) answer(name string) (json.RawMessage, bool) {
+	r.mu.Lock()
+	handler, ok := r.queries[name]
+	r.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	r.turn.Lock()
+	defer r.turn.Unlock()
+	raw, err := json.Marshal(handler())
+	if err != nil {
+		return nil, false
+	}
+	return raw, true
+}
+
+// answerAll returns the answer of every registered query.
+func (r *localRun) answerAll() map[string]json.RawMessage {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.queries))
+	for name := range r.queries {
+		names = append(names, name)
+	}
+	r.mu.Unlock()
+	answers := make(map[string]json.RawMessage, len(names))
+	for _, name := range names {
+		if raw, ok := r.answer(name); ok {
+			answers[name] = raw
+		}
+	}
+	return answers
+}
+
+// localSignal buffers the payloads raised under one signal name.
+type localSignal struct {
+	mu    sync.Mutex
+	queue []json.RawMessage
+}
+
+func (s *localSignal) push(payload json.RawMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = append(s.queue, payload)
+}
+
+func (s *localSignal) Pending() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queue) > 0
+}
+
+func (s *localSignal) Receive(out any) bool {
+	s.mu.Lock()
+	if len(s.queue) == 0 {
+		s.mu.Unlock()
+		return false
+	}
+	payload := s.queue[0]
+	s.queue = s.queue[1:]
+	s.mu.Unlock()
+	if out != nil {
+		_ = json.Unmarshal(payload, out)
+	}
+	return true
+}
+
+// MemoryClosedRunStore implements migrations.ClosedRunStore in memory, for
+// tests and for running without Postgres.
+type MemoryClosedRunStore struct {
+	mu   sync.Mutex
+	runs map[string]migrations.ClosedRun
+}
+
+// NewMemoryClosedRunStore creates an empty MemoryClosedRunStore.
+func NewMemoryClosedRunStore() *MemoryClosedRunStore {
+	return &MemoryClosedRunStore{runs: make(map[string]migrations.ClosedRun)}
+}
+
+// SaveClosedRun stores r, replacing an earlier run with the same ID.
+func (s *MemoryClosedRunStore) SaveClosedRun(_ context.Context, r migrations.ClosedRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs[r.ID] = r
+	return nil
+}
+
+// GetClosedRun returns the closed run, or nil when there is none with that ID.
+func (s *MemoryClosedRunStore) GetClosedRun(_ context.Context, id string) (*migrations.ClosedRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.runs[id]
+	if !ok {
+		return nil, nil //nolint:nilnil
+	}
+	return &r, nil
+}
@@ -0,0 +1,695 @@
+package execution_test
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/migrations/execution"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// runStore records the run outcomes and candidate statuses the activities
+// write. The rest of MigrationStore is never called by a migration run.
+type runStore struct {
+	migrations.MigrationStore
+
+	mu        sync.Mutex
+	migration *api.Migration // what Get returns, for bulk starts
+	finished  map[string]api.RunAttemptStatus
+	statuses  map[string]api.CandidateStatus
+}
+
+func newRunStore() *runStore {
+	return &runStore{
+		finished: make(map[string]api.RunAttemptStatus),
+		statuses: make(map[string]api.CandidateStatus),
+	}
+}
+
+func (s *runStore) SetCandidateStatus(_ context.Context, _, candidateID string, status api.CandidateStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statuses[candidateID] = status
+	return nil
+}
+
+func (s *runStore) FinishRun(_ context.Context, runID string, status api.RunAttemptStatus, _ []api.StepState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.finished[runID] = status
+	return nil
+}
+
+func (s *runStore) Get(context.Context, string) (*api.Migration, error) { return s.migration, nil }
+
+func (s *runStore) ListRuns(context.Context, string, string) ([]api.RunAttempt, error) {
+	return nil, nil
+}
+
+func (s *runStore) CreateRun(context.Context, api.RunAttempt) error { return nil }
+
+func (s *runStore) outcome(runID string) api.RunAttemptStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.finished[runID]
+}
+
+// callbackMigrator answers every dispatch through the engine with the next of
+// statuses, or leaves the step in flight once they run out.
+type callbackMigrator struct {
+	engine *execution.LocalEngine
+
+	mu       sync.Mutex
+	statuses []api.StepStatusEventStatus
+	reqs     []api.DispatchStepRequest
+}
+
+func (m *callbackMigrator) Dispatch(ctx context.Context, req api.DispatchStepRequest) error {
+	m.mu.Lock()
+	m.reqs = append(m.reqs, req)
+	if len(m.statuses) == 0 {
+		m.mu.Unlock()
+		return nil
+	}
+	status := m.statuses[0]
+	m.statuses = m.statuses[1:]
+	m.mu.Unlock()
+	return m.engine.RaiseEvent(ctx, req.CallbackId, req.EventName, api.StepStatusEvent{
+		StepName:    req.StepName,
+		CandidateId: req.Candidate.Id,
+		Status:      status,
+		Attempt:     req.Attempt,
+	})
+}
+
+func (m *callbackMigrator) dispatched() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.reqs)
+}
+
+// newLocalEngine returns a LocalEngine whose steps are answered by a
+// callbackMigrator with statuses.
+func newLocalEngine(
+	t *testing.T,
+	statuses ...api.StepStatusEventStatus,
+) (*execution.LocalEngine, *runStore, *callbackMigrator) {
+	t.Helper()
+	engine := execution.NewLocalEngine(execution.NewMemoryClosedRunStore(), slog.Default())
+	store := newRunStore()
+	migrator := &callbackMigrator{engine: engine, statuses: statuses}
+	engine.RegisterActivities(execution.NewActivities(migrator, store, nil, nil, nil, nil, nil, nil, slog.Default()))
+	return engine, store, migrator
+}
+
+func localManifest(steps ...string) api.MigrationManifest {
+	m := api.MigrationManifest{
+		MigrationId: "mig-abc",
+		Candidates:  []api.Candidate{{Id: "billing-api"}},
+	}
+	for _, s := range steps {
+		m.Steps = append(m.Steps, api.StepDefinition{Name: s, MigratorApp: "app-chart-migrator"})
+	}
+	return m
+}
+
+// awaitClosed waits until the run has closed and returns its status.
+func awaitClosed(t *testing.T, engine *execution.LocalEngine, runID string) *migrations.RunStatus {
+	t.Helper()
+	var status *migrations.RunStatus
+	require.Eventually(t, func() bool {
+		s, err := engine.GetStatus(context.Background(), runID)
+		require.NoError(t, err)
+		status = s
+		return s.RuntimeStatus != migrations.RuntimeStatusRunning
+	}, 5*time.Second, 5*time.Millisecond)
+	return status
+}
+
+// ─── Local engine ────────────────────────────────────────────────────────────
+
+func TestLocalEngine_MigrationRun_Success(t *testing.T) {
+	engine, store, _ := newLocalEngine(t, api.StepStatusEventStatusSucceeded, api.StepStatusEventStatusSucceeded)
+	ctx := context.Background()
+
+	runID, err := engine.StartRun(ctx, "MigrationOrchestrator", "mig-abc__billing-api",
+		localManifest("update-chart", "cleanup"))
+	require.NoError(t, err)
+	assert.Equal(t, "mig-abc__billing-api", runID)
+
+	status := awaitClosed(t, engine, runID)
+	assert.Equal(t, migrations.RuntimeStatusCompleted, status.RuntimeStatus)
+	require.Len(t, status.Steps, 2)
+	assert.Equal(t, "update-chart", status.Steps[0].StepName)
+	assert.Equal(t, api.StepStateStatusSucceeded, status.Steps[1].Status)
+	assert.Equal(t, api.RunAttemptStatusCompleted, store.outcome(runID))
+	assert.Equal(t, api.CandidateStatusCompleted, store.statuses["billing-api"])
+}
+
+func TestLocalEngine_MigrationRun_FailedStepRetried(t *testing.T) {
+	engine, _, migrator := newLocalEngine(t, api.StepStatusEventStatusFailed, api.StepStatusEventStatusSucceeded)
+	ctx := context.Background()
+	runID, err := engine.StartRun(ctx, "MigrationOrchestrator", "mig-abc__billing-api", localManifest("update-chart"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		s, err := engine.GetStatus(ctx, runID)
+		require.NoError(t, err)
+		return len(s.Steps) == 1 && s.Steps[0].Status == api.StepStateStatusFailed
+	}, 5*time.Second, 5*time.Millisecond)
+	require.NoError(t, engine.RaiseEvent(ctx, runID, migrations.RetryStepEventName("update-chart", "billing-api"), nil))
+
+	status := awaitClosed(t, engine, runID)
+	assert.Equal(t, migrations.RuntimeStatusCompleted, status.RuntimeStatus)
+	assert.Equal(t, api.StepStateStatusSucceeded, status.Steps[0].Status)
+	assert.Equal(t, 2, migrator.dispatched())
+}
+
+func TestLocalEngine_CancelRun_ResetsCandidate(t *testing.T) {
+	engine, store, migrator := newLocalEngine(t) // the step never calls back
+	ctx := context.Background()
+	runID, err := engine.StartRun(ctx, "MigrationOrchestrator", "mig-abc__billing-api", localManifest("update-chart"))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return migrator.dispatched() == 1 }, 5*time.Second, 5*time.Millisecond)
+
+	require.NoError(t, engine.CancelRun(ctx, runID))
+	engine.Wait()
+
+	status, err := engine.GetStatus(ctx, runID)
+	require.NoError(t, err)
+	assert.Equal(t, migrations.RuntimeStatusCompleted, status.RuntimeStatus, "a cancelled run returns a failed result")
+	assert.Equal(t, api.RunAttemptStatusCancelled, store.outcome(runID))
+	assert.Equal(t, api.CandidateStatusNotStarted, store.statuses["billing-api"])
+
+	err = engine.RaiseEvent(ctx, runID, migrations.StepEventName("update-chart", "billing-api"), nil)
+	assert.ErrorContains(t, err, "already closed")
+}
+
+func TestLocalEngine_QueryRun_AnswersWhileRunningAndAfterClose(t *testing.T) {
+	engine, _, migrator := newLocalEngine(t)
+	ctx := context.Background()
+	runID, err := engine.StartRun(ctx, "MigrationOrchestrator", "mig-abc__billing-api", localManifest("update-chart"))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return migrator.dispatched() == 1 }, 5*time.Second, 5*time.Millisecond)
+
+	require.NoError(t, engine.RaiseEvent(ctx, runID, migrations.PauseStateEventName("billing-api"), migrations.PauseState{Paused: true}))
+	require.Eventually(t, func() bool {
+		s, err := engine.GetStatus(ctx, runID)
+		require.NoError(t, err)
+		return s.Paused
+	}, 5*time.Second, 5*time.Millisecond)
+
+	var live execution.MigrationResult
+	require.NoError(t, engine.QueryRun(ctx, runID, "progress", &live))
+	assert.Equal(t, "paused", live.Status)
+	require.Len(t, live.Results, 1)
+	assert.Equal(t, api.StepStateStatusInProgress, live.Results[0].Status)
+
+	require.NoError(t, engine.RaiseEvent(ctx, runID, migrations.StepEventName("update-chart", "billing-api"),
+		api.StepStatusEvent{StepName: "update-chart", CandidateId: "billing-api", Status: "succeeded"}))
+	awaitClosed(t, engine, runID)
+
+	var closed execution.MigrationResult
+	require.NoError(t, engine.QueryRun(ctx, runID, "progress", &closed))
+	require.Len(t, closed.Results, 1)
+	assert.Equal(t, api.StepStateStatusSucceeded, closed.Results[0].Status)
+}
+
+func TestLocalEngine_UnknownRun_IsNotFound(t *testing.T) {
+	engine, _, _ := newLocalEngine(t)
+
+	_, err := engine.GetStatus(context.Background(), "missing")
+
+	var notFound migrations.RunNotFoundError
+	require.ErrorAs(t, err, &notFound)
+	require.ErrorAs(t, engine.CancelRun(context.Background(), "missing"), &notFound)
+}
+
+func TestLocalEngine_StartRun_RejectsRunInFlight(t *testing.T) {
+	engine, _, migrator := newLocalEngine(t)
+	ctx := context.Background()
+	_, err := engine.StartRun(ctx, "MigrationOrchestrator", "mig-abc__billing-api", localManifest("update-chart"))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return migrator.dispatched() == 1 }, 5*time.Second, 5*time.Millisecond)
+	t.Cleanup(func() {
+		_ = engine.CancelRun(ctx, "mig-abc__billing-api")
+		engine.Wait()
+	})
+
+	_, err = engine.StartRun(ctx, "MigrationOrchestrator", "mig-abc__billing-api", localManifest("update-chart"))
+	assert.ErrorContains(t, err, "already running")
+}
+
+func TestLocalEngine_ListOpenRuns_FiltersByRunType(t *testing.T) {
+	engine, _, migrator := newLocalEngine(t)
+	ctx := context.Background()
+	_, err := engine.StartRun(ctx, "MigrationOrchestrator", "mig-abc__billing-api", localManifest("update-chart"))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return migrator.dispatched() == 1 }, 5*time.Second, 5*time.Millisecond)
+	t.Cleanup(func() {
+		_ = engine.CancelRun(ctx, "mig-abc__billing-api")
+		engine.Wait()
+	})
+
+	ids, err := engine.ListOpenRuns(ctx, "MigrationOrchestrator", migrations.RollbackRunType)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mig-abc__billing-api"}, ids)
+	ids, err = engine.ListOpenRuns(ctx, migrations.RollbackRunType)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+// ─── Both engines ────────────────────────────────────────────────────────────
+
+func ptr[T any](v T) *T { return &v }
+
+// scenarioDriver is what a scenario sees of the engine it runs on.
+type scenarioDriver struct {
+	now  func() time.Time
+	send func(after time.Duration, runID, signal string, payload any)
+}
+
+// callback answers a dispatched step with status after the given delay.
+func (d scenarioDriver) callback(req api.DispatchStepRequest, status api.StepStatusEventStatus, after time.Duration) {
+	d.send(after, req.CallbackId, req.EventName, api.StepStatusEvent{
+		StepName:    req.StepName,
+		CandidateId: req.Candidate.Id,
+		Status:      status,
+		Attempt:     req.Attempt,
+	})
+}
+
+// answerFunc answers the n-th dispatch (from 1) of a step to a candidate.
+type answerFunc func(d scenarioDriver, req api.DispatchStepRequest, n int)
+
+// succeed answers every dispatch with a succeeded callback.
+func succeed(d scenarioDriver, req api.DispatchStepRequest, _ int) {
+	d.callback(req, api.StepStatusEventStatusSucceeded, 10*time.Millisecond)
+}
+
+// scenarioMigrator answers dispatches as its scenario says.
+type scenarioMigrator struct {
+	driver scenarioDriver
+	answer answerFunc
+
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (m *scenarioMigrator) Dispatch(_ context.Context, req api.DispatchStepRequest) error {
+	m.mu.Lock()
+	m.counts[req.Candidate.Id+"/"+req.StepName]++
+	n := m.counts[req.Candidate.Id+"/"+req.StepName]
+	m.mu.Unlock()
+	m.answer(m.driver, req, n)
+	return nil
+}
+
+// eventLog records the lifecycle events of a run by candidate and step.
+type eventLog struct {
+	migrations.EventStore
+
+	mu     sync.Mutex
+	events map[string][]string
+}
+
+func (l *eventLog) RecordEvent(_ context.Context, e migrations.StepEvent) error {
+	key := e.CandidateID
+	if e.StepName != "" {
+		key += "/" + e.StepName
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events[key] = append(l.events[key], e.EventType)
+	return nil
+}
+
+// freezeCalendar holds every step back from the first check until d later.
+type freezeCalendar struct {
+	migrations.FreezeCalendar
+	d time.Duration
+
+	mu     sync.Mutex
+	window *migrations.FreezeWindow
+}
+
+func (c *freezeCalendar) ListFreezeWindows(
+	_ context.Context,
+	filter migrations.FreezeWindowFilter,
+) ([]migrations.FreezeWindow, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.window == nil {
+		c.window = &migrations.FreezeWindow{
+			Reason:   "release week",
+			StartsAt: filter.EndsAfter.Add(-time.Hour),
+			EndsAt:   filter.EndsAfter.Add(c.d),
+		}
+	}
+	if !c.window.EndsAt.After(filter.EndsAfter) {
+		return nil, nil
+	}
+	return []migrations.FreezeWindow{*c.window}, nil
+}
+
+// firedStarts records the scheduled starts fired.
+type firedStarts struct {
+	mu  sync.Mutex
+	ids []string
+}
+
+func (f *firedStarts) FireScheduledStart(_ context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ids = append(f.ids, id)
+	return nil
+}
+
+// scenario is a run both engines must carry out alike.
+type scenario struct {
+	name      string
+	runType   string
+	runID     string
+	input     func(now time.Time) any
+	answer    answerFunc
+	freeze    time.Duration  // holds every step back this long when set
+	migration *api.Migration // for bulk starts
+
+	wantEvents   map[string][]string
+	wantStatuses map[string]api.CandidateStatus
+	wantFired    []string
+}
+
+// scenarioOutcome is what a scenario run left behind.
+type scenarioOutcome struct {
+	events   map[string][]string
+	statuses map[string]api.CandidateStatus
+	fired    []string
+}
+
+// scenarioDeps are the activities' dependencies for one run of sc.
+type scenarioDeps struct {
+	migrator *scenarioMigrator
+	store    *runStore
+	events   *eventLog
+	starter  *firedStarts
+	acts     *execution.Activities
+}
+
+func newScenarioDeps(sc scenario) *scenarioDeps {
+	d := &scenarioDeps{
+		migrator: &scenarioMigrator{answer: sc.answer, counts: make(map[string]int)},
+		store:    newRunStore(),
+		events:   &eventLog{events: make(map[string][]string)},
+		starter:  &firedStarts{},
+	}
+	d.store.migration = sc.migration
+	var freezes migrations.FreezeCalendar
+	if sc.freeze > 0 {
+		freezes = &freezeCalendar{d: sc.freeze}
+	}
+	d.acts = execution.NewActivities(d.migrator, d.store, d.events, nil, freezes, nil, nil, d.starter, slog.Default())
+	return d
+}
+
+func (d *scenarioDeps) outcome() scenarioOutcome {
+	return scenarioOutcome{events: d.events.events, statuses: d.store.statuses, fired: d.starter.ids}
+}
+
+// runOnTemporal runs sc in the Temporal test environment.
+func runOnTemporal(t *testing.T, sc scenario) scenarioOutcome {
+	t.Helper()
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(execution.MigrationOrchestrator)
+	env.RegisterWorkflowWithOptions(execution.RollbackOrchestrator,
+		workflow.RegisterOptions{Name: migrations.RollbackRunType})
+	env.RegisterWorkflowWithOptions(execution.BulkStartOrchestrator,
+		workflow.RegisterOptions{Name: migrations.BulkStartRunType})
+	env.RegisterWorkflowWithOptions(execution.ScheduledStartOrchestrator,
+		workflow.RegisterOptions{Name: migrations.ScheduledStartRunType})
+
+	deps := newScenarioDeps(sc)
+	deps.migrator.driver = scenarioDriver{
+		now: env.Now,
+		send: func(after time.Duration, runID, signal string, payload any) {
+			env.RegisterDelayedCallback(func() {
+				require.NoError(t, env.SignalWorkflowByID(runID, signal, payload))
+			}, after)
+		},
+	}
+	env.RegisterActivity(deps.acts)
+	env.SetStartWorkflowOptions(client.StartWorkflowOptions{ID: sc.runID})
+
+	env.ExecuteWorkflow(sc.runType, sc.input(env.Now()))
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	return deps.outcome()
+}
+
+// runOnLocalEngine runs sc on a LocalEngine.
+func runOnLocalEngine(t *testing.T, sc scenario) scenarioOutcome {
+	t.Helper()
+	engine := execution.NewLocalEngine(execution.NewMemoryClosedRunStore(), slog.Default())
+	deps := newScenarioDeps(sc)
+	deps.migrator.driver = scenarioDriver{
+		now: time.Now,
+		send: func(after time.Duration, runID, signal string, payload any) {
+			time.AfterFunc(after, func() {
+				_ = engine.RaiseEvent(context.Background(), runID, signal, payload)
+			})
+		},
+	}
+	engine.RegisterActivities(deps.acts)
+
+	_, err := engine.StartRun(context.Background(), sc.runType, sc.runID, sc.input(time.Now()))
+	require.NoError(t, err)
+	status := awaitClosed(t, engine, sc.runID)
+	engine.Wait()
+
+	require.Equal(t, migrations.RuntimeStatusCompleted, status.RuntimeStatus)
+	return deps.outcome()
+}
+
+// manifestInput returns m as the input of every run of the scenario.
+func manifestInput(m api.MigrationManifest) func(time.Time) any {
+	return func(time.Time) any { return m }
+}
+
+func withSteps(steps ...api.StepDefinition) api.MigrationManifest {
+	m := localManifest()
+	m.Steps = steps
+	return m
+}
+
+func step(name string) api.StepDefinition {
+	return api.StepDefinition{Name: name, MigratorApp: "app-chart-migrator"}
+}
+
+var (
+	ranStep   = []string{migrations.EventStepDispatched, migrations.EventStepCompleted}
+	ranRun    = []string{migrations.EventRunStarted, migrations.EventRunCompleted}
+	completed = map[string]api.CandidateStatus{"billing-api": api.CandidateStatusCompleted}
+)
+
+func engineScenarios() []scenario {
+	dependent := step("open-pr")
+	dependent.DependsOn = &[]string{"update-chart"}
+	unless := step("notify-payments")
+	unless.When = ptr(`metadata.team == "payments"`)
+	timed := step("update-chart")
+	timed.TimeoutSeconds = ptr(1)
+	revert := step(migrations.RollbackStepName("update-chart"))
+	revert.Type = ptr("revert-chart")
+
+	return []scenario{
+		{
+			name:    "pause and resume",
+			runType: "MigrationOrchestrator",
+			runID:   "mig-abc__billing-api",
+			input:   manifestInput(withSteps(step("update-chart"), step("open-pr"))),
+			answer: func(d scenarioDriver, req api.DispatchStepRequest, n int) {
+				if req.StepName == "update-chart" {
+					d.send(0, req.CallbackId, migrations.PauseStateEventName("billing-api"), migrations.PauseState{Paused: true})
+					d.send(200*time.Millisecond, req.CallbackId, migrations.PauseStateEventName("billing-api"), migrations.PauseState{})
+				}
+				succeed(d, req, n)
+			},
+			wantEvents: map[string][]string{
+				"billing-api": {
+					migrations.EventRunStarted, migrations.EventRunPaused,
+					migrations.EventRunResumed, migrations.EventRunCompleted,
+				},
+				"billing-api/update-chart": ranStep,
+				"billing-api/open-pr":      ranStep,
+			},
+			wantStatuses: completed,
+		},
+		{
+			name:    "skip a failed step",
+			runType: "MigrationOrchestrator",
+			runID:   "mig-abc__billing-api",
+			input:   manifestInput(withSteps(step("update-chart"), step("open-pr"))),
+			answer: func(d scenarioDriver, req api.DispatchStepRequest, n int) {
+				if req.StepName != "update-chart" {
+					succeed(d, req, n)
+					return
+				}
+				d.callback(req, api.StepStatusEventStatusFailed, 10*time.Millisecond)
+				d.send(50*time.Millisecond, req.CallbackId, migrations.SkipStepEventName("update-chart", "billing-api"),
+					api.SkipStepRequest{StepName: "update-chart", Reason: "done by hand"})
+			},
+			wantEvents: map[string][]string{
+				"billing-api": ranRun,
+				"billing-api/update-chart": {
+					migrations.EventStepDispatched, migrations.EventStepCompleted, migrations.EventStepSkipped,
+				},
+				"billing-api/open-pr": ranStep,
+			},
+			wantStatuses: completed,
+		},
+		{
+			name:    "freeze window",
+			runType: "MigrationOrchestrator",
+			runID:   "mig-abc__billing-api",
+			input:   manifestInput(withSteps(step("update-chart"))),
+			answer:  succeed,
+			freeze:  1500 * time.Millisecond,
+			wantEvents: map[string][]string{
+				"billing-api": ranRun,
+				"billing-api/update-chart": {
+					migrations.EventStepWaitingForWindow, migrations.EventStepDispatched, migrations.EventStepCompleted,
+				},
+			},
+			wantStatuses: completed,
+		},
+		{
+			name:    "dependsOn",
+			runType: "MigrationOrchestrator",
+			runID:   "mig-abc__billing-api",
+			input:   manifestInput(withSteps(dependent, step("update-chart"), step("notify"))),
+			answer:  succeed,
+			wantEvents: map[string][]string{
+				"billing-api":              ranRun,
+				"billing-api/update-chart": ranStep,
+				"billing-api/open-pr":      ranStep,
+				"billing-api/notify":       ranStep,
+			},
+			wantStatuses: completed,
+		},
+		{
+			name:    "when",
+			runType: "MigrationOrchestrator",
+			runID:   "mig-abc__billing-api",
+			input:   manifestInput(withSteps(step("update-chart"), unless)),
+			answer:  succeed,
+			wantEvents: map[string][]string{
+				"billing-api":                 ranRun,
+				"billing-api/update-chart":    ranStep,
+				"billing-api/notify-payments": {migrations.EventStepSkipped},
+			},
+			wantStatuses: completed,
+		},
+		{
+			name:    "step timeout",
+			runType: "MigrationOrchestrator",
+			runID:   "mig-abc__billing-api",
+			input:   manifestInput(withSteps(timed)),
+			answer: func(d scenarioDriver, req api.DispatchStepRequest, _ int) {
+				d.callback(req, api.StepStatusEventStatusSucceeded, 1500*time.Millisecond)
+			},
+			wantEvents: map[string][]string{
+				"billing-api": ranRun,
+				"billing-api/update-chart": {
+					migrations.EventStepDispatched, migrations.EventStepTimedOut, migrations.EventStepCompleted,
+				},
+			},
+			wantStatuses: completed,
+		},
+		{
+			name:    "bulk start",
+			runType: migrations.BulkStartRunType,
+			runID:   "bulk-1",
+			input: func(time.Time) any {
+				return migrations.BulkStartInput{
+					MigrationID:  "mig-abc",
+					CandidateIDs: []string{"billing-api", "search-api", "ledger-api"},
+					MaxInFlight:  2,
+				}
+			},
+			migration: &api.Migration{
+				Id:         "mig-abc",
+				Steps:      []api.StepDefinition{step("update-chart")},
+				Candidates: []api.Candidate{{Id: "billing-api"}, {Id: "search-api"}, {Id: "ledger-api"}},
+			},
+			answer: succeed,
+			wantEvents: map[string][]string{
+				"billing-api":              ranRun,
+				"billing-api/update-chart": ranStep,
+				"search-api":               ranRun,
+				"search-api/update-chart":  ranStep,
+				"ledger-api":               ranRun,
+				"ledger-api/update-chart":  ranStep,
+			},
+			wantStatuses: map[string]api.CandidateStatus{
+				"billing-api": api.CandidateStatusCompleted,
+				"search-api":  api.CandidateStatusCompleted,
+				"ledger-api":  api.CandidateStatusCompleted,
+			},
+		},
+		{
+			name:    "scheduled start",
+			runType: migrations.ScheduledStartRunType,
+			runID:   "sched-1",
+			input: func(now time.Time) any {
+				return migrations.ScheduledStartInput{ID: "sched-1", StartAt: now.Add(200 * time.Millisecond)}
+			},
+			answer:    succeed,
+			wantFired: []string{"sched-1"},
+		},
+		{
+			name:    "rollback",
+			runType: migrations.RollbackRunType,
+			runID:   "mig-abc__billing-api__2",
+			input:   manifestInput(withSteps(revert)),
+			answer:  succeed,
+			wantEvents: map[string][]string{
+				"billing-api": {migrations.EventRollbackStarted, migrations.EventRollbackCompleted},
+				"billing-api/" + migrations.RollbackStepName("update-chart"): ranStep,
+			},
+			wantStatuses: map[string]api.CandidateStatus{"billing-api": api.CandidateStatusNotStarted},
+		},
+	}
+}
+
+// TestEngines_RunScenariosAlike runs every scenario on the Temporal test
+// environment and on a LocalEngine, which share the orchestration but not the
+// runtime beneath it.
+func TestEngines_RunScenariosAlike(t *testing.T) {
+	for _, sc := range engineScenarios() {
+		t.Run(sc.name, func(t *testing.T) {
+			t.Parallel()
+			want := scenarioOutcome{events: sc.wantEvents, statuses: sc.wantStatuses, fired: sc.wantFired}
+			if want.events == nil {
+				want.events = map[string][]string{}
+			}
+			if want.statuses == nil {
+				want.statuses = map[string]api.CandidateStatus{}
+			}
+
+			assert.Equal(t, want, runOnTemporal(t, sc), "Temporal")
+			assert.Equal(t, want, runOnLocalEngine(t, sc), "LocalEngine")
+		})
+	}
+}
@@ -0,0 +1,335 @@
+package execution
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// Compile-time check: localRuntime implements runtime.
+var _ runtime = localRuntime{}
+
+// localRuntime runs the run types in-process for LocalEngine.
+//
+// A run's coroutines are goroutines that take turns: each holds the run's turn
+// lock while it executes and lets go of it only inside a blocking call, so they
+// never run at the same time, as in a Temporal workflow. Whatever may unblock
+// a waiting coroutine (a signal, a timer, a child closing, another coroutine
+// yielding) wakes the run, and every waiter checks its condition again.
+type localRuntime struct {
+	ctx    context.Context
+	run    *localRun
+	engine *LocalEngine
+}
+
+func (r localRuntime) RunID() string  { return r.run.id }
+func (r localRuntime) Now() time.Time { return time.Now() }
+func (r localRuntime) Logger() logger { return r.engine.log.With("runId", r.run.id) }
+func (r localRuntime) Err() error     { return r.ctx.Err() }
+
+func (r localRuntime) WithCancel() (runtime, func()) {
+	ctx, cancel := context.WithCancel(r.ctx)
+	return localRuntime{ctx: ctx, run: r.run, engine: r.engine}, cancel
+}
+
+func (r localRuntime) Disconnected() runtime {
+	return localRuntime{ctx: context.WithoutCancel(r.ctx), run: r.run, engine: r.engine}
+}
+
+func (r localRuntime) Go(fn func(rt runtime)) {
+	r.engine.wg.Add(1)
+	go func() {
+		defer r.engine.wg.Done()
+		r.run.turn.Lock()
+		defer r.run.turn.Unlock()
+		defer r.run.wake()
+		fn(r)
+	}()
+}
+
+func (r localRuntime) Sleep(d time.Duration) error {
+	var err error
+	r.yield(func() {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-r.ctx.Done():
+			err = r.ctx.Err()
+		case <-t.C:
+		}
+	})
+	return err
+}
+
+func (r localRuntime) NewTimer(d time.Duration) timer {
+	t := &localTimer{}
+	t.timer = time.AfterFunc(d, func() {
+		t.fired.Store(true)
+		r.run.wake()
+	})
+	return t
+}
+
+func (r localRuntime) Await(cond func() bool) error {
+	r.run.wake()
+	for {
+		woken := r.run.wakeup()
+		if cond() {
+			return nil
+		}
+		if err := r.ctx.Err(); err != nil {
+			return err
+		}
+		r.run.turn.Unlock()
+		select {
+		case <-woken:
+		case <-r.ctx.Done():
+		}
+		r.run.turn.Lock()
+	}
+}
+
+func (r localRuntime) Signal(name string) signal { return r.run.signal(name) }
+
+// ExecuteActivity calls the Activities method called name. Its input and
+// result are passed through JSON, as Temporal's data converter would, so the
+// activity never shares memory with the run while the run goes on without it.
+func (r localRuntime) ExecuteActivity(name string, opts activityOptions, input, out any) error {
+	if err := r.ctx.Err(); err != nil {
+		return err
+	}
+	call, err := r.activity(name, input)
+	if err != nil {
+		return err
+	}
+	policy := opts.retry
+	if policy == nil {
+		policy = localDefaultRetry
+	}
+	var result any
+	r.yield(func() {
+		err = callLocalActivity(r.ctx, policy, opts.timeout, func(ctx context.Context) error {
+			var callErr error
+			result, callErr = call(ctx)
+			return callErr
+		})
+	})
+	if err != nil || out == nil {
+		return err
+	}
+	return remarshal(result, out)
+}
+
+// ExecuteLocalActivity makes a single attempt. It runs even once the run is
+// cancelled, so the events of a cancelled run are still recorded.
+func (r localRuntime) ExecuteLocalActivity(name string, timeout time.Duration, input any) error {
+	call, err := r.activity(name, input)
+	if err != nil {
+		return err
+	}
+	r.yield(func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(r.ctx), timeout)
+		defer cancel()
+		_, err = call(ctx)
+	})
+	return err
+}
+
+func (r localRuntime) StartChild(runType, id string, input any) (childRun, error) {
+	child, err := r.engine.start(runType, id, input)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		<-child.done
+		r.run.wake()
+	}()
+	return localChild{rt: r, run: child}, nil
+}
+
+// GetVersion returns maxSupported: a local run never outlives the process
+// that started it, so it always follows the latest version.
+func (r localRuntime) GetVersion(_ string, maxSupported workflow.Version) workflow.Version {
+	return maxSupported
+}
+
+func (r localRuntime) SetQuery(name string, handler func() any) error {
+	r.run.setQuery(name, handler)
+	return nil
+}
+
+// yield runs block, which must not touch run state, with the turn handed to
+// the run's other coroutines.
+func (r localRuntime) yield(block func()) {
+	r.run.wake()
+	r.run.turn.Unlock()
+	defer r.run.turn.Lock()
+	block()
+}
+
+// activity returns a call of the Activities method called name with input.
+func (r localRuntime) activity(name string, input any) (func(context.Context) (any, error), error) {
+	method := reflect.ValueOf(r.engine.activities()).MethodByName(name)
+	if !method.IsValid() {
+		return nil, fmt.Errorf("unknown activity %q", name)
+	}
+	arg := reflect.New(method.Type().In(1))
+	if err := remarshal(input, arg.Interface()); err != nil {
+		return nil, fmt.Errorf("activity %q: %w", name, err)
+	}
+	return func(ctx context.Context) (any, error) {
+		out := method.Call([]reflect.Value{reflect.ValueOf(ctx), arg.Elem()})
+		err, _ := out[len(out)-1].Interface().(error)
+		if len(out) == 1 || err != nil {
+			return nil, err
+		}
+		return out[0].Interface(), nil
+	}, nil
+}
+
+// remarshal copies in into out through its JSON encoding.
+func remarshal(in, out any) error {
+	raw, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("encode: %w", err)
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+	return nil
+}
+
+// localTimer is a timer of a local run.
+type localTimer struct {
+	timer *time.Timer
+	fired atomic.Bool
+}
+
+func (t *localTimer) Fired() bool { return t.fired.Load() }
+func (t *localTimer) Stop()       { t.timer.Stop() }
+
+// localChild is a child run started by a local run.
+type localChild struct {
+	rt  localRuntime
+	run *localRun
+}
+
+func (c localChild) Done() bool {
+	select {
+	case <-c.run.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c localChild) Get(out any) error {
+	c.rt.yield(func() { <-c.run.done })
+	if c.run.err != nil {
+		return c.run.err
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(c.run.result, out)
+}
+
+// localDefaultRetry is Temporal's default activity retry policy, which the
+// run types rely on for every activity without a policy of its own.
+var localDefaultRetry = &temporal.RetryPolicy{
+	InitialInterval:    time.Second,
+	BackoffCoefficient: 2,
+	MaximumInterval:    100 * time.Second,
+}
+
+// callLocalActivity calls fn, each attempt bounded by attemptTimeout, until it
+// succeeds, fails with a non-retryable application error, uses up the
+// policy's attempts or ctx ends. It returns the last error. Unset fields of
+// the policy take Temporal's defaults.
+func callLocalActivity(
+	ctx context.Context,
+	policy *temporal.RetryPolicy,
+	attemptTimeout time.Duration,
+	fn func(context.Context) error,
+) error {
+	backoff := cmp.Or(policy.InitialInterval, localDefaultRetry.InitialInterval)
+	coefficient := cmp.Or(policy.BackoffCoefficient, localDefaultRetry.BackoffCoefficient)
+	maxBackoff := cmp.Or(policy.MaximumInterval, 100*backoff)
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, attemptTimeout)
+		err := fn(attemptCtx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		var appErr *temporal.ApplicationError
+		if errors.As(err, &appErr) &&
+			(appErr.NonRetryable() || slices.Contains(policy.NonRetryableErrorTypes, appErr.Type())) {
+			return err
+		}
+		if policy.MaximumAttempts > 0 && attempt >= int(policy.MaximumAttempts) {
+			return err
+		}
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+		backoff = min(time.Duration(float64(backoff)*coefficient), maxBackoff)
+	}
+}
+
+// ─── Run types ───────────────────────────────────────────────────────────────
+
+// localRunFunc executes one run type on rt. input is the JSON encoding of the
+// value passed to StartRun.
+type localRunFunc func(rt runtime, input json.RawMessage) (any, error)
+
+// localRunType returns the run type the Temporal worker registers under
+// runType, decoding its input from JSON.
+func localRunType(runType string) (localRunFunc, bool) {
+	switch runType {
+	case migrationLifecycle.name:
+		return decodeInput(func(rt runtime, m api.MigrationManifest) (any, error) {
+			return orchestrate(rt, m, migrationLifecycle)
+		}), true
+	case migrations.RollbackRunType:
+		return decodeInput(func(rt runtime, m api.MigrationManifest) (any, error) {
+			return orchestrate(rt, m, rollbackLifecycle)
+		}), true
+	case migrations.BulkStartRunType:
+		return decodeInput(func(rt runtime, input migrations.BulkStartInput) (any, error) {
+			return bulkStart(rt, input)
+		}), true
+	case migrations.ScheduledStartRunType:
+		return decodeInput(func(rt runtime, input migrations.ScheduledStartInput) (any, error) {
+			return nil, scheduledStart(rt, input)
+		}), true
+	}
+	return nil, false
+}
+
+// decodeInput adapts run to take its input as JSON.
+func decodeInput[T any](run func(runtime, T) (any, error)) localRunFunc {
+	return func(rt runtime, raw json.RawMessage) (any, error) {
+		var input T
+		if err := json.Unmarshal(raw, &input); err != nil {
+			return nil, fmt.Errorf("decode input: %w", err)
+		}
+		return run(rt, input)
+	}
+}
@@ -0,0 +1,31 @@
+package execution
+
+import (
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+var rollbackLifecycle = runLifecycle{
+	name:           migrations.RollbackRunType,
+	startedEvent:   migrations.EventRollbackStarted,
+	completedEvent: migrations.EventRollbackCompleted,
+	cancelledEvent: migrations.EventRollbackCancelled,
+	finalStatus:    string(api.CandidateStatusNotStarted),
+}
+
+// RollbackOrchestrator is the Temporal workflow that undoes a candidate's run.
+//
+// Its manifest is built by migrations.BuildRollbackManifest and holds one
+// compensating step per step that succeeded, already in the order they must
+// run. The steps are dispatched exactly like those of a MigrationOrchestrator
+// run — callbacks, pause, retry, skip and timeouts all behave the same — and
+// progress is exposed through the same "progress" query. Once every
+// compensation has finished the candidate is returned to not_started.
+func RollbackOrchestrator(
+	ctx workflow.Context,
+	manifest api.MigrationManifest,
+) (MigrationResult, error) {
+	return orchestrate(temporalRuntime{ctx: ctx}, manifest, rollbackLifecycle)
+}
@@ -0,0 +1,117 @@
+package execution
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// runtime is what the run types need from the engine executing them. The
+// orchestration in workflow.go, rollback.go, bulk.go and schedule.go is
+// written once against it: temporalRuntime runs it as a Temporal workflow for
+// the worker, localRuntime in-process for LocalEngine.
+//
+// A runtime is a scope, like a workflow.Context. It is cancelled with the run
+// or by the cancel function WithCancel returned, and a blocking call on it
+// returns once it is cancelled. Only one of a run's coroutines executes at a
+// time and they only switch inside blocking calls, so run state needs no locks.
+type runtime interface {
+	// RunID is the ID the run was started under; it is the callback ID of
+	// every step it dispatches.
+	RunID() string
+	// Now is the run's current time; in a Temporal run it is replay-safe.
+	Now() time.Time
+	Logger() logger
+
+	// Err is non-nil once the scope is cancelled.
+	Err() error
+	// WithCancel returns a child scope and the function that cancels it.
+	WithCancel() (runtime, func())
+	// Disconnected returns a scope that is not cancelled with this one, so
+	// cleanup can still run after the run is cancelled.
+	Disconnected() runtime
+	// Go runs fn as a new coroutine of the run.
+	Go(fn func(rt runtime))
+
+	// Sleep blocks for d. It returns an error if the scope is cancelled first.
+	Sleep(d time.Duration) error
+	// NewTimer starts a timer that fires after d unless stopped first.
+	NewTimer(d time.Duration) timer
+	// Await blocks until cond holds, and returns an error instead if the
+	// scope is cancelled first. cond must not block or change run state; it
+	// is evaluated again whenever the run's state may have changed.
+	Await(cond func() bool) error
+	// Signal returns the channel of the named signal.
+	Signal(name string) signal
+
+	// ExecuteActivity runs the named method of Activities with input and
+	// stores its result, if it has one, in out.
+	ExecuteActivity(name string, opts activityOptions, input, out any) error
+	// ExecuteLocalActivity runs the named method of Activities once, for
+	// bookkeeping the run does not wait on durably.
+	ExecuteLocalActivity(name string, timeout time.Duration, input any) error
+	// StartChild starts a run of runType with ID id and returns once it has
+	// started. The child outlives its parent.
+	StartChild(runType, id string, input any) (childRun, error)
+
+	// GetVersion returns the version of changeID the run follows, at most
+	// maxSupported. Runs that started before the change replay with
+	// workflow.DefaultVersion; new runs get maxSupported.
+	GetVersion(changeID string, maxSupported workflow.Version) workflow.Version
+	// SetQuery registers the handler of the named query.
+	SetQuery(name string, handler func() any) error
+}
+
+// activityOptions bound one activity call: each attempt by timeout, the
+// attempts by retry (the engine's default policy when nil).
+type activityOptions struct {
+	timeout time.Duration
+	retry   *temporal.RetryPolicy
+}
+
+// signal is the channel of one named signal. Payloads queue until received.
+type signal interface {
+	// Pending reports whether a payload is waiting.
+	Pending() bool
+	// Receive takes the oldest payload, decoding it into out unless out is
+	// nil. It does not block: it returns false when nothing is waiting.
+	Receive(out any) bool
+}
+
+// timer fires once, after the duration it was started with.
+type timer interface {
+	Fired() bool
+	Stop()
+}
+
+// childRun is a run started by StartChild.
+type childRun interface {
+	// Done reports whether the child has closed.
+	Done() bool
+	// Get waits for the child to close and decodes its result into out.
+	Get(out any) error
+}
+
+// logger is the part of the Temporal workflow logger and slog.Logger the run
+// types log through.
+type logger interface {
+	Info(msg string, keyvals ...any)
+	Warn(msg string, keyvals ...any)
+}
+
+// awaitAny blocks until one of signals has a payload waiting or t (if non-nil)
+// has fired. It returns an error if the scope is cancelled first.
+func awaitAny(rt runtime, t timer, signals ...signal) error {
+	return rt.Await(func() bool {
+		if t != nil && t.Fired() {
+			return true
+		}
+		for _, s := range signals {
+			if s.Pending() {
+				return true
+			}
+		}
+		return false
+	})
+}
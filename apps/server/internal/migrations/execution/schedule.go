@@ -0,0 +1,31 @@
+package execution
+
+import (
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+)
+
+// ScheduledStartOrchestrator is the Temporal workflow that waits for a
+// scheduled start's time and then fires it.
+//
+// The wait is a durable timer, so it survives worker restarts and deploys.
+// Firing is left to the FireScheduledStart activity, which starts the run
+// through the service so the same guards apply as when an operator starts it.
+// Cancelling the run cancels the wait; the start itself is marked cancelled
+// by the service first, so a run that is not cancelled in time fires nothing.
+func ScheduledStartOrchestrator(ctx workflow.Context, input migrations.ScheduledStartInput) error {
+	return scheduledStart(temporalRuntime{ctx: ctx}, input)
+}
+
+// scheduledStart runs a ScheduledStartOrchestrator on rt.
+func scheduledStart(rt runtime, input migrations.ScheduledStartInput) error {
+	rt.Logger().Info("ScheduledStartOrchestrator started", "id", input.ID, "startAt", input.StartAt)
+
+	if wait := input.StartAt.Sub(rt.Now()); wait > 0 {
+		if err := rt.Sleep(wait); err != nil {
+			return err // cancelled while waiting
+		}
+	}
+	return rt.ExecuteActivity("FireScheduledStart", fiveAttempts, input.ID, nil)
+}
@@ -0,0 +1,63 @@
+package execution_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/migrations/execution"
+)
+
+func TestScheduledStartOrchestrator_FiresAtStartTime(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+	startAt := env.Now().Add(2 * time.Hour)
+	var firedID string
+	var firedAt time.Time
+	env.OnActivity(acts.FireScheduledStart, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			firedID = args.String(1)
+			firedAt = env.Now()
+		})
+
+	env.ExecuteWorkflow(execution.ScheduledStartOrchestrator, migrations.ScheduledStartInput{
+		ID:      "schedule__mig-abc__1",
+		StartAt: startAt,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, "schedule__mig-abc__1", firedID)
+	require.False(t, firedAt.Before(startAt), "fired at %s, before %s", firedAt, startAt)
+}
+
+func TestScheduledStartOrchestrator_CancelledBeforeStartTime_DoesNotFire(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+	fired := false
+	env.OnActivity(acts.FireScheduledStart, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(mock.Arguments) { fired = true }).
+		Maybe()
+	env.RegisterDelayedCallback(env.CancelWorkflow, time.Hour)
+
+	env.ExecuteWorkflow(execution.ScheduledStartOrchestrator, migrations.ScheduledStartInput{
+		ID:      "schedule__mig-abc__1",
+		StartAt: env.Now().Add(2 * time.Hour),
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.Error(t, env.GetWorkflowError())
+	require.False(t, fired)
+}
@@ -0,0 +1,112 @@
+package execution
+
+import (
+	"time"
+
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Compile-time check: temporalRuntime implements runtime.
+var _ runtime = temporalRuntime{}
+
+// temporalRuntime runs the run types as Temporal workflows. Every call maps
+// onto the workflow API, so the timers, activities and child workflows a run
+// records in its history are those of the workflow it replays.
+type temporalRuntime struct {
+	ctx workflow.Context
+}
+
+func (r temporalRuntime) RunID() string  { return workflow.GetInfo(r.ctx).WorkflowExecution.ID }
+func (r temporalRuntime) Now() time.Time { return workflow.Now(r.ctx) }
+func (r temporalRuntime) Logger() logger { return workflow.GetLogger(r.ctx) }
+func (r temporalRuntime) Err() error     { return r.ctx.Err() }
+
+func (r temporalRuntime) WithCancel() (runtime, func()) {
+	ctx, cancel := workflow.WithCancel(r.ctx)
+	return temporalRuntime{ctx: ctx}, cancel
+}
+
+func (r temporalRuntime) Disconnected() runtime {
+	ctx, _ := workflow.NewDisconnectedContext(r.ctx)
+	return temporalRuntime{ctx: ctx}
+}
+
+func (r temporalRuntime) Go(fn func(rt runtime)) {
+	workflow.Go(r.ctx, func(ctx workflow.Context) { fn(temporalRuntime{ctx: ctx}) })
+}
+
+func (r temporalRuntime) Sleep(d time.Duration) error { return workflow.Sleep(r.ctx, d) }
+
+func (r temporalRuntime) NewTimer(d time.Duration) timer {
+	ctx, cancel := workflow.WithCancel(r.ctx)
+	return temporalTimer{ctx: ctx, future: workflow.NewTimer(ctx, d), cancel: cancel}
+}
+
+func (r temporalRuntime) Await(cond func() bool) error { return workflow.Await(r.ctx, cond) }
+
+func (r temporalRuntime) Signal(name string) signal {
+	return temporalSignal{ch: workflow.GetSignalChannel(r.ctx, name)}
+}
+
+func (r temporalRuntime) ExecuteActivity(name string, opts activityOptions, input, out any) error {
+	ctx := workflow.WithActivityOptions(r.ctx, workflow.ActivityOptions{
+		TaskQueue:           workflow.GetInfo(r.ctx).TaskQueueName,
+		StartToCloseTimeout: opts.timeout,
+		RetryPolicy:         opts.retry,
+	})
+	return workflow.ExecuteActivity(ctx, name, input).Get(r.ctx, out)
+}
+
+func (r temporalRuntime) ExecuteLocalActivity(name string, timeout time.Duration, input any) error {
+	ctx := workflow.WithLocalActivityOptions(r.ctx, workflow.LocalActivityOptions{ScheduleToCloseTimeout: timeout})
+	return workflow.ExecuteLocalActivity(ctx, name, input).Get(ctx, nil)
+}
+
+// StartChild starts the child on a disconnected context and abandons it when
+// the parent closes.
+func (r temporalRuntime) StartChild(runType, id string, input any) (childRun, error) {
+	ctx, _ := workflow.NewDisconnectedContext(r.ctx)
+	ctx = workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{
+		WorkflowID:        id,
+		TaskQueue:         workflow.GetInfo(r.ctx).TaskQueueName,
+		ParentClosePolicy: enumspb.PARENT_CLOSE_POLICY_ABANDON,
+	})
+	future := workflow.ExecuteChildWorkflow(ctx, runType, input)
+	if err := future.GetChildWorkflowExecution().Get(r.ctx, nil); err != nil {
+		return nil, err
+	}
+	return temporalChild{ctx: r.ctx, future: future}, nil
+}
+
+func (r temporalRuntime) GetVersion(changeID string, maxSupported workflow.Version) workflow.Version {
+	return workflow.GetVersion(r.ctx, changeID, workflow.DefaultVersion, maxSupported)
+}
+
+func (r temporalRuntime) SetQuery(name string, handler func() any) error {
+	return workflow.SetQueryHandler(r.ctx, name, func() (any, error) { return handler(), nil })
+}
+
+type temporalTimer struct {
+	ctx    workflow.Context
+	future workflow.Future
+	cancel func()
+}
+
+func (t temporalTimer) Fired() bool { return t.future.IsReady() && t.future.Get(t.ctx, nil) == nil }
+func (t temporalTimer) Stop()       { t.cancel() }
+
+type temporalSignal struct {
+	ch workflow.ReceiveChannel
+}
+
+func (s temporalSignal) Pending() bool        { return s.ch.Len() > 0 }
+func (s temporalSignal) Receive(out any) bool { return s.ch.ReceiveAsync(out) }
+
+type temporalChild struct {
+	ctx    workflow.Context
+	future workflow.ChildWorkflowFuture
+}
+
+func (c temporalChild) Done() bool        { return c.future.IsReady() }
+func (c temporalChild) Get(out any) error { return c.future.Get(c.ctx, out) }
@@ -1,25 +1,41 @@
 package execution
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"math"
+	"slices"
+	"strconv"
 	"time"
 
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
 
 	"github.com/tilsley/loom/apps/server/internal/migrations"
 	"github.com/tilsley/loom/pkg/api"
 )
 
-// localActivityOptions for fire-and-forget event recording.
-var localActOpts = workflow.LocalActivityOptions{
-	ScheduleToCloseTimeout: 5 * time.Second,
-}
+// recordEventTimeout bounds fire-and-forget event recording.
+const recordEventTimeout = 5 * time.Second
+
+// Options of the activities a run makes. Those without a retry policy are
+// retried until their timeout runs out.
+var (
+	// runActivity is for bookkeeping that must eventually succeed.
+	runActivity = activityOptions{timeout: 24 * time.Hour}
+	// shortActivity is for quick bookkeeping, such as the cleanup after a run
+	// failed or was cancelled.
+	shortActivity = activityOptions{timeout: 30 * time.Second}
+	// fiveAttempts and threeAttempts are for calls the run can do without.
+	fiveAttempts  = activityOptions{timeout: 30 * time.Second, retry: &temporal.RetryPolicy{MaximumAttempts: 5}}
+	threeAttempts = activityOptions{timeout: 30 * time.Second, retry: &temporal.RetryPolicy{MaximumAttempts: 3}}
+)
 
 // recordEvent fires a local activity to persist a lifecycle event.
 // It is fire-and-forget: errors are logged but never block the workflow.
-func recordEvent(ctx workflow.Context, event migrations.StepEvent) {
-	laCtx := workflow.WithLocalActivityOptions(ctx, localActOpts)
-	_ = workflow.ExecuteLocalActivity(laCtx, "RecordEvent", event).Get(laCtx, nil)
+func recordEvent(rt runtime, event migrations.StepEvent) {
+	_ = rt.ExecuteLocalActivity("RecordEvent", recordEventTimeout, event)
 }
 
 // MigrationResult is the return type of MigrationOrchestrator.
@@ -33,130 +49,262 @@ type MigrationResult struct {
 
 const (
 	resultRunning   = "running"
+	resultPaused    = "paused"
 	resultCompleted = "completed"
 	resultFailed    = "failed"
 )
 
 // MigrationOrchestrator is the Temporal workflow that sequences a full migration.
 //
-// For each step in the manifest it iterates candidate repos sequentially:
+// Steps run as a dependency graph (see migrations.StepDependencies): a step
+// starts once every step it depends on has finished, so independent steps run
+// concurrently. For each step it iterates candidate repos sequentially:
 //  1. Dispatches the step to a migrator via the DispatchStep activity.
 //  2. Waits for a "step-completed" signal from the migrator.
-//  3. Records the result and advances to the next candidate/step.
+//  3. Records the result and advances to the next candidate.
+//
+// Pause and resume signals hold the run between steps: a step already in flight
+// runs to completion, but the next one is not dispatched until the run is resumed.
 //
-// A query handler ("progress") exposes accumulated results in real-time.
+// A query handler ("progress") exposes accumulated results in real-time, in
+// manifest step order regardless of the order concurrent steps started in.
 func MigrationOrchestrator(
 	ctx workflow.Context,
 	manifest api.MigrationManifest,
 ) (MigrationResult, error) {
-	workflow.GetLogger(ctx).Info("MigrationOrchestrator started", "migrationId", manifest.MigrationId, "steps", len(manifest.Steps), "candidates", len(manifest.Candidates))
+	return orchestrate(temporalRuntime{ctx: ctx}, manifest, migrationLifecycle)
+}
+
+// runLifecycle describes how a kind of run reports its start and end: the
+// events it records and the candidate status it leaves behind on success.
+type runLifecycle struct {
+	name           string
+	startedEvent   string
+	completedEvent string
+	cancelledEvent string
+	finalStatus    string
+}
+
+var migrationLifecycle = runLifecycle{
+	name:           "MigrationOrchestrator",
+	startedEvent:   migrations.EventRunStarted,
+	completedEvent: migrations.EventRunCompleted,
+	cancelledEvent: migrations.EventRunCancelled,
+	finalStatus:    resultCompleted,
+}
+
+// orchestrate runs the manifest's step graph for its candidates, recording
+// lifecycle events and candidate status as described by lc.
+func orchestrate(rt runtime, manifest api.MigrationManifest, lc runLifecycle) (MigrationResult, error) {
+	rt.Logger().Info(lc.name+" started", "migrationId", manifest.MigrationId, "steps", len(manifest.Steps), "candidates", len(manifest.Candidates))
+
+	if err := migrations.ValidateStepGraph(manifest.Steps); err != nil {
+		return MigrationResult{}, err
+	}
 
 	results := make([]api.StepState, 0, len(manifest.Steps)*len(manifest.Candidates))
+	pause := newPauseGate(rt, manifest)
 
-	if err := workflow.SetQueryHandler(ctx, "progress", func() (MigrationResult, error) {
+	if err := rt.SetQuery("progress", func() any {
+		status := resultRunning
+		if pause.paused {
+			status = resultPaused
+		}
 		return MigrationResult{
 			MigrationId: manifest.MigrationId,
-			Status:      resultRunning,
-			Results:     results,
-		}, nil
+			Status:      status,
+			Results:     orderResults(manifest, results),
+		}
 	}); err != nil {
 		return MigrationResult{}, fmt.Errorf("register query handler: %w", err)
 	}
 
-	runStartTime := workflow.Now(ctx)
+	runStartTime := rt.Now()
 
 	// Record run_started event.
-	recordEvent(ctx, migrations.StepEvent{
+	recordEvent(rt, migrations.StepEvent{
 		MigrationID: manifest.MigrationId,
 		CandidateID: candidateID(manifest),
-		EventType:   migrations.EventRunStarted,
-	})
-
-	actCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
-		TaskQueue:           workflow.GetInfo(ctx).TaskQueueName,
-		StartToCloseTimeout: 24 * time.Hour,
+		EventType:   lc.startedEvent,
 	})
 
 	var failed bool
 	defer func() {
 		if failed {
 			// Record run_cancelled event with duration.
-			dur := int(workflow.Now(ctx).Sub(runStartTime).Milliseconds())
-			recordEvent(ctx, migrations.StepEvent{
+			dur := int(rt.Now().Sub(runStartTime).Milliseconds())
+			recordEvent(rt, migrations.StepEvent{
 				MigrationID: manifest.MigrationId,
 				CandidateID: candidateID(manifest),
-				EventType:   migrations.EventRunCancelled,
+				EventType:   lc.cancelledEvent,
 				DurationMs:  &dur,
 			})
 
-			// Use a disconnected context so the cleanup activity can run even if the
-			// workflow context has been cancelled (e.g. operator hit Cancel).
+			// Use a disconnected scope so the cleanup activity can run even if the
+			// run has been cancelled (e.g. operator hit Cancel).
 			// Without this, ExecuteActivity on a cancelled ctx returns immediately
 			// without scheduling, leaving the workflow stuck in RUNNING state forever.
-			cleanupCtx, _ := workflow.NewDisconnectedContext(ctx)
-			cleanupActCtx := workflow.WithActivityOptions(cleanupCtx, workflow.ActivityOptions{
-				TaskQueue:           workflow.GetInfo(ctx).TaskQueueName,
-				StartToCloseTimeout: 30 * time.Second,
-			})
-			// No saga compensation — PRs cannot be automatically undone.
-			runResetCandidate(cleanupActCtx, cleanupCtx, manifest)
-		}
-	}()
+			cleanup := rt.Disconnected()
+			// Nothing is undone here; an operator can start a rollback run
+			// that dispatches each step's compensation instead.
+			runResetCandidate(cleanup, manifest)
 
-	for _, step := range manifest.Steps {
-		for i := range manifest.Candidates {
-			ok, err := processStep(ctx, actCtx, manifest, step, &manifest.Candidates[i], &results)
-			if err != nil {
-				failed = true
-				return MigrationResult{}, err
-			}
-			if !ok {
-				failed = true
-				return MigrationResult{
-					MigrationId: manifest.MigrationId,
-					Status:      resultFailed,
-					Results:     results,
-				}, nil
+			outcome := api.RunAttemptStatusFailed
+			if rt.Err() != nil {
+				outcome = api.RunAttemptStatusCancelled
 			}
+			runFinishAttempt(cleanup, shortActivity, outcome, orderResults(manifest, results))
 		}
+	}()
+
+	ok, err := runStepGraph(rt, manifest, &results, pause)
+	if err != nil {
+		failed = true
+		return MigrationResult{}, err
+	}
+	if !ok {
+		failed = true
+		return MigrationResult{
+			MigrationId: manifest.MigrationId,
+			Status:      resultFailed,
+			Results:     orderResults(manifest, results),
+		}, nil
 	}
 
-	runUpdateCandidateStatus(actCtx, ctx, manifest, resultCompleted)
+	runUpdateCandidateStatus(rt, manifest, lc.finalStatus)
+	runFinishAttempt(rt, runActivity, api.RunAttemptStatusCompleted, orderResults(manifest, results))
 
 	// Record run_completed event with total duration.
-	runDur := int(workflow.Now(ctx).Sub(runStartTime).Milliseconds())
-	recordEvent(ctx, migrations.StepEvent{
+	runDur := int(rt.Now().Sub(runStartTime).Milliseconds())
+	recordEvent(rt, migrations.StepEvent{
 		MigrationID: manifest.MigrationId,
 		CandidateID: candidateID(manifest),
-		EventType:   migrations.EventRunCompleted,
+		EventType:   lc.completedEvent,
 		DurationMs:  &runDur,
 	})
 
 	return MigrationResult{
 		MigrationId: manifest.MigrationId,
 		Status:      resultCompleted,
-		Results:     results,
+		Results:     orderResults(manifest, results),
 	}, nil
 }
 
+// runStepGraph runs the manifest's steps in dependency order. Every step whose
+// dependencies have finished is started in its own coroutine, so independent
+// steps run concurrently and a step with several dependencies joins on all of
+// them. The first step that is cancelled or fails to dispatch stops the graph:
+// no further steps are started and those still in flight are cancelled.
+// Returns the same values as processStep.
+func runStepGraph(
+	rt runtime,
+	manifest api.MigrationManifest,
+	results *[]api.StepState,
+	pause *pauseGate,
+) (bool, error) {
+	deps := migrations.StepDependencies(manifest.Steps)
+	graph, cancelGraph := rt.WithCancel()
+	defer cancelGraph()
+
+	done := make(map[string]bool, len(manifest.Steps))
+	started := make(map[string]bool, len(manifest.Steps))
+	var stopped bool
+	var stopErr error
+	inFlight, finished := 0, 0
+
+	for {
+		for _, step := range manifest.Steps {
+			if stopped || started[step.Name] || !migrations.DependenciesDone(deps[step.Name], done) {
+				continue
+			}
+			started[step.Name] = true
+			inFlight++
+			graph.Go(func(rt runtime) {
+				defer func() {
+					inFlight--
+					finished++
+				}()
+				for i := range manifest.Candidates {
+					ok, err := processStep(rt, manifest, step, &manifest.Candidates[i], results, pause)
+					if err != nil || !ok {
+						if !stopped {
+							stopped, stopErr = true, err
+							cancelGraph()
+						}
+						return
+					}
+				}
+				done[step.Name] = true
+			})
+		}
+		if inFlight == 0 {
+			return !stopped, stopErr
+		}
+
+		// Wait on a disconnected scope so a cancelled run still joins every
+		// coroutine before returning; they unwind through graph.
+		seen := finished
+		_ = rt.Disconnected().Await(func() bool { return finished > seen })
+	}
+}
+
 // processStep runs the retry loop for a single step+candidate pair.
+// A step whose when expression does not hold is marked skipped without being
+// dispatched.
+// A failed step is re-dispatched automatically while the step's RetryPolicy
+// has attempts left, and otherwise waits for the operator to retry or skip it.
+// Each dispatch carries its attempt number; callbacks repeating an event ID or
+// answering an earlier attempt are discarded (see callbackGuard).
+// A dispatch the migrator refuses for good, or that still fails once the step's
+// DispatchRetryPolicy is used up, fails the step like a failed callback would.
 // Returns (true, nil) on success, (false, nil) if the operator cancels while
-// waiting for a retry, and (false, err) if the DispatchStep activity fails.
+// waiting for a retry or while paused, and (false, err) if the run is cancelled
+// during a dispatch.
 func processStep(
-	ctx, actCtx workflow.Context,
+	rt runtime,
 	manifest api.MigrationManifest,
 	step api.StepDefinition,
 	candidate *api.Candidate,
 	results *[]api.StepState,
+	pause *pauseGate,
 ) (bool, error) {
-	callbackID := workflow.GetInfo(ctx).WorkflowExecution.ID
+	callbackID := rt.RunID()
 	stepCompletedSignal := migrations.StepEventName(step.Name, candidate.Id)
 
-	stepCompletedCh := workflow.GetSignalChannel(ctx, stepCompletedSignal)
-	retryCh := workflow.GetSignalChannel(ctx, migrations.RetryStepEventName(step.Name, candidate.Id))
-	updateInputsCh := workflow.GetSignalChannel(ctx, migrations.UpdateInputsEventName(candidate.Id))
+	stepCompletedCh := rt.Signal(stepCompletedSignal)
+	retryCh := rt.Signal(migrations.RetryStepEventName(step.Name, candidate.Id))
+	skipCh := rt.Signal(migrations.SkipStepEventName(step.Name, candidate.Id))
+	updateInputsCh := rt.Signal(migrations.UpdateInputsEventName(candidate.Id))
+
+	if step.When != nil {
+		run, err := migrations.EvaluateWhen(*step.When, whenContext(*results, *candidate))
+		if err != nil {
+			return false, fmt.Errorf("evaluate when for step %q: %w", step.Name, err)
+		}
+		if !run {
+			skipped := skippedState(currentResult(*results, step.Name, *candidate), "condition not met: "+*step.When)
+			upsertResult(results, skipped)
+			recordStepSkipped(rt, manifest, skipped)
+			return true, nil
+		}
+	}
 
+	guard := &callbackGuard{migrationID: manifest.MigrationId, seen: make(map[string]bool)}
+	attempt := 1
 	for {
+		guard.attempt = attempt
+		slot := &dispatchSlot{
+			migratorApp: step.MigratorApp,
+			holder:      migrations.DispatchSlotHolder(callbackID, step.Name, candidate.Id, attempt),
+		}
+
+		// Hold here while the run is paused, a freeze window covers the step or
+		// its migrator app is at its dispatch limits; the step is only
+		// dispatched once resumed, outside every window and holding a slot.
+		if ok, err := awaitDispatchAllowed(rt, manifest, step, *candidate, results, pause, slot); !ok {
+			return false, err // cancelled while held, or the calendar or limiter could not be read
+		}
+
 		// Drain any pending input updates before building the dispatch request
 		// so that metadata edits made while the workflow was waiting take effect.
 		drainInputUpdates(updateInputsCh, candidate)
@@ -179,39 +327,42 @@ func processStep(
 			EventName:   stepCompletedSignal,
 			MigratorApp: step.MigratorApp,
 			MigratorUrl: manifest.MigratorUrl,
+			Attempt:     &attempt,
 		}
-		stepStart := workflow.Now(ctx)
-		if err := workflow.ExecuteActivity(actCtx, "DispatchStep", req).Get(ctx, nil); err != nil {
-			return false, fmt.Errorf("dispatch step %q for %q: %w", step.Name, candidate.Id, err)
-		}
-
-		// Record step_dispatched.
-		recordEvent(ctx, migrations.StepEvent{
-			MigrationID: manifest.MigrationId,
-			CandidateID: candidate.Id,
-			StepName:    step.Name,
-			EventType:   migrations.EventStepDispatched,
-		})
+		stepStart := rt.Now()
+		var last api.StepState
+		if err := rt.ExecuteActivity("DispatchStep", dispatchActivityOptions(step.DispatchRetry), req, nil); err != nil {
+			slot.release(rt)
+			if rt.Err() != nil || rt.GetVersion(dispatchFailureChange, 1) < 1 {
+				return false, fmt.Errorf("dispatch step %q for %q: %w", step.Name, candidate.Id, err)
+			}
+			// The migrator refused the step for good or stayed unreachable
+			// through the dispatch retries: fail the step, not the run.
+			last = dispatchFailedState(step.Name, *candidate, err)
+			upsertResult(results, last)
+		} else {
+			// Record step_dispatched.
+			recordEvent(rt, migrations.StepEvent{
+				MigrationID: manifest.MigrationId,
+				CandidateID: candidate.Id,
+				StepName:    step.Name,
+				EventType:   migrations.EventStepDispatched,
+			})
 
-		// Keep receiving signals until the step reaches a terminal state.
-		// "pending" is intermediate — keep waiting for the final signal.
-		// awaitStepCompletion returns false if the workflow was cancelled mid-wait,
-		// in which case it does NOT append to results (safe to return immediately).
-		// When it returns true it has always appended, so results is non-empty.
-		for {
-			if !awaitStepCompletion(ctx, stepCompletedCh, *candidate, results) {
+			if !waitForStepResult(rt, manifest, step, *candidate, stepCompletedCh, skipCh, guard, results, slot) {
 				return false, nil // cancelled while waiting for step signal
 			}
-			last := (*results)[len(*results)-1]
-			if last.Status != api.StepStateStatusPending {
-				break
+
+			// waitForStepResult has always upserted a terminal result for this step.
+			last = currentResult(*results, step.Name, *candidate)
+			if last.Status == api.StepStateStatusSkipped {
+				recordStepSkipped(rt, manifest, last)
+				return true, nil
 			}
 		}
 
-		last := (*results)[len(*results)-1]
-
 		// Record step_completed with duration and status.
-		stepDur := int(workflow.Now(ctx).Sub(stepStart).Milliseconds())
+		stepDur := int(rt.Now().Sub(stepStart).Milliseconds())
 		stepStatus := string(last.Status)
 		stepMeta := make(map[string]string)
 		if last.Metadata != nil {
@@ -219,7 +370,7 @@ func processStep(
 				stepMeta[k] = v
 			}
 		}
-		recordEvent(ctx, migrations.StepEvent{
+		recordEvent(rt, migrations.StepEvent{
 			MigrationID: manifest.MigrationId,
 			CandidateID: candidate.Id,
 			StepName:    step.Name,
@@ -234,17 +385,32 @@ func processStep(
 		}
 
 		// Leave the failed result visible in the query while waiting for retry/cancel
-		// so the UI can show the failed state and retry button.
-		if !awaitRetryOrCancel(ctx, retryCh) {
+		// so the UI can show the failed state and retry button. With attempts left
+		// under the retry policy, a durable timer retries it without the operator.
+		backoff, autoRetry := retryBackoff(step.RetryPolicy, attempt)
+		action, skip := awaitRetryOrCancel(rt, retryCh, skipCh, backoff, autoRetry)
+		if action == failedStepCancelled {
 			return false, nil // operator cancelled while waiting for retry
 		}
+		if action == failedStepSkipped {
+			skipped := skippedState(last, skip.Reason)
+			upsertResult(results, skipped)
+			recordStepSkipped(rt, manifest, skipped)
+			return true, nil
+		}
 
-		// Record step_retried.
-		recordEvent(ctx, migrations.StepEvent{
+		// Record step_retried with the number of the attempt about to be dispatched.
+		attempt++
+		retryMeta := map[string]string{"attempt": strconv.Itoa(attempt)}
+		if action == failedStepAutoRetried {
+			retryMeta["automatic"] = "true"
+		}
+		recordEvent(rt, migrations.StepEvent{
 			MigrationID: manifest.MigrationId,
 			CandidateID: candidate.Id,
 			StepName:    step.Name,
 			EventType:   migrations.EventStepRetried,
+			Metadata:    retryMeta,
 		})
 
 		// Retry signal received: clear the failed result before re-dispatching.
@@ -252,13 +418,305 @@ func processStep(
 	}
 }
 
+// pauseGate tracks the operator pause/resume state of a run. Signals are
+// received by a background coroutine so a pause takes effect even while the
+// main loop is blocked waiting on a step callback.
+type pauseGate struct {
+	paused bool
+}
+
+// pauseOrderChange gates applying every pause and resume waiting on a run
+// before its events are recorded, so that runs started before it existed replay
+// applying them one at a time.
+const pauseOrderChange = "pause-order"
+
+// newPauseGate starts listening for pause and resume signals for the run's
+// candidate, recording run_paused/run_resumed on each state change. They arrive
+// on one PauseStateEventName signal and are applied in the order they were sent,
+// all that are waiting at once, so that the run never acts on a state a later
+// signal has already replaced. The older separate pause and resume signals are
+// still heard for runs that received them before it existed.
+func newPauseGate(rt runtime, manifest api.MigrationManifest) *pauseGate {
+	g := &pauseGate{}
+	cid := candidateID(manifest)
+	stateCh := rt.Signal(migrations.PauseStateEventName(cid))
+	pauseCh := rt.Signal(migrations.PauseEventName(cid))
+	resumeCh := rt.Signal(migrations.ResumeEventName(cid))
+	applyAll := rt.GetVersion(pauseOrderChange, 1) >= 1
+
+	rt.Go(func(rt runtime) {
+		for awaitAny(rt, nil, stateCh, pauseCh, resumeCh) == nil {
+			var changes []string
+			for {
+				paused, ok := receivePauseState(stateCh, pauseCh, resumeCh)
+				if !ok {
+					break
+				}
+				if rt.Err() == nil && paused != g.paused {
+					g.paused = paused
+					eventType := migrations.EventRunResumed
+					if paused {
+						eventType = migrations.EventRunPaused
+					}
+					changes = append(changes, eventType)
+				}
+				if !applyAll {
+					break
+				}
+			}
+			for _, eventType := range changes {
+				recordEvent(rt, migrations.StepEvent{
+					MigrationID: manifest.MigrationId,
+					CandidateID: cid,
+					EventType:   eventType,
+				})
+			}
+		}
+	})
+	return g
+}
+
+// receivePauseState takes the next pause or resume waiting on the run and
+// reports whether the run should be paused. ok is false when none is waiting.
+func receivePauseState(stateCh, pauseCh, resumeCh signal) (paused, ok bool) {
+	var state migrations.PauseState
+	switch {
+	case stateCh.Receive(&state):
+		return state.Paused, true
+	case pauseCh.Receive(nil):
+		return true, true
+	default:
+		return false, resumeCh.Receive(nil)
+	}
+}
+
+// wait blocks while the run is paused. Returns false if the workflow was
+// cancelled before it was resumed.
+func (g *pauseGate) wait(rt runtime) bool {
+	if !g.paused {
+		return true
+	}
+	rt.Logger().Info("run paused, holding next dispatch")
+	return rt.Await(func() bool { return !g.paused }) == nil
+}
+
+// dispatchFailureChange gates failing the step, rather than the run, when a
+// step cannot be dispatched, so that runs started before it existed replay
+// failing the run.
+const dispatchFailureChange = "dispatch-failure"
+
+// freezeWindowCheckChange gates the freeze window check, so that runs started
+// before it existed replay without it.
+const freezeWindowCheckChange = "freeze-window-check"
+
+// freezeRecheckInterval bounds how long a step held back by a freeze window
+// sleeps before the calendar is checked again, so that deleting a window
+// releases its steps without waiting for its original end.
+const freezeRecheckInterval = 15 * time.Minute
+
+// awaitDispatchAllowed blocks while the run is paused, a freeze window covers
+// the step or the step's migrator app is at its dispatch limits. While a window
+// covers it, the step shows as waiting_for_window and a durable timer sleeps
+// until the window ends (or freezeRecheckInterval, if sooner). While the app is
+// at its limits, the step shows as queued and sleeps for as long as the limiter
+// asks. After each wait every check is made again, since the run may have been
+// paused or another window opened in the meantime. On (true, nil) slot holds
+// the step's dispatch slot. Returns (false, nil) if the workflow was cancelled
+// while waiting and (false, err) if the CheckFreezeWindows or
+// AcquireDispatchSlot activity failed.
+func awaitDispatchAllowed(
+	rt runtime,
+	manifest api.MigrationManifest,
+	step api.StepDefinition,
+	candidate api.Candidate,
+	results *[]api.StepState,
+	pause *pauseGate,
+	slot *dispatchSlot,
+) (bool, error) {
+	held, queued := false, false
+	for {
+		if !pause.wait(rt) {
+			return false, nil
+		}
+
+		var check CheckFreezeWindowsResult
+		if rt.GetVersion(freezeWindowCheckChange, 1) >= 1 {
+			input := CheckFreezeWindowsInput{
+				MigrationID: manifest.MigrationId,
+				StepConfig:  derefMetadata(step.Config),
+				At:          rt.Now(),
+			}
+			if err := rt.ExecuteActivity("CheckFreezeWindows", runActivity, input, &check); err != nil {
+				if rt.Err() != nil {
+					return false, nil
+				}
+				return false, fmt.Errorf("check freeze windows for step %q: %w", step.Name, err)
+			}
+		}
+		if check.Window == nil {
+			wait, err := slot.acquire(rt)
+			if err != nil {
+				if rt.Err() != nil {
+					return false, nil
+				}
+				return false, fmt.Errorf("acquire dispatch slot for step %q: %w", step.Name, err)
+			}
+			if wait == nil {
+				if held || queued {
+					// Drop the waiting result so its metadata does not carry over to the dispatch.
+					removeResult(results, step.Name, candidate.Id)
+				}
+				return true, nil
+			}
+
+			md := map[string]string{
+				"migratorApp":  step.MigratorApp,
+				"queuedReason": wait.Reason,
+			}
+			upsertResult(results, api.StepState{
+				StepName:  step.Name,
+				Candidate: candidate,
+				Status:    api.StepStateStatusQueued,
+				Metadata:  &md,
+			})
+			if !queued {
+				queued = true
+				recordEvent(rt, migrations.StepEvent{
+					MigrationID: manifest.MigrationId,
+					CandidateID: candidate.Id,
+					StepName:    step.Name,
+					EventType:   migrations.EventStepQueued,
+					Status:      string(api.StepStateStatusQueued),
+					Metadata:    md,
+				})
+			}
+			if err := rt.Sleep(max(wait.RetryAfter, time.Second)); err != nil {
+				return false, nil
+			}
+			continue
+		}
+
+		window := *check.Window
+		md := map[string]string{
+			"freezeWindowId": strconv.FormatInt(window.ID, 10),
+			"freezeReason":   window.Reason,
+			"waitingUntil":   window.EndsAt.UTC().Format(time.RFC3339),
+		}
+		upsertResult(results, api.StepState{
+			StepName:  step.Name,
+			Candidate: candidate,
+			Status:    api.StepStateStatusWaitingForWindow,
+			Metadata:  &md,
+		})
+		if !held {
+			held = true
+			recordEvent(rt, migrations.StepEvent{
+				MigrationID: manifest.MigrationId,
+				CandidateID: candidate.Id,
+				StepName:    step.Name,
+				EventType:   migrations.EventStepWaitingForWindow,
+				Status:      string(api.StepStateStatusWaitingForWindow),
+				Metadata:    md,
+			})
+		}
+
+		wait := min(window.EndsAt.Sub(rt.Now()), freezeRecheckInterval)
+		if err := rt.Sleep(max(wait, time.Second)); err != nil {
+			return false, nil
+		}
+	}
+}
+
+// dispatchSlotChange gates dispatch slots, so that runs started before dispatch
+// limits existed replay without acquiring or releasing any, and runs started
+// before slots were renewed replay without renewing them.
+const (
+	dispatchSlotChange    = "dispatch-slot"
+	dispatchSlotsAcquired = 1
+	dispatchSlotsRenewed  = 2
+)
+
+// dispatchSlot is one dispatch's share of its migrator app's dispatch limits.
+// It is held from just before the dispatch until the migrator first reports
+// back on it, or the wait for it ends some other way, and renewed every
+// migrations.DispatchSlotRenewInterval while held.
+type dispatchSlot struct {
+	migratorApp string
+	holder      string
+	held        bool   // a slot was recorded that must be released
+	stopRenew   func() // stops the renewals of a held slot
+}
+
+// acquire asks for the slot through the AcquireDispatchSlot activity. It
+// returns nil once the slot is acquired, and otherwise the refused grant, which
+// says how long to wait before asking again. Runs from before dispatch limits
+// get the slot without asking, and since none is held release does nothing.
+func (s *dispatchSlot) acquire(rt runtime) (*migrations.DispatchSlotGrant, error) {
+	version := rt.GetVersion(dispatchSlotChange, dispatchSlotsRenewed)
+	if version == workflow.DefaultVersion {
+		return nil, nil //nolint:nilnil
+	}
+	var grant migrations.DispatchSlotGrant
+	input := DispatchSlotInput{MigratorApp: s.migratorApp, Holder: s.holder}
+	if err := rt.ExecuteActivity("AcquireDispatchSlot", runActivity, input, &grant); err != nil {
+		return nil, err
+	}
+	if !grant.Acquired {
+		return &grant, nil
+	}
+	s.held = grant.Held
+	if s.held && version >= dispatchSlotsRenewed {
+		s.renew(rt)
+	}
+	return nil, nil //nolint:nilnil
+}
+
+// renew starts a coroutine that renews the held slot through the
+// RenewDispatchSlot activity until release stops it. A failed renewal is
+// logged and tried again on the next tick, which is still within the lease.
+func (s *dispatchSlot) renew(rt runtime) {
+	rt, cancel := rt.WithCancel()
+	s.stopRenew = cancel
+	input := DispatchSlotInput{MigratorApp: s.migratorApp, Holder: s.holder}
+	rt.Go(func(rt runtime) {
+		for rt.Sleep(migrations.DispatchSlotRenewInterval) == nil {
+			err := rt.ExecuteActivity("RenewDispatchSlot", threeAttempts, input, nil)
+			if err != nil && rt.Err() == nil {
+				rt.Logger().Warn("failed to renew dispatch slot", "holder", s.holder, "error", err)
+			}
+		}
+	})
+}
+
+// release frees a held slot through the ReleaseDispatchSlot activity; later
+// calls do nothing. It runs on a disconnected scope when the run has been
+// cancelled. A failed release is logged: the slot lapses
+// migrations.DispatchSlotLease after its last renewal.
+func (s *dispatchSlot) release(rt runtime) {
+	if !s.held {
+		return
+	}
+	s.held = false
+	if s.stopRenew != nil {
+		s.stopRenew()
+		s.stopRenew = nil
+	}
+	if rt.Err() != nil {
+		rt = rt.Disconnected()
+	}
+	input := DispatchSlotInput{MigratorApp: s.migratorApp, Holder: s.holder}
+	if err := rt.ExecuteActivity("ReleaseDispatchSlot", fiveAttempts, input, nil); err != nil {
+		rt.Logger().Warn("failed to release dispatch slot", "holder", s.holder, "error", err)
+	}
+}
+
 // drainInputUpdates consumes all pending update-inputs signals from the channel
-// and merges them into the candidate's metadata. ReceiveAsync is non-blocking —
+// and merges them into the candidate's metadata. Receive is non-blocking —
 // it returns false when the channel is empty.
-func drainInputUpdates(ch workflow.ReceiveChannel, candidate *api.Candidate) {
+func drainInputUpdates(ch signal, candidate *api.Candidate) {
 	for {
 		var inputs map[string]string
-		if !ch.ReceiveAsync(&inputs) {
+		if !ch.Receive(&inputs) {
 			return
 		}
 		if candidate.Metadata == nil {
@@ -271,24 +729,132 @@ func drainInputUpdates(ch workflow.ReceiveChannel, candidate *api.Candidate) {
 	}
 }
 
-// awaitStepCompletion blocks until a step-completed signal arrives or the
-// workflow is cancelled. Returns false if the workflow was cancelled before
-// a signal arrived (no result is appended in that case).
+// waitForStepResult keeps receiving signals until the step reaches a terminal
+// state. "pending" is intermediate: it is recorded once as step_pending and the
+// wait goes on. If the step declares timeoutSeconds and the deadline passes first,
+// the step is marked timed_out and escalated, and the wait goes on for a late
+// callback or an operator skip. The dispatch slot is released as soon as the
+// first of these arrives. Returns false if the workflow was cancelled mid-wait;
+// when it returns true results holds the step's terminal result.
+func waitForStepResult(
+	rt runtime,
+	manifest api.MigrationManifest,
+	step api.StepDefinition,
+	candidate api.Candidate,
+	stepCompletedCh, skipCh signal,
+	guard *callbackGuard,
+	results *[]api.StepState,
+	slot *dispatchSlot,
+) bool {
+	var deadline timer
+	if step.TimeoutSeconds != nil {
+		deadline = rt.NewTimer(time.Duration(*step.TimeoutSeconds) * time.Second)
+		defer deadline.Stop()
+	}
+
+	pendingRecorded := false
+	for {
+		ok := awaitStepCompletion(rt, step.Name, stepCompletedCh, skipCh, deadline, candidate, guard, results)
+		slot.release(rt)
+		if !ok {
+			return false
+		}
+		last := currentResult(*results, step.Name, candidate)
+		if last.Status == api.StepStateStatusTimedOut {
+			deadline = nil // fires once
+			escalateTimeout(rt, manifest, step, last)
+			continue
+		}
+		if last.Status != api.StepStateStatusPending {
+			return true
+		}
+		if !pendingRecorded {
+			pendingRecorded = true
+			recordEvent(rt, migrations.StepEvent{
+				MigrationID: manifest.MigrationId,
+				CandidateID: candidate.Id,
+				StepName:    step.Name,
+				EventType:   migrations.EventStepPending,
+				Status:      string(api.StepStateStatusPending),
+				Metadata:    derefMetadata(last.Metadata),
+			})
+		}
+	}
+}
+
+// escalateTimeout records a step_timed_out event and fires the escalation hook
+// via the EscalateStep activity. Hook failures are logged, never propagated —
+// the step keeps waiting either way.
+func escalateTimeout(rt runtime, manifest api.MigrationManifest, step api.StepDefinition, state api.StepState) {
+	timeout := strconv.Itoa(*step.TimeoutSeconds)
+	recordEvent(rt, migrations.StepEvent{
+		MigrationID: manifest.MigrationId,
+		CandidateID: state.Candidate.Id,
+		StepName:    step.Name,
+		EventType:   migrations.EventStepTimedOut,
+		Status:      string(api.StepStateStatusTimedOut),
+		Metadata:    map[string]string{"timeoutSeconds": timeout},
+	})
+
+	escalation := migrations.StepEscalation{
+		MigrationID:    manifest.MigrationId,
+		CandidateID:    state.Candidate.Id,
+		StepName:       step.Name,
+		RunID:          rt.RunID(),
+		TimeoutSeconds: *step.TimeoutSeconds,
+		Metadata:       derefMetadata(state.Metadata),
+	}
+	if err := rt.ExecuteActivity("EscalateStep", fiveAttempts, escalation, nil); err != nil {
+		rt.Logger().Warn("failed to escalate timed-out step", "step", step.Name, "error", err)
+	}
+}
+
+// awaitStepCompletion blocks until a step-completed or skip-step signal arrives,
+// the deadline (if non-nil) fires, or the workflow is cancelled. Returns false if
+// the workflow was cancelled first (no result is appended in that case). A skip is
+// recorded as a skipped result and a fired deadline as a timed_out result.
+// Step-completed signals the guard rejects are discarded and the wait goes on.
+// When several are ready at once they are taken in that order.
 func awaitStepCompletion(
-	ctx workflow.Context,
-	stepCompletedCh workflow.ReceiveChannel,
+	rt runtime,
+	stepName string,
+	stepCompletedCh, skipCh signal,
+	deadline timer,
 	candidate api.Candidate,
+	guard *callbackGuard,
 	results *[]api.StepState,
 ) bool {
 	var event api.StepStatusEvent
-	var received bool
-	sel := workflow.NewSelector(ctx)
-	sel.AddReceive(stepCompletedCh, func(c workflow.ReceiveChannel, _ bool) {
-		c.Receive(ctx, &event)
-		received = true
-	})
-	sel.AddReceive(ctx.Done(), func(_ workflow.ReceiveChannel, _ bool) {})
-	sel.Select(ctx)
+	var skip api.SkipStepRequest
+	var received, skipped, timedOut bool
+	for !received && !skipped && !timedOut && rt.Err() == nil {
+		if awaitAny(rt, deadline, stepCompletedCh, skipCh) != nil {
+			break
+		}
+		switch {
+		case stepCompletedCh.Pending():
+			var e api.StepStatusEvent
+			stepCompletedCh.Receive(&e)
+			if guard.accept(rt, e) {
+				event, received = e, true
+			}
+		case skipCh.Pending():
+			skipCh.Receive(&skip)
+			skipped = true
+		default:
+			timedOut = true
+		}
+	}
+	if skipped {
+		upsertResult(results, skippedState(currentResult(*results, stepName, candidate), skip.Reason))
+		return true
+	}
+	if timedOut {
+		current := currentResult(*results, stepName, candidate)
+		current.Status = api.StepStateStatusTimedOut
+		upsertResult(results, current)
+		return true
+	}
 	if !received {
 		return false
 	}
@@ -302,24 +868,241 @@ func awaitStepCompletion(
 	return true
 }
 
-// awaitRetryOrCancel blocks until either a retry-step signal or workflow cancellation.
-// Returns true if a retry was requested, false if the workflow was cancelled.
-func awaitRetryOrCancel(ctx workflow.Context, retryCh workflow.ReceiveChannel) bool {
-	var retryReceived bool
-	sel := workflow.NewSelector(ctx)
-	sel.AddReceive(retryCh, func(c workflow.ReceiveChannel, _ bool) {
-		c.Receive(ctx, nil)
-		retryReceived = true
+// callbackGuard discards step callbacks that must not change the step's state:
+// a resend of an event already received (same eventId) and a late callback
+// answering an earlier attempt of a retried step. Each discarded callback is
+// recorded as callback_ignored.
+type callbackGuard struct {
+	migrationID string
+	attempt     int             // the step's current dispatch attempt
+	seen        map[string]bool // event IDs already accepted for the step
+}
+
+// accept reports whether event should be applied to the step, recording it as
+// ignored when it should not.
+func (g *callbackGuard) accept(rt runtime, event api.StepStatusEvent) bool {
+	if reason := g.ignoreReason(event); reason != "" {
+		rt.Logger().Info("ignoring step callback", "step", event.StepName, "reason", reason)
+		recordEvent(rt, migrations.IgnoredCallbackEvent(g.migrationID, event, reason))
+		return false
+	}
+	return true
+}
+
+// ignoreReason returns why event must be discarded, or "" after noting its
+// event ID when it may be applied.
+func (g *callbackGuard) ignoreReason(event api.StepStatusEvent) string {
+	switch {
+	case migrations.IsStaleAttempt(event, g.attempt):
+		return migrations.IgnoredStaleAttempt
+	case event.EventId != nil && g.seen[*event.EventId]:
+		return migrations.IgnoredDuplicate
+	}
+	if event.EventId != nil {
+		g.seen[*event.EventId] = true
+	}
+	return ""
+}
+
+// failedStepAction is the operator decision that unblocks a failed step.
+type failedStepAction int
+
+const (
+	failedStepCancelled failedStepAction = iota
+	failedStepRetried
+	failedStepAutoRetried
+	failedStepSkipped
+)
+
+// Defaults for the optional DispatchRetryPolicy fields. The dispatch request
+// itself is quick; the timeout only bounds a migrator that never answers.
+const (
+	dispatchTimeout                  = time.Minute
+	defaultDispatchAttempts          = 10
+	defaultDispatchInitialBackoff    = time.Second
+	defaultDispatchMaxBackoff        = 5 * time.Minute
+	defaultDispatchBackoffMultiplier = 2.0
+)
+
+// dispatchActivityOptions returns the options the DispatchStep activity runs
+// with under policy.
+func dispatchActivityOptions(policy *api.DispatchRetryPolicy) activityOptions {
+	return activityOptions{timeout: dispatchTimeout, retry: dispatchRetryPolicy(policy)}
+}
+
+// dispatchRetryPolicy returns the retry policy for dispatching a step under
+// policy. Unset and out-of-range fields take the defaults.
+func dispatchRetryPolicy(policy *api.DispatchRetryPolicy) *temporal.RetryPolicy {
+	retry := &temporal.RetryPolicy{
+		InitialInterval:        defaultDispatchInitialBackoff,
+		BackoffCoefficient:     defaultDispatchBackoffMultiplier,
+		MaximumInterval:        defaultDispatchMaxBackoff,
+		MaximumAttempts:        defaultDispatchAttempts,
+		NonRetryableErrorTypes: []string{PermanentDispatchErrorType},
+	}
+	if policy != nil {
+		if policy.MaxAttempts != nil && *policy.MaxAttempts >= 1 {
+			retry.MaximumAttempts = int32(min(*policy.MaxAttempts, math.MaxInt32)) //nolint:gosec // clamped
+		}
+		if policy.InitialBackoffSeconds != nil && *policy.InitialBackoffSeconds >= 1 {
+			retry.InitialInterval = time.Duration(*policy.InitialBackoffSeconds) * time.Second
+		}
+		if policy.MaxBackoffSeconds != nil && *policy.MaxBackoffSeconds >= 1 {
+			retry.MaximumInterval = time.Duration(*policy.MaxBackoffSeconds) * time.Second
+		}
+		if policy.BackoffMultiplier != nil && *policy.BackoffMultiplier >= 1 {
+			retry.BackoffCoefficient = *policy.BackoffMultiplier
+		}
+	}
+	retry.MaximumInterval = max(retry.MaximumInterval, retry.InitialInterval)
+	return retry
+}
+
+// dispatchFailedState is the failed result of a step that could not be
+// dispatched, with the error under "dispatchError".
+func dispatchFailedState(stepName string, candidate api.Candidate, err error) api.StepState {
+	msg := err.Error()
+	var appErr *temporal.ApplicationError
+	if errors.As(err, &appErr) {
+		msg = appErr.Message()
+	}
+	md := map[string]string{"dispatchError": msg}
+	return api.StepState{
+		StepName:  stepName,
+		Candidate: candidate,
+		Status:    api.StepStateStatusFailed,
+		Metadata:  &md,
+	}
+}
+
+// Defaults for the optional RetryPolicy fields.
+const (
+	defaultInitialBackoff    = 10 * time.Second
+	defaultBackoffMultiplier = 2.0
+)
+
+// retryBackoff returns how long to wait before automatically retrying a step
+// whose attempt-th dispatch failed. ok is false when the policy is absent or its
+// attempts are used up, in which case the step waits for a manual retry.
+func retryBackoff(policy *api.RetryPolicy, attempt int) (backoff time.Duration, ok bool) {
+	if policy == nil || attempt >= policy.MaxAttempts {
+		return 0, false
+	}
+	initial := defaultInitialBackoff
+	if policy.InitialBackoffSeconds != nil {
+		initial = time.Duration(*policy.InitialBackoffSeconds) * time.Second
+	}
+	multiplier := defaultBackoffMultiplier
+	if policy.BackoffMultiplier != nil {
+		multiplier = *policy.BackoffMultiplier
+	}
+	return time.Duration(float64(initial) * math.Pow(multiplier, float64(attempt-1))), true
+}
+
+// awaitRetryOrCancel blocks until a retry-step signal, a skip-step signal, or
+// workflow cancellation, and reports which one arrived. For a skip, the
+// operator's request is returned alongside. When autoRetry is set, a durable
+// timer for backoff also races the signals and triggers a retry when it fires.
+func awaitRetryOrCancel(
+	rt runtime,
+	retryCh, skipCh signal,
+	backoff time.Duration,
+	autoRetry bool,
+) (failedStepAction, api.SkipStepRequest) {
+	var skip api.SkipStepRequest
+	var backoffTimer timer
+	if autoRetry {
+		backoffTimer = rt.NewTimer(backoff)
+		defer backoffTimer.Stop()
+	}
+	if awaitAny(rt, backoffTimer, retryCh, skipCh) != nil {
+		return failedStepCancelled, skip
+	}
+	switch {
+	case backoffTimer != nil && backoffTimer.Fired():
+		return failedStepAutoRetried, skip
+	case retryCh.Receive(nil):
+		return failedStepRetried, skip
+	default:
+		skipCh.Receive(&skip)
+		return failedStepSkipped, skip
+	}
+}
+
+// currentResult returns the result recorded for the step+candidate, or an empty
+// result for them when there is none yet.
+func currentResult(results []api.StepState, stepName string, candidate api.Candidate) api.StepState {
+	for _, r := range results {
+		if r.StepName == stepName && r.Candidate.Id == candidate.Id {
+			return r
+		}
+	}
+	return api.StepState{StepName: stepName, Candidate: candidate}
+}
+
+// whenContext builds the context a step's when expression is evaluated in: the
+// candidate's metadata and the results recorded so far for that candidate.
+func whenContext(results []api.StepState, candidate api.Candidate) migrations.WhenContext {
+	steps := make(map[string]api.StepState)
+	for _, r := range results {
+		if r.Candidate.Id == candidate.Id {
+			steps[r.StepName] = r
+		}
+	}
+	return migrations.WhenContext{
+		Metadata: derefMetadata(candidate.Metadata),
+		Steps:    steps,
+	}
+}
+
+// derefMetadata returns a copy of step metadata as a plain map (nil when absent).
+func derefMetadata(md *map[string]string) map[string]string {
+	if md == nil {
+		return nil
+	}
+	out := make(map[string]string, len(*md))
+	for k, v := range *md {
+		out[k] = v
+	}
+	return out
+}
+
+// skippedState returns a copy of the step's current result marked as skipped,
+// keeping any metadata the migrator already reported (e.g. prUrl) and adding
+// the operator's reason under "skipReason".
+func skippedState(current api.StepState, reason string) api.StepState {
+	md := make(map[string]string)
+	if current.Metadata != nil {
+		for k, v := range *current.Metadata {
+			md[k] = v
+		}
+	}
+	md["skipReason"] = reason
+	current.Status = api.StepStateStatusSkipped
+	current.Metadata = &md
+	return current
+}
+
+// recordStepSkipped records a step_skipped event carrying the operator's reason.
+func recordStepSkipped(rt runtime, manifest api.MigrationManifest, skipped api.StepState) {
+	var reason string
+	if skipped.Metadata != nil {
+		reason = (*skipped.Metadata)["skipReason"]
+	}
+	recordEvent(rt, migrations.StepEvent{
+		MigrationID: manifest.MigrationId,
+		CandidateID: skipped.Candidate.Id,
+		StepName:    skipped.StepName,
+		EventType:   migrations.EventStepSkipped,
+		Status:      string(api.StepStateStatusSkipped),
+		Metadata:    map[string]string{"reason": reason},
 	})
-	sel.AddReceive(ctx.Done(), func(_ workflow.ReceiveChannel, _ bool) {})
-	sel.Select(ctx)
-	return retryReceived
 }
 
 // runUpdateCandidateStatus persists the final candidate status via the
 // UpdateCandidateStatus activity. Errors are logged but not propagated —
 // a status update failure is not worth failing the workflow over.
-func runUpdateCandidateStatus(actCtx, ctx workflow.Context, manifest api.MigrationManifest, status string) {
+func runUpdateCandidateStatus(rt runtime, manifest api.MigrationManifest, status string) {
 	if len(manifest.Candidates) == 0 {
 		return
 	}
@@ -334,14 +1117,14 @@ func runUpdateCandidateStatus(actCtx, ctx workflow.Context, manifest api.Migrati
 	// than failing the workflow — the migration did complete, and propagating
 	// this error would record it as failed in Temporal history, which is wrong.
 	// The cost is a stale candidate status in Redis until the next run.
-	if err := workflow.ExecuteActivity(actCtx, "UpdateCandidateStatus", input).Get(ctx, nil); err != nil {
-		workflow.GetLogger(ctx).Warn("failed to update candidate status", "error", err, "status", status)
+	if err := rt.ExecuteActivity("UpdateCandidateStatus", runActivity, input, nil); err != nil {
+		rt.Logger().Warn("failed to update candidate status", "error", err, "status", status)
 	}
 }
 
 // runResetCandidate returns the candidate to not_started via the UpdateCandidateStatus activity.
 // Called from a deferred function when the workflow fails or is cancelled mid-run.
-func runResetCandidate(actCtx, ctx workflow.Context, manifest api.MigrationManifest) {
+func runResetCandidate(rt runtime, manifest api.MigrationManifest) {
 	if len(manifest.Candidates) == 0 {
 		return
 	}
@@ -350,8 +1133,29 @@ func runResetCandidate(actCtx, ctx workflow.Context, manifest api.MigrationManif
 		CandidateID: manifest.Candidates[0].Id,
 		Status:      string(api.CandidateStatusNotStarted),
 	}
-	if err := workflow.ExecuteActivity(actCtx, "UpdateCandidateStatus", input).Get(ctx, nil); err != nil {
-		workflow.GetLogger(ctx).Warn("failed to reset candidate", "error", err)
+	if err := rt.ExecuteActivity("UpdateCandidateStatus", shortActivity, input, nil); err != nil {
+		rt.Logger().Warn("failed to reset candidate", "error", err)
+	}
+}
+
+// finishRunChange gates recording the outcome of a run attempt, so that runs
+// started before run attempts were kept replay without it.
+const finishRunChange = "finish-run"
+
+// runFinishAttempt records the outcome and final step results of this run
+// attempt via the FinishRun activity. As with candidate status updates, a
+// failure is logged rather than failing the run.
+func runFinishAttempt(rt runtime, opts activityOptions, status api.RunAttemptStatus, results []api.StepState) {
+	if rt.GetVersion(finishRunChange, 1) < 1 {
+		return
+	}
+	input := FinishRunInput{
+		RunID:  rt.RunID(),
+		Status: status,
+		Steps:  results,
+	}
+	if err := rt.ExecuteActivity("FinishRun", opts, input, nil); err != nil {
+		rt.Logger().Warn("failed to record run outcome", "error", err, "status", status)
 	}
 }
 
@@ -372,6 +1176,21 @@ func upsertResult(results *[]api.StepState, r api.StepState) {
 	*results = append(*results, r)
 }
 
+// orderResults returns a copy of results sorted by the step's position in the
+// manifest. Concurrent steps start and finish in any order, so results are
+// appended out of step order; candidates keep their relative order.
+func orderResults(manifest api.MigrationManifest, results []api.StepState) []api.StepState {
+	pos := make(map[string]int, len(manifest.Steps))
+	for i, s := range manifest.Steps {
+		pos[s.Name] = i
+	}
+	ordered := slices.Clone(results)
+	slices.SortStableFunc(ordered, func(a, b api.StepState) int {
+		return cmp.Compare(pos[a.StepName], pos[b.StepName])
+	})
+	return ordered
+}
+
 // removeResult removes the entry for the given step+candidate from results (inverse of upsertResult).
 func removeResult(results *[]api.StepState, stepName, candidateId string) {
 	for i, r := range *results {
@@ -1,13 +1,20 @@
 package execution_test
 
 import (
+	"context"
 	"log/slog"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
 
 	"github.com/tilsley/loom/apps/server/internal/migrations"
 	"github.com/tilsley/loom/apps/server/internal/migrations/execution"
@@ -18,12 +25,13 @@ import (
 // All activity methods are mocked via env.OnActivity so the nil dependencies
 // are never actually called.
 func newActivities() *execution.Activities {
-	return execution.NewActivities(nil, nil, nil, slog.Default())
+	return execution.NewActivities(nil, nil, nil, nil, nil, nil, nil, nil, slog.Default())
 }
 
 // dummyMigrator configures env so that every DispatchStep call immediately signals
 // step-completed back to the workflow, simulating a worker that succeeds instantly.
 func dummyMigrator(env *testsuite.TestWorkflowEnvironment, acts *execution.Activities) {
+	env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Maybe()
 	env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).Return(nil).Maybe()
 	env.OnActivity(acts.DispatchStep, mock.Anything, mock.Anything).
 		Return(nil).
@@ -71,6 +79,86 @@ func TestMigrationOrchestrator_SingleStep_Success(t *testing.T) {
 	require.Equal(t, api.StepStateStatusSucceeded, result.Results[0].Status)
 }
 
+func TestMigrationOrchestrator_RecordsAttemptOutcome(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+
+	var finished execution.FinishRunInput
+	env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Once().
+		Run(func(args mock.Arguments) { finished = args.Get(1).(execution.FinishRunInput) })
+	dummyMigrator(env, acts)
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil)
+	env.SetStartWorkflowOptions(client.StartWorkflowOptions{ID: "mig-abc__billing-api__2"})
+
+	manifest := api.MigrationManifest{
+		MigrationId: "mig-abc",
+		Candidates:  []api.Candidate{{Id: "billing-api"}},
+		Steps:       []api.StepDefinition{{Name: "update-chart", MigratorApp: "app-chart-migrator"}},
+	}
+
+	env.ExecuteWorkflow(execution.MigrationOrchestrator, manifest)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, "mig-abc__billing-api__2", finished.RunID)
+	require.Equal(t, api.RunAttemptStatusCompleted, finished.Status)
+	require.Len(t, finished.Steps, 1)
+	require.Equal(t, api.StepStateStatusSucceeded, finished.Steps[0].Status)
+}
+
+// TestMigrationOrchestrator_AttemptOutcome_NotRecordedByEarlierRuns verifies
+// that a run started before run attempts were kept records no outcome, whether
+// it completes or fails.
+func TestMigrationOrchestrator_AttemptOutcome_NotRecordedByEarlierRuns(t *testing.T) {
+	for name, dispatchErr := range map[string]error{
+		"completed": nil,
+		"failed": temporal.NewNonRetryableApplicationError(
+			"chart not found", execution.PermanentDispatchErrorType, nil,
+		),
+	} {
+		t.Run(name, func(t *testing.T) {
+			ts := &testsuite.WorkflowTestSuite{}
+			env := ts.NewTestWorkflowEnvironment()
+
+			acts := newActivities()
+			env.RegisterActivity(acts)
+
+			env.OnGetVersion("finish-run", workflow.DefaultVersion, 1).Return(workflow.DefaultVersion)
+			env.OnGetVersion("dispatch-failure", workflow.DefaultVersion, 1).Return(workflow.DefaultVersion)
+			finishes := 0
+			env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Maybe().
+				Run(func(mock.Arguments) { finishes++ })
+			env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).Return(nil)
+			env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil)
+			env.OnActivity(acts.DispatchStep, mock.Anything, mock.Anything).
+				Return(dispatchErr).
+				Run(func(args mock.Arguments) {
+					req := args.Get(1).(api.DispatchStepRequest)
+					env.RegisterDelayedCallback(func() {
+						env.SignalWorkflow(req.EventName, api.StepStatusEvent{
+							StepName:    req.StepName,
+							CandidateId: req.Candidate.Id,
+							Status:      api.StepStatusEventStatusSucceeded,
+						})
+					}, time.Millisecond)
+				})
+
+			env.ExecuteWorkflow(execution.MigrationOrchestrator, api.MigrationManifest{
+				MigrationId: "mig-abc",
+				Candidates:  []api.Candidate{{Id: "billing-api"}},
+				Steps:       []api.StepDefinition{{Name: "update-chart", MigratorApp: "app-chart-migrator"}},
+			})
+
+			require.True(t, env.IsWorkflowCompleted())
+			require.Equal(t, dispatchErr != nil, env.GetWorkflowError() != nil)
+			require.Zero(t, finishes)
+		})
+	}
+}
+
 func TestMigrationOrchestrator_MultiStep_Success(t *testing.T) {
 	ts := &testsuite.WorkflowTestSuite{}
 	env := ts.NewTestWorkflowEnvironment()
@@ -134,6 +222,130 @@ func TestMigrationOrchestrator_MultiCandidate_Success(t *testing.T) {
 	require.Contains(t, ids, "payments-svc")
 }
 
+// ─── Step graph ───────────────────────────────────────────────────────────────
+
+// graphManifest returns a manifest whose two root steps are independent and
+// whose third step joins on both.
+func graphManifest() api.MigrationManifest {
+	join := []string{"generate-app-chart", "disable-base-resource-prune"}
+	return api.MigrationManifest{
+		MigrationId: "mig-abc",
+		Candidates:  []api.Candidate{{Id: "billing-api"}},
+		Steps: []api.StepDefinition{
+			{Name: "generate-app-chart", MigratorApp: "app-chart-migrator"},
+			{Name: "disable-base-resource-prune", MigratorApp: "app-chart-migrator"},
+			{Name: "swap-chart", MigratorApp: "app-chart-migrator", DependsOn: &join},
+		},
+	}
+}
+
+// TestMigrationOrchestrator_StepGraph_RunsIndependentStepsConcurrently verifies
+// that independent steps are dispatched together and that a step depending on
+// both is only dispatched after both have completed.
+func TestMigrationOrchestrator_StepGraph_RunsIndependentStepsConcurrently(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+
+	env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil)
+
+	start := env.Now()
+	dispatchedAt := map[string]time.Time{}
+	var dispatchOrder []string
+	env.OnActivity(acts.DispatchStep, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			req := args.Get(1).(api.DispatchStepRequest)
+			dispatchedAt[req.StepName] = env.Now()
+			dispatchOrder = append(dispatchOrder, req.StepName)
+			// The prune step's migrator is slower, so it finishes last.
+			delay := time.Minute
+			if req.StepName == "disable-base-resource-prune" {
+				delay = time.Hour
+			}
+			env.RegisterDelayedCallback(func() {
+				env.SignalWorkflow(req.EventName, api.StepStatusEvent{
+					StepName:    req.StepName,
+					CandidateId: req.Candidate.Id,
+					Status:      api.StepStatusEventStatusSucceeded,
+				})
+			}, delay)
+		})
+
+	env.ExecuteWorkflow(execution.MigrationOrchestrator, graphManifest())
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Less(t, dispatchedAt["disable-base-resource-prune"].Sub(start), time.Minute,
+		"independent steps should be dispatched without waiting for each other")
+	require.GreaterOrEqual(t, dispatchedAt["swap-chart"].Sub(start), time.Hour,
+		"swap-chart should wait for its slowest dependency")
+	require.Equal(t, "swap-chart", dispatchOrder[2])
+
+	var result execution.MigrationResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, "completed", result.Status)
+	require.Len(t, result.Results, 3)
+	// Results are reported in manifest order, not completion order.
+	require.Equal(t, "generate-app-chart", result.Results[0].StepName)
+	require.Equal(t, "disable-base-resource-prune", result.Results[1].StepName)
+	require.Equal(t, "swap-chart", result.Results[2].StepName)
+}
+
+// TestMigrationOrchestrator_StepGraph_FailedDependencyHoldsJoin verifies that a
+// step whose dependency has failed is not dispatched while the failed step
+// waits for a retry, even though its other dependency has finished.
+func TestMigrationOrchestrator_StepGraph_FailedDependencyHoldsJoin(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+
+	env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	dispatched := map[string]bool{}
+	env.OnActivity(acts.DispatchStep, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			req := args.Get(1).(api.DispatchStepRequest)
+			dispatched[req.StepName] = true
+			status := api.StepStatusEventStatusSucceeded
+			if req.StepName == "generate-app-chart" {
+				status = api.StepStatusEventStatusFailed
+			}
+			env.RegisterDelayedCallback(func() {
+				env.SignalWorkflow(req.EventName, api.StepStatusEvent{
+					StepName:    req.StepName,
+					CandidateId: req.Candidate.Id,
+					Status:      status,
+				})
+			}, time.Millisecond)
+		})
+
+	var progress execution.MigrationResult
+	env.RegisterDelayedCallback(func() {
+		val, err := env.QueryWorkflow("progress")
+		require.NoError(t, err)
+		require.NoError(t, val.Get(&progress))
+		env.CancelWorkflow()
+	}, time.Hour)
+
+	env.ExecuteWorkflow(execution.MigrationOrchestrator, graphManifest())
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.False(t, dispatched["swap-chart"], "swap-chart must not start while a dependency has failed")
+	require.Len(t, progress.Results, 2)
+	require.Equal(t, api.StepStateStatusFailed, progress.Results[0].Status)
+	require.Equal(t, api.StepStateStatusSucceeded, progress.Results[1].Status)
+}
+
 // ─── Failure + retry ─────────────────────────────────────────────────────────
 
 func TestMigrationOrchestrator_StepFailure_RetrySucceeds(t *testing.T) {
@@ -144,6 +356,7 @@ func TestMigrationOrchestrator_StepFailure_RetrySucceeds(t *testing.T) {
 	env.RegisterActivity(acts)
 
 	// First dispatch signals failure; a retry signal is then sent; second dispatch succeeds.
+	env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Maybe()
 	env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).Return(nil).Maybe()
 	retrySent := false
 	env.OnActivity(acts.DispatchStep, mock.Anything, mock.Anything).
@@ -195,32 +408,59 @@ func TestMigrationOrchestrator_StepFailure_RetrySucceeds(t *testing.T) {
 	require.Equal(t, api.StepStateStatusSucceeded, result.Results[0].Status)
 }
 
-// ─── Manual review step ───────────────────────────────────────────────────────
-
-// TestMigrationOrchestrator_ManualReviewStep_DispatchedToWorker verifies that a
-// manual-review step is dispatched to the worker like any other step. The worker
-// is responsible for signalling awaiting_review and the operator completes it
-// via the standard event endpoint.
-func TestMigrationOrchestrator_ManualReviewStep_DispatchedToWorker(t *testing.T) {
+// TestMigrationOrchestrator_PermanentDispatchError_FailsStep verifies that a
+// dispatch the migrator refuses fails the step with the error instead of the
+// workflow, and that a retry signal dispatches the step again.
+func TestMigrationOrchestrator_PermanentDispatchError_FailsStep(t *testing.T) {
 	ts := &testsuite.WorkflowTestSuite{}
 	env := ts.NewTestWorkflowEnvironment()
 
 	acts := newActivities()
 	env.RegisterActivity(acts)
 
-	dummyMigrator(env, acts)
+	env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Maybe()
+	var completed []migrations.StepEvent
+	env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			if ev := args.Get(1).(migrations.StepEvent); ev.EventType == migrations.EventStepCompleted {
+				completed = append(completed, ev)
+			}
+		})
+	env.OnActivity(acts.DispatchStep, mock.Anything, mock.Anything).
+		Return(temporal.NewNonRetryableApplicationError(
+			"chart not found", execution.PermanentDispatchErrorType, nil,
+		)).
+		Once()
+	env.OnActivity(acts.DispatchStep, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			req := args.Get(1).(api.DispatchStepRequest)
+			env.RegisterDelayedCallback(func() {
+				env.SignalWorkflow(req.EventName, api.StepStatusEvent{
+					StepName:    req.StepName,
+					CandidateId: req.Candidate.Id,
+					Status:      api.StepStatusEventStatusSucceeded,
+				})
+			}, time.Millisecond)
+		})
 	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil)
 
+	var failed api.StepState
+	env.RegisterDelayedCallback(func() {
+		val, err := env.QueryWorkflow("progress")
+		require.NoError(t, err)
+		var progress execution.MigrationResult
+		require.NoError(t, val.Get(&progress))
+		require.Len(t, progress.Results, 1)
+		failed = progress.Results[0]
+		env.SignalWorkflow(migrations.RetryStepEventName("update-chart", "billing-api"), nil)
+	}, time.Minute)
+
 	manifest := api.MigrationManifest{
 		MigrationId: "mig-abc",
 		Candidates:  []api.Candidate{{Id: "billing-api"}},
-		Steps: []api.StepDefinition{
-			{
-				Name:        "review",
-				MigratorApp: "app-chart-migrator",
-				Config:      &map[string]string{"type": "manual-review", "instructions": "approve the PR"},
-			},
-		},
+		Steps:       []api.StepDefinition{{Name: "update-chart", MigratorApp: "app-chart-migrator"}},
 	}
 
 	env.ExecuteWorkflow(execution.MigrationOrchestrator, manifest)
@@ -228,75 +468,111 @@ func TestMigrationOrchestrator_ManualReviewStep_DispatchedToWorker(t *testing.T)
 	require.True(t, env.IsWorkflowCompleted())
 	require.NoError(t, env.GetWorkflowError())
 
+	require.Equal(t, api.StepStateStatusFailed, failed.Status)
+	require.NotNil(t, failed.Metadata)
+	require.Equal(t, "chart not found", (*failed.Metadata)["dispatchError"])
+
+	require.Len(t, completed, 2)
+	require.Equal(t, string(api.StepStateStatusFailed), completed[0].Status)
+	require.Equal(t, "chart not found", completed[0].Metadata["dispatchError"])
+
 	var result execution.MigrationResult
 	require.NoError(t, env.GetWorkflowResult(&result))
 	require.Equal(t, "completed", result.Status)
-	require.Len(t, result.Results, 1)
 	require.Equal(t, api.StepStateStatusSucceeded, result.Results[0].Status)
 }
 
-// ─── Update inputs signal ─────────────────────────────────────────────────────
+// TestMigrationOrchestrator_PermanentDispatchError_FailsEarlierRuns verifies
+// that a run started before dispatch failures failed the step still fails
+// when a dispatch is refused.
+func TestMigrationOrchestrator_PermanentDispatchError_FailsEarlierRuns(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
 
-// TestMigrationOrchestrator_UpdateInputs_AppliedOnRetry verifies that metadata
-// sent via the update-inputs signal is used in the next DispatchStep call.
-func TestMigrationOrchestrator_UpdateInputs_AppliedOnRetry(t *testing.T) {
+	acts := newActivities()
+	env.RegisterActivity(acts)
+
+	env.OnGetVersion("dispatch-failure", workflow.DefaultVersion, 1).Return(workflow.DefaultVersion)
+	env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(acts.DispatchStep, mock.Anything, mock.Anything).
+		Return(temporal.NewNonRetryableApplicationError(
+			"chart not found", execution.PermanentDispatchErrorType, nil,
+		))
+
+	env.ExecuteWorkflow(execution.MigrationOrchestrator, api.MigrationManifest{
+		MigrationId: "mig-abc",
+		Candidates:  []api.Candidate{{Id: "billing-api"}},
+		Steps:       []api.StepDefinition{{Name: "update-chart", MigratorApp: "app-chart-migrator"}},
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.ErrorContains(t, env.GetWorkflowError(), "chart not found")
+}
+
+// ─── Duplicate and stale callbacks ───────────────────────────────────────────
+
+// TestMigrationOrchestrator_IgnoresDuplicateAndStaleCallbacks verifies that a
+// callback answering an earlier attempt of a retried step, and a resend of an
+// event already received, are recorded as callback_ignored without changing
+// the step.
+func TestMigrationOrchestrator_IgnoresDuplicateAndStaleCallbacks(t *testing.T) {
 	ts := &testsuite.WorkflowTestSuite{}
 	env := ts.NewTestWorkflowEnvironment()
 
 	acts := newActivities()
 	env.RegisterActivity(acts)
 
-	// Track the DispatchStepRequests so we can inspect metadata on retry.
-	env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).Return(nil).Maybe()
-	var dispatches []api.DispatchStepRequest
-	retrySent := false
+	var ignored []map[string]string
+	env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) {
+			if ev := args.Get(1).(migrations.StepEvent); ev.EventType == migrations.EventCallbackIgnored {
+				ignored = append(ignored, ev.Metadata)
+			}
+		})
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil)
 
+	var attempts []int
 	env.OnActivity(acts.DispatchStep, mock.Anything, mock.Anything).
 		Return(nil).
 		Run(func(args mock.Arguments) {
 			req := args.Get(1).(api.DispatchStepRequest)
-			dispatches = append(dispatches, req)
-
+			attempts = append(attempts, *req.Attempt)
+			event := func(id string, attempt int, status api.StepStatusEventStatus) api.StepStatusEvent {
+				return api.StepStatusEvent{
+					StepName:    req.StepName,
+					CandidateId: req.Candidate.Id,
+					Status:      status,
+					EventId:     &id,
+					Attempt:     &attempt,
+				}
+			}
 			env.RegisterDelayedCallback(func() {
-				if !retrySent {
-					// First dispatch → signal failure.
-					env.SignalWorkflow(req.EventName, api.StepStatusEvent{
-						StepName:    req.StepName,
-						CandidateId: req.Candidate.Id,
-						Status:      api.StepStatusEventStatusFailed,
-					})
-					// While waiting for retry, push updated inputs then send retry.
-					env.RegisterDelayedCallback(func() {
-						env.SignalWorkflow(
-							migrations.UpdateInputsEventName(req.Candidate.Id),
-							map[string]string{"repoName": "fixed-name"},
-						)
-						env.RegisterDelayedCallback(func() {
-							retrySent = true
-							env.SignalWorkflow(
-								migrations.RetryStepEventName(req.StepName, req.Candidate.Id),
-								nil,
-							)
-						}, time.Millisecond)
-					}, time.Millisecond)
-				} else {
-					// Second dispatch (after retry) → succeed.
-					env.SignalWorkflow(req.EventName, api.StepStatusEvent{
-						StepName:    req.StepName,
-						CandidateId: req.Candidate.Id,
-						Status:      api.StepStatusEventStatusSucceeded,
-					})
+				if *req.Attempt == 1 {
+					env.SignalWorkflow(req.EventName, event("evt-1", 1, api.StepStatusEventStatusFailed))
+					return
 				}
+				// A late merge for the first attempt, then a pending update sent twice.
+				env.SignalWorkflow(req.EventName, event("evt-late", 1, api.StepStatusEventStatusMerged))
+				env.SignalWorkflow(req.EventName, event("evt-2", 2, api.StepStatusEventStatusPending))
+				env.SignalWorkflow(req.EventName, event("evt-2", 2, api.StepStatusEventStatusPending))
+				env.RegisterDelayedCallback(func() {
+					env.SignalWorkflow(req.EventName, event("evt-3", 2, api.StepStatusEventStatusSucceeded))
+				}, time.Millisecond)
 			}, time.Millisecond)
 		})
 
-	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil)
-
-	md := map[string]string{"repoName": "typo-name"}
+	initial := 1
 	manifest := api.MigrationManifest{
 		MigrationId: "mig-abc",
-		Candidates:  []api.Candidate{{Id: "billing-api", Metadata: &md}},
-		Steps:       []api.StepDefinition{{Name: "update-chart", MigratorApp: "app-chart-migrator"}},
+		Candidates:  []api.Candidate{{Id: "billing-api"}},
+		Steps: []api.StepDefinition{{
+			Name:        "update-chart",
+			MigratorApp: "app-chart-migrator",
+			RetryPolicy: &api.RetryPolicy{MaxAttempts: 2, InitialBackoffSeconds: &initial},
+		}},
 	}
 
 	env.ExecuteWorkflow(execution.MigrationOrchestrator, manifest)
@@ -304,18 +580,1090 @@ func TestMigrationOrchestrator_UpdateInputs_AppliedOnRetry(t *testing.T) {
 	require.True(t, env.IsWorkflowCompleted())
 	require.NoError(t, env.GetWorkflowError())
 
-	// Should have been dispatched twice (initial + retry).
-	require.Len(t, dispatches, 2)
+	var result execution.MigrationResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, "completed", result.Status)
+	require.Equal(t, api.StepStateStatusSucceeded, result.Results[0].Status)
 
-	// First dispatch should have the original metadata.
-	require.NotNil(t, dispatches[0].Candidate.Metadata)
-	require.Equal(t, "typo-name", (*dispatches[0].Candidate.Metadata)["repoName"])
+	require.Equal(t, []int{1, 2}, attempts)
+	require.Equal(t, []map[string]string{
+		{"reason": migrations.IgnoredStaleAttempt, "eventId": "evt-late", "attempt": "1"},
+		{"reason": migrations.IgnoredDuplicate, "eventId": "evt-2", "attempt": "2"},
+	}, ignored)
+}
 
-	// Second dispatch (after update-inputs signal) should have the corrected metadata.
-	require.NotNil(t, dispatches[1].Candidate.Metadata)
-	require.Equal(t, "fixed-name", (*dispatches[1].Candidate.Metadata)["repoName"])
+// ─── Automatic retry policy ───────────────────────────────────────────────────
+
+// failingMigrator configures env so that the first failures dispatches of every
+// step signal failed and later ones signal succeeded. It returns the dispatch times.
+func failingMigrator(env *testsuite.TestWorkflowEnvironment, acts *execution.Activities, failures int) *[]time.Time {
+	var dispatched []time.Time
+	env.OnActivity(acts.DispatchStep, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			req := args.Get(1).(api.DispatchStepRequest)
+			dispatched = append(dispatched, env.Now())
+			status := api.StepStatusEventStatusSucceeded
+			if len(dispatched) <= failures {
+				status = api.StepStatusEventStatusFailed
+			}
+			env.RegisterDelayedCallback(func() {
+				env.SignalWorkflow(req.EventName, api.StepStatusEvent{
+					StepName:    req.StepName,
+					CandidateId: req.Candidate.Id,
+					Status:      status,
+				})
+			}, time.Millisecond)
+		})
+	return &dispatched
+}
+
+// TestMigrationOrchestrator_RetryPolicy_RetriesWithBackoff verifies that a step
+// with a retry policy is re-dispatched after exponentially growing waits, and that
+// each automatic attempt is recorded as step_retried with its attempt number.
+func TestMigrationOrchestrator_RetryPolicy_RetriesWithBackoff(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+
+	var retried []map[string]string
+	env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) {
+			if ev := args.Get(1).(migrations.StepEvent); ev.EventType == migrations.EventStepRetried {
+				retried = append(retried, ev.Metadata)
+			}
+		})
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil)
+	dispatched := failingMigrator(env, acts, 2)
+
+	initial, multiplier := 30, 2.0
+	manifest := api.MigrationManifest{
+		MigrationId: "mig-abc",
+		Candidates:  []api.Candidate{{Id: "billing-api"}},
+		Steps: []api.StepDefinition{{
+			Name:        "update-chart",
+			MigratorApp: "app-chart-migrator",
+			RetryPolicy: &api.RetryPolicy{
+				MaxAttempts:           3,
+				InitialBackoffSeconds: &initial,
+				BackoffMultiplier:     &multiplier,
+			},
+		}},
+	}
+
+	env.ExecuteWorkflow(execution.MigrationOrchestrator, manifest)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
 
 	var result execution.MigrationResult
 	require.NoError(t, env.GetWorkflowResult(&result))
 	require.Equal(t, "completed", result.Status)
+	require.Equal(t, api.StepStateStatusSucceeded, result.Results[0].Status)
+
+	require.Len(t, *dispatched, 3)
+	require.GreaterOrEqual(t, (*dispatched)[1].Sub((*dispatched)[0]), 30*time.Second)
+	require.GreaterOrEqual(t, (*dispatched)[2].Sub((*dispatched)[1]), 60*time.Second)
+
+	require.Equal(t, []map[string]string{
+		{"attempt": "2", "automatic": "true"},
+		{"attempt": "3", "automatic": "true"},
+	}, retried)
+}
+
+// TestMigrationOrchestrator_RetryPolicy_ExhaustedFallsBackToManual verifies that
+// once the policy's attempts are used up the step waits for an operator retry.
+func TestMigrationOrchestrator_RetryPolicy_ExhaustedFallsBackToManual(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+
+	env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil)
+	dispatched := failingMigrator(env, acts, 2)
+
+	// Only the operator's retry, a day later, gets the third dispatch out.
+	env.RegisterDelayedCallback(func() {
+		require.Len(t, *dispatched, 2)
+		env.SignalWorkflow(migrations.RetryStepEventName("update-chart", "billing-api"), nil)
+	}, 24*time.Hour)
+
+	manifest := api.MigrationManifest{
+		MigrationId: "mig-abc",
+		Candidates:  []api.Candidate{{Id: "billing-api"}},
+		Steps: []api.StepDefinition{{
+			Name:        "update-chart",
+			MigratorApp: "app-chart-migrator",
+			RetryPolicy: &api.RetryPolicy{MaxAttempts: 2},
+		}},
+	}
+
+	start := env.Now()
+	env.ExecuteWorkflow(execution.MigrationOrchestrator, manifest)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Len(t, *dispatched, 3)
+	require.GreaterOrEqual(t, (*dispatched)[2].Sub(start), 24*time.Hour)
+}
+
+// ─── Step timeout ─────────────────────────────────────────────────────────────
+
+// TestMigrationOrchestrator_StepTimeout_EscalatesAndKeepsWaiting verifies that a
+// step whose callback does not arrive within timeoutSeconds is marked timed_out,
+// recorded and escalated once, and that a late callback still completes it.
+func TestMigrationOrchestrator_StepTimeout_EscalatesAndKeepsWaiting(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+
+	var eventTypes []string
+	env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) {
+			eventTypes = append(eventTypes, args.Get(1).(migrations.StepEvent).EventType)
+		})
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil)
+
+	var escalations []migrations.StepEscalation
+	env.OnActivity(acts.EscalateStep, mock.Anything, mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) {
+			escalations = append(escalations, args.Get(1).(migrations.StepEscalation))
+		})
+
+	// The PR is opened straight away but only merged two days later.
+	env.OnActivity(acts.DispatchStep, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			req := args.Get(1).(api.DispatchStepRequest)
+			env.RegisterDelayedCallback(func() {
+				env.SignalWorkflow(req.EventName, api.StepStatusEvent{
+					StepName:    req.StepName,
+					CandidateId: req.Candidate.Id,
+					Status:      api.StepStatusEventStatusPending,
+					Metadata:    &map[string]string{"prUrl": "https://github.com/org/repo/pull/1"},
+				})
+			}, time.Millisecond)
+			env.RegisterDelayedCallback(func() {
+				env.SignalWorkflow(req.EventName, api.StepStatusEvent{
+					StepName:    req.StepName,
+					CandidateId: req.Candidate.Id,
+					Status:      api.StepStatusEventStatusMerged,
+				})
+			}, 48*time.Hour)
+		})
+
+	var statusAfterDeadline api.StepStateStatus
+	env.RegisterDelayedCallback(func() {
+		val, err := env.QueryWorkflow("progress")
+		require.NoError(t, err)
+		var progress execution.MigrationResult
+		require.NoError(t, val.Get(&progress))
+		statusAfterDeadline = progress.Results[0].Status
+	}, 25*time.Hour)
+
+	timeout := 24 * 60 * 60
+	manifest := api.MigrationManifest{
+		MigrationId: "mig-abc",
+		Candidates:  []api.Candidate{{Id: "billing-api"}},
+		Steps: []api.StepDefinition{{
+			Name:           "open-pr",
+			MigratorApp:    "app-chart-migrator",
+			TimeoutSeconds: &timeout,
+		}},
+	}
+
+	env.ExecuteWorkflow(execution.MigrationOrchestrator, manifest)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, api.StepStateStatusTimedOut, statusAfterDeadline)
+
+	require.Len(t, escalations, 1)
+	require.Equal(t, "open-pr", escalations[0].StepName)
+	require.Equal(t, timeout, escalations[0].TimeoutSeconds)
+	require.Equal(t, "https://github.com/org/repo/pull/1", escalations[0].Metadata["prUrl"])
+	require.Contains(t, eventTypes, migrations.EventStepPending)
+	require.Contains(t, eventTypes, migrations.EventStepTimedOut)
+
+	var result execution.MigrationResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, "completed", result.Status)
+	require.Equal(t, api.StepStateStatusMerged, result.Results[0].Status)
+}
+
+// ─── Skip step ────────────────────────────────────────────────────────────────
+
+// TestMigrationOrchestrator_SkipFailedStep_Advances verifies that skipping a
+// failed step records it as skipped with the reason and moves on to the next step.
+func TestMigrationOrchestrator_SkipFailedStep_Advances(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+
+	var skippedEvent migrations.StepEvent
+	env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) {
+			if ev := args.Get(1).(migrations.StepEvent); ev.EventType == migrations.EventStepSkipped {
+				skippedEvent = ev
+			}
+		})
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil)
+
+	// The first step fails; the operator skips it. The second step succeeds.
+	env.OnActivity(acts.DispatchStep, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			req := args.Get(1).(api.DispatchStepRequest)
+			env.RegisterDelayedCallback(func() {
+				if req.StepName != "disable-prune" {
+					env.SignalWorkflow(req.EventName, api.StepStatusEvent{
+						StepName:    req.StepName,
+						CandidateId: req.Candidate.Id,
+						Status:      api.StepStatusEventStatusSucceeded,
+					})
+					return
+				}
+				env.SignalWorkflow(req.EventName, api.StepStatusEvent{
+					StepName:    req.StepName,
+					CandidateId: req.Candidate.Id,
+					Status:      api.StepStatusEventStatusFailed,
+				})
+				env.RegisterDelayedCallback(func() {
+					env.SignalWorkflow(
+						migrations.SkipStepEventName(req.StepName, req.Candidate.Id),
+						api.SkipStepRequest{StepName: req.StepName, Reason: "done by hand"},
+					)
+				}, time.Millisecond)
+			}, time.Millisecond)
+		})
+
+	manifest := api.MigrationManifest{
+		MigrationId: "mig-abc",
+		Candidates:  []api.Candidate{{Id: "billing-api"}},
+		Steps: []api.StepDefinition{
+			{Name: "disable-prune", MigratorApp: "app-chart-migrator"},
+			{Name: "update-chart", MigratorApp: "app-chart-migrator"},
+		},
+	}
+
+	env.ExecuteWorkflow(execution.MigrationOrchestrator, manifest)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	var result execution.MigrationResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, "completed", result.Status)
+	require.Len(t, result.Results, 2)
+	require.Equal(t, api.StepStateStatusSkipped, result.Results[0].Status)
+	require.Equal(t, "done by hand", (*result.Results[0].Metadata)["skipReason"])
+	require.Equal(t, api.StepStateStatusSucceeded, result.Results[1].Status)
+
+	require.Equal(t, "disable-prune", skippedEvent.StepName)
+	require.Equal(t, "done by hand", skippedEvent.Metadata["reason"])
+}
+
+// TestMigrationOrchestrator_SkipPendingStep_KeepsMetadata verifies that a step
+// parked in pending (e.g. an open PR) can be skipped, keeping its metadata.
+func TestMigrationOrchestrator_SkipPendingStep_KeepsMetadata(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+
+	env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(acts.DispatchStep, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			req := args.Get(1).(api.DispatchStepRequest)
+			env.RegisterDelayedCallback(func() {
+				env.SignalWorkflow(req.EventName, api.StepStatusEvent{
+					StepName:    req.StepName,
+					CandidateId: req.Candidate.Id,
+					Status:      api.StepStatusEventStatusPending,
+					Metadata:    &map[string]string{"prUrl": "https://github.com/org/repo/pull/1"},
+				})
+				env.RegisterDelayedCallback(func() {
+					env.SignalWorkflow(
+						migrations.SkipStepEventName(req.StepName, req.Candidate.Id),
+						api.SkipStepRequest{StepName: req.StepName, Reason: "merged out of band"},
+					)
+				}, time.Millisecond)
+			}, time.Millisecond)
+		})
+
+	manifest := api.MigrationManifest{
+		MigrationId: "mig-abc",
+		Candidates:  []api.Candidate{{Id: "billing-api"}},
+		Steps:       []api.StepDefinition{{Name: "open-pr", MigratorApp: "app-chart-migrator"}},
+	}
+
+	env.ExecuteWorkflow(execution.MigrationOrchestrator, manifest)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	var result execution.MigrationResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, "completed", result.Status)
+	require.Len(t, result.Results, 1)
+	require.Equal(t, api.StepStateStatusSkipped, result.Results[0].Status)
+	require.Equal(t, "https://github.com/org/repo/pull/1", (*result.Results[0].Metadata)["prUrl"])
+	require.Equal(t, "merged out of band", (*result.Results[0].Metadata)["skipReason"])
+}
+
+// ─── Conditional steps ────────────────────────────────────────────────────────
+
+// TestMigrationOrchestrator_When_SkipsStepWhenConditionFails verifies that a step
+// whose when expression does not hold is recorded as skipped without being
+// dispatched, and that later steps can read earlier results.
+func TestMigrationOrchestrator_When_SkipsStepWhenConditionFails(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+
+	env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil)
+
+	var dispatched []string
+	env.OnActivity(acts.DispatchStep, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			req := args.Get(1).(api.DispatchStepRequest)
+			dispatched = append(dispatched, req.StepName)
+			env.RegisterDelayedCallback(func() {
+				env.SignalWorkflow(req.EventName, api.StepStatusEvent{
+					StepName:    req.StepName,
+					CandidateId: req.Candidate.Id,
+					Status:      api.StepStatusEventStatusSucceeded,
+				})
+			}, time.Millisecond)
+		})
+
+	paymentsOnly := `metadata.team == "payments"`
+	afterSkip := `steps.notify-payments.status == "skipped"`
+	manifest := api.MigrationManifest{
+		MigrationId: "mig-abc",
+		Candidates:  []api.Candidate{{Id: "search-api", Metadata: &map[string]string{"team": "search"}}},
+		Steps: []api.StepDefinition{
+			{Name: "update-chart", MigratorApp: "app-chart-migrator"},
+			{Name: "notify-payments", MigratorApp: "app-chart-migrator", When: &paymentsOnly},
+			{Name: "open-pr", MigratorApp: "app-chart-migrator", When: &afterSkip},
+		},
+	}
+
+	env.ExecuteWorkflow(execution.MigrationOrchestrator, manifest)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, []string{"update-chart", "open-pr"}, dispatched)
+
+	var result execution.MigrationResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, "completed", result.Status)
+	require.Len(t, result.Results, 3)
+	require.Equal(t, api.StepStateStatusSkipped, result.Results[1].Status)
+	require.NotNil(t, result.Results[1].Metadata)
+	require.Equal(t, "condition not met: "+paymentsOnly, (*result.Results[1].Metadata)["skipReason"])
+	require.Equal(t, api.StepStateStatusSucceeded, result.Results[2].Status)
+}
+
+// ─── Rollback ─────────────────────────────────────────────────────────────────
+
+// TestRollbackOrchestrator_DispatchesCompensationsAndResetsCandidate verifies
+// that a rollback run dispatches its compensating steps in manifest order and
+// returns the candidate to not_started once they have all finished.
+func TestRollbackOrchestrator_DispatchesCompensationsAndResetsCandidate(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+
+	var dispatched []string
+	env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.DispatchStep, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			req := args.Get(1).(api.DispatchStepRequest)
+			dispatched = append(dispatched, *req.Type)
+			env.RegisterDelayedCallback(func() {
+				env.SignalWorkflow(req.EventName, api.StepStatusEvent{
+					StepName:    req.StepName,
+					CandidateId: req.Candidate.Id,
+					Status:      api.StepStatusEventStatusSucceeded,
+				})
+			}, time.Millisecond)
+		})
+	var finalStatus string
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			finalStatus = args.Get(1).(execution.UpdateCandidateStatusInput).Status
+		})
+
+	revertChart, enablePrune := "revert-chart", "enable-sync-prune"
+	manifest := api.MigrationManifest{
+		MigrationId: "mig-abc",
+		Candidates:  []api.Candidate{{Id: "billing-api"}},
+		Steps: []api.StepDefinition{
+			{Name: migrations.RollbackStepName("swap-chart-prod"), MigratorApp: "app-chart-migrator", Type: &revertChart},
+			{Name: migrations.RollbackStepName("disable-sync-prune-prod"), MigratorApp: "app-chart-migrator", Type: &enablePrune},
+		},
+	}
+
+	env.ExecuteWorkflow(execution.RollbackOrchestrator, manifest)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, []string{revertChart, enablePrune}, dispatched)
+	require.Equal(t, string(api.CandidateStatusNotStarted), finalStatus)
+
+	var result execution.MigrationResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, "completed", result.Status)
+	require.Len(t, result.Results, 2)
+}
+
+// ─── Manual review step ───────────────────────────────────────────────────────
+
+// TestMigrationOrchestrator_ManualReviewStep_DispatchedToWorker verifies that a
+// manual-review step is dispatched to the worker like any other step. The worker
+// is responsible for signalling awaiting_review and the operator completes it
+// via the standard event endpoint.
+func TestMigrationOrchestrator_ManualReviewStep_DispatchedToWorker(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+
+	dummyMigrator(env, acts)
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil)
+
+	manifest := api.MigrationManifest{
+		MigrationId: "mig-abc",
+		Candidates:  []api.Candidate{{Id: "billing-api"}},
+		Steps: []api.StepDefinition{
+			{
+				Name:        "review",
+				MigratorApp: "app-chart-migrator",
+				Config:      &map[string]string{"type": "manual-review", "instructions": "approve the PR"},
+			},
+		},
+	}
+
+	env.ExecuteWorkflow(execution.MigrationOrchestrator, manifest)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	var result execution.MigrationResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, "completed", result.Status)
+	require.Len(t, result.Results, 1)
+	require.Equal(t, api.StepStateStatusSucceeded, result.Results[0].Status)
+}
+
+// ─── Update inputs signal ─────────────────────────────────────────────────────
+
+// TestMigrationOrchestrator_UpdateInputs_AppliedOnRetry verifies that metadata
+// sent via the update-inputs signal is used in the next DispatchStep call.
+func TestMigrationOrchestrator_UpdateInputs_AppliedOnRetry(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+
+	// Track the DispatchStepRequests so we can inspect metadata on retry.
+	env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).Return(nil).Maybe()
+	var dispatches []api.DispatchStepRequest
+	retrySent := false
+
+	env.OnActivity(acts.DispatchStep, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			req := args.Get(1).(api.DispatchStepRequest)
+			dispatches = append(dispatches, req)
+
+			env.RegisterDelayedCallback(func() {
+				if !retrySent {
+					// First dispatch → signal failure.
+					env.SignalWorkflow(req.EventName, api.StepStatusEvent{
+						StepName:    req.StepName,
+						CandidateId: req.Candidate.Id,
+						Status:      api.StepStatusEventStatusFailed,
+					})
+					// While waiting for retry, push updated inputs then send retry.
+					env.RegisterDelayedCallback(func() {
+						env.SignalWorkflow(
+							migrations.UpdateInputsEventName(req.Candidate.Id),
+							map[string]string{"repoName": "fixed-name"},
+						)
+						env.RegisterDelayedCallback(func() {
+							retrySent = true
+							env.SignalWorkflow(
+								migrations.RetryStepEventName(req.StepName, req.Candidate.Id),
+								nil,
+							)
+						}, time.Millisecond)
+					}, time.Millisecond)
+				} else {
+					// Second dispatch (after retry) → succeed.
+					env.SignalWorkflow(req.EventName, api.StepStatusEvent{
+						StepName:    req.StepName,
+						CandidateId: req.Candidate.Id,
+						Status:      api.StepStatusEventStatusSucceeded,
+					})
+				}
+			}, time.Millisecond)
+		})
+
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil)
+
+	md := map[string]string{"repoName": "typo-name"}
+	manifest := api.MigrationManifest{
+		MigrationId: "mig-abc",
+		Candidates:  []api.Candidate{{Id: "billing-api", Metadata: &md}},
+		Steps:       []api.StepDefinition{{Name: "update-chart", MigratorApp: "app-chart-migrator"}},
+	}
+
+	env.ExecuteWorkflow(execution.MigrationOrchestrator, manifest)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	// Should have been dispatched twice (initial + retry).
+	require.Len(t, dispatches, 2)
+
+	// First dispatch should have the original metadata.
+	require.NotNil(t, dispatches[0].Candidate.Metadata)
+	require.Equal(t, "typo-name", (*dispatches[0].Candidate.Metadata)["repoName"])
+
+	// Second dispatch (after update-inputs signal) should have the corrected metadata.
+	require.NotNil(t, dispatches[1].Candidate.Metadata)
+	require.Equal(t, "fixed-name", (*dispatches[1].Candidate.Metadata)["repoName"])
+
+	var result execution.MigrationResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, "completed", result.Status)
+}
+
+// ─── Pause / resume ───────────────────────────────────────────────────────────
+
+// TestMigrationOrchestrator_Pause_HoldsNextDispatch verifies that a pause sent
+// while a step is in flight lets that step finish, holds the next dispatch until
+// resume, and reports "paused" through the progress query in the meantime, both
+// for the pause-state signal and, in runs started before it, for the separate
+// pause and resume signals that came before it.
+func TestMigrationOrchestrator_Pause_HoldsNextDispatch(t *testing.T) {
+	for name, signals := range map[string]struct {
+		earlier       bool
+		pause, resume func(env *testsuite.TestWorkflowEnvironment)
+	}{
+		"pause-state": {
+			pause: func(env *testsuite.TestWorkflowEnvironment) {
+				env.SignalWorkflow(migrations.PauseStateEventName("billing-api"), migrations.PauseState{Paused: true})
+			},
+			resume: func(env *testsuite.TestWorkflowEnvironment) {
+				env.SignalWorkflow(migrations.PauseStateEventName("billing-api"), migrations.PauseState{Paused: false})
+			},
+		},
+		"separate signals": {
+			earlier: true,
+			pause: func(env *testsuite.TestWorkflowEnvironment) {
+				env.SignalWorkflow(migrations.PauseEventName("billing-api"), nil)
+			},
+			resume: func(env *testsuite.TestWorkflowEnvironment) {
+				env.SignalWorkflow(migrations.ResumeEventName("billing-api"), nil)
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ts := &testsuite.WorkflowTestSuite{}
+			env := ts.NewTestWorkflowEnvironment()
+
+			acts := newActivities()
+			env.RegisterActivity(acts)
+
+			if signals.earlier {
+				env.OnGetVersion("pause-order", workflow.DefaultVersion, 1).Return(workflow.DefaultVersion)
+			}
+			env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Maybe()
+			env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).Return(nil).Maybe()
+			env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil)
+
+			start := env.Now()
+			dispatchedAt := map[string]time.Time{}
+			env.OnActivity(acts.DispatchStep, mock.Anything, mock.Anything).
+				Return(nil).
+				Run(func(args mock.Arguments) {
+					req := args.Get(1).(api.DispatchStepRequest)
+					dispatchedAt[req.StepName] = env.Now()
+					env.RegisterDelayedCallback(func() {
+						if req.StepName == "update-chart" {
+							signals.pause(env)
+						}
+						env.SignalWorkflow(req.EventName, api.StepStatusEvent{
+							StepName:    req.StepName,
+							CandidateId: req.Candidate.Id,
+							Status:      api.StepStatusEventStatusSucceeded,
+						})
+					}, time.Millisecond)
+				})
+
+			var pausedStatus string
+			env.RegisterDelayedCallback(func() {
+				val, err := env.QueryWorkflow("progress")
+				require.NoError(t, err)
+				var progress execution.MigrationResult
+				require.NoError(t, val.Get(&progress))
+				pausedStatus = progress.Status
+				_, dispatched := dispatchedAt["open-pr"]
+				require.False(t, dispatched, "open-pr should not be dispatched while paused")
+
+				signals.resume(env)
+			}, time.Hour)
+
+			env.ExecuteWorkflow(execution.MigrationOrchestrator, pauseManifest())
+
+			require.True(t, env.IsWorkflowCompleted())
+			require.NoError(t, env.GetWorkflowError())
+			require.Equal(t, "paused", pausedStatus)
+			require.GreaterOrEqual(t, dispatchedAt["open-pr"].Sub(start), time.Hour)
+
+			var result execution.MigrationResult
+			require.NoError(t, env.GetWorkflowResult(&result))
+			require.Equal(t, "completed", result.Status)
+			require.Len(t, result.Results, 2)
+		})
+	}
+}
+
+// TestMigrationOrchestrator_Pause_AppliesSignalsInSendOrder verifies that a
+// pause, a resume and a pause sent back to back while a step is in flight leave
+// the run paused, as the operator last asked, rather than applying them in
+// whatever order they are read.
+func TestMigrationOrchestrator_Pause_AppliesSignalsInSendOrder(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+
+	env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil)
+	var runEvents []string
+	env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) {
+			if ev := args.Get(1).(migrations.StepEvent); ev.StepName == "" {
+				runEvents = append(runEvents, ev.EventType)
+			}
+		})
+
+	setPaused := func(paused bool) {
+		env.SignalWorkflow(migrations.PauseStateEventName("billing-api"), migrations.PauseState{Paused: paused})
+	}
+	start := env.Now()
+	dispatchedAt := map[string]time.Time{}
+	env.OnActivity(acts.DispatchStep, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			req := args.Get(1).(api.DispatchStepRequest)
+			dispatchedAt[req.StepName] = env.Now()
+			env.RegisterDelayedCallback(func() {
+				if req.StepName == "update-chart" {
+					setPaused(true)
+					setPaused(false)
+					setPaused(true)
+				}
+				env.SignalWorkflow(req.EventName, api.StepStatusEvent{
+					StepName:    req.StepName,
+					CandidateId: req.Candidate.Id,
+					Status:      api.StepStatusEventStatusSucceeded,
+				})
+			}, time.Millisecond)
+		})
+
+	env.RegisterDelayedCallback(func() {
+		_, dispatched := dispatchedAt["open-pr"]
+		require.False(t, dispatched, "open-pr should not be dispatched after the last pause")
+		setPaused(false)
+	}, time.Hour)
+
+	env.ExecuteWorkflow(execution.MigrationOrchestrator, pauseManifest())
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.GreaterOrEqual(t, dispatchedAt["open-pr"].Sub(start), time.Hour)
+	require.Equal(t, migrations.EventRunPaused, runEvents[len(runEvents)-3])
+	require.Equal(t, []string{migrations.EventRunResumed, migrations.EventRunCompleted}, runEvents[len(runEvents)-2:])
+}
+
+// pauseManifest is a two-step run for the pause tests, so a pause sent while
+// the first step is in flight has a next dispatch to hold.
+func pauseManifest() api.MigrationManifest {
+	return api.MigrationManifest{
+		MigrationId: "mig-abc",
+		Candidates:  []api.Candidate{{Id: "billing-api"}},
+		Steps: []api.StepDefinition{
+			{Name: "update-chart", MigratorApp: "app-chart-migrator"},
+			{Name: "open-pr", MigratorApp: "app-chart-migrator"},
+		},
+	}
+}
+
+// ─── Freeze windows ───────────────────────────────────────────────────────────
+
+// TestMigrationOrchestrator_FreezeWindow_HoldsDispatchUntilWindowEnds verifies
+// that a step covered by a freeze window shows as waiting_for_window and is
+// only dispatched once the window has ended.
+func TestMigrationOrchestrator_FreezeWindow_HoldsDispatchUntilWindowEnds(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+
+	dummyMigrator(env, acts)
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil)
+
+	start := env.Now()
+	window := migrations.FreezeWindow{
+		ID:         4,
+		Reason:     "quarter-end freeze",
+		StartsAt:   start.Add(-time.Hour),
+		EndsAt:     start.Add(2 * time.Hour),
+		StepConfig: map[string]string{"env": "prod"},
+	}
+	env.OnActivity(acts.CheckFreezeWindows, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, input execution.CheckFreezeWindowsInput) (execution.CheckFreezeWindowsResult, error) {
+			return execution.CheckFreezeWindowsResult{
+				Window: migrations.BlockingFreezeWindow(
+					[]migrations.FreezeWindow{window}, input.MigrationID, input.StepConfig, input.At),
+			}, nil
+		})
+
+	dispatchedAt := map[string]time.Time{}
+	env.SetOnActivityStartedListener(func(info *activity.Info, _ context.Context, args converter.EncodedValues) {
+		if info.ActivityType.Name != "DispatchStep" {
+			return
+		}
+		var req api.DispatchStepRequest
+		require.NoError(t, args.Get(&req))
+		dispatchedAt[req.StepName] = env.Now()
+	})
+
+	var waiting api.StepState
+	env.RegisterDelayedCallback(func() {
+		val, err := env.QueryWorkflow("progress")
+		require.NoError(t, err)
+		var progress execution.MigrationResult
+		require.NoError(t, val.Get(&progress))
+		for _, r := range progress.Results {
+			if r.StepName == "deploy-prod" {
+				waiting = r
+			}
+		}
+	}, 30*time.Minute)
+
+	prod := map[string]string{"env": "prod"}
+	manifest := api.MigrationManifest{
+		MigrationId: "mig-abc",
+		Candidates:  []api.Candidate{{Id: "billing-api"}},
+		Steps: []api.StepDefinition{
+			{Name: "update-chart", MigratorApp: "app-chart-migrator"},
+			{Name: "deploy-prod", MigratorApp: "app-chart-migrator", Config: &prod},
+		},
+	}
+
+	env.ExecuteWorkflow(execution.MigrationOrchestrator, manifest)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Less(t, dispatchedAt["update-chart"].Sub(start), time.Minute, "steps outside the window's scope run")
+	require.GreaterOrEqual(t, dispatchedAt["deploy-prod"].Sub(start), 2*time.Hour)
+
+	require.Equal(t, api.StepStateStatusWaitingForWindow, waiting.Status)
+	require.NotNil(t, waiting.Metadata)
+	require.Equal(t, "quarter-end freeze", (*waiting.Metadata)["freezeReason"])
+	require.Equal(t, window.EndsAt.UTC().Format(time.RFC3339), (*waiting.Metadata)["waitingUntil"])
+
+	var result execution.MigrationResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, "completed", result.Status)
+	require.Equal(t, api.StepStateStatusSucceeded, result.Results[1].Status)
+	require.Nil(t, result.Results[1].Metadata, "the waiting metadata is dropped once dispatched")
+}
+
+// TestMigrationOrchestrator_FreezeWindow_NotCheckedByEarlierRuns verifies that
+// a run started before freeze windows existed replays without checking them.
+func TestMigrationOrchestrator_FreezeWindow_NotCheckedByEarlierRuns(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+
+	dummyMigrator(env, acts)
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil)
+	env.OnGetVersion("freeze-window-check", workflow.DefaultVersion, 1).Return(workflow.DefaultVersion)
+	checks := 0
+	env.OnActivity(acts.CheckFreezeWindows, mock.Anything, mock.Anything).
+		Return(execution.CheckFreezeWindowsResult{}, nil).
+		Run(func(mock.Arguments) { checks++ })
+
+	env.ExecuteWorkflow(execution.MigrationOrchestrator, api.MigrationManifest{
+		MigrationId: "mig-abc",
+		Candidates:  []api.Candidate{{Id: "billing-api"}},
+		Steps:       []api.StepDefinition{{Name: "update-chart", MigratorApp: "app-chart-migrator"}},
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Zero(t, checks)
+}
+
+// ─── Dispatch limits ──────────────────────────────────────────────────────────
+
+// TestMigrationOrchestrator_DispatchLimit_QueuesUntilSlotFree verifies that a
+// step whose migrator app is at its dispatch limits shows as queued, is
+// dispatched once a slot is granted, and releases the slot when the migrator
+// reports back.
+func TestMigrationOrchestrator_DispatchLimit_QueuesUntilSlotFree(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+
+	dummyMigrator(env, acts)
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil)
+
+	start := env.Now()
+	refusals := 3
+	env.OnActivity(acts.AcquireDispatchSlot, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, _ execution.DispatchSlotInput) (migrations.DispatchSlotGrant, error) {
+			if refusals > 0 {
+				refusals--
+				return migrations.DispatchSlotGrant{
+					RetryAfter: 10 * time.Second,
+					Reason:     "1 dispatches in flight (max 1)",
+				}, nil
+			}
+			return migrations.DispatchSlotGrant{Acquired: true, Held: true}, nil
+		})
+	var released []execution.DispatchSlotInput
+	env.OnActivity(acts.ReleaseDispatchSlot, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, input execution.DispatchSlotInput) error {
+			released = append(released, input)
+			return nil
+		})
+
+	var dispatchedAt time.Time
+	env.SetOnActivityStartedListener(func(info *activity.Info, _ context.Context, _ converter.EncodedValues) {
+		if info.ActivityType.Name == "DispatchStep" {
+			dispatchedAt = env.Now()
+		}
+	})
+
+	var queued api.StepState
+	env.RegisterDelayedCallback(func() {
+		val, err := env.QueryWorkflow("progress")
+		require.NoError(t, err)
+		var progress execution.MigrationResult
+		require.NoError(t, val.Get(&progress))
+		require.Len(t, progress.Results, 1)
+		queued = progress.Results[0]
+	}, 15*time.Second)
+
+	env.ExecuteWorkflow(execution.MigrationOrchestrator, api.MigrationManifest{
+		MigrationId: "mig-abc",
+		Candidates:  []api.Candidate{{Id: "billing-api"}},
+		Steps:       []api.StepDefinition{{Name: "update-chart", MigratorApp: "app-chart-migrator"}},
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.GreaterOrEqual(t, dispatchedAt.Sub(start), 30*time.Second)
+
+	require.Equal(t, api.StepStateStatusQueued, queued.Status)
+	require.NotNil(t, queued.Metadata)
+	require.Equal(t, "app-chart-migrator", (*queued.Metadata)["migratorApp"])
+	require.Equal(t, "1 dispatches in flight (max 1)", (*queued.Metadata)["queuedReason"])
+
+	require.Len(t, released, 1)
+	require.Equal(t, "app-chart-migrator", released[0].MigratorApp)
+	require.True(t, strings.HasSuffix(released[0].Holder, "/update-chart/billing-api/1"), released[0].Holder)
+
+	var result execution.MigrationResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, api.StepStateStatusSucceeded, result.Results[0].Status)
+	require.Nil(t, result.Results[0].Metadata, "the queued metadata is dropped once dispatched")
+}
+
+// TestMigrationOrchestrator_DispatchLimit_ReleasesSlotOnCancel verifies that a
+// slot held by a dispatch the migrator never answered is released when the run
+// is cancelled.
+func TestMigrationOrchestrator_DispatchLimit_ReleasesSlotOnCancel(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+
+	env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.DispatchStep, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(acts.AcquireDispatchSlot, mock.Anything, mock.Anything).
+		Return(migrations.DispatchSlotGrant{Acquired: true, Held: true}, nil)
+	releases := 0
+	env.OnActivity(acts.ReleaseDispatchSlot, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(mock.Arguments) { releases++ })
+	env.RegisterDelayedCallback(env.CancelWorkflow, time.Hour)
+
+	env.ExecuteWorkflow(execution.MigrationOrchestrator, api.MigrationManifest{
+		MigrationId: "mig-abc",
+		Candidates:  []api.Candidate{{Id: "billing-api"}},
+		Steps:       []api.StepDefinition{{Name: "update-chart", MigratorApp: "app-chart-migrator"}},
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.Equal(t, 1, releases)
+}
+
+// TestMigrationOrchestrator_DispatchLimit_RenewsSlotWhileOutstanding verifies
+// that a held slot is renewed while the migrator has not reported back, and no
+// longer once it has.
+func TestMigrationOrchestrator_DispatchLimit_RenewsSlotWhileOutstanding(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+
+	env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.DispatchStep, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(acts.AcquireDispatchSlot, mock.Anything, mock.Anything).
+		Return(migrations.DispatchSlotGrant{Acquired: true, Held: true}, nil)
+	env.OnActivity(acts.ReleaseDispatchSlot, mock.Anything, mock.Anything).Return(nil)
+	renewals := 0
+	env.OnActivity(acts.RenewDispatchSlot, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(mock.Arguments) { renewals++ })
+
+	var renewedBeforeCallback int
+	env.RegisterDelayedCallback(func() {
+		renewedBeforeCallback = renewals
+		env.SignalWorkflow(migrations.StepEventName("update-chart", "billing-api"), api.StepStatusEvent{
+			StepName:    "update-chart",
+			CandidateId: "billing-api",
+			Status:      api.StepStatusEventStatusSucceeded,
+		})
+	}, 11*time.Minute)
+
+	env.ExecuteWorkflow(execution.MigrationOrchestrator, api.MigrationManifest{
+		MigrationId: "mig-abc",
+		Candidates:  []api.Candidate{{Id: "billing-api"}},
+		Steps:       []api.StepDefinition{{Name: "update-chart", MigratorApp: "app-chart-migrator"}},
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Equal(t, 5, renewedBeforeCallback, "renewed every two minutes while outstanding")
+	require.Equal(t, renewedBeforeCallback, renewals, "not renewed once released")
+}
+
+// TestMigrationOrchestrator_DispatchLimit_NotRenewedByEarlierRuns verifies that
+// a run started before slots were renewed replays without renewing them.
+func TestMigrationOrchestrator_DispatchLimit_NotRenewedByEarlierRuns(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+
+	env.OnActivity(acts.FinishRun, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.RecordEvent, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil).Maybe()
+	env.OnActivity(acts.DispatchStep, mock.Anything, mock.Anything).Return(nil)
+	env.OnGetVersion("dispatch-slot", workflow.DefaultVersion, 2).Return(workflow.Version(1))
+	env.OnActivity(acts.AcquireDispatchSlot, mock.Anything, mock.Anything).
+		Return(migrations.DispatchSlotGrant{Acquired: true, Held: true}, nil)
+	releases := 0
+	env.OnActivity(acts.ReleaseDispatchSlot, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(mock.Arguments) { releases++ })
+	renewals := 0
+	env.OnActivity(acts.RenewDispatchSlot, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(mock.Arguments) { renewals++ })
+	env.RegisterDelayedCallback(env.CancelWorkflow, time.Hour)
+
+	env.ExecuteWorkflow(execution.MigrationOrchestrator, api.MigrationManifest{
+		MigrationId: "mig-abc",
+		Candidates:  []api.Candidate{{Id: "billing-api"}},
+		Steps:       []api.StepDefinition{{Name: "update-chart", MigratorApp: "app-chart-migrator"}},
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.Equal(t, 1, releases)
+	require.Zero(t, renewals)
+}
+
+// TestMigrationOrchestrator_DispatchLimit_NotAppliedByEarlierRuns verifies that
+// a run started before dispatch limits existed replays without acquiring or
+// releasing a slot.
+func TestMigrationOrchestrator_DispatchLimit_NotAppliedByEarlierRuns(t *testing.T) {
+	ts := &testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+
+	acts := newActivities()
+	env.RegisterActivity(acts)
+
+	dummyMigrator(env, acts)
+	env.OnActivity(acts.UpdateCandidateStatus, mock.Anything, mock.Anything).Return(nil)
+	env.OnGetVersion("dispatch-slot", workflow.DefaultVersion, 2).Return(workflow.DefaultVersion)
+	calls := 0
+	env.OnActivity(acts.AcquireDispatchSlot, mock.Anything, mock.Anything).
+		Return(migrations.DispatchSlotGrant{Acquired: true, Held: true}, nil).
+		Run(func(mock.Arguments) { calls++ })
+	env.OnActivity(acts.ReleaseDispatchSlot, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(mock.Arguments) { calls++ })
+
+	env.ExecuteWorkflow(execution.MigrationOrchestrator, api.MigrationManifest{
+		MigrationId: "mig-abc",
+		Candidates:  []api.Candidate{{Id: "billing-api"}},
+		Steps:       []api.StepDefinition{{Name: "update-chart", MigratorApp: "app-chart-migrator"}},
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Zero(t, calls)
 }
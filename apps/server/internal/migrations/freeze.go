@@ -0,0 +1,56 @@
+package migrations
+
+import "time"
+
+// Covers reports whether w holds back a step of migrationID with the given
+// config at t.
+func (w FreezeWindow) Covers(migrationID string, stepConfig map[string]string, t time.Time) bool {
+	if w.MigrationID != "" && w.MigrationID != migrationID {
+		return false
+	}
+	if t.Before(w.StartsAt) || !t.Before(w.EndsAt) {
+		return false
+	}
+	for k, v := range w.StepConfig {
+		if stepConfig[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// BlockingFreezeWindow returns the window covering the step at t that ends
+// last, or nil when the step may be dispatched.
+func BlockingFreezeWindow(
+	windows []FreezeWindow,
+	migrationID string,
+	stepConfig map[string]string,
+	t time.Time,
+) *FreezeWindow {
+	var blocking *FreezeWindow
+	for i, w := range windows {
+		if w.Covers(migrationID, stepConfig, t) && (blocking == nil || w.EndsAt.After(blocking.EndsAt)) {
+			blocking = &windows[i]
+		}
+	}
+	return blocking
+}
+
+// validateFreezeWindow checks a freeze window before it is saved.
+func validateFreezeWindow(w FreezeWindow) error {
+	if w.Reason == "" {
+		return InvalidFreezeWindowError{Reason: "reason is required"}
+	}
+	if w.StartsAt.IsZero() || w.EndsAt.IsZero() {
+		return InvalidFreezeWindowError{Reason: "startsAt and endsAt are required"}
+	}
+	if !w.EndsAt.After(w.StartsAt) {
+		return InvalidFreezeWindowError{Reason: "endsAt must be after startsAt"}
+	}
+	for k := range w.StepConfig {
+		if k == "" {
+			return InvalidFreezeWindowError{Reason: "stepConfig keys must not be empty"}
+		}
+	}
+	return nil
+}
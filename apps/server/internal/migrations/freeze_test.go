@@ -0,0 +1,51 @@
+package migrations_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+)
+
+func TestFreezeWindow_Covers(t *testing.T) {
+	start := time.Date(2026, 12, 20, 0, 0, 0, 0, time.UTC)
+	global := migrations.FreezeWindow{StartsAt: start, EndsAt: start.Add(24 * time.Hour)}
+	scoped := migrations.FreezeWindow{
+		MigrationID: "mig-1",
+		StartsAt:    start,
+		EndsAt:      start.Add(24 * time.Hour),
+		StepConfig:  map[string]string{"env": "prod"},
+	}
+	prod := map[string]string{"env": "prod", "region": "eu"}
+
+	assert.True(t, global.Covers("mig-2", nil, start), "global windows cover every migration")
+	assert.False(t, global.Covers("mig-2", nil, start.Add(-time.Second)), "not yet started")
+	assert.False(t, global.Covers("mig-2", nil, start.Add(24*time.Hour)), "the end is exclusive")
+
+	assert.True(t, scoped.Covers("mig-1", prod, start.Add(time.Hour)))
+	assert.False(t, scoped.Covers("mig-2", prod, start.Add(time.Hour)), "other migration")
+	assert.False(t, scoped.Covers("mig-1", map[string]string{"env": "staging"}, start.Add(time.Hour)))
+	assert.False(t, scoped.Covers("mig-1", nil, start.Add(time.Hour)), "step without the config key")
+}
+
+func TestBlockingFreezeWindow(t *testing.T) {
+	now := time.Date(2026, 12, 20, 12, 0, 0, 0, time.UTC)
+	windows := []migrations.FreezeWindow{
+		{ID: 1, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+		{ID: 2, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(3 * time.Hour), MigrationID: "mig-1"},
+		{ID: 3, StartsAt: now.Add(time.Hour), EndsAt: now.Add(5 * time.Hour)},
+	}
+
+	w := migrations.BlockingFreezeWindow(windows, "mig-1", nil, now)
+	require.NotNil(t, w)
+	assert.Equal(t, int64(2), w.ID, "the covering window that ends last")
+
+	w = migrations.BlockingFreezeWindow(windows, "mig-2", nil, now)
+	require.NotNil(t, w)
+	assert.Equal(t, int64(1), w.ID)
+
+	assert.Nil(t, migrations.BlockingFreezeWindow(windows, "mig-2", nil, now.Add(-2*time.Hour)))
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/platform/auth"
+)
+
+// anonymousActor is recorded as the actor when auth is disabled.
+const anonymousActor = "anonymous"
+
+// auditMigrationKey lets a handler name the migration a call acted on when it
+// is not in the URL (announcements carry it in the body).
+const auditMigrationKey = "audit.migrationId"
+
+// maxAuditedBody bounds the request body of an audited call; larger bodies are
+// refused with 413.
+const maxAuditedBody = 1 << 20
+
+// audited records the call in the audit log once the rest of the chain has run:
+// the caller, the route's migration and candidate, the request body and the
+// response status. It is mounted after the role check, so the body of a caller
+// without the route's role is never read and such calls are not recorded;
+// handlers that check the role on a migration named in the body are. A
+// failure to write the entry is logged; the response has already been sent.
+func (h *Handler) audited(action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w := &errorCapture{ResponseWriter: c.Writer}
+		c.Writer = w
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxAuditedBody))
+		if err != nil {
+			status := http.StatusBadRequest
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+			body = nil
+		} else {
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+			c.Next()
+		}
+
+		entry := migrations.AuditEntry{
+			Actor:       anonymousActor,
+			Action:      action,
+			MigrationID: c.Param("id"),
+			CandidateID: c.Param("candidateId"),
+			StatusCode:  w.Status(),
+			Outcome:     migrations.AuditSucceeded,
+		}
+		if p, ok := auth.PrincipalFrom(c); ok {
+			entry.Actor = p.Subject
+		}
+		if id := c.GetString(auditMigrationKey); id != "" {
+			entry.MigrationID = id
+		}
+		if json.Valid(body) {
+			entry.Payload = redactSecret(body)
+		}
+		if w.Status() >= http.StatusBadRequest {
+			entry.Outcome = migrations.AuditFailed
+			entry.Error = w.errorMessage()
+		}
+		if err := h.svc.RecordAudit(c.Request.Context(), entry); err != nil {
+			h.log.Error("failed to record audit entry", "action", action, "actor", entry.Actor, "error", err)
+		}
+	}
+}
+
+// redactSecret blanks the "secret" field of a JSON object body (webhook
+// subscriptions carry one) so that it does not end up in the audit log.
+func redactSecret(body []byte) []byte {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(body, &fields) != nil {
+		return body
+	}
+	if _, ok := fields["secret"]; !ok {
+		return body
+	}
+	fields["secret"] = json.RawMessage(`"[redacted]"`)
+	redacted, err := json.Marshal(fields)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// errorCapture keeps the body of error responses so the audit entry can say
+// why a call failed.
+type errorCapture struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *errorCapture) Write(b []byte) (int, error) {
+	if w.Status() >= http.StatusBadRequest {
+		w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *errorCapture) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// errorMessage returns the "error" field of a JSON error response, or the raw
+// body when it has none.
+func (w *errorCapture) errorMessage() string {
+	var resp struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(w.body.Bytes(), &resp) == nil && resp.Error != "" {
+		return resp.Error
+	}
+	return w.body.String()
+}
+
+// ListAudit handles GET /audit — a page of audit entries, newest first,
+// filtered by the migrationId, candidateId and actor query parameters. Pass the
+// previous page's nextBefore as before to fetch the next one. Entries for
+// migrations the caller may not operate are left out.
+func (h *Handler) ListAudit(c *gin.Context) {
+	filter := migrations.AuditFilter{
+		MigrationID: c.Query("migrationId"),
+		CandidateID: c.Query("candidateId"),
+		Actor:       c.Query("actor"),
+	}
+	if v := c.Query("before"); v != "" {
+		before, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || before <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "before must be a positive entry ID"})
+			return
+		}
+		filter.Before = before
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+			return
+		}
+		filter.Limit = limit
+	}
+
+	page, err := h.svc.ListAudit(c.Request.Context(), filter)
+	if err != nil {
+		h.log.Error("list audit failed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch audit log"})
+		return
+	}
+	visible := make([]migrations.AuditEntry, 0, len(page.Entries))
+	for _, e := range page.Entries {
+		if auth.Allowed(c, auth.RoleOperator, e.MigrationID) {
+			visible = append(visible, e)
+		}
+	}
+	page.Entries = visible
+	c.JSON(http.StatusOK, page)
+}
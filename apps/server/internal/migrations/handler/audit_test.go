@@ -0,0 +1,202 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// ─── Recording ───────────────────────────────────────────────────────────────
+
+func TestAudit_RecordsSuccessfulStart(t *testing.T) {
+	ts := newTestServer(t)
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{
+		Id:         "mig-abc",
+		Steps:      []api.StepDefinition{{Name: "update-chart", MigratorApp: "app-chart-migrator"}},
+		Candidates: []api.Candidate{{Id: "billing-api"}},
+	}))
+	inputs := map[string]string{"env": "prod"}
+
+	w := ts.do(http.MethodPost, "/migrations/mig-abc/candidates/billing-api/start", api.StartRequest{Inputs: &inputs})
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	require.Len(t, ts.audit.entries, 1)
+	e := ts.audit.entries[0]
+	assert.Equal(t, "anonymous", e.Actor)
+	assert.Equal(t, "start", e.Action)
+	assert.Equal(t, "mig-abc", e.MigrationID)
+	assert.Equal(t, "billing-api", e.CandidateID)
+	assert.JSONEq(t, `{"inputs":{"env":"prod"}}`, string(e.Payload))
+	assert.Equal(t, http.StatusAccepted, e.StatusCode)
+	assert.Equal(t, migrations.AuditSucceeded, e.Outcome)
+	assert.Empty(t, e.Error)
+}
+
+func TestAudit_RecordsFailedCallWithError(t *testing.T) {
+	ts := newTestServer(t)
+
+	w := ts.do(http.MethodPost, "/migrations/unknown/candidates/billing-api/cancel", nil)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Len(t, ts.audit.entries, 1)
+	e := ts.audit.entries[0]
+	assert.Equal(t, "cancel", e.Action)
+	assert.Equal(t, migrations.AuditFailed, e.Outcome)
+	assert.Equal(t, http.StatusNotFound, e.StatusCode)
+	assert.NotEmpty(t, e.Error)
+}
+
+func TestAudit_RecordsRefusedCallWithActor(t *testing.T) {
+	ts := newAuthTestServer(t)
+
+	w := ts.doAs("migrator", http.MethodPost, "/registry/announce", api.MigrationAnnouncement{
+		Id:          "mig-other",
+		Name:        "Other",
+		Steps:       []api.StepDefinition{{Name: "update-chart", MigratorApp: "app-chart-migrator"}},
+		MigratorUrl: "http://app-chart-migrator:3001",
+	})
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.Len(t, ts.audit.entries, 1)
+	e := ts.audit.entries[0]
+	assert.Equal(t, "migrator", e.Actor)
+	assert.Equal(t, "mig-other", e.MigrationID)
+	assert.Equal(t, migrations.AuditFailed, e.Outcome)
+	assert.NotEmpty(t, e.Error)
+}
+
+func TestAudit_CallerWithoutRouteRoleIsNotRecorded(t *testing.T) {
+	ts := newAuthTestServer(t)
+
+	w := ts.doAs("viewer", http.MethodPost, "/migrations/mig-abc/candidates/billing-api/start", nil)
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, ts.audit.entries)
+}
+
+func TestAudit_OversizedBodyIsRefused(t *testing.T) {
+	ts := newTestServer(t)
+	inputs := map[string]string{"blob": strings.Repeat("x", 1<<20)}
+
+	w := ts.do(http.MethodPost, "/migrations/mig-abc/candidates/billing-api/start", api.StartRequest{Inputs: &inputs})
+
+	require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	require.Len(t, ts.audit.entries, 1)
+	e := ts.audit.entries[0]
+	assert.Equal(t, migrations.AuditFailed, e.Outcome)
+	assert.Empty(t, e.Payload)
+}
+
+func TestAudit_AnnounceRecordsMigrationFromBody(t *testing.T) {
+	ts := newTestServer(t)
+
+	w := ts.do(http.MethodPost, "/registry/announce", api.MigrationAnnouncement{
+		Id:          "migrate-chart",
+		Name:        "Migrate chart",
+		Steps:       []api.StepDefinition{{Name: "update-chart", MigratorApp: "app-chart-migrator"}},
+		MigratorUrl: "http://app-chart-migrator:3001",
+	})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, ts.audit.entries, 1)
+	assert.Equal(t, "announce", ts.audit.entries[0].Action)
+	assert.Equal(t, "migrate-chart", ts.audit.entries[0].MigrationID)
+}
+
+func TestAudit_ReadsAreNotRecorded(t *testing.T) {
+	ts := newTestServer(t)
+
+	ts.do(http.MethodGet, "/migrations", nil)
+
+	assert.Empty(t, ts.audit.entries)
+}
+
+// ─── GET /audit ──────────────────────────────────────────────────────────────
+
+func seedAudit(ts *testServer, entries ...migrations.AuditEntry) {
+	for _, e := range entries {
+		_ = ts.audit.Append(context.Background(), e)
+	}
+}
+
+func decodeAuditPage(t *testing.T, body []byte) migrations.AuditPage {
+	t.Helper()
+	var page migrations.AuditPage
+	require.NoError(t, json.Unmarshal(body, &page))
+	return page
+}
+
+func TestListAudit_PagesNewestFirst(t *testing.T) {
+	ts := newTestServer(t)
+	seedAudit(ts,
+		migrations.AuditEntry{Actor: "alice", Action: "start", MigrationID: "mig-abc"},
+		migrations.AuditEntry{Actor: "alice", Action: "cancel", MigrationID: "mig-abc"},
+		migrations.AuditEntry{Actor: "bob", Action: "start", MigrationID: "mig-abc"},
+	)
+
+	w := ts.do(http.MethodGet, "/audit?limit=2", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	page := decodeAuditPage(t, w.Body.Bytes())
+	require.Len(t, page.Entries, 2)
+	assert.Equal(t, int64(3), page.Entries[0].ID)
+	assert.Equal(t, int64(2), page.Entries[1].ID)
+	require.NotNil(t, page.NextBefore)
+	assert.Equal(t, int64(2), *page.NextBefore)
+
+	w = ts.do(http.MethodGet, "/audit?limit=2&before=2", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	page = decodeAuditPage(t, w.Body.Bytes())
+	require.Len(t, page.Entries, 1)
+	assert.Equal(t, int64(1), page.Entries[0].ID)
+	assert.Nil(t, page.NextBefore)
+}
+
+func TestListAudit_Filters(t *testing.T) {
+	ts := newTestServer(t)
+	seedAudit(ts,
+		migrations.AuditEntry{Actor: "alice", Action: "start", MigrationID: "mig-abc", CandidateID: "billing-api"},
+		migrations.AuditEntry{Actor: "bob", Action: "start", MigrationID: "mig-abc", CandidateID: "payments-api"},
+		migrations.AuditEntry{Actor: "alice", Action: "start", MigrationID: "mig-other", CandidateID: "billing-api"},
+	)
+
+	page := decodeAuditPage(t, ts.do(http.MethodGet, "/audit?migrationId=mig-abc&actor=alice", nil).Body.Bytes())
+	require.Len(t, page.Entries, 1)
+	assert.Equal(t, int64(1), page.Entries[0].ID)
+
+	page = decodeAuditPage(t, ts.do(http.MethodGet, "/audit?candidateId=billing-api", nil).Body.Bytes())
+	assert.Len(t, page.Entries, 2)
+}
+
+func TestListAudit_InvalidParams_Returns400(t *testing.T) {
+	ts := newTestServer(t)
+
+	assert.Equal(t, http.StatusBadRequest, ts.do(http.MethodGet, "/audit?before=abc", nil).Code)
+	assert.Equal(t, http.StatusBadRequest, ts.do(http.MethodGet, "/audit?limit=0", nil).Code)
+}
+
+func TestListAudit_OnlyShowsMigrationsTheCallerOperates(t *testing.T) {
+	ts := newAuthTestServer(t)
+	seedAudit(ts,
+		migrations.AuditEntry{Actor: "alice", Action: "start", MigrationID: "mig-abc"},
+		migrations.AuditEntry{Actor: "alice", Action: "start", MigrationID: "mig-other"},
+	)
+
+	assert.Equal(t, http.StatusForbidden, ts.doAs("viewer", http.MethodGet, "/audit", nil).Code)
+
+	w := ts.doAs("operator", http.MethodGet, "/audit", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	page := decodeAuditPage(t, w.Body.Bytes())
+	require.Len(t, page.Entries, 1)
+	assert.Equal(t, "mig-abc", page.Entries[0].MigrationID)
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// BulkStart handles POST /migrations/:id/bulk-start — queues every candidate
+// matching the selector and starts them in waves of at most maxInFlight runs.
+func (h *Handler) BulkStart(c *gin.Context) {
+	id := c.Param("id")
+
+	span := trace.SpanFromContext(c.Request.Context())
+	span.SetAttributes(attribute.String("migration.id", id))
+
+	var req api.BulkStartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.svc.BulkStart(c.Request.Context(), id, req)
+	if err != nil {
+		var noneSelected migrations.NoCandidatesSelectedError
+		if errors.As(err, &noneSelected) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		var migNotFound migrations.MigrationNotFoundError
+		if errors.As(err, &migNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.log.Error("failed to bulk start", "id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, resp)
+}
+
+// GetBulkStart handles GET /migrations/:id/bulk-starts/:bulkId — returns how far
+// a bulk start has got.
+func (h *Handler) GetBulkStart(c *gin.Context) {
+	id := c.Param("id")
+	bulkID := c.Param("bulkId")
+
+	progress, err := h.svc.GetBulkStart(c.Request.Context(), id, bulkID)
+	if err != nil {
+		h.log.Error("failed to get bulk start", "id", id, "bulkId", bulkID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if progress == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "bulk start not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
@@ -0,0 +1,106 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// ─── POST /migrations/:id/bulk-start ──────────────────────────────────────────
+
+func TestBulkStart_Returns202WithQueuedCandidates(t *testing.T) {
+	ts := newTestServer(t)
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{
+		Id:    "mig-abc",
+		Steps: []api.StepDefinition{{Name: "update-chart", MigratorApp: "app-chart-migrator"}},
+		Candidates: []api.Candidate{
+			{Id: "billing-api", Kind: "application"},
+			{Id: "payments-api", Kind: "application"},
+		},
+	}))
+
+	kind := "application"
+	w := ts.do(http.MethodPost, "/migrations/mig-abc/bulk-start", api.BulkStartRequest{
+		Selector:    api.CandidateSelector{Kind: &kind},
+		MaxInFlight: 1,
+	})
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var resp api.BulkStartResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []string{"billing-api", "payments-api"}, resp.CandidateIds)
+	assert.NotEmpty(t, resp.Id)
+}
+
+func TestBulkStart_NoMatch_Returns400(t *testing.T) {
+	ts := newTestServer(t)
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{
+		Id:         "mig-abc",
+		Candidates: []api.Candidate{{Id: "billing-api", Kind: "application"}},
+	}))
+
+	kind := "kafka-topic"
+	w := ts.do(http.MethodPost, "/migrations/mig-abc/bulk-start", api.BulkStartRequest{
+		Selector:    api.CandidateSelector{Kind: &kind},
+		MaxInFlight: 1,
+	})
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestBulkStart_MigrationNotFound_Returns404(t *testing.T) {
+	ts := newTestServer(t)
+
+	w := ts.do(http.MethodPost, "/migrations/unknown/bulk-start", api.BulkStartRequest{MaxInFlight: 1})
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestBulkStart_ZeroMaxInFlight_FailsValidation(t *testing.T) {
+	ts := newTestServerWithValidation(t)
+
+	w := ts.do(http.MethodPost, "/migrations/mig-abc/bulk-start", map[string]any{
+		"selector":    map[string]any{},
+		"maxInFlight": 0,
+	})
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// ─── GET /migrations/:id/bulk-starts/:bulkId ─────────────────────────────────
+
+func TestGetBulkStart_Returns200(t *testing.T) {
+	ts := newTestServer(t)
+	bulkID := migrations.BulkStartID("mig-abc", "xyz")
+	ts.engine.queryFn = func(_ context.Context, id, _ string, out any) error {
+		*out.(*api.BulkStartProgress) = api.BulkStartProgress{
+			Id:     id,
+			Status: api.BulkStartProgressStatusRunning,
+			Total:  2,
+		}
+		return nil
+	}
+
+	w := ts.do(http.MethodGet, "/migrations/mig-abc/bulk-starts/"+bulkID, nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var progress api.BulkStartProgress
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &progress))
+	assert.Equal(t, bulkID, progress.Id)
+	assert.Equal(t, 2, progress.Total)
+}
+
+func TestGetBulkStart_NotFound_Returns404(t *testing.T) {
+	ts := newTestServer(t)
+
+	w := ts.do(http.MethodGet, "/migrations/mig-abc/bulk-starts/unknown", nil)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
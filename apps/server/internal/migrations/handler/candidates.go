@@ -4,6 +4,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"slices"
 
 	"github.com/gin-gonic/gin"
 	"go.opentelemetry.io/otel/attribute"
@@ -90,6 +91,85 @@ func (h *Handler) CancelRun(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// PauseRun handles POST /migrations/:id/candidates/:candidateId/pause —
+// holds the active run before its next step dispatch.
+func (h *Handler) PauseRun(c *gin.Context) {
+	id := c.Param("id")
+	candidateID := c.Param("candidateId")
+
+	if err := h.svc.Pause(c.Request.Context(), id, candidateID); err != nil {
+		var notRunning migrations.CandidateNotRunningError
+		if errors.As(err, &notRunning) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		var migNotFound migrations.MigrationNotFoundError
+		var candNotFound migrations.CandidateNotFoundError
+		if errors.As(err, &migNotFound) || errors.As(err, &candNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.log.Error("failed to pause run", "id", id, "candidateId", candidateID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// ResumeRun handles POST /migrations/:id/candidates/:candidateId/resume —
+// lets a paused run continue from the step where it stopped.
+func (h *Handler) ResumeRun(c *gin.Context) {
+	id := c.Param("id")
+	candidateID := c.Param("candidateId")
+
+	if err := h.svc.Resume(c.Request.Context(), id, candidateID); err != nil {
+		var notRunning migrations.CandidateNotRunningError
+		if errors.As(err, &notRunning) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		var migNotFound migrations.MigrationNotFoundError
+		var candNotFound migrations.CandidateNotFoundError
+		if errors.As(err, &migNotFound) || errors.As(err, &candNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.log.Error("failed to resume run", "id", id, "candidateId", candidateID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// RollbackRun handles POST /migrations/:id/candidates/:candidateId/rollback —
+// starts a run that dispatches the compensations of the candidate's completed steps.
+func (h *Handler) RollbackRun(c *gin.Context) {
+	id := c.Param("id")
+	candidateID := c.Param("candidateId")
+
+	if err := h.svc.Rollback(c.Request.Context(), id, candidateID); err != nil {
+		var notAllowed migrations.RollbackNotAllowedError
+		if errors.As(err, &notAllowed) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		var migNotFound migrations.MigrationNotFoundError
+		var candNotFound migrations.CandidateNotFoundError
+		var versionNotFound migrations.MigrationVersionNotFoundError
+		if errors.As(err, &migNotFound) || errors.As(err, &candNotFound) || errors.As(err, &versionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.log.Error("failed to start rollback", "id", id, "candidateId", candidateID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
 // RetryStep handles POST /migrations/:id/candidates/:candidateId/retry-step —
 // raises a retry-step signal into the active run, re-dispatching the named step.
 func (h *Handler) RetryStep(c *gin.Context) {
@@ -122,6 +202,83 @@ func (h *Handler) RetryStep(c *gin.Context) {
 	c.Status(http.StatusAccepted)
 }
 
+// SkipStep handles POST /migrations/:id/candidates/:candidateId/skip-step —
+// raises a skip-step signal so the run records the step as skipped and moves on.
+func (h *Handler) SkipStep(c *gin.Context) {
+	id := c.Param("id")
+	candidateID := c.Param("candidateId")
+
+	var req api.SkipStepRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.svc.SkipStep(c.Request.Context(), id, candidateID, req); err != nil {
+		var notRunning migrations.CandidateNotRunningError
+		var notSkippable migrations.StepNotSkippableError
+		if errors.As(err, &notRunning) || errors.As(err, &notSkippable) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		var migNotFound migrations.MigrationNotFoundError
+		var candNotFound migrations.CandidateNotFoundError
+		var runNotFound migrations.RunNotFoundError
+		if errors.As(err, &migNotFound) || errors.As(err, &candNotFound) || errors.As(err, &runNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.log.Error("failed to skip step", "id", id, "candidateId", candidateID, "step", req.StepName, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// terminalStepStatuses are the outcomes an operator may report for a step.
+var terminalStepStatuses = []api.StepStatusEventStatus{
+	api.StepStatusEventStatusSucceeded,
+	api.StepStatusEventStatusFailed,
+	api.StepStatusEventStatusMerged,
+}
+
+// CompleteStep handles POST /migrations/:id/candidates/:candidateId/complete-step —
+// an operator reports a step's outcome, e.g. approving a manual review.
+func (h *Handler) CompleteStep(c *gin.Context) {
+	id := c.Param("id")
+	candidateID := c.Param("candidateId")
+
+	var req api.CompleteStepRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !slices.Contains(terminalStepStatuses, api.StepStatusEventStatus(req.Status)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be succeeded, failed or merged"})
+		return
+	}
+
+	if err := h.svc.CompleteStep(c.Request.Context(), id, candidateID, req); err != nil {
+		var notRunning migrations.CandidateNotRunningError
+		if errors.As(err, &notRunning) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		var migNotFound migrations.MigrationNotFoundError
+		var candNotFound migrations.CandidateNotFoundError
+		if errors.As(err, &migNotFound) || errors.As(err, &candNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.log.Error("failed to complete step", "id", id, "candidateId", candidateID, "step", req.StepName, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
 // UpdateInputs handles PATCH /migrations/:id/candidates/:candidateId/inputs —
 // updates operator-supplied inputs in the candidate's metadata.
 func (h *Handler) UpdateInputs(c *gin.Context) {
@@ -173,3 +330,51 @@ func (h *Handler) GetCandidateSteps(c *gin.Context) {
 
 	c.JSON(http.StatusOK, resp)
 }
+
+// ListRuns handles GET /migrations/:id/candidates/:candidateId/runs —
+// lists every run attempt of the candidate, newest first.
+func (h *Handler) ListRuns(c *gin.Context) {
+	id := c.Param("id")
+	candidateID := c.Param("candidateId")
+
+	runs, err := h.svc.ListRuns(c.Request.Context(), id, candidateID)
+	if err != nil {
+		var migNotFound migrations.MigrationNotFoundError
+		var candNotFound migrations.CandidateNotFoundError
+		if errors.As(err, &migNotFound) || errors.As(err, &candNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.log.Error("failed to list runs", "id", id, "candidateId", candidateID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.ListRunsResponse{Runs: runs})
+}
+
+// ResetCandidate handles POST /migrations/:id/candidates/:candidateId/reset —
+// returns a completed candidate to not_started so it can be run again.
+func (h *Handler) ResetCandidate(c *gin.Context) {
+	id := c.Param("id")
+	candidateID := c.Param("candidateId")
+
+	if err := h.svc.Reset(c.Request.Context(), id, candidateID); err != nil {
+		var notCompleted migrations.CandidateNotCompletedError
+		if errors.As(err, &notCompleted) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		var migNotFound migrations.MigrationNotFoundError
+		var candNotFound migrations.CandidateNotFoundError
+		if errors.As(err, &migNotFound) || errors.As(err, &candNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.log.Error("failed to reset candidate", "id", id, "candidateId", candidateID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -173,6 +173,287 @@ func TestRetryStep_CandidateNotRunning_Returns409(t *testing.T) {
 	require.Equal(t, http.StatusConflict, w.Code)
 }
 
+// ─── POST /migrations/:id/candidates/:candidateId/skip-step ──────────────────
+
+func TestSkipStep_Success(t *testing.T) {
+	ts := newTestServer(t)
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{
+		Id:         "mig-abc",
+		Candidates: []api.Candidate{{Id: "billing-api", Status: api.CandidateStatusRunning}},
+	}))
+	ts.engine.getStatusFn = func(_ context.Context, _ string) (*migrations.RunStatus, error) {
+		return &migrations.RunStatus{
+			RuntimeStatus: "RUNNING",
+			Steps: []api.StepState{{
+				StepName: "update-chart", Candidate: api.Candidate{Id: "billing-api"}, Status: api.StepStateStatusFailed,
+			}},
+		}, nil
+	}
+
+	w := ts.do(http.MethodPost, "/migrations/mig-abc/candidates/billing-api/skip-step",
+		api.SkipStepRequest{StepName: "update-chart", Reason: "done by hand"})
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+}
+
+func TestSkipStep_StepNotSkippable_Returns409(t *testing.T) {
+	ts := newTestServer(t)
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{
+		Id:         "mig-abc",
+		Candidates: []api.Candidate{{Id: "billing-api", Status: api.CandidateStatusRunning}},
+	}))
+
+	w := ts.do(http.MethodPost, "/migrations/mig-abc/candidates/billing-api/skip-step",
+		api.SkipStepRequest{StepName: "update-chart", Reason: "done by hand"})
+
+	require.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestSkipStep_MigrationNotFound_Returns404(t *testing.T) {
+	ts := newTestServer(t)
+
+	w := ts.do(http.MethodPost, "/migrations/unknown/candidates/billing-api/skip-step",
+		api.SkipStepRequest{StepName: "update-chart", Reason: "done by hand"})
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSkipStep_MissingReason_Returns400(t *testing.T) {
+	ts := newTestServerWithValidation(t)
+
+	w := ts.do(http.MethodPost, "/migrations/mig-abc/candidates/billing-api/skip-step",
+		map[string]string{"stepName": "update-chart"})
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// ─── POST /migrations/:id/candidates/:candidateId/pause|resume ───────────────
+
+func TestPauseRun_Returns202(t *testing.T) {
+	ts := newTestServer(t)
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{
+		Id:         "mig-abc",
+		Candidates: []api.Candidate{{Id: "billing-api", Status: api.CandidateStatusRunning}},
+	}))
+	var raised string
+	ts.engine.raiseEventFn = func(_ context.Context, _, event string, _ any) error {
+		raised = event
+		return nil
+	}
+
+	w := ts.do(http.MethodPost, "/migrations/mig-abc/candidates/billing-api/pause", nil)
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, migrations.PauseStateEventName("billing-api"), raised)
+}
+
+func TestPauseRun_MigrationNotFound_Returns404(t *testing.T) {
+	ts := newTestServer(t)
+
+	w := ts.do(http.MethodPost, "/migrations/unknown/candidates/billing-api/pause", nil)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPauseRun_CandidateNotRunning_Returns409(t *testing.T) {
+	ts := newTestServer(t)
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{
+		Id:         "mig-abc",
+		Candidates: []api.Candidate{{Id: "billing-api", Status: api.CandidateStatusNotStarted}},
+	}))
+
+	w := ts.do(http.MethodPost, "/migrations/mig-abc/candidates/billing-api/pause", nil)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestResumeRun_Returns202(t *testing.T) {
+	ts := newTestServer(t)
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{
+		Id:         "mig-abc",
+		Candidates: []api.Candidate{{Id: "billing-api", Status: api.CandidateStatusRunning}},
+	}))
+	var raised string
+	ts.engine.raiseEventFn = func(_ context.Context, _, event string, _ any) error {
+		raised = event
+		return nil
+	}
+
+	w := ts.do(http.MethodPost, "/migrations/mig-abc/candidates/billing-api/resume", nil)
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, migrations.PauseStateEventName("billing-api"), raised)
+}
+
+// ─── POST /migrations/:id/candidates/:candidateId/rollback ────────────────────
+
+func TestRollbackRun_Returns202(t *testing.T) {
+	ts := newTestServer(t)
+	revert := "revert-chart"
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{
+		Id:         "mig-abc",
+		Candidates: []api.Candidate{{Id: "billing-api", Status: api.CandidateStatusCompleted}},
+		Steps:      []api.StepDefinition{{Name: "swap-chart", MigratorApp: "app", CompensationType: &revert}},
+	}))
+	ts.engine.getStatusFn = func(_ context.Context, _ string) (*migrations.RunStatus, error) {
+		return &migrations.RunStatus{RuntimeStatus: migrations.RuntimeStatusCompleted}, nil
+	}
+	ts.engine.queryFn = func(_ context.Context, _, _ string, out any) error {
+		raw, _ := json.Marshal(map[string]any{"results": []api.StepState{
+			{StepName: "swap-chart", Candidate: api.Candidate{Id: "billing-api"}, Status: api.StepStateStatusSucceeded},
+		}})
+		return json.Unmarshal(raw, out)
+	}
+	var startedType string
+	ts.engine.startFn = func(_ context.Context, name, id string, _ any) (string, error) {
+		startedType = name
+		return id, nil
+	}
+
+	w := ts.do(http.MethodPost, "/migrations/mig-abc/candidates/billing-api/rollback", nil)
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, migrations.RollbackRunType, startedType)
+}
+
+func TestRollbackRun_RunInProgress_Returns409(t *testing.T) {
+	ts := newTestServer(t)
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{
+		Id:         "mig-abc",
+		Candidates: []api.Candidate{{Id: "billing-api", Status: api.CandidateStatusRunning}},
+	}))
+
+	w := ts.do(http.MethodPost, "/migrations/mig-abc/candidates/billing-api/rollback", nil)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestRollbackRun_MigrationNotFound_Returns404(t *testing.T) {
+	ts := newTestServer(t)
+
+	w := ts.do(http.MethodPost, "/migrations/unknown/candidates/billing-api/rollback", nil)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// ─── POST /migrations/:id/candidates/:candidateId/complete-step ───────────────
+
+func TestCompleteStep_RaisesEventOnCurrentRun(t *testing.T) {
+	ts := newTestServer(t)
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{
+		Id:         "mig-abc",
+		Candidates: []api.Candidate{{Id: "billing-api", Status: api.CandidateStatusRunning}},
+	}))
+	var runID string
+	var raised api.StepStatusEvent
+	ts.engine.raiseEventFn = func(_ context.Context, id, _ string, payload any) error {
+		runID = id
+		raised, _ = payload.(api.StepStatusEvent)
+		return nil
+	}
+
+	w := ts.do(http.MethodPost, "/migrations/mig-abc/candidates/billing-api/complete-step", api.CompleteStepRequest{
+		StepName: "review-argocd",
+		Status:   "succeeded",
+	})
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, "mig-abc__billing-api", runID)
+	assert.Equal(t, "billing-api", raised.CandidateId)
+	assert.Equal(t, api.StepStatusEventStatusSucceeded, raised.Status)
+}
+
+func TestCompleteStep_InvalidStatus_Returns400(t *testing.T) {
+	ts := newTestServer(t)
+
+	w := ts.do(http.MethodPost, "/migrations/mig-abc/candidates/billing-api/complete-step", api.CompleteStepRequest{
+		StepName: "review-argocd",
+		Status:   "pending",
+	})
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCompleteStep_NotRunning_Returns409(t *testing.T) {
+	ts := newTestServer(t)
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{
+		Id:         "mig-abc",
+		Candidates: []api.Candidate{{Id: "billing-api", Status: api.CandidateStatusNotStarted}},
+	}))
+
+	w := ts.do(http.MethodPost, "/migrations/mig-abc/candidates/billing-api/complete-step", api.CompleteStepRequest{
+		StepName: "review-argocd",
+		Status:   "succeeded",
+	})
+
+	require.Equal(t, http.StatusConflict, w.Code)
+}
+
+// ─── POST /migrations/:id/candidates/:candidateId/reset ───────────────────────
+
+func TestResetCandidate_Returns204(t *testing.T) {
+	ts := newTestServer(t)
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{
+		Id:         "mig-abc",
+		Candidates: []api.Candidate{{Id: "billing-api", Status: api.CandidateStatusCompleted}},
+	}))
+
+	w := ts.do(http.MethodPost, "/migrations/mig-abc/candidates/billing-api/reset", nil)
+
+	require.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestResetCandidate_NotCompleted_Returns409(t *testing.T) {
+	ts := newTestServer(t)
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{
+		Id:         "mig-abc",
+		Candidates: []api.Candidate{{Id: "billing-api", Status: api.CandidateStatusRunning}},
+	}))
+
+	w := ts.do(http.MethodPost, "/migrations/mig-abc/candidates/billing-api/reset", nil)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestResetCandidate_MigrationNotFound_Returns404(t *testing.T) {
+	ts := newTestServer(t)
+
+	w := ts.do(http.MethodPost, "/migrations/unknown/candidates/billing-api/reset", nil)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// ─── GET /migrations/:id/candidates/:candidateId/runs ─────────────────────────
+
+func TestListRuns_Returns200(t *testing.T) {
+	ts := newTestServer(t)
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{
+		Id:         "mig-abc",
+		Candidates: []api.Candidate{{Id: "billing-api", Status: api.CandidateStatusCompleted}},
+	}))
+	ts.store.runs = []api.RunAttempt{{
+		RunId: "mig-abc__billing-api", MigrationId: "mig-abc", CandidateId: "billing-api",
+		Attempt: 1, Type: api.RunAttemptTypeMigration, Status: api.RunAttemptStatusCompleted,
+	}}
+
+	w := ts.do(http.MethodGet, "/migrations/mig-abc/candidates/billing-api/runs", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp api.ListRunsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Runs, 1)
+	assert.Equal(t, api.RunAttemptStatusCompleted, resp.Runs[0].Status)
+}
+
+func TestListRuns_CandidateNotFound_Returns404(t *testing.T) {
+	ts := newTestServer(t)
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{Id: "mig-abc"}))
+
+	w := ts.do(http.MethodGet, "/migrations/mig-abc/candidates/billing-api/runs", nil)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
 // ─── GET /migrations/:id/candidates/:candidateId/steps ────────────────────────
 
 func TestGetCandidateSteps_NotFound_Returns404(t *testing.T) {
@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/platform/auth"
 	"github.com/tilsley/loom/pkg/api"
 )
 
@@ -19,6 +22,12 @@ func (h *Handler) Event(c *gin.Context) {
 	}
 
 	if err := h.svc.HandleEvent(c.Request.Context(), id, event); err != nil {
+		var duplicate migrations.DuplicateEventError
+		if errors.As(err, &duplicate) {
+			h.log.Info("ignored duplicate event", "instanceId", id, "eventId", duplicate.EventID)
+			c.JSON(http.StatusAccepted, gin.H{"status": "duplicate"})
+			return
+		}
 		h.log.Error("failed to handle event", "instanceId", id, "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -34,9 +43,28 @@ func (h *Handler) Announce(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	c.Set(auditMigrationKey, announcement.Id)
+	if !auth.Allowed(c, auth.RoleMigrator, announcement.Id) {
+		auth.Forbid(c, auth.RoleMigrator, announcement.Id)
+		return
+	}
 
 	m, err := h.svc.Announce(c.Request.Context(), announcement)
 	if err != nil {
+		var invalidGraph migrations.InvalidStepGraphError
+		var invalidCondition migrations.InvalidStepConditionError
+		var invalidLimit migrations.InvalidDispatchLimitError
+		var invalidID migrations.InvalidMigrationIDError
+		if errors.As(err, &invalidGraph) || errors.As(err, &invalidCondition) ||
+			errors.As(err, &invalidLimit) || errors.As(err, &invalidID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		var limitConflict migrations.DispatchLimitConflictError
+		if errors.As(err, &limitConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		h.log.Error("failed to handle announcement", "id", announcement.Id, "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -3,13 +3,17 @@ package handler_test
 import (
 	"bytes"
 	"context"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/migrations/handler"
 	"github.com/tilsley/loom/pkg/api"
 )
 
@@ -33,6 +37,53 @@ func TestEvent_RaisesSignal(t *testing.T) {
 	assert.NotEmpty(t, raised)
 }
 
+// claimingEventStore remembers claimed callback event IDs and ignores everything else.
+type claimingEventStore struct {
+	migrations.EventStore
+	claimed map[string]bool
+}
+
+func (s *claimingEventStore) ClaimCallback(_ context.Context, runID, eventID string) (bool, error) {
+	if s.claimed[runID+"/"+eventID] {
+		return false, nil
+	}
+	s.claimed[runID+"/"+eventID] = true
+	return true, nil
+}
+
+func (s *claimingEventStore) RecordEvent(context.Context, migrations.StepEvent) error { return nil }
+
+func TestEvent_DuplicateEventID_AcceptedWithoutRaising(t *testing.T) {
+	ts := newTestServer(t)
+	raised := 0
+	ts.engine.raiseEventFn = func(context.Context, string, string, any) error {
+		raised++
+		return nil
+	}
+	events := &claimingEventStore{claimed: map[string]bool{}}
+	ts.router = gin.New()
+	svc := migrations.NewService(
+		ts.engine, ts.store, ts.dryRun, events, ts.audit, ts.progress, ts.webhooks, ts.freezes, ts.schedules, nil,
+		ts.migrators,
+	)
+	handler.RegisterRoutes(ts.router, svc, slog.Default(), nil)
+
+	eventID := "evt-1"
+	event := api.StepStatusEvent{
+		StepName:    "update-chart",
+		CandidateId: "billing-api",
+		Status:      api.StepStatusEventStatusMerged,
+		EventId:     &eventID,
+	}
+	first := ts.do(http.MethodPost, "/event/mig__billing-api", event)
+	second := ts.do(http.MethodPost, "/event/mig__billing-api", event)
+
+	require.Equal(t, http.StatusAccepted, first.Code)
+	require.Equal(t, http.StatusAccepted, second.Code)
+	assert.JSONEq(t, `{"status":"duplicate"}`, second.Body.String())
+	assert.Equal(t, 1, raised)
+}
+
 func TestEvent_BadJSON_Returns400(t *testing.T) {
 	ts := newTestServer(t)
 	req := httptest.NewRequest(http.MethodPost, "/event/run-123",
@@ -64,3 +115,54 @@ func TestAnnounce_DirectJSON(t *testing.T) {
 	require.NotNil(t, m)
 	assert.Equal(t, "Migrate chart", m.Name)
 }
+
+func TestAnnounce_InvalidStepGraph(t *testing.T) {
+	ts := newTestServer(t)
+
+	missing := []string{"does-not-exist"}
+	ann := api.MigrationAnnouncement{
+		Id:          "migrate-chart",
+		Name:        "Migrate chart",
+		Steps:       []api.StepDefinition{{Name: "update-chart", MigratorApp: "app-chart-migrator", DependsOn: &missing}},
+		MigratorUrl: "http://app-chart-migrator:3001",
+	}
+
+	w := ts.do(http.MethodPost, "/registry/announce", ann)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "does-not-exist")
+}
+
+func TestAnnounce_InvalidDispatchLimits(t *testing.T) {
+	ts := newTestServer(t)
+
+	limits := []api.DispatchLimit{{MigratorApp: "app-chart-migrator"}, {MigratorApp: "app-chart-migrator"}}
+	ann := api.MigrationAnnouncement{
+		Id:             "migrate-chart",
+		Name:           "Migrate chart",
+		Steps:          []api.StepDefinition{{Name: "update-chart", MigratorApp: "app-chart-migrator"}},
+		MigratorUrl:    "http://app-chart-migrator:3001",
+		DispatchLimits: &limits,
+	}
+
+	w := ts.do(http.MethodPost, "/registry/announce", ann)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "declared more than once")
+}
+
+func TestAnnounce_InvalidMigrationID(t *testing.T) {
+	ts := newTestServer(t)
+
+	ann := api.MigrationAnnouncement{
+		Id:          "bulk:migrate-chart",
+		Name:        "Migrate chart",
+		Steps:       []api.StepDefinition{{Name: "update-chart", MigratorApp: "app-chart-migrator"}},
+		MigratorUrl: "http://app-chart-migrator:3001",
+	}
+
+	w := ts.do(http.MethodPost, "/registry/announce", ann)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "must not contain")
+}
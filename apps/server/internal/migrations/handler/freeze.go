@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/platform/auth"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// everyMigration stands for all migrations in role checks: only grants whose
+// patterns match any migration ID (such as "*") match it. Global freeze
+// windows need the operator role on every migration.
+const everyMigration = "*"
+
+// CreateFreezeWindow handles POST /freeze-windows — adds a window during which
+// matching steps are held back instead of dispatched.
+func (h *Handler) CreateFreezeWindow(c *gin.Context) {
+	var req api.CreateFreezeWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	window := migrations.FreezeWindow{Reason: req.Reason, StartsAt: req.StartsAt, EndsAt: req.EndsAt}
+	if req.MigrationId != nil {
+		window.MigrationID = *req.MigrationId // global when omitted
+	}
+	if req.StepConfig != nil {
+		window.StepConfig = *req.StepConfig
+	}
+	c.Set(auditMigrationKey, window.MigrationID)
+	if scope := freezeScope(window.MigrationID); !auth.Allowed(c, auth.RoleOperator, scope) {
+		auth.Forbid(c, auth.RoleOperator, scope)
+		return
+	}
+
+	w, err := h.svc.CreateFreezeWindow(c.Request.Context(), window)
+	if err != nil {
+		h.freezeWindowError(c, "failed to create freeze window", err)
+		return
+	}
+	c.JSON(http.StatusCreated, w)
+}
+
+// ListFreezeWindows handles GET /freeze-windows — the windows that have not
+// ended yet, or all of them with includeEnded=true, ordered by start time. With
+// migrationId, only that migration's windows and the global ones are returned.
+// Windows of migrations the caller may not view are left out.
+func (h *Handler) ListFreezeWindows(c *gin.Context) {
+	filter := migrations.FreezeWindowFilter{MigrationID: c.Query("migrationId")}
+	if c.Query("includeEnded") != "true" {
+		filter.EndsAfter = time.Now()
+	}
+
+	windows, err := h.svc.ListFreezeWindows(c.Request.Context(), filter)
+	if err != nil {
+		h.freezeWindowError(c, "failed to list freeze windows", err)
+		return
+	}
+	visible := make([]migrations.FreezeWindow, 0, len(windows))
+	for _, w := range windows {
+		if w.MigrationID == "" || auth.Allowed(c, auth.RoleViewer, w.MigrationID) {
+			visible = append(visible, w)
+		}
+	}
+	c.JSON(http.StatusOK, visible)
+}
+
+// DeleteFreezeWindow handles DELETE /freeze-windows/:windowId. Steps the
+// window holds back are dispatched once their run next checks the calendar.
+func (h *Handler) DeleteFreezeWindow(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("windowId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "windowId must be a number"})
+		return
+	}
+
+	w, err := h.svc.GetFreezeWindow(c.Request.Context(), id)
+	if err != nil {
+		h.freezeWindowError(c, "failed to get freeze window", err)
+		return
+	}
+	c.Set(auditMigrationKey, w.MigrationID)
+	if scope := freezeScope(w.MigrationID); !auth.Allowed(c, auth.RoleOperator, scope) {
+		auth.Forbid(c, auth.RoleOperator, scope)
+		return
+	}
+
+	if err := h.svc.DeleteFreezeWindow(c.Request.Context(), id); err != nil {
+		h.freezeWindowError(c, "failed to delete freeze window", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// freezeScope returns the migration a role check for a window is made against.
+func freezeScope(migrationID string) string {
+	if migrationID == "" {
+		return everyMigration
+	}
+	return migrationID
+}
+
+// freezeWindowError maps the errors of the freeze window service methods to responses.
+func (h *Handler) freezeWindowError(c *gin.Context, msg string, err error) {
+	var invalid migrations.InvalidFreezeWindowError
+	if errors.As(err, &invalid) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var migNotFound migrations.MigrationNotFoundError
+	var windowNotFound migrations.FreezeWindowNotFoundError
+	if errors.As(err, &migNotFound) || errors.As(err, &windowNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	var unavailable migrations.FreezeCalendarUnavailableError
+	if errors.As(err, &unavailable) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	h.log.Error(msg, "error", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,151 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/platform/auth"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+func newFreezeTestServer(t *testing.T) *testServer {
+	t.Helper()
+	ts := newTestServer(t)
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{Id: "mig-abc"}))
+	return ts
+}
+
+func freezeBody(migrationID string, startsAt, endsAt time.Time) map[string]any {
+	body := map[string]any{
+		"reason":     "holiday freeze",
+		"startsAt":   startsAt.Format(time.RFC3339),
+		"endsAt":     endsAt.Format(time.RFC3339),
+		"stepConfig": map[string]string{"env": "prod"},
+	}
+	if migrationID != "" {
+		body["migrationId"] = migrationID
+	}
+	return body
+}
+
+// ─── POST /freeze-windows ────────────────────────────────────────────────────
+
+func TestCreateFreezeWindow_GlobalAndPerMigration(t *testing.T) {
+	ts := newFreezeTestServer(t)
+	now := time.Now()
+
+	w := ts.do(http.MethodPost, "/freeze-windows", freezeBody("", now, now.Add(time.Hour)))
+	require.Equal(t, http.StatusCreated, w.Code)
+	w = ts.do(http.MethodPost, "/freeze-windows", freezeBody("mig-abc", now, now.Add(time.Hour)))
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created migrations.FreezeWindow
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, int64(2), created.ID)
+	assert.Equal(t, "mig-abc", created.MigrationID)
+	assert.Equal(t, map[string]string{"env": "prod"}, created.StepConfig)
+
+	require.Len(t, ts.audit.entries, 2)
+	assert.Equal(t, "create-freeze-window", ts.audit.entries[1].Action)
+	assert.Equal(t, "mig-abc", ts.audit.entries[1].MigrationID)
+}
+
+func TestCreateFreezeWindow_Errors(t *testing.T) {
+	ts := newFreezeTestServer(t)
+	now := time.Now()
+
+	assert.Equal(t, http.StatusBadRequest,
+		ts.do(http.MethodPost, "/freeze-windows", freezeBody("", now, now.Add(-time.Hour))).Code)
+	assert.Equal(t, http.StatusNotFound,
+		ts.do(http.MethodPost, "/freeze-windows", freezeBody("unknown", now, now.Add(time.Hour))).Code)
+}
+
+func TestCreateFreezeWindow_ValidatedAgainstSpec(t *testing.T) {
+	ts := newTestServerWithValidation(t)
+	now := time.Now()
+
+	assert.Equal(t, http.StatusCreated,
+		ts.do(http.MethodPost, "/freeze-windows", freezeBody("", now, now.Add(time.Hour))).Code)
+	assert.Equal(t, http.StatusBadRequest, ts.do(http.MethodPost, "/freeze-windows",
+		map[string]any{"startsAt": now.Format(time.RFC3339), "endsAt": now.Format(time.RFC3339)}).Code,
+		"reason is required")
+}
+
+// ─── GET /freeze-windows ─────────────────────────────────────────────────────
+
+func TestListFreezeWindows_FiltersByMigrationAndEnd(t *testing.T) {
+	ts := newFreezeTestServer(t)
+	now := time.Now()
+	ts.freezes.windows = []migrations.FreezeWindow{
+		{ID: 1, Reason: "global", StartsAt: now, EndsAt: now.Add(time.Hour)},
+		{ID: 2, Reason: "ours", MigrationID: "mig-abc", StartsAt: now, EndsAt: now.Add(time.Hour)},
+		{ID: 3, Reason: "theirs", MigrationID: "mig-other", StartsAt: now, EndsAt: now.Add(time.Hour)},
+		{ID: 4, Reason: "over", StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour)},
+	}
+	ids := func(path string) []int64 {
+		w := ts.do(http.MethodGet, path, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var windows []migrations.FreezeWindow
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &windows))
+		out := []int64{}
+		for _, fw := range windows {
+			out = append(out, fw.ID)
+		}
+		return out
+	}
+
+	assert.Equal(t, []int64{1, 2, 3}, ids("/freeze-windows"))
+	assert.Equal(t, []int64{1, 2}, ids("/freeze-windows?migrationId=mig-abc"))
+	assert.Equal(t, []int64{1, 2, 3, 4}, ids("/freeze-windows?includeEnded=true"))
+}
+
+// ─── DELETE /freeze-windows/:windowId ────────────────────────────────────────
+
+func TestDeleteFreezeWindow(t *testing.T) {
+	ts := newFreezeTestServer(t)
+	now := time.Now()
+	ts.freezes.windows = []migrations.FreezeWindow{
+		{ID: 1, Reason: "ours", MigrationID: "mig-abc", StartsAt: now, EndsAt: now.Add(time.Hour)},
+	}
+
+	assert.Equal(t, http.StatusNoContent, ts.do(http.MethodDelete, "/freeze-windows/1", nil).Code)
+	assert.Empty(t, ts.freezes.windows)
+	assert.Equal(t, http.StatusNotFound, ts.do(http.MethodDelete, "/freeze-windows/1", nil).Code)
+	assert.Equal(t, http.StatusBadRequest, ts.do(http.MethodDelete, "/freeze-windows/abc", nil).Code)
+
+	require.NotEmpty(t, ts.audit.entries)
+	assert.Equal(t, "delete-freeze-window", ts.audit.entries[0].Action)
+	assert.Equal(t, "mig-abc", ts.audit.entries[0].MigrationID)
+}
+
+// ─── Authorization ───────────────────────────────────────────────────────────
+
+func TestFreezeWindows_GlobalWindowsNeedOperatorOnEveryMigration(t *testing.T) {
+	ts := newTestServerWithAuth(t, stubAuthenticator{
+		"operator": {Subject: "operator", Grants: []auth.Grant{
+			{Role: auth.RoleOperator, Migrations: []string{"mig-abc"}},
+		}},
+		"admin": {Subject: "admin", Grants: []auth.Grant{
+			{Role: auth.RoleOperator, Migrations: []string{"*"}},
+		}},
+	})
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{Id: "mig-abc"}))
+	now := time.Now()
+
+	assert.Equal(t, http.StatusForbidden,
+		ts.doAs("operator", http.MethodPost, "/freeze-windows", freezeBody("", now, now.Add(time.Hour))).Code)
+	assert.Equal(t, http.StatusCreated,
+		ts.doAs("operator", http.MethodPost, "/freeze-windows", freezeBody("mig-abc", now, now.Add(time.Hour))).Code)
+	assert.Equal(t, http.StatusCreated,
+		ts.doAs("admin", http.MethodPost, "/freeze-windows", freezeBody("", now, now.Add(time.Hour))).Code)
+
+	assert.Equal(t, http.StatusForbidden, ts.doAs("operator", http.MethodDelete, "/freeze-windows/2", nil).Code)
+	assert.Equal(t, http.StatusNoContent, ts.doAs("operator", http.MethodDelete, "/freeze-windows/1", nil).Code)
+}
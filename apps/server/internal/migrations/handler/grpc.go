@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/platform/auth"
+	"github.com/tilsley/loom/pkg/migratorpb"
+	"github.com/tilsley/loom/pkg/signing"
+)
+
+// LoomGRPC implements the Loom gRPC service for migrators that talk gRPC.
+// Each call makes the same checks as the HTTP route it mirrors: the bearer
+// token in the "authorization" metadata and the caller's role, the callback
+// signature in the signing metadata, the required fields, and an entry in the
+// audit log for announcements and registrations.
+type LoomGRPC struct {
+	migratorpb.UnimplementedLoomServer
+	svc            *migrations.Service
+	log            *slog.Logger
+	callbacks      *auth.CallbackVerifier
+	authenticators []auth.Authenticator
+}
+
+// NewLoomGRPC creates a LoomGRPC. Callbacks must be signed when callbacks is
+// non-nil, and calls must carry a token one of authenticators accepts when
+// there are any; without authenticators every call is allowed, as on the
+// HTTP routes.
+func NewLoomGRPC(
+	svc *migrations.Service,
+	log *slog.Logger,
+	callbacks *auth.CallbackVerifier,
+	authenticators ...auth.Authenticator,
+) *LoomGRPC {
+	return &LoomGRPC{svc: svc, log: log, callbacks: callbacks, authenticators: authenticators}
+}
+
+// Announce mirrors POST /registry/announce.
+func (s *LoomGRPC) Announce(
+	ctx context.Context,
+	req *migratorpb.AnnounceRequest,
+) (resp *migratorpb.AnnounceResponse, err error) {
+	announcement := req.GetAnnouncement().API()
+	actor := anonymousActor
+	defer func() { s.audit(ctx, "announce", actor, announcement.Id, announcement, err) }()
+
+	if actor, err = s.authorize(ctx, auth.RoleMigrator, announcement.Id); err != nil {
+		return nil, err
+	}
+	if err := requireFields(
+		field{"announcement.id", announcement.Id},
+		field{"announcement.name", announcement.Name},
+		field{"announcement.migrator_url", announcement.MigratorUrl},
+	); err != nil {
+		return nil, err
+	}
+
+	m, err := s.svc.Announce(ctx, announcement)
+	if err != nil {
+		var invalidGraph migrations.InvalidStepGraphError
+		var invalidCondition migrations.InvalidStepConditionError
+		var invalidLimit migrations.InvalidDispatchLimitError
+		var invalidID migrations.InvalidMigrationIDError
+		if errors.As(err, &invalidGraph) || errors.As(err, &invalidCondition) ||
+			errors.As(err, &invalidLimit) || errors.As(err, &invalidID) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		var limitConflict migrations.DispatchLimitConflictError
+		if errors.As(err, &limitConflict) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		s.log.Error("failed to handle announcement", "id", announcement.Id, "error", err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	s.log.Info("migration announced", "id", m.Id, "name", m.Name, "migratorUrl", announcement.MigratorUrl)
+	return &migratorpb.AnnounceResponse{}, nil
+}
+
+// RegisterMigrator mirrors POST /registry/migrators. A migrator app is not
+// tied to one migration, so it only needs the migrator role on some migration.
+func (s *LoomGRPC) RegisterMigrator(
+	ctx context.Context,
+	req *migratorpb.MigratorRegistration,
+) (resp *migratorpb.RegisteredMigrator, err error) {
+	registration := req.API()
+	actor := anonymousActor
+	defer func() { s.audit(ctx, "register-migrator", actor, "", registration, err) }()
+
+	if actor, err = s.authorize(ctx, auth.RoleMigrator, ""); err != nil {
+		return nil, err
+	}
+	if err := requireFields(
+		field{"migrator_app", registration.MigratorApp},
+		field{"url", registration.Url},
+	); err != nil {
+		return nil, err
+	}
+
+	m, err := s.svc.RegisterMigrator(ctx, registration)
+	if err != nil {
+		var invalid migrations.InvalidMigratorRegistrationError
+		if errors.As(err, &invalid) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		var unavailable migrations.MigratorRegistryUnavailableError
+		if errors.As(err, &unavailable) {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+		s.log.Error("failed to register migrator", "error", err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	s.log.Info("migrator registered", "migratorApp", m.MigratorApp, "url", m.Url)
+	return migratorpb.NewRegisteredMigrator(*m), nil
+}
+
+// SendCallback mirrors POST /event/:id. An event already applied under the
+// same event ID is acknowledged with duplicate set.
+func (s *LoomGRPC) SendCallback(
+	ctx context.Context,
+	req *migratorpb.SendCallbackRequest,
+) (*migratorpb.SendCallbackResponse, error) {
+	id := req.GetCallbackId()
+	if id == "" {
+		return nil, status.Error(codes.InvalidArgument, "callback_id is required")
+	}
+	if _, err := s.authorize(ctx, auth.RoleMigrator, migrationOfRun(id)); err != nil {
+		return nil, err
+	}
+	if s.callbacks != nil {
+		if err := s.verifySignature(ctx, id, req.GetEvent()); err != nil {
+			return nil, err
+		}
+	}
+
+	event := req.GetEvent().API()
+	if err := requireFields(
+		field{"event.step_name", event.StepName},
+		field{"event.candidate_id", event.CandidateId},
+		field{"event.status", string(event.Status)},
+	); err != nil {
+		return nil, err
+	}
+
+	if err := s.svc.HandleEvent(ctx, id, event); err != nil {
+		var duplicate migrations.DuplicateEventError
+		if errors.As(err, &duplicate) {
+			s.log.Info("ignored duplicate event", "instanceId", id, "eventId", duplicate.EventID)
+			return &migratorpb.SendCallbackResponse{Duplicate: true}, nil
+		}
+		s.log.Error("failed to handle event", "instanceId", id, "error", err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &migratorpb.SendCallbackResponse{}, nil
+}
+
+// authorize authenticates the call by the bearer token in its metadata and
+// checks that the caller holds role on migrationID (on any migration when it
+// is empty). It returns the caller's subject, or anonymousActor when auth is
+// disabled.
+func (s *LoomGRPC) authorize(ctx context.Context, role auth.Role, migrationID string) (string, error) {
+	if len(s.authenticators) == 0 {
+		return anonymousActor, nil
+	}
+	p, err := auth.Authenticate(ctx, firstMetadata(ctx, "authorization"), s.authenticators...)
+	if err != nil {
+		return anonymousActor, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if !p.Can(role, migrationID) {
+		msg := "requires role " + string(role)
+		if migrationID != "" {
+			msg += " on migration " + migrationID
+		}
+		return p.Subject, status.Error(codes.PermissionDenied, msg)
+	}
+	return p.Subject, nil
+}
+
+// verifySignature checks the callback signature in the call's metadata, which
+// covers callbackID and the event as migratorpb.CallbackPayload serializes it.
+func (s *LoomGRPC) verifySignature(ctx context.Context, callbackID string, event *migratorpb.StepStatusEvent) error {
+	payload, err := migratorpb.CallbackPayload(event)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	err = s.callbacks.Verify(migrationOfRun(callbackID), callbackID, payload,
+		firstMetadata(ctx, signing.TimestampHeader), firstMetadata(ctx, signing.SignatureHeader),
+		event.GetEventId() != "")
+	if err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	return nil
+}
+
+// audit records a call in the audit log like the audited HTTP middleware,
+// with the request converted to its JSON payload and the outcome as the HTTP
+// status the mirrored route would have answered with.
+func (s *LoomGRPC) audit(ctx context.Context, action, actor, migrationID string, req any, callErr error) {
+	entry := migrations.AuditEntry{
+		Actor:       actor,
+		Action:      action,
+		MigrationID: migrationID,
+		StatusCode:  http.StatusOK,
+		Outcome:     migrations.AuditSucceeded,
+	}
+	if payload, err := json.Marshal(req); err == nil {
+		entry.Payload = payload
+	}
+	if callErr != nil {
+		st := status.Convert(callErr)
+		entry.StatusCode = httpStatus(st.Code())
+		entry.Outcome = migrations.AuditFailed
+		entry.Error = st.Message()
+	}
+	if err := s.svc.RecordAudit(ctx, entry); err != nil {
+		s.log.Error("failed to record audit entry", "action", action, "actor", actor, "error", err)
+	}
+}
+
+// field is a required field of a gRPC request, by its proto name.
+type field struct{ name, value string }
+
+// requireFields returns INVALID_ARGUMENT naming the first of fields that is empty.
+func requireFields(fields ...field) error {
+	for _, f := range fields {
+		if f.value == "" {
+			return status.Error(codes.InvalidArgument, f.name+" is required")
+		}
+	}
+	return nil
+}
+
+// firstMetadata returns the first value of key in the call's incoming metadata.
+func firstMetadata(ctx context.Context, key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// httpStatus maps a gRPC code onto the HTTP status with the same meaning.
+func httpStatus(c codes.Code) int {
+	switch c {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.FailedPrecondition:
+		return http.StatusConflict
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
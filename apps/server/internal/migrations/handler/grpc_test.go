@@ -0,0 +1,172 @@
+package handler_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/migrations/handler"
+	"github.com/tilsley/loom/apps/server/internal/platform/auth"
+	"github.com/tilsley/loom/pkg/api"
+	"github.com/tilsley/loom/pkg/migratorpb"
+	"github.com/tilsley/loom/pkg/signing"
+)
+
+// withToken returns a call context carrying token as its bearer token.
+func withToken(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func succeededEvent() *migratorpb.StepStatusEvent {
+	return migratorpb.NewStepStatusEvent(api.StepStatusEvent{
+		StepName:    "update-chart",
+		CandidateId: "billing-api",
+		Status:      api.StepStatusEventStatusSucceeded,
+	})
+}
+
+// ─── Loom gRPC service ───────────────────────────────────────────────────────
+
+func TestLoomGRPC_SendCallback_RaisesSignal(t *testing.T) {
+	ts := newTestServer(t)
+	var raised string
+	var payload api.StepStatusEvent
+	ts.engine.raiseEventFn = func(_ context.Context, _, event string, data any) error {
+		raised = event
+		payload, _ = data.(api.StepStatusEvent)
+		return nil
+	}
+
+	resp, err := handler.NewLoomGRPC(ts.svc, slog.Default(), nil).SendCallback(context.Background(),
+		&migratorpb.SendCallbackRequest{CallbackId: "run-123", Event: succeededEvent()})
+
+	require.NoError(t, err)
+	assert.False(t, resp.GetDuplicate())
+	assert.NotEmpty(t, raised)
+	assert.Equal(t, api.StepStatusEventStatusSucceeded, payload.Status)
+}
+
+func TestLoomGRPC_SendCallback_RequiresFields(t *testing.T) {
+	ts := newTestServer(t)
+	event := succeededEvent()
+	event.Status = migratorpb.StepStatus_STEP_STATUS_UNSPECIFIED
+
+	_, err := handler.NewLoomGRPC(ts.svc, slog.Default(), nil).SendCallback(context.Background(),
+		&migratorpb.SendCallbackRequest{CallbackId: "run-123", Event: event})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "event.status")
+}
+
+func TestLoomGRPC_Announce_SavesMigration(t *testing.T) {
+	ts := newTestServer(t)
+	five := 5
+	ann := migratorpb.NewMigrationAnnouncement(api.MigrationAnnouncement{
+		Id:             "migrate-chart",
+		Name:           "Migrate chart",
+		Steps:          []api.StepDefinition{{Name: "update-chart", MigratorApp: "app-chart-migrator"}},
+		MigratorUrl:    "grpc://app-chart-migrator:9090",
+		DispatchLimits: &[]api.DispatchLimit{{MigratorApp: "app-chart-migrator", MaxInFlight: &five}},
+	})
+
+	_, err := handler.NewLoomGRPC(ts.svc, slog.Default(), nil).Announce(context.Background(),
+		&migratorpb.AnnounceRequest{Announcement: ann})
+
+	require.NoError(t, err)
+	m, err := ts.store.Get(context.Background(), "migrate-chart")
+	require.NoError(t, err)
+	require.NotNil(t, m)
+	assert.Equal(t, "grpc://app-chart-migrator:9090", m.MigratorUrl)
+	require.Len(t, m.Steps, 1)
+	assert.Equal(t, "app-chart-migrator", m.Steps[0].MigratorApp)
+
+	require.Len(t, ts.audit.entries, 1)
+	e := ts.audit.entries[0]
+	assert.Equal(t, "announce", e.Action)
+	assert.Equal(t, "migrate-chart", e.MigrationID)
+	assert.Equal(t, migrations.AuditSucceeded, e.Outcome)
+	assert.Contains(t, string(e.Payload), `"maxInFlight":5`, "the payload is the announcement's JSON")
+}
+
+func TestLoomGRPC_RegisterMigrator_ReturnsRegistration(t *testing.T) {
+	ts := newTestServer(t)
+
+	m, err := handler.NewLoomGRPC(ts.svc, slog.Default(), nil).RegisterMigrator(context.Background(),
+		&migratorpb.MigratorRegistration{MigratorApp: "secrets-migrator", Url: "grpc://secrets:9090"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "grpc://secrets:9090", m.GetHealthUrl(), "gRPC migrators default to the gRPC health protocol")
+	assert.False(t, m.GetRegisteredAt().AsTime().IsZero())
+}
+
+func TestLoomGRPC_ChecksTokenAndRole(t *testing.T) {
+	ts := newTestServer(t)
+	srv := handler.NewLoomGRPC(ts.svc, slog.Default(), nil, testTokens)
+	req := &migratorpb.MigratorRegistration{MigratorApp: "secrets-migrator", Url: "secrets:9090"}
+
+	_, err := srv.RegisterMigrator(context.Background(), req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = srv.RegisterMigrator(withToken("unknown"), req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = srv.RegisterMigrator(withToken("viewer"), req)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = srv.RegisterMigrator(withToken("migrator"), req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "absolute http, https, grpc or grpcs URL")
+
+	_, err = srv.SendCallback(withToken("migrator"),
+		&migratorpb.SendCallbackRequest{CallbackId: "mig-other__billing-api", Event: succeededEvent()})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "the role is checked on the run's migration")
+
+	require.Len(t, ts.audit.entries, 4, "refused registrations are audited too")
+	assert.Equal(t, "anonymous", ts.audit.entries[0].Actor)
+	assert.Equal(t, http.StatusUnauthorized, ts.audit.entries[0].StatusCode)
+	assert.Equal(t, "viewer", ts.audit.entries[2].Actor)
+	assert.Equal(t, http.StatusForbidden, ts.audit.entries[2].StatusCode)
+	assert.Equal(t, http.StatusBadRequest, ts.audit.entries[3].StatusCode)
+	assert.Equal(t, migrations.AuditFailed, ts.audit.entries[3].Outcome)
+}
+
+func TestLoomGRPC_SendCallback_ChecksSignatureMetadata(t *testing.T) {
+	callbacks, err := auth.NewCallbackVerifier([]auth.CallbackSecretConfig{
+		{Migrator: "app-chart-migrator", Secret: "s3cret", Migrations: []string{"mig-abc"}},
+	})
+	require.NoError(t, err)
+	srv := handler.NewLoomGRPC(newTestServer(t).svc, slog.Default(), callbacks)
+	req := &migratorpb.SendCallbackRequest{CallbackId: "mig-abc__billing-api", Event: succeededEvent()}
+
+	_, err = srv.SendCallback(context.Background(), req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	payload, err := migratorpb.CallbackPayload(req.GetEvent())
+	require.NoError(t, err)
+	now := time.Now()
+	signed := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		signing.TimestampHeader, strconv.FormatInt(now.Unix(), 10),
+		signing.SignatureHeader, signing.Sign("s3cret", now, "mig-abc__billing-api", payload),
+	))
+	_, err = srv.SendCallback(signed, req)
+	require.NoError(t, err)
+
+	_, err = srv.SendCallback(signed, req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "a callback without an event ID is not replayable")
+
+	tampered := succeededEvent()
+	tampered.Status = migratorpb.StepStatus_STEP_STATUS_FAILED
+	_, err = srv.SendCallback(signed,
+		&migratorpb.SendCallbackRequest{CallbackId: "mig-abc__billing-api", Event: tampered})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -3,8 +3,12 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/platform/auth"
 )
 
 // MetricsOverview returns aggregate totals for the metrics dashboard.
@@ -62,5 +66,39 @@ func (h *Handler) MetricsFailures(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch failures"})
 		return
 	}
-	c.JSON(http.StatusOK, failures)
+	visible := make([]migrations.StepEvent, 0, len(failures))
+	for _, f := range failures {
+		if auth.Allowed(c, auth.RoleViewer, f.MigrationID) {
+			visible = append(visible, f)
+		}
+	}
+	c.JSON(http.StatusOK, visible)
+}
+
+// StuckSteps returns steps that have been in_progress or pending for longer than
+// the olderThan query parameter (a Go duration such as "6h"; default 24h).
+func (h *Handler) StuckSteps(c *gin.Context) {
+	olderThan := 24 * time.Hour
+	if v := c.Query("olderThan"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "olderThan must be a positive duration, e.g. 6h"})
+			return
+		}
+		olderThan = parsed
+	}
+
+	steps, err := h.svc.GetStuckSteps(c.Request.Context(), olderThan)
+	if err != nil {
+		h.log.Error("stuck steps failed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch stuck steps"})
+		return
+	}
+	visible := make([]migrations.StuckStep, 0, len(steps))
+	for _, st := range steps {
+		if auth.Allowed(c, auth.RoleViewer, st.MigrationID) {
+			visible = append(visible, st)
+		}
+	}
+	c.JSON(http.StatusOK, visible)
 }
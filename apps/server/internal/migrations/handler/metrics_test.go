@@ -57,6 +57,24 @@ func TestMetricsTimeline(t *testing.T) {
 	})
 }
 
+func TestStuckSteps(t *testing.T) {
+	t.Run("returns empty list when no event store", func(t *testing.T) {
+		ts := newTestServer(t)
+		w := ts.do("GET", "/steps/stuck?olderThan=6h", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var steps []migrations.StuckStep
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &steps))
+		assert.Empty(t, steps)
+	})
+
+	t.Run("rejects invalid olderThan", func(t *testing.T) {
+		ts := newTestServer(t)
+		w := ts.do("GET", "/steps/stuck?olderThan=soon", nil)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
 func TestMetricsFailures(t *testing.T) {
 	t.Run("returns empty failures when no event store", func(t *testing.T) {
 		ts := newTestServer(t)
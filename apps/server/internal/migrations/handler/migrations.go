@@ -3,14 +3,16 @@ package handler
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/platform/auth"
 	"github.com/tilsley/loom/pkg/api"
 )
 
-// List handles GET /migrations — lists all migrations.
+// List handles GET /migrations — lists the migrations the caller may view.
 func (h *Handler) List(c *gin.Context) {
 	items, err := h.svc.List(c.Request.Context())
 	if err != nil {
@@ -19,7 +21,13 @@ func (h *Handler) List(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, api.ListMigrationsResponse{Migrations: items})
+	visible := make([]api.Migration, 0, len(items))
+	for _, m := range items {
+		if auth.Allowed(c, auth.RoleViewer, m.Id) {
+			visible = append(visible, m)
+		}
+	}
+	c.JSON(http.StatusOK, api.ListMigrationsResponse{Migrations: visible})
 }
 
 // GetMigration handles GET /migrations/:id — gets a specific migration.
@@ -40,6 +48,52 @@ func (h *Handler) GetMigration(c *gin.Context) {
 	c.JSON(http.StatusOK, m)
 }
 
+// ListVersions handles GET /migrations/:id/versions — lists every version of a migration's definition.
+func (h *Handler) ListVersions(c *gin.Context) {
+	id := c.Param("id")
+
+	versions, err := h.svc.ListVersions(c.Request.Context(), id)
+	if err != nil {
+		var notFound migrations.MigrationNotFoundError
+		if errors.As(err, &notFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.log.Error("failed to list versions", "id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.ListMigrationVersionsResponse{Versions: versions})
+}
+
+// DiffVersions handles GET /migrations/:id/versions/diff?from=&to= — compares two versions.
+func (h *Handler) DiffVersions(c *gin.Context) {
+	id := c.Param("id")
+
+	from, errFrom := strconv.Atoi(c.Query("from"))
+	to, errTo := strconv.Atoi(c.Query("to"))
+	if errFrom != nil || errTo != nil || from < 1 || to < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to must be version numbers"})
+		return
+	}
+
+	diff, err := h.svc.DiffVersions(c.Request.Context(), id, from, to)
+	if err != nil {
+		var migNotFound migrations.MigrationNotFoundError
+		var versionNotFound migrations.MigrationVersionNotFoundError
+		if errors.As(err, &migNotFound) || errors.As(err, &versionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.log.Error("failed to diff versions", "id", id, "from", from, "to", to, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
 // SubmitCandidates handles POST /migrations/:id/candidates — worker submits discovered candidates.
 func (h *Handler) SubmitCandidates(c *gin.Context) {
 	id := c.Param("id")
@@ -56,6 +110,12 @@ func (h *Handler) SubmitCandidates(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
+		var invalidGraph migrations.InvalidStepGraphError
+		var invalidCondition migrations.InvalidStepConditionError
+		if errors.As(err, &invalidGraph) || errors.As(err, &invalidCondition) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		h.log.Error("failed to submit candidates", "id", id, "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -65,6 +65,73 @@ func TestGetMigration_Found(t *testing.T) {
 	assert.Equal(t, "mig-abc", m.Id)
 }
 
+// ─── GET /migrations/:id/versions ─────────────────────────────────────────────
+
+// announceVersions announces mig-abc twice with different steps, leaving it at version 2.
+func announceVersions(t *testing.T, ts *testServer) {
+	t.Helper()
+	for _, step := range []string{"step-1", "step-2"} {
+		w := ts.do(http.MethodPost, "/registry/announce", api.MigrationAnnouncement{
+			Id:          "mig-abc",
+			Name:        "Migrate chart",
+			MigratorUrl: "http://migrator:3001",
+			Steps:       []api.StepDefinition{{Name: step, MigratorApp: "app"}},
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+}
+
+func TestListVersions_ReturnsVersionsOldestFirst(t *testing.T) {
+	ts := newTestServer(t)
+	announceVersions(t, ts)
+
+	w := ts.do(http.MethodGet, "/migrations/mig-abc/versions", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp api.ListMigrationVersionsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Versions, 2)
+	assert.Equal(t, 1, resp.Versions[0].Version)
+	assert.Equal(t, 2, resp.Versions[1].Version)
+}
+
+func TestListVersions_MigrationNotFound(t *testing.T) {
+	ts := newTestServer(t)
+	w := ts.do(http.MethodGet, "/migrations/nonexistent/versions", nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// ─── GET /migrations/:id/versions/diff ───────────────────────────────────────
+
+func TestDiffVersions_Success(t *testing.T) {
+	ts := newTestServer(t)
+	announceVersions(t, ts)
+
+	w := ts.do(http.MethodGet, "/migrations/mig-abc/versions/diff?from=1&to=2", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var diff api.MigrationVersionDiff
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &diff))
+	require.Len(t, diff.StepsAdded, 1)
+	assert.Equal(t, "step-2", diff.StepsAdded[0].Name)
+	require.Len(t, diff.StepsRemoved, 1)
+	assert.Equal(t, "step-1", diff.StepsRemoved[0].Name)
+}
+
+func TestDiffVersions_VersionNotFound(t *testing.T) {
+	ts := newTestServer(t)
+	announceVersions(t, ts)
+
+	w := ts.do(http.MethodGet, "/migrations/mig-abc/versions/diff?from=1&to=5", nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDiffVersions_MissingParams_Returns400(t *testing.T) {
+	ts := newTestServer(t)
+	w := ts.do(http.MethodGet, "/migrations/mig-abc/versions/diff?from=1", nil)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 // ─── POST /migrations/:id/candidates ─────────────────────────────────────────
 
 func TestSubmitCandidates_Success(t *testing.T) {
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// RegisterMigrator handles POST /registry/migrators — a migrator app registers
+// the base URL its steps are dispatched to and its health endpoint.
+func (h *Handler) RegisterMigrator(c *gin.Context) {
+	var req api.MigratorRegistration
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	m, err := h.svc.RegisterMigrator(c.Request.Context(), req)
+	if err != nil {
+		h.migratorError(c, "failed to register migrator", err)
+		return
+	}
+	h.log.Info("migrator registered", "migratorApp", m.MigratorApp, "url", m.Url)
+	c.JSON(http.StatusOK, m)
+}
+
+// ListMigrators handles GET /registry/migrators — every registered migrator
+// app, ordered by app-id.
+func (h *Handler) ListMigrators(c *gin.Context) {
+	migrators, err := h.svc.ListMigrators(c.Request.Context())
+	if err != nil {
+		h.migratorError(c, "failed to list migrators", err)
+		return
+	}
+	if migrators == nil {
+		migrators = []api.RegisteredMigrator{}
+	}
+	c.JSON(http.StatusOK, api.ListMigratorsResponse{Migrators: migrators})
+}
+
+// migratorError maps the errors of the migrator registry service methods to responses.
+func (h *Handler) migratorError(c *gin.Context, msg string, err error) {
+	var invalid migrations.InvalidMigratorRegistrationError
+	if errors.As(err, &invalid) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var unavailable migrations.MigratorRegistryUnavailableError
+	if errors.As(err, &unavailable) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	h.log.Error(msg, "error", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
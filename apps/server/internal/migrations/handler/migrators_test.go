@@ -0,0 +1,67 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// ─── POST /registry/migrators ────────────────────────────────────────────────
+
+func TestRegisterMigrator(t *testing.T) {
+	ts := newTestServer(t)
+
+	w := ts.do(http.MethodPost, "/registry/migrators", map[string]string{
+		"migratorApp": "secrets-migrator",
+		"url":         "http://secrets-migrator:3002/",
+	})
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var m api.RegisteredMigrator
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &m))
+	assert.Equal(t, "http://secrets-migrator:3002", m.Url)
+	assert.Equal(t, "http://secrets-migrator:3002/health", m.HealthUrl)
+	assert.False(t, m.RegisteredAt.IsZero())
+	assert.Equal(t, m, ts.migrators.migrators["secrets-migrator"])
+}
+
+func TestRegisterMigrator_Invalid_Returns400(t *testing.T) {
+	ts := newTestServer(t)
+
+	w := ts.do(http.MethodPost, "/registry/migrators", map[string]string{
+		"migratorApp": "secrets-migrator",
+		"url":         "secrets-migrator:3002",
+	})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "absolute http, https, grpc or grpcs URL")
+	assert.Empty(t, ts.migrators.migrators)
+}
+
+// ─── GET /registry/migrators ─────────────────────────────────────────────────
+
+func TestListMigrators(t *testing.T) {
+	ts := newTestServer(t)
+
+	w := ts.do(http.MethodGet, "/registry/migrators", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"migrators":[]}`, w.Body.String())
+
+	for _, app := range []string{"secrets-migrator", "app-chart-migrator"} {
+		require.Equal(t, http.StatusOK, ts.do(http.MethodPost, "/registry/migrators", map[string]string{
+			"migratorApp": app,
+			"url":         "http://" + app + ":3000",
+		}).Code)
+	}
+	w = ts.do(http.MethodGet, "/registry/migrators", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp api.ListMigratorsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Migrators, 2)
+	assert.Equal(t, "app-chart-migrator", resp.Migrators[0].MigratorApp)
+}
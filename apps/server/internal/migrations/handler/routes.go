@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/platform/auth"
 )
 
 // Handler translates HTTP requests into calls on the migrations.Service.
@@ -15,30 +16,112 @@ type Handler struct {
 }
 
 // RegisterRoutes mounts the Loom migration API onto the given Gin engine.
-func RegisterRoutes(r *gin.Engine, svc *migrations.Service, log *slog.Logger) {
+// Every route declares the role it requires; the check is a no-op unless
+// auth.Middleware authenticated the request. Step callbacks must be signed
+// when callbacks is non-nil. Mutating routes other than step callbacks are
+// recorded in the audit log.
+func RegisterRoutes(r *gin.Engine, svc *migrations.Service, log *slog.Logger, callbacks *auth.CallbackVerifier) {
 	h := &Handler{svc: svc, log: log}
 
-	r.POST("/event/:id", h.Event)
+	viewer := auth.Require(auth.RoleViewer, migrationParam)
+	operator := auth.Require(auth.RoleOperator, migrationParam)
+	migrator := auth.Require(auth.RoleMigrator, migrationParam)
+	// Cross-migration routes only need the role on some migration; the
+	// handlers narrow their responses to the migrations the caller may see.
+	anyViewer := auth.Require(auth.RoleViewer, nil)
+	anyOperator := auth.Require(auth.RoleOperator, nil)
 
-	r.POST("/registry/announce", h.Announce)
+	event := []gin.HandlerFunc{auth.Require(auth.RoleMigrator, runMigration)}
+	if callbacks != nil {
+		event = append(event, callbacks.Middleware(runParam, runMigration))
+	}
+	r.POST("/event/:id", append(event, h.Event)...)
+
+	// The announced migration ID is in the body; Announce checks it.
+	r.POST("/registry/announce", auth.Require(auth.RoleMigrator, nil), h.audited("announce"), h.Announce)
+	// A migrator app is not tied to one migration, so registering it only
+	// needs the migrator role on some migration.
+	r.POST("/registry/migrators",
+		auth.Require(auth.RoleMigrator, nil), h.audited("register-migrator"), h.RegisterMigrator)
+	r.GET("/registry/migrators", anyViewer, h.ListMigrators)
 
 	// Migrations
-	r.GET("/migrations", h.List)
-	r.GET("/migrations/:id", h.GetMigration)
-	r.POST("/migrations/:id/candidates", h.SubmitCandidates)
-	r.GET("/migrations/:id/candidates", h.GetCandidates)
-	r.POST("/migrations/:id/dry-run", h.DryRun)
+	r.GET("/migrations", anyViewer, h.List)
+	r.GET("/migrations/:id", viewer, h.GetMigration)
+	r.GET("/migrations/:id/versions", viewer, h.ListVersions)
+	r.GET("/migrations/:id/versions/diff", viewer, h.DiffVersions)
+	r.POST("/migrations/:id/candidates", migrator, h.audited("submit-candidates"), h.SubmitCandidates)
+	r.GET("/migrations/:id/candidates", viewer, h.GetCandidates)
+	r.POST("/migrations/:id/dry-run", operator, h.DryRun)
+	r.POST("/migrations/:id/bulk-start", operator, h.audited("bulk-start"), h.BulkStart)
+	r.GET("/migrations/:id/bulk-starts/:bulkId", viewer, h.GetBulkStart)
+	r.POST("/migrations/:id/scheduled-starts", operator, h.audited("schedule-start"), h.ScheduleStart)
+	r.GET("/migrations/:id/scheduled-starts", viewer, h.ListScheduledStarts)
+	r.DELETE("/migrations/:id/scheduled-starts/:scheduleId",
+		operator, h.audited("cancel-scheduled-start"), h.CancelScheduledStart)
 
 	// Candidate lifecycle (candidate ID in URL)
-	r.POST("/migrations/:id/candidates/:candidateId/start", h.StartRun)
-	r.POST("/migrations/:id/candidates/:candidateId/cancel", h.CancelRun)
-	r.POST("/migrations/:id/candidates/:candidateId/retry-step", h.RetryStep)
-	r.PATCH("/migrations/:id/candidates/:candidateId/inputs", h.UpdateInputs)
-	r.GET("/migrations/:id/candidates/:candidateId/steps", h.GetCandidateSteps)
+	r.POST("/migrations/:id/candidates/:candidateId/start", operator, h.audited("start"), h.StartRun)
+	r.POST("/migrations/:id/candidates/:candidateId/cancel", operator, h.audited("cancel"), h.CancelRun)
+	r.POST("/migrations/:id/candidates/:candidateId/pause", operator, h.audited("pause"), h.PauseRun)
+	r.POST("/migrations/:id/candidates/:candidateId/resume", operator, h.audited("resume"), h.ResumeRun)
+	r.POST("/migrations/:id/candidates/:candidateId/rollback", operator, h.audited("rollback"), h.RollbackRun)
+	r.POST("/migrations/:id/candidates/:candidateId/reset", operator, h.audited("reset"), h.ResetCandidate)
+	r.POST("/migrations/:id/candidates/:candidateId/retry-step", operator, h.audited("retry-step"), h.RetryStep)
+	r.POST("/migrations/:id/candidates/:candidateId/skip-step", operator, h.audited("skip-step"), h.SkipStep)
+	r.POST("/migrations/:id/candidates/:candidateId/complete-step",
+		operator, h.audited("complete-step"), h.CompleteStep)
+	r.PATCH("/migrations/:id/candidates/:candidateId/inputs", operator, h.audited("update-inputs"), h.UpdateInputs)
+	r.GET("/migrations/:id/candidates/:candidateId/steps", viewer, h.GetCandidateSteps)
+	r.GET("/migrations/:id/candidates/:candidateId/runs", viewer, h.ListRuns)
+
+	// Webhooks
+	r.POST("/migrations/:id/webhooks", operator, h.audited("create-webhook"), h.CreateWebhook)
+	r.GET("/migrations/:id/webhooks", operator, h.ListWebhooks)
+	r.DELETE("/migrations/:id/webhooks/:webhookId", operator, h.audited("delete-webhook"), h.DeleteWebhook)
+	r.GET("/migrations/:id/webhooks/:webhookId/deliveries", operator, h.ListWebhookDeliveries)
+
+	// Live progress as Server-Sent Events (not in OpenAPI spec — passes through validation middleware)
+	r.GET("/migrations/:id/stream", viewer, h.StreamMigration)
+	r.GET("/migrations/:id/candidates/:candidateId/stream", viewer, h.StreamCandidate)
 
 	// Metrics (not in OpenAPI spec — passes through validation middleware)
-	r.GET("/metrics/overview", h.MetricsOverview)
-	r.GET("/metrics/steps", h.MetricsSteps)
-	r.GET("/metrics/timeline", h.MetricsTimeline)
-	r.GET("/metrics/failures", h.MetricsFailures)
+	r.GET("/metrics/overview", anyViewer, h.MetricsOverview)
+	r.GET("/metrics/steps", anyViewer, h.MetricsSteps)
+	r.GET("/metrics/timeline", anyViewer, h.MetricsTimeline)
+	r.GET("/metrics/failures", anyViewer, h.MetricsFailures)
+
+	// Operations
+	r.GET("/steps/stuck", anyViewer, h.StuckSteps)
+	r.GET("/audit", anyOperator, h.ListAudit)
+
+	// Freeze windows. A window's migration is in the body or the stored window,
+	// so the handlers check the role on it; global windows need it on every
+	// migration.
+	r.POST("/freeze-windows", anyOperator, h.audited("create-freeze-window"), h.CreateFreezeWindow)
+	r.GET("/freeze-windows", anyViewer, h.ListFreezeWindows)
+	r.DELETE("/freeze-windows/:windowId", anyOperator, h.audited("delete-freeze-window"), h.DeleteFreezeWindow)
+}
+
+func migrationParam(c *gin.Context) string {
+	return c.Param("id")
+}
+
+func runParam(c *gin.Context) string {
+	return c.Param("id")
+}
+
+// runMigration reads the migration ID from the run ID in the URL. An
+// unparseable run ID is returned whole, so it only matches grants on every
+// migration.
+func runMigration(c *gin.Context) string {
+	return migrationOfRun(c.Param("id"))
+}
+
+func migrationOfRun(runID string) string {
+	migrationID, _, err := migrations.ParseRunID(runID)
+	if err != nil {
+		return runID
+	}
+	return migrationID
 }
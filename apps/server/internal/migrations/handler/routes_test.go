@@ -0,0 +1,145 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/platform/auth"
+	"github.com/tilsley/loom/pkg/api"
+	"github.com/tilsley/loom/pkg/signing"
+)
+
+// ─── Authorization ───────────────────────────────────────────────────────────
+
+// testTokens holds one token per role, each granted on mig-abc.
+var testTokens = stubAuthenticator{
+	"viewer": {Subject: "viewer", Grants: []auth.Grant{
+		{Role: auth.RoleViewer, Migrations: []string{"mig-abc"}},
+	}},
+	"operator": {Subject: "operator", Grants: []auth.Grant{
+		{Role: auth.RoleOperator, Migrations: []string{"mig-abc"}},
+	}},
+	"migrator": {Subject: "migrator", Grants: []auth.Grant{
+		{Role: auth.RoleMigrator, Migrations: []string{"mig-abc"}},
+	}},
+}
+
+func newAuthTestServer(t *testing.T) *testServer {
+	t.Helper()
+	ts := newTestServerWithAuth(t, testTokens)
+	for _, id := range []string{"mig-abc", "mig-other"} {
+		require.NoError(t, ts.store.Save(context.Background(), api.Migration{
+			Id:         id,
+			Candidates: []api.Candidate{{Id: "billing-api", Status: api.CandidateStatusNotStarted}},
+		}))
+	}
+	return ts
+}
+
+func TestAuth_MissingToken_Returns401(t *testing.T) {
+	ts := newAuthTestServer(t)
+
+	w := ts.do(http.MethodGet, "/migrations/mig-abc", nil)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuth_ViewerCannotStartRun_Returns403(t *testing.T) {
+	ts := newAuthTestServer(t)
+
+	w := ts.doAs("viewer", http.MethodPost, "/migrations/mig-abc/candidates/billing-api/start", nil)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAuth_OperatorScopedToGrantedMigrations(t *testing.T) {
+	ts := newAuthTestServer(t)
+
+	w := ts.doAs("operator", http.MethodPost, "/migrations/mig-abc/candidates/billing-api/start", nil)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	w = ts.doAs("operator", http.MethodPost, "/migrations/mig-other/candidates/billing-api/start", nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAuth_ListMigrations_OnlyReturnsVisible(t *testing.T) {
+	ts := newAuthTestServer(t)
+
+	w := ts.doAs("viewer", http.MethodGet, "/migrations", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp api.ListMigrationsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Migrations, 1)
+	assert.Equal(t, "mig-abc", resp.Migrations[0].Id)
+}
+
+func TestAuth_Event_RequiresMigratorOnRunsMigration(t *testing.T) {
+	ts := newAuthTestServer(t)
+	event := api.StepStatusEvent{
+		StepName:    "update-chart",
+		CandidateId: "billing-api",
+		Status:      api.StepStatusEventStatusSucceeded,
+	}
+
+	w := ts.doAs("migrator", http.MethodPost, "/event/mig-abc__billing-api", event)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	w = ts.doAs("migrator", http.MethodPost, "/event/mig-other__billing-api", event)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = ts.doAs("operator", http.MethodPost, "/event/mig-abc__billing-api", event)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAuth_Announce_RequiresMigratorOnAnnouncedMigration(t *testing.T) {
+	ts := newAuthTestServer(t)
+
+	w := ts.doAs("migrator", http.MethodPost, "/registry/announce", api.MigrationAnnouncement{
+		Id:          "mig-other",
+		Name:        "Other",
+		Candidates:  []api.Candidate{},
+		Steps:       []api.StepDefinition{},
+		MigratorUrl: "http://migrator",
+	})
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+// ─── Signed callbacks ────────────────────────────────────────────────────────
+
+func TestEvent_RequiresSignatureWhenSecretsConfigured(t *testing.T) {
+	callbacks, err := auth.NewCallbackVerifier([]auth.CallbackSecretConfig{
+		{Migrator: "app-chart-migrator", Secret: "s3cret", Migrations: []string{"mig-abc"}},
+	})
+	require.NoError(t, err)
+	ts := newTestServerWithCallbacks(t, callbacks)
+	body, err := json.Marshal(api.StepStatusEvent{
+		StepName:    "update-chart",
+		CandidateId: "billing-api",
+		Status:      api.StepStatusEventStatusSucceeded,
+	})
+	require.NoError(t, err)
+
+	send := func(sign bool) int {
+		req := httptest.NewRequest(http.MethodPost, "/event/mig-abc__billing-api", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if sign {
+			signing.SignRequest(req, "s3cret", "mig-abc__billing-api", body, time.Now())
+		}
+		w := httptest.NewRecorder()
+		ts.router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, send(false))
+	assert.Equal(t, http.StatusAccepted, send(true))
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// ScheduleStart handles POST /migrations/:id/scheduled-starts — queues a start
+// of one candidate, or of a bulk selection, for startAt.
+func (h *Handler) ScheduleStart(c *gin.Context) {
+	var req api.ScheduleStartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	scheduled, err := h.svc.ScheduleStart(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		h.scheduleError(c, "failed to schedule start", err)
+		return
+	}
+	c.JSON(http.StatusCreated, scheduled)
+}
+
+// ListScheduledStarts handles GET /migrations/:id/scheduled-starts — every
+// scheduled start of the migration, soonest first, whatever its status.
+func (h *Handler) ListScheduledStarts(c *gin.Context) {
+	starts, err := h.svc.ListScheduledStarts(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.scheduleError(c, "failed to list scheduled starts", err)
+		return
+	}
+	if starts == nil {
+		starts = []api.ScheduledStart{}
+	}
+	c.JSON(http.StatusOK, api.ListScheduledStartsResponse{ScheduledStarts: starts})
+}
+
+// CancelScheduledStart handles DELETE /migrations/:id/scheduled-starts/:scheduleId
+// — cancels a scheduled start that has not fired yet.
+func (h *Handler) CancelScheduledStart(c *gin.Context) {
+	if err := h.svc.CancelScheduledStart(c.Request.Context(), c.Param("id"), c.Param("scheduleId")); err != nil {
+		h.scheduleError(c, "failed to cancel scheduled start", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// scheduleError maps the errors of the scheduled start service methods to responses.
+func (h *Handler) scheduleError(c *gin.Context, msg string, err error) {
+	var invalid migrations.InvalidScheduleError
+	if errors.As(err, &invalid) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var migNotFound migrations.MigrationNotFoundError
+	var candNotFound migrations.CandidateNotFoundError
+	var scheduleNotFound migrations.ScheduledStartNotFoundError
+	if errors.As(err, &migNotFound) || errors.As(err, &candNotFound) || errors.As(err, &scheduleNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	var notPending migrations.ScheduledStartNotPendingError
+	if errors.As(err, &notPending) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	var unavailable migrations.SchedulesUnavailableError
+	if errors.As(err, &unavailable) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	h.log.Error(msg, "migrationId", c.Param("id"), "error", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,108 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/pkg/api"
+)
+
+func newScheduleTestServer(t *testing.T) *testServer {
+	t.Helper()
+	ts := newTestServer(t)
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{
+		Id:         "mig-abc",
+		Candidates: []api.Candidate{{Id: "billing-api"}},
+	}))
+	return ts
+}
+
+// ─── POST /migrations/:id/scheduled-starts ───────────────────────────────────
+
+func TestScheduleStart_Created(t *testing.T) {
+	ts := newScheduleTestServer(t)
+	startAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	w := ts.do(http.MethodPost, "/migrations/mig-abc/scheduled-starts", map[string]any{
+		"candidateId": "billing-api",
+		"startAt":     startAt.Format(time.RFC3339),
+	})
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	var scheduled api.ScheduledStart
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &scheduled))
+	assert.Equal(t, api.ScheduledStartStatusScheduled, scheduled.Status)
+	assert.True(t, startAt.Equal(scheduled.StartAt))
+	require.Len(t, ts.schedules.starts, 1)
+
+	require.Len(t, ts.audit.entries, 1)
+	assert.Equal(t, "schedule-start", ts.audit.entries[0].Action)
+	assert.Equal(t, "mig-abc", ts.audit.entries[0].MigrationID)
+}
+
+func TestScheduleStart_Errors(t *testing.T) {
+	ts := newScheduleTestServer(t)
+	startAt := time.Now().Add(time.Hour).Format(time.RFC3339)
+
+	assert.Equal(t, http.StatusBadRequest, ts.do(http.MethodPost, "/migrations/mig-abc/scheduled-starts",
+		map[string]any{"startAt": startAt}).Code)
+	assert.Equal(t, http.StatusNotFound, ts.do(http.MethodPost, "/migrations/mig-abc/scheduled-starts",
+		map[string]any{"candidateId": "unknown", "startAt": startAt}).Code)
+	assert.Equal(t, http.StatusNotFound, ts.do(http.MethodPost, "/migrations/unknown/scheduled-starts",
+		map[string]any{"candidateId": "billing-api", "startAt": startAt}).Code)
+}
+
+// ─── GET /migrations/:id/scheduled-starts ────────────────────────────────────
+
+func TestListScheduledStarts(t *testing.T) {
+	ts := newScheduleTestServer(t)
+
+	w := ts.do(http.MethodGet, "/migrations/mig-abc/scheduled-starts", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"scheduledStarts":[]}`, w.Body.String())
+
+	ts.schedules.starts = []api.ScheduledStart{
+		{Id: "schedule:mig-abc__1", MigrationId: "mig-abc", Status: api.ScheduledStartStatusScheduled},
+		{Id: "schedule:mig-other__1", MigrationId: "mig-other", Status: api.ScheduledStartStatusScheduled},
+	}
+	w = ts.do(http.MethodGet, "/migrations/mig-abc/scheduled-starts", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp api.ListScheduledStartsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.ScheduledStarts, 1)
+	assert.Equal(t, "schedule:mig-abc__1", resp.ScheduledStarts[0].Id)
+
+	assert.Equal(t, http.StatusNotFound, ts.do(http.MethodGet, "/migrations/unknown/scheduled-starts", nil).Code)
+}
+
+// ─── DELETE /migrations/:id/scheduled-starts/:scheduleId ─────────────────────
+
+func TestCancelScheduledStart(t *testing.T) {
+	ts := newScheduleTestServer(t)
+	ts.schedules.starts = []api.ScheduledStart{
+		{Id: "schedule:mig-abc__1", MigrationId: "mig-abc", Status: api.ScheduledStartStatusScheduled},
+		{Id: "schedule:mig-abc__2", MigrationId: "mig-abc", Status: api.ScheduledStartStatusStarted},
+	}
+	var cancelled string
+	ts.engine.cancelFn = func(_ context.Context, id string) error {
+		cancelled = id
+		return nil
+	}
+
+	path := "/migrations/mig-abc/scheduled-starts/"
+	assert.Equal(t, http.StatusNoContent, ts.do(http.MethodDelete, path+"schedule:mig-abc__1", nil).Code)
+	assert.Equal(t, "schedule:mig-abc__1", cancelled)
+	assert.Equal(t, api.ScheduledStartStatusCancelled, ts.schedules.starts[0].Status)
+
+	assert.Equal(t, http.StatusConflict, ts.do(http.MethodDelete, path+"schedule:mig-abc__1", nil).Code)
+	assert.Equal(t, http.StatusConflict, ts.do(http.MethodDelete, path+"schedule:mig-abc__2", nil).Code)
+	assert.Equal(t, http.StatusNotFound, ts.do(http.MethodDelete, path+"schedule:mig-abc__3", nil).Code)
+	assert.Equal(t, http.StatusNotFound,
+		ts.do(http.MethodDelete, "/migrations/mig-other/scheduled-starts/schedule:mig-abc__2", nil).Code)
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+)
+
+// keepaliveInterval is how often an idle stream sends a comment line, so that
+// proxies do not close the connection.
+const keepaliveInterval = 15 * time.Second
+
+// StreamMigration handles GET /migrations/:id/stream — Server-Sent Events for
+// every step transition and candidate status change in the migration.
+func (h *Handler) StreamMigration(c *gin.Context) {
+	h.stream(c, migrations.ProgressFilter{MigrationID: c.Param("id")})
+}
+
+// StreamCandidate handles GET /migrations/:id/candidates/:candidateId/stream —
+// Server-Sent Events for one candidate's step transitions and status changes.
+func (h *Handler) StreamCandidate(c *gin.Context) {
+	h.stream(c, migrations.ProgressFilter{MigrationID: c.Param("id"), CandidateID: c.Param("candidateId")})
+}
+
+// stream writes each progress event as a "progress" event whose id is the
+// event's ID. Clients resume with the Last-Event-ID header, which EventSource
+// sends when it reconnects, or with the lastEventId query parameter. Without
+// either, only events recorded from now on are sent. A "resync" event tells a
+// resuming client that some of the events it missed were pruned.
+func (h *Handler) stream(c *gin.Context, filter migrations.ProgressFilter) {
+	afterID, err := lastEventID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	events, err := h.svc.SubscribeProgress(c.Request.Context(), filter, afterID)
+	if err != nil {
+		var migNotFound migrations.MigrationNotFoundError
+		var candNotFound migrations.CandidateNotFoundError
+		if errors.As(err, &migNotFound) || errors.As(err, &candNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		var unavailable migrations.ProgressFeedUnavailableError
+		if errors.As(err, &unavailable) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
+		h.log.Error("subscribe to progress failed", "migrationId", filter.MigrationID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to stream progress"})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	keepalive := time.NewTicker(keepaliveInterval)
+	defer keepalive.Stop()
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				h.log.Error("marshal progress event failed", "id", e.ID, "error", err)
+				return
+			}
+			name := "progress"
+			if e.EventType == migrations.EventProgressResync {
+				name = "resync"
+			}
+			fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, name, data)
+		case <-keepalive.C:
+			_, _ = io.WriteString(c.Writer, ": keepalive\n\n")
+		case <-c.Request.Context().Done():
+			return
+		}
+		c.Writer.Flush()
+	}
+}
+
+// lastEventID reads the ID of the last event the client received, or 0.
+func lastEventID(c *gin.Context) (int64, error) {
+	v := c.GetHeader("Last-Event-ID")
+	if v == "" {
+		v = c.Query("lastEventId")
+	}
+	if v == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || id < 0 {
+		return 0, errors.New("last event ID must be a non-negative number")
+	}
+	return id, nil
+}
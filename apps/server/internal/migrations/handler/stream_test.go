@@ -0,0 +1,126 @@
+package handler_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/migrations/handler"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+func newStreamTestServer(t *testing.T) *testServer {
+	t.Helper()
+	ts := newTestServer(t)
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{
+		Id:         "mig-abc",
+		Candidates: []api.Candidate{{Id: "billing-api"}, {Id: "payments-api"}},
+	}))
+	ts.progress.events = []migrations.ProgressEvent{
+		{ID: 1, MigrationID: "mig-abc", CandidateID: "billing-api", EventType: migrations.EventCandidateStatusChanged,
+			Status: "running"},
+		{ID: 2, MigrationID: "mig-abc", CandidateID: "payments-api", EventType: migrations.EventCandidateStatusChanged,
+			Status: "running"},
+		{ID: 3, MigrationID: "mig-abc", CandidateID: "billing-api", EventType: migrations.EventStepDispatched,
+			StepName: "update-chart", Status: "in_progress"},
+	}
+	return ts
+}
+
+// ─── GET /migrations/:id/stream ──────────────────────────────────────────────
+
+func TestStreamMigration_SendsEveryCandidate(t *testing.T) {
+	ts := newStreamTestServer(t)
+
+	w := ts.do(http.MethodGet, "/migrations/mig-abc/stream", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	body := w.Body.String()
+	assert.Contains(t, body, "id: 1\nevent: progress\ndata: ")
+	assert.Contains(t, body, "id: 2\n")
+	assert.Contains(t, body, "id: 3\n")
+	assert.Contains(t, body, `"eventType":"step_dispatched"`)
+}
+
+func TestStreamMigration_NotFound(t *testing.T) {
+	ts := newStreamTestServer(t)
+
+	w := ts.do(http.MethodGet, "/migrations/unknown/stream", nil)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestStreamMigration_WithoutFeed_Returns503(t *testing.T) {
+	ts := newStreamTestServer(t)
+	ts.router = gin.New()
+	svc := migrations.NewService(
+		ts.engine, ts.store, ts.dryRun, nil, ts.audit, nil, ts.webhooks, ts.freezes, ts.schedules, nil,
+		ts.migrators,
+	)
+	handler.RegisterRoutes(ts.router, svc, slog.Default(), nil)
+
+	w := ts.do(http.MethodGet, "/migrations/mig-abc/stream", nil)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+// ─── GET /migrations/:id/candidates/:candidateId/stream ──────────────────────
+
+func TestStreamCandidate_OnlySendsThatCandidate(t *testing.T) {
+	ts := newStreamTestServer(t)
+
+	w := ts.do(http.MethodGet, "/migrations/mig-abc/candidates/billing-api/stream", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "id: 1\n")
+	assert.NotContains(t, body, "id: 2\n")
+	assert.Contains(t, body, "id: 3\n")
+}
+
+func TestStreamCandidate_ResumesFromLastEventID(t *testing.T) {
+	ts := newStreamTestServer(t)
+	req := httptest.NewRequest(http.MethodGet, "/migrations/mig-abc/candidates/billing-api/stream", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	w := httptest.NewRecorder()
+
+	ts.router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, int64(1), ts.progress.afterID)
+	assert.NotContains(t, w.Body.String(), "id: 1\n")
+	assert.Contains(t, w.Body.String(), "id: 3\n")
+
+	ts.do(http.MethodGet, "/migrations/mig-abc/candidates/billing-api/stream?lastEventId=3", nil)
+	assert.Equal(t, int64(3), ts.progress.afterID, "query parameter for clients that cannot set headers")
+}
+
+func TestStreamCandidate_Errors(t *testing.T) {
+	ts := newStreamTestServer(t)
+
+	assert.Equal(t, http.StatusNotFound,
+		ts.do(http.MethodGet, "/migrations/mig-abc/candidates/unknown/stream", nil).Code)
+	assert.Equal(t, http.StatusBadRequest,
+		ts.do(http.MethodGet, "/migrations/mig-abc/candidates/billing-api/stream?lastEventId=abc", nil).Code)
+}
+
+func TestStreamCandidate_SendsResyncAsItsOwnEvent(t *testing.T) {
+	ts := newStreamTestServer(t)
+	ts.progress.events = append([]migrations.ProgressEvent{
+		{ID: 1, MigrationID: "mig-abc", CandidateID: "billing-api", EventType: migrations.EventProgressResync},
+	}, ts.progress.events[2:]...)
+
+	w := ts.do(http.MethodGet, "/migrations/mig-abc/candidates/billing-api/stream", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "id: 1\nevent: resync\ndata: ")
+	assert.Contains(t, w.Body.String(), "id: 3\nevent: progress\ndata: ")
+}
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -13,6 +15,7 @@ import (
 
 	"github.com/tilsley/loom/apps/server/internal/migrations"
 	"github.com/tilsley/loom/apps/server/internal/migrations/handler"
+	"github.com/tilsley/loom/apps/server/internal/platform/auth"
 	"github.com/tilsley/loom/apps/server/internal/platform/validation"
 	"github.com/tilsley/loom/pkg/api"
 	"github.com/tilsley/loom/schemas"
@@ -29,6 +32,7 @@ type stubEngine struct {
 	getStatusFn  func(ctx context.Context, id string) (*migrations.RunStatus, error)
 	raiseEventFn func(ctx context.Context, id, event string, payload any) error
 	cancelFn     func(ctx context.Context, id string) error
+	queryFn      func(ctx context.Context, id, queryType string, out any) error
 }
 
 func (e *stubEngine) StartRun(ctx context.Context, name, id string, input any) (string, error) {
@@ -59,6 +63,13 @@ func (e *stubEngine) CancelRun(ctx context.Context, id string) error {
 	return nil
 }
 
+func (e *stubEngine) QueryRun(ctx context.Context, id, queryType string, out any) error {
+	if e.queryFn != nil {
+		return e.queryFn(ctx, id, queryType, out)
+	}
+	return migrations.RunNotFoundError{InstanceID: id}
+}
+
 type stubDryRunner struct {
 	result *api.DryRunResult
 	err    error
@@ -71,6 +82,8 @@ func (d *stubDryRunner) DryRun(_ context.Context, _ string, _ api.DryRunRequest)
 type memStore struct {
 	migrations  map[string]api.Migration
 	candidates  map[string][]api.Candidate
+	versions    map[string][]api.MigrationVersion
+	runs        []api.RunAttempt
 	setStatusFn func(ctx context.Context, migID, candidateID string, status api.CandidateStatus) error
 }
 
@@ -78,6 +91,7 @@ func newMemStore() *memStore {
 	return &memStore{
 		migrations: make(map[string]api.Migration),
 		candidates: make(map[string][]api.Candidate),
+		versions:   make(map[string][]api.MigrationVersion),
 	}
 }
 
@@ -154,26 +168,322 @@ func (m *memStore) UpdateCandidateMetadata(_ context.Context, migID, candidateID
 	return nil
 }
 
+func (m *memStore) SaveVersion(_ context.Context, v api.MigrationVersion) error {
+	m.versions[v.MigrationId] = append(m.versions[v.MigrationId], v)
+	return nil
+}
+
+func (m *memStore) ListVersions(_ context.Context, migID string) ([]api.MigrationVersion, error) {
+	return m.versions[migID], nil
+}
+
+func (m *memStore) GetVersion(_ context.Context, migID string, version int) (*api.MigrationVersion, error) {
+	for _, v := range m.versions[migID] {
+		if v.Version == version {
+			return &v, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memStore) CreateRun(_ context.Context, r api.RunAttempt) error {
+	m.runs = append(m.runs, r)
+	return nil
+}
+
+func (m *memStore) FinishRun(_ context.Context, runID string, status api.RunAttemptStatus, steps []api.StepState) error {
+	for i := range m.runs {
+		if m.runs[i].RunId == runID {
+			m.runs[i].Status = status
+			if steps != nil {
+				m.runs[i].Steps = &steps
+			}
+		}
+	}
+	return nil
+}
+
+func (m *memStore) ListRuns(_ context.Context, migID, candidateID string) ([]api.RunAttempt, error) {
+	var out []api.RunAttempt
+	for i := len(m.runs) - 1; i >= 0; i-- {
+		if m.runs[i].MigrationId == migID && m.runs[i].CandidateId == candidateID {
+			out = append(out, m.runs[i])
+		}
+	}
+	return out, nil
+}
+
+// memAuditLog keeps audit entries in memory, newest last.
+type memAuditLog struct {
+	entries []migrations.AuditEntry
+}
+
+func (l *memAuditLog) Append(_ context.Context, e migrations.AuditEntry) error {
+	e.ID = int64(len(l.entries) + 1)
+	l.entries = append(l.entries, e)
+	return nil
+}
+
+func (l *memAuditLog) List(_ context.Context, f migrations.AuditFilter) ([]migrations.AuditEntry, error) {
+	var out []migrations.AuditEntry
+	for i := len(l.entries) - 1; i >= 0 && len(out) < f.Limit; i-- {
+		e := l.entries[i]
+		if (f.MigrationID == "" || e.MigrationID == f.MigrationID) &&
+			(f.CandidateID == "" || e.CandidateID == f.CandidateID) &&
+			(f.Actor == "" || e.Actor == f.Actor) &&
+			(f.Before == 0 || e.ID < f.Before) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// stubProgressFeed replays its events to each subscriber, then ends the
+// subscription.
+type stubProgressFeed struct {
+	events  []migrations.ProgressEvent
+	afterID int64
+}
+
+func (f *stubProgressFeed) Subscribe(
+	_ context.Context,
+	filter migrations.ProgressFilter,
+	afterID int64,
+) (<-chan migrations.ProgressEvent, error) {
+	f.afterID = afterID
+	ch := make(chan migrations.ProgressEvent, len(f.events))
+	for _, e := range f.events {
+		if e.ID > afterID && e.MigrationID == filter.MigrationID &&
+			(filter.CandidateID == "" || e.CandidateID == filter.CandidateID) {
+			ch <- e
+		}
+	}
+	close(ch)
+	return ch, nil
+}
+
+// memWebhookStore keeps webhooks and their deliveries in memory.
+type memWebhookStore struct {
+	webhooks   []migrations.WebhookSubscription
+	deliveries []migrations.WebhookDelivery
+}
+
+func (s *memWebhookStore) CreateWebhook(_ context.Context, w *migrations.WebhookSubscription) error {
+	w.ID = int64(len(s.webhooks) + 1)
+	s.webhooks = append(s.webhooks, *w)
+	return nil
+}
+
+func (s *memWebhookStore) ListWebhooks(_ context.Context, migID string) ([]migrations.WebhookSubscription, error) {
+	var out []migrations.WebhookSubscription
+	for _, w := range s.webhooks {
+		if w.MigrationID == migID {
+			out = append(out, w)
+		}
+	}
+	return out, nil
+}
+
+func (s *memWebhookStore) GetWebhook(
+	_ context.Context,
+	migID string,
+	id int64,
+) (*migrations.WebhookSubscription, error) {
+	for _, w := range s.webhooks {
+		if w.MigrationID == migID && w.ID == id {
+			return &w, nil
+		}
+	}
+	return nil, nil //nolint:nilnil
+}
+
+func (s *memWebhookStore) DeleteWebhook(_ context.Context, migID string, id int64) (bool, error) {
+	for i, w := range s.webhooks {
+		if w.MigrationID == migID && w.ID == id {
+			s.webhooks = append(s.webhooks[:i], s.webhooks[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *memWebhookStore) ListDeliveries(_ context.Context, id int64, limit int) ([]migrations.WebhookDelivery, error) {
+	var out []migrations.WebhookDelivery
+	for i := len(s.deliveries) - 1; i >= 0 && len(out) < limit; i-- {
+		if s.deliveries[i].WebhookID == id {
+			out = append(out, s.deliveries[i])
+		}
+	}
+	return out, nil
+}
+
+// memFreezeCalendar keeps freeze windows in memory.
+type memFreezeCalendar struct {
+	windows []migrations.FreezeWindow
+}
+
+func (c *memFreezeCalendar) CreateFreezeWindow(_ context.Context, w *migrations.FreezeWindow) error {
+	w.ID = int64(len(c.windows) + 1)
+	c.windows = append(c.windows, *w)
+	return nil
+}
+
+func (c *memFreezeCalendar) ListFreezeWindows(
+	_ context.Context,
+	f migrations.FreezeWindowFilter,
+) ([]migrations.FreezeWindow, error) {
+	var out []migrations.FreezeWindow
+	for _, w := range c.windows {
+		if f.MigrationID != "" && w.MigrationID != "" && w.MigrationID != f.MigrationID {
+			continue
+		}
+		if !f.EndsAfter.IsZero() && !w.EndsAt.After(f.EndsAfter) {
+			continue
+		}
+		out = append(out, w)
+	}
+	return out, nil
+}
+
+func (c *memFreezeCalendar) GetFreezeWindow(_ context.Context, id int64) (*migrations.FreezeWindow, error) {
+	for _, w := range c.windows {
+		if w.ID == id {
+			return &w, nil
+		}
+	}
+	return nil, nil //nolint:nilnil
+}
+
+func (c *memFreezeCalendar) DeleteFreezeWindow(_ context.Context, id int64) (bool, error) {
+	for i, w := range c.windows {
+		if w.ID == id {
+			c.windows = append(c.windows[:i], c.windows[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// memScheduleStore keeps scheduled starts in memory.
+type memScheduleStore struct {
+	starts []api.ScheduledStart
+}
+
+func (s *memScheduleStore) CreateScheduledStart(_ context.Context, start api.ScheduledStart) error {
+	s.starts = append(s.starts, start)
+	return nil
+}
+
+func (s *memScheduleStore) ListScheduledStarts(_ context.Context, migID string) ([]api.ScheduledStart, error) {
+	var out []api.ScheduledStart
+	for _, start := range s.starts {
+		if start.MigrationId == migID {
+			out = append(out, start)
+		}
+	}
+	return out, nil
+}
+
+func (s *memScheduleStore) GetScheduledStart(_ context.Context, id string) (*api.ScheduledStart, error) {
+	for _, start := range s.starts {
+		if start.Id == id {
+			return &start, nil
+		}
+	}
+	return nil, nil //nolint:nilnil
+}
+
+func (s *memScheduleStore) TransitionScheduledStart(
+	_ context.Context,
+	id string,
+	from, to api.ScheduledStartStatus,
+	runID, errMsg string,
+) (bool, error) {
+	for i := range s.starts {
+		if s.starts[i].Id != id || s.starts[i].Status != from {
+			continue
+		}
+		s.starts[i].Status = to
+		if runID != "" {
+			s.starts[i].RunId = &runID
+		}
+		if errMsg != "" {
+			s.starts[i].Error = &errMsg
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// memMigratorRegistry keeps migrator registrations in memory.
+type memMigratorRegistry struct {
+	migrators map[string]api.RegisteredMigrator
+}
+
+func (r *memMigratorRegistry) RegisterMigrator(_ context.Context, m api.RegisteredMigrator) error {
+	if r.migrators == nil {
+		r.migrators = map[string]api.RegisteredMigrator{}
+	}
+	r.migrators[m.MigratorApp] = m
+	return nil
+}
+
+func (r *memMigratorRegistry) GetMigrator(_ context.Context, app string) (*api.RegisteredMigrator, error) {
+	m, ok := r.migrators[app]
+	if !ok {
+		return nil, nil //nolint:nilnil
+	}
+	return &m, nil
+}
+
+func (r *memMigratorRegistry) ListMigrators(_ context.Context) ([]api.RegisteredMigrator, error) {
+	result := make([]api.RegisteredMigrator, 0, len(r.migrators))
+	for _, m := range r.migrators {
+		result = append(result, m)
+	}
+	slices.SortFunc(result, func(a, b api.RegisteredMigrator) int {
+		return strings.Compare(a.MigratorApp, b.MigratorApp)
+	})
+	return result, nil
+}
+
 // ─── Test server builder ──────────────────────────────────────────────────────
 
 type testServer struct {
-	router *gin.Engine
-	store  *memStore
-	engine *stubEngine
-	dryRun *stubDryRunner
+	router    *gin.Engine
+	svc       *migrations.Service
+	store     *memStore
+	engine    *stubEngine
+	dryRun    *stubDryRunner
+	audit     *memAuditLog
+	progress  *stubProgressFeed
+	webhooks  *memWebhookStore
+	freezes   *memFreezeCalendar
+	schedules *memScheduleStore
+	migrators *memMigratorRegistry
 }
 
 func newTestServer(t *testing.T) *testServer {
 	t.Helper()
 	ts := &testServer{
-		store:  newMemStore(),
-		engine: &stubEngine{},
-		dryRun: &stubDryRunner{},
+		store:     newMemStore(),
+		engine:    &stubEngine{},
+		dryRun:    &stubDryRunner{},
+		audit:     &memAuditLog{},
+		progress:  &stubProgressFeed{},
+		webhooks:  &memWebhookStore{},
+		freezes:   &memFreezeCalendar{},
+		schedules: &memScheduleStore{},
+		migrators: &memMigratorRegistry{},
 	}
-	svc := migrations.NewService(ts.engine, ts.store, ts.dryRun, nil)
+	svc := migrations.NewService(
+		ts.engine, ts.store, ts.dryRun, nil, ts.audit, ts.progress, ts.webhooks, ts.freezes, ts.schedules, nil,
+		ts.migrators,
+	)
 	r := gin.New()
-	handler.RegisterRoutes(r, svc, slog.Default())
+	handler.RegisterRoutes(r, svc, slog.Default(), nil)
 	ts.router = r
+	ts.svc = svc
 	return ts
 }
 
@@ -184,11 +494,67 @@ func newTestServerWithValidation(t *testing.T) *testServer {
 	require.NoError(t, err)
 	r := gin.New()
 	r.Use(mw)
-	handler.RegisterRoutes(r, migrations.NewService(ts.engine, ts.store, ts.dryRun, nil), slog.Default())
+	svc := migrations.NewService(
+		ts.engine, ts.store, ts.dryRun, nil, ts.audit, ts.progress, ts.webhooks, ts.freezes, ts.schedules, nil,
+		ts.migrators,
+	)
+	handler.RegisterRoutes(r, svc, slog.Default(), nil)
+	ts.router = r
+	return ts
+}
+
+// stubAuthenticator accepts the tokens in its map.
+type stubAuthenticator map[string]auth.Principal
+
+func (a stubAuthenticator) Authenticate(_ context.Context, token string) (auth.Principal, error) {
+	p, ok := a[token]
+	if !ok {
+		return auth.Principal{}, auth.ErrUnknownToken
+	}
+	return p, nil
+}
+
+func newTestServerWithAuth(t *testing.T, tokens stubAuthenticator) *testServer {
+	t.Helper()
+	ts := newTestServer(t)
+	r := gin.New()
+	r.Use(auth.Middleware(tokens))
+	svc := migrations.NewService(
+		ts.engine, ts.store, ts.dryRun, nil, ts.audit, ts.progress, ts.webhooks, ts.freezes, ts.schedules, nil,
+		ts.migrators,
+	)
+	handler.RegisterRoutes(r, svc, slog.Default(), nil)
 	ts.router = r
 	return ts
 }
 
+func newTestServerWithCallbacks(t *testing.T, callbacks *auth.CallbackVerifier) *testServer {
+	t.Helper()
+	ts := newTestServer(t)
+	r := gin.New()
+	svc := migrations.NewService(
+		ts.engine, ts.store, ts.dryRun, nil, ts.audit, ts.progress, ts.webhooks, ts.freezes, ts.schedules, nil,
+		ts.migrators,
+	)
+	handler.RegisterRoutes(r, svc, slog.Default(), callbacks)
+	ts.router = r
+	return ts
+}
+
+// doAs sends the request with token as its bearer token.
+func (ts *testServer) doAs(token, method, path string, body any) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		_ = json.NewEncoder(&buf).Encode(body)
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	ts.router.ServeHTTP(w, req)
+	return w
+}
+
 func (ts *testServer) do(method, path string, body any) *httptest.ResponseRecorder {
 	var buf bytes.Buffer
 	if body != nil {
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// CreateWebhook handles POST /migrations/:id/webhooks — subscribes a URL to
+// some of the migration's events. The response carries the signing secret;
+// it is not shown again.
+func (h *Handler) CreateWebhook(c *gin.Context) {
+	id := c.Param("id")
+
+	var req api.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sub := migrations.WebhookSubscription{URL: req.Url, Events: req.Events}
+	if req.Templates != nil {
+		sub.Templates = *req.Templates
+	}
+	if req.Secret != nil {
+		sub.Secret = *req.Secret // generated when omitted
+	}
+	w, err := h.svc.CreateWebhook(c.Request.Context(), id, sub)
+	if err != nil {
+		h.webhookError(c, "failed to create webhook", err)
+		return
+	}
+	c.JSON(http.StatusCreated, w)
+}
+
+// ListWebhooks handles GET /migrations/:id/webhooks.
+func (h *Handler) ListWebhooks(c *gin.Context) {
+	webhooks, err := h.svc.ListWebhooks(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.webhookError(c, "failed to list webhooks", err)
+		return
+	}
+	if webhooks == nil {
+		webhooks = []migrations.WebhookSubscription{}
+	}
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// DeleteWebhook handles DELETE /migrations/:id/webhooks/:webhookId — removes
+// the subscription together with its delivery history.
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+	webhookID, ok := webhookParam(c)
+	if !ok {
+		return
+	}
+	if err := h.svc.DeleteWebhook(c.Request.Context(), c.Param("id"), webhookID); err != nil {
+		h.webhookError(c, "failed to delete webhook", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries handles GET /migrations/:id/webhooks/:webhookId/deliveries
+// — the webhook's latest deliveries, newest first, up to the limit query
+// parameter.
+func (h *Handler) ListWebhookDeliveries(c *gin.Context) {
+	webhookID, ok := webhookParam(c)
+	if !ok {
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+		return
+	}
+
+	deliveries, err := h.svc.ListWebhookDeliveries(c.Request.Context(), c.Param("id"), webhookID, limit)
+	if err != nil {
+		h.webhookError(c, "failed to list webhook deliveries", err)
+		return
+	}
+	if deliveries == nil {
+		deliveries = []migrations.WebhookDelivery{}
+	}
+	c.JSON(http.StatusOK, deliveries)
+}
+
+func webhookParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("webhookId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "webhookId must be a number"})
+		return 0, false
+	}
+	return id, true
+}
+
+// webhookError maps the errors of the webhook service methods to responses.
+func (h *Handler) webhookError(c *gin.Context, msg string, err error) {
+	var invalid migrations.InvalidWebhookError
+	if errors.As(err, &invalid) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var migNotFound migrations.MigrationNotFoundError
+	var webhookNotFound migrations.WebhookNotFoundError
+	if errors.As(err, &migNotFound) || errors.As(err, &webhookNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	var unavailable migrations.WebhooksUnavailableError
+	if errors.As(err, &unavailable) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	h.log.Error(msg, "migrationId", c.Param("id"), "error", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,121 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+func newWebhookTestServer(t *testing.T) *testServer {
+	t.Helper()
+	ts := newTestServer(t)
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{Id: "mig-abc"}))
+	return ts
+}
+
+var webhookBody = map[string]any{
+	"url":    "https://hooks.example.com/loom",
+	"events": []string{"pr_opened", "step_failed"},
+}
+
+// ─── POST /migrations/:id/webhooks ───────────────────────────────────────────
+
+func TestCreateWebhook_ReturnsSecretOnce(t *testing.T) {
+	ts := newWebhookTestServer(t)
+
+	w := ts.do(http.MethodPost, "/migrations/mig-abc/webhooks", webhookBody)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created migrations.WebhookSubscription
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, int64(1), created.ID)
+	assert.Equal(t, "mig-abc", created.MigrationID)
+	assert.NotEmpty(t, created.Secret)
+
+	w = ts.do(http.MethodGet, "/migrations/mig-abc/webhooks", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var list []migrations.WebhookSubscription
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, []string{"pr_opened", "step_failed"}, list[0].Events)
+	assert.NotContains(t, w.Body.String(), "secret")
+}
+
+func TestCreateWebhook_RedactsSecretInAudit(t *testing.T) {
+	ts := newWebhookTestServer(t)
+	body := map[string]any{"url": webhookBody["url"], "events": webhookBody["events"], "secret": "shared"}
+
+	w := ts.do(http.MethodPost, "/migrations/mig-abc/webhooks", body)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Len(t, ts.audit.entries, 1)
+	assert.Equal(t, "create-webhook", ts.audit.entries[0].Action)
+	assert.NotContains(t, string(ts.audit.entries[0].Payload), "shared")
+	assert.Contains(t, string(ts.audit.entries[0].Payload), `"secret":"[redacted]"`)
+}
+
+func TestCreateWebhook_Errors(t *testing.T) {
+	ts := newWebhookTestServer(t)
+
+	assert.Equal(t, http.StatusBadRequest, ts.do(http.MethodPost, "/migrations/mig-abc/webhooks",
+		map[string]any{"url": "https://hooks.example.com", "events": []string{"nope"}}).Code)
+	assert.Equal(t, http.StatusNotFound, ts.do(http.MethodPost, "/migrations/unknown/webhooks", webhookBody).Code)
+}
+
+func TestCreateWebhook_ValidatedAgainstSpec(t *testing.T) {
+	ts := newTestServerWithValidation(t)
+	require.NoError(t, ts.store.Save(context.Background(), api.Migration{Id: "mig-abc"}))
+
+	assert.Equal(t, http.StatusCreated, ts.do(http.MethodPost, "/migrations/mig-abc/webhooks", webhookBody).Code)
+	assert.Equal(t, http.StatusBadRequest, ts.do(http.MethodPost, "/migrations/mig-abc/webhooks",
+		map[string]any{"events": webhookBody["events"]}).Code, "url is required")
+	assert.Equal(t, http.StatusBadRequest,
+		ts.do(http.MethodGet, "/migrations/mig-abc/webhooks/1/deliveries?limit=0", nil).Code)
+}
+
+// ─── DELETE /migrations/:id/webhooks/:webhookId ──────────────────────────────
+
+func TestDeleteWebhook(t *testing.T) {
+	ts := newWebhookTestServer(t)
+	require.Equal(t, http.StatusCreated, ts.do(http.MethodPost, "/migrations/mig-abc/webhooks", webhookBody).Code)
+
+	assert.Equal(t, http.StatusNoContent, ts.do(http.MethodDelete, "/migrations/mig-abc/webhooks/1", nil).Code)
+	assert.Equal(t, http.StatusNotFound, ts.do(http.MethodDelete, "/migrations/mig-abc/webhooks/1", nil).Code)
+	assert.Equal(t, http.StatusBadRequest, ts.do(http.MethodDelete, "/migrations/mig-abc/webhooks/abc", nil).Code)
+}
+
+// ─── GET /migrations/:id/webhooks/:webhookId/deliveries ──────────────────────
+
+func TestListWebhookDeliveries_NewestFirst(t *testing.T) {
+	ts := newWebhookTestServer(t)
+	require.Equal(t, http.StatusCreated, ts.do(http.MethodPost, "/migrations/mig-abc/webhooks", webhookBody).Code)
+	ts.webhooks.deliveries = []migrations.WebhookDelivery{
+		{ID: 1, WebhookID: 1, Event: "pr_opened", State: migrations.WebhookDeliveryDelivered, Attempts: 1},
+		{ID: 2, WebhookID: 1, Event: "step_failed", State: migrations.WebhookDeliveryPending, Attempts: 2,
+			LastError: "webhook returned HTTP 502"},
+	}
+
+	w := ts.do(http.MethodGet, "/migrations/mig-abc/webhooks/1/deliveries?limit=1", nil)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var deliveries []migrations.WebhookDelivery
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &deliveries))
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, int64(2), deliveries[0].ID)
+	assert.Equal(t, "webhook returned HTTP 502", deliveries[0].LastError)
+}
+
+func TestListWebhookDeliveries_Errors(t *testing.T) {
+	ts := newWebhookTestServer(t)
+
+	assert.Equal(t, http.StatusNotFound, ts.do(http.MethodGet, "/migrations/mig-abc/webhooks/9/deliveries", nil).Code)
+	assert.Equal(t, http.StatusBadRequest,
+		ts.do(http.MethodGet, "/migrations/mig-abc/webhooks/9/deliveries?limit=0", nil).Code)
+}
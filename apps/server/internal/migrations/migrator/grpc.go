@@ -0,0 +1,163 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+	"github.com/tilsley/loom/pkg/migratorpb"
+)
+
+// Compile-time checks: the gRPC adapters implement the migrator ports.
+var (
+	_ migrations.MigratorNotifier = (*GRPCMigratorNotifier)(nil)
+	_ migrations.DryRunner        = (*GRPCDryRunAdapter)(nil)
+)
+
+// GRPCConns keeps one client connection per migrator scheme and host. grpc://
+// URLs are dialled in plaintext and grpcs:// URLs with TLS.
+type GRPCConns struct {
+	opts  []grpc.DialOption
+	mu    sync.Mutex
+	conns map[string]*grpc.ClientConn
+}
+
+// NewGRPCConns creates a GRPCConns whose connections are created with opts
+// in addition to their transport credentials.
+func NewGRPCConns(opts ...grpc.DialOption) *GRPCConns {
+	return &GRPCConns{opts: opts, conns: make(map[string]*grpc.ClientConn)}
+}
+
+// conn returns the connection for migratorURL, creating it on first use.
+// Connections are established lazily, so an unreachable migrator fails the
+// call rather than this.
+func (g *GRPCConns) conn(migratorURL string) (*grpc.ClientConn, error) {
+	u, err := url.Parse(migratorURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse migrator URL: %w", err)
+	}
+	creds := insecure.NewCredentials()
+	switch u.Scheme {
+	case migrations.SchemeGRPC:
+	case migrations.SchemeGRPCS:
+		creds = credentials.NewTLS(nil)
+	default:
+		return nil, fmt.Errorf("migrator URL %q is not a gRPC URL", migratorURL)
+	}
+
+	// Keyed by scheme too, so that a grpcs:// URL never gets the plaintext
+	// connection of a grpc:// URL for the same host.
+	key := u.Scheme + "://" + u.Host
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if cc, ok := g.conns[key]; ok {
+		return cc, nil
+	}
+	cc, err := grpc.NewClient(u.Host, append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, g.opts...)...)
+	if err != nil {
+		return nil, fmt.Errorf("create gRPC client for %q: %w", migratorURL, err)
+	}
+	g.conns[key] = cc
+	return cc, nil
+}
+
+// Close closes every connection.
+func (g *GRPCConns) Close() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var firstErr error
+	for key, cc := range g.conns {
+		if err := cc.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(g.conns, key)
+	}
+	return firstErr
+}
+
+// GRPCMigratorNotifier implements MigratorNotifier by calling Migrator.DispatchStep
+// on the migrator at req.MigratorUrl.
+type GRPCMigratorNotifier struct {
+	conns *GRPCConns
+}
+
+// NewGRPCMigratorNotifier creates a new GRPCMigratorNotifier.
+func NewGRPCMigratorNotifier(conns *GRPCConns) *GRPCMigratorNotifier {
+	return &GRPCMigratorNotifier{conns: conns}
+}
+
+// Dispatch sends a step request to the migrator URL carried in req.MigratorUrl.
+// Failures are returned as migrations.DispatchError, classified by status code
+// (see dispatchErrorCode).
+func (n *GRPCMigratorNotifier) Dispatch(ctx context.Context, req api.DispatchStepRequest) error {
+	cc, err := n.conns.conn(req.MigratorUrl)
+	if err != nil {
+		return migrations.DispatchError{MigratorApp: req.MigratorApp, Message: err.Error(), Permanent: true}
+	}
+
+	_, err = migratorpb.NewMigratorClient(cc).DispatchStep(ctx, migratorpb.NewDispatchStepRequest(req))
+	if err == nil {
+		return nil
+	}
+	st := status.Convert(err)
+	code := dispatchErrorCode(st.Code())
+	return migrations.DispatchError{
+		MigratorApp: req.MigratorApp,
+		Code:        code,
+		Message:     st.Code().String() + ": " + st.Message(),
+		Permanent:   code != migrations.DispatchErrorTransient,
+	}
+}
+
+// dispatchErrorCode maps the status of a failed DispatchStep call onto the
+// DispatchError codes: codes a later call may not get again are transient.
+func dispatchErrorCode(c codes.Code) string {
+	switch c {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted,
+		codes.Internal, codes.Unknown:
+		return migrations.DispatchErrorTransient
+	case codes.Unimplemented:
+		return migrations.DispatchErrorUnsupportedStep
+	default:
+		return migrations.DispatchErrorInvalidRequest
+	}
+}
+
+// GRPCDryRunAdapter implements DryRunner by calling Migrator.DryRun.
+type GRPCDryRunAdapter struct {
+	conns *GRPCConns
+}
+
+// NewGRPCDryRunAdapter creates a new GRPCDryRunAdapter.
+func NewGRPCDryRunAdapter(conns *GRPCConns) *GRPCDryRunAdapter {
+	return &GRPCDryRunAdapter{conns: conns}
+}
+
+// DryRun calls Migrator.DryRun on the migrator at migratorUrl and returns the result.
+func (d *GRPCDryRunAdapter) DryRun(
+	ctx context.Context,
+	migratorUrl string,
+	req api.DryRunRequest,
+) (*api.DryRunResult, error) {
+	cc, err := d.conns.conn(migratorUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := migratorpb.NewMigratorClient(cc).DryRun(ctx, migratorpb.NewDryRunRequest(req))
+	if err != nil {
+		return nil, fmt.Errorf("invoke dry-run: %w", err)
+	}
+	result := resp.API()
+	return &result, nil
+}
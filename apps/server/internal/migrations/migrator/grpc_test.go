@@ -0,0 +1,187 @@
+package migrator_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/migrations/migrator"
+	"github.com/tilsley/loom/pkg/api"
+	"github.com/tilsley/loom/pkg/migratorpb"
+)
+
+// fakeMigrator records dispatched steps and answers every call with err.
+type fakeMigrator struct {
+	migratorpb.UnimplementedMigratorServer
+	dispatched []api.DispatchStepRequest
+	err        error
+}
+
+func (m *fakeMigrator) DispatchStep(
+	_ context.Context,
+	req *migratorpb.DispatchStepRequest,
+) (*migratorpb.DispatchStepResponse, error) {
+	m.dispatched = append(m.dispatched, req.API())
+	return &migratorpb.DispatchStepResponse{}, m.err
+}
+
+func (m *fakeMigrator) DryRun(_ context.Context, req *migratorpb.DryRunRequest) (*migratorpb.DryRunResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	before := "chart: old"
+	return migratorpb.NewDryRunResult(api.DryRunResult{Steps: []api.StepDryRunResult{{
+		StepName: req.GetSteps()[0].GetName(),
+		Files: &[]api.FileDiff{{
+			Repo: "acme/billing-api", Path: "Chart.yaml", Status: api.Modified, Before: &before, After: "chart: new",
+		}},
+	}}}), nil
+}
+
+// serveMigrator serves m on a local port and returns its grpc:// URL.
+func serveMigrator(t *testing.T, m *fakeMigrator) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	migratorpb.RegisterMigratorServer(srv, m)
+	go srv.Serve(lis) //nolint:errcheck
+	t.Cleanup(srv.Stop)
+	return "grpc://" + lis.Addr().String()
+}
+
+func newGRPCConns(t *testing.T) *migrator.GRPCConns {
+	t.Helper()
+	conns := migrator.NewGRPCConns()
+	t.Cleanup(func() { _ = conns.Close() })
+	return conns
+}
+
+// ─── Dispatch ────────────────────────────────────────────────────────────────
+
+func TestGRPCDispatch_SendsRequest(t *testing.T) {
+	m := &fakeMigrator{}
+	req := baseDispatchReq
+	req.MigratorUrl = serveMigrator(t, m)
+
+	require.NoError(t, migrator.NewGRPCMigratorNotifier(newGRPCConns(t)).Dispatch(context.Background(), req))
+
+	require.Len(t, m.dispatched, 1)
+	assert.Equal(t, "update-chart", m.dispatched[0].StepName)
+	assert.Equal(t, "billing-api", m.dispatched[0].Candidate.Id)
+	assert.Equal(t, req, m.dispatched[0], "the request arrives unchanged")
+}
+
+func TestGRPCDispatch_ClassifiesStatus(t *testing.T) {
+	tests := []struct {
+		code      codes.Code
+		errCode   string
+		permanent bool
+	}{
+		{codes.Unavailable, migrations.DispatchErrorTransient, false},
+		{codes.ResourceExhausted, migrations.DispatchErrorTransient, false},
+		{codes.InvalidArgument, migrations.DispatchErrorInvalidRequest, true},
+		{codes.FailedPrecondition, migrations.DispatchErrorInvalidRequest, true},
+		{codes.Unimplemented, migrations.DispatchErrorUnsupportedStep, true},
+	}
+	m := &fakeMigrator{}
+	req := baseDispatchReq
+	req.MigratorUrl = serveMigrator(t, m)
+	n := migrator.NewGRPCMigratorNotifier(newGRPCConns(t))
+
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			m.err = status.Error(tt.code, "refused")
+
+			err := n.Dispatch(context.Background(), req)
+
+			var dispatchErr migrations.DispatchError
+			require.ErrorAs(t, err, &dispatchErr)
+			assert.Equal(t, tt.errCode, dispatchErr.Code)
+			assert.Equal(t, tt.permanent, dispatchErr.Permanent)
+			assert.Contains(t, err.Error(), "refused")
+		})
+	}
+}
+
+func TestGRPCDispatch_Unreachable_IsTransient(t *testing.T) {
+	req := baseDispatchReq
+	req.MigratorUrl = "grpc://127.0.0.1:1" // nothing listening
+
+	err := migrator.NewGRPCMigratorNotifier(newGRPCConns(t)).Dispatch(context.Background(), req)
+
+	var dispatchErr migrations.DispatchError
+	require.ErrorAs(t, err, &dispatchErr)
+	assert.False(t, dispatchErr.Permanent)
+}
+
+func TestGRPCDispatch_TLSNeverSharesPlaintextConnection(t *testing.T) {
+	m := &fakeMigrator{}
+	req := baseDispatchReq
+	req.MigratorUrl = serveMigrator(t, m)
+	n := migrator.NewGRPCMigratorNotifier(newGRPCConns(t))
+	require.NoError(t, n.Dispatch(context.Background(), req))
+
+	// The migrator only speaks plaintext, so the TLS handshake must fail.
+	req.MigratorUrl = "grpcs://" + strings.TrimPrefix(req.MigratorUrl, "grpc://")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.Error(t, n.Dispatch(ctx, req))
+	assert.Len(t, m.dispatched, 1)
+}
+
+// ─── Dry run ─────────────────────────────────────────────────────────────────
+
+func TestGRPCDryRun_ReturnsResult(t *testing.T) {
+	url := serveMigrator(t, &fakeMigrator{})
+
+	result, err := migrator.NewGRPCDryRunAdapter(newGRPCConns(t)).DryRun(context.Background(), url, baseDryRunReq)
+
+	require.NoError(t, err)
+	require.Len(t, result.Steps, 1)
+	assert.Equal(t, "update-chart", result.Steps[0].StepName)
+	require.NotNil(t, result.Steps[0].Files)
+	diff := (*result.Steps[0].Files)[0]
+	assert.Equal(t, api.Modified, diff.Status)
+	require.NotNil(t, diff.Before)
+	assert.Equal(t, "chart: old", *diff.Before)
+}
+
+// ─── Transport routing ───────────────────────────────────────────────────────
+
+type namedNotifier struct {
+	name string
+	got  *string
+}
+
+func (n namedNotifier) Dispatch(context.Context, api.DispatchStepRequest) error {
+	*n.got = n.name
+	return nil
+}
+
+func TestNotifier_RoutesByScheme(t *testing.T) {
+	var got string
+	n := migrator.NewNotifier(namedNotifier{"http", &got}, namedNotifier{"grpc", &got})
+
+	for url, want := range map[string]string{
+		"http://migrator:3001":  "http",
+		"https://migrator":      "http",
+		"grpc://migrator:9090":  "grpc",
+		"grpcs://migrator:9443": "grpc",
+		"":                      "http",
+	} {
+		req := baseDispatchReq
+		req.MigratorUrl = url
+		require.NoError(t, n.Dispatch(context.Background(), req))
+		assert.Equal(t, want, got, url)
+	}
+}
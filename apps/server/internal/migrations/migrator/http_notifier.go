@@ -5,13 +5,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/tilsley/loom/apps/server/internal/migrations"
 	"github.com/tilsley/loom/pkg/api"
 )
 
-// Compile-time check: *HTTPWorkerNotifier implements migrations.WorkerNotifier.
+// Compile-time check: *HTTPMigratorNotifier implements migrations.MigratorNotifier.
 var _ migrations.MigratorNotifier = (*HTTPMigratorNotifier)(nil)
 
 // HTTPMigratorNotifier implements MigratorNotifier by posting DispatchStepRequest
@@ -25,10 +26,20 @@ func NewHTTPMigratorNotifier(client *http.Client) *HTTPMigratorNotifier {
 	return &HTTPMigratorNotifier{client: client}
 }
 
+// maxErrorBody caps how much of a migrator's error answer is read.
+const maxErrorBody = 64 << 10
+
 // Dispatch sends a step request directly to the migrator URL carried in req.MigratorUrl.
+// Failures are returned as migrations.DispatchError: unreachable migrators and
+// retryable answers (see migrations.RetryableDispatch) are transient, every
+// other failure is permanent.
 func (n *HTTPMigratorNotifier) Dispatch(ctx context.Context, req api.DispatchStepRequest) error {
 	if req.MigratorUrl == "" {
-		return fmt.Errorf("no worker URL in dispatch request for step %q", req.StepName)
+		return migrations.DispatchError{
+			MigratorApp: req.MigratorApp,
+			Message:     fmt.Sprintf("no migrator URL in dispatch request for step %q", req.StepName),
+			Permanent:   true,
+		}
 	}
 
 	body, err := json.Marshal(req)
@@ -38,18 +49,32 @@ func (n *HTTPMigratorNotifier) Dispatch(ctx context.Context, req api.DispatchSte
 
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.MigratorUrl+"/dispatch-step", bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("create dispatch request: %w", err)
+		return migrations.DispatchError{MigratorApp: req.MigratorApp, Message: err.Error(), Permanent: true}
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 
 	resp, err := n.client.Do(httpReq)
 	if err != nil {
-		return fmt.Errorf("notify migrator %q: %w", req.MigratorApp, err)
+		return migrations.DispatchError{MigratorApp: req.MigratorApp, Message: err.Error()}
 	}
 	defer resp.Body.Close() //nolint:errcheck
 
-	if resp.StatusCode >= 300 {
-		return fmt.Errorf("migrator %q returned HTTP %d", req.MigratorApp, resp.StatusCode)
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	// The answer may carry a DispatchError; anything else is classified by status.
+	var answer api.DispatchError
+	if raw, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody)); err == nil {
+		_ = json.Unmarshal(raw, &answer)
+	}
+	dispatchErr := migrations.DispatchError{
+		MigratorApp: req.MigratorApp,
+		StatusCode:  resp.StatusCode,
+		Code:        answer.Code,
+		Permanent:   !migrations.RetryableDispatch(resp.StatusCode, answer.Code),
+	}
+	if answer.Message != nil {
+		dispatchErr.Message = *answer.Message
 	}
-	return nil
+	return dispatchErr
 }
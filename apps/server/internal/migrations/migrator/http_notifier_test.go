@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/tilsley/loom/apps/server/internal/migrations"
 	"github.com/tilsley/loom/apps/server/internal/migrations/migrator"
 	"github.com/tilsley/loom/pkg/api"
 )
@@ -108,6 +109,55 @@ func TestDispatch_Non2xxResponse_ReturnsError(t *testing.T) {
 	assert.Contains(t, err.Error(), "500")
 }
 
+func TestDispatch_ErrorBody_ClassifiesError(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		permanent bool
+	}{
+		{"5xx without a body is retried", http.StatusBadGateway, "", false},
+		{"4xx without a body is permanent", http.StatusNotFound, "", true},
+		{"429 is retried", http.StatusTooManyRequests, "", false},
+		{"code overrides a 4xx", http.StatusConflict, `{"code":"transient","message":"lock held"}`, false},
+		{"code overrides a 5xx", http.StatusServiceUnavailable, `{"code":"unsupported_step","message":"no"}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = io.WriteString(w, tt.body)
+			}))
+			defer srv.Close()
+
+			req := baseDispatchReq
+			req.MigratorUrl = srv.URL
+			err := newNotifier().Dispatch(context.Background(), req)
+
+			var dispatchErr migrations.DispatchError
+			require.ErrorAs(t, err, &dispatchErr)
+			assert.Equal(t, tt.status, dispatchErr.StatusCode)
+			assert.Equal(t, tt.permanent, dispatchErr.Permanent)
+		})
+	}
+}
+
+func TestDispatch_ErrorBody_MessageInError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"code":"invalid_request","message":"chart values missing"}`)
+	}))
+	defer srv.Close()
+
+	req := baseDispatchReq
+	req.MigratorUrl = srv.URL
+	err := newNotifier().Dispatch(context.Background(), req)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid_request")
+	assert.Contains(t, err.Error(), "chart values missing")
+}
+
 func TestDispatch_ConnectionRefused_ReturnsError(t *testing.T) {
 	n := newNotifier()
 	req := baseDispatchReq
@@ -115,5 +165,7 @@ func TestDispatch_ConnectionRefused_ReturnsError(t *testing.T) {
 
 	err := n.Dispatch(context.Background(), req)
 
-	assert.Error(t, err)
+	var dispatchErr migrations.DispatchError
+	require.ErrorAs(t, err, &dispatchErr)
+	assert.False(t, dispatchErr.Permanent, "an unreachable migrator may come back")
 }
@@ -0,0 +1,47 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+	"github.com/tilsley/loom/pkg/dispatchqueue"
+)
+
+// Compile-time check: *RedisStreamNotifier implements migrations.MigratorNotifier.
+var _ migrations.MigratorNotifier = (*RedisStreamNotifier)(nil)
+
+// RedisStreamNotifier implements MigratorNotifier by appending each
+// DispatchStepRequest to the Redis Stream of its migrator app (see
+// pkg/dispatchqueue). Migrators pull the stream at their own pace, so a
+// dispatch succeeds once the entry is stored, whether or not the migrator is up.
+type RedisStreamNotifier struct {
+	rdb redis.Cmdable
+}
+
+// NewRedisStreamNotifier creates a RedisStreamNotifier.
+func NewRedisStreamNotifier(rdb redis.Cmdable) *RedisStreamNotifier {
+	return &RedisStreamNotifier{rdb: rdb}
+}
+
+// Dispatch appends req to the stream of req.MigratorApp. A request without a
+// migrator app fails permanently; Redis failures are transient.
+func (n *RedisStreamNotifier) Dispatch(ctx context.Context, req api.DispatchStepRequest) error {
+	if req.MigratorApp == "" {
+		return migrations.DispatchError{
+			Message:   fmt.Sprintf("no migrator app in dispatch request for step %q", req.StepName),
+			Permanent: true,
+		}
+	}
+	if _, err := dispatchqueue.Append(ctx, n.rdb, req); err != nil {
+		return migrations.DispatchError{
+			MigratorApp: req.MigratorApp,
+			Code:        migrations.DispatchErrorTransient,
+			Message:     err.Error(),
+		}
+	}
+	return nil
+}
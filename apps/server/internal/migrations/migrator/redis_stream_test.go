@@ -0,0 +1,70 @@
+package migrator_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/migrations/migrator"
+	"github.com/tilsley/loom/pkg/api"
+	"github.com/tilsley/loom/pkg/dispatchqueue"
+)
+
+// ─── Redis Streams dispatch ──────────────────────────────────────────────────
+
+func TestRedisStreamDispatch_AppendsToAppStream(t *testing.T) {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR not set — skipping Redis integration tests")
+	}
+	rdb := redis.NewClient(&redis.Options{Addr: addr})
+	req := baseDispatchReq
+	req.MigratorApp = "test-redis-stream-notifier"
+	t.Cleanup(func() {
+		_ = rdb.Del(context.Background(), dispatchqueue.Stream(req.MigratorApp)).Err()
+		_ = rdb.Close()
+	})
+
+	require.NoError(t, migrator.NewRedisStreamNotifier(rdb).Dispatch(context.Background(), req))
+
+	msgs, err := rdb.XRange(context.Background(), dispatchqueue.Stream(req.MigratorApp), "-", "+").Result()
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	var got api.DispatchStepRequest
+	require.NoError(t, json.Unmarshal([]byte(msgs[0].Values[dispatchqueue.RequestField].(string)), &got))
+	assert.Equal(t, req.StepName, got.StepName)
+	assert.Equal(t, req.CallbackId, got.CallbackId)
+}
+
+func TestRedisStreamDispatch_RedisDown_IsTransient(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1}) // nothing listening
+	t.Cleanup(func() { _ = rdb.Close() })
+	req := baseDispatchReq
+	req.MigratorApp = "app-chart-migrator"
+
+	err := migrator.NewRedisStreamNotifier(rdb).Dispatch(context.Background(), req)
+
+	var dispatchErr migrations.DispatchError
+	require.ErrorAs(t, err, &dispatchErr)
+	assert.False(t, dispatchErr.Permanent)
+	assert.Equal(t, migrations.DispatchErrorTransient, dispatchErr.Code)
+}
+
+func TestRedisStreamDispatch_NoMigratorApp_IsPermanent(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1"})
+	t.Cleanup(func() { _ = rdb.Close() })
+	req := baseDispatchReq
+	req.MigratorApp = ""
+
+	err := migrator.NewRedisStreamNotifier(rdb).Dispatch(context.Background(), req)
+
+	var dispatchErr migrations.DispatchError
+	require.ErrorAs(t, err, &dispatchErr)
+	assert.True(t, dispatchErr.Permanent)
+}
@@ -0,0 +1,54 @@
+package migrator
+
+import (
+	"context"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// Compile-time checks: the transport routers implement the migrator ports.
+var (
+	_ migrations.MigratorNotifier = (*Notifier)(nil)
+	_ migrations.DryRunner        = (*DryRunner)(nil)
+)
+
+// Notifier dispatches each step over the transport its migrator URL names:
+// gRPC for grpc:// and grpcs:// URLs, HTTP for every other URL.
+type Notifier struct {
+	http migrations.MigratorNotifier
+	grpc migrations.MigratorNotifier
+}
+
+// NewNotifier creates a Notifier from one notifier per transport.
+func NewNotifier(http, grpc migrations.MigratorNotifier) *Notifier {
+	return &Notifier{http: http, grpc: grpc}
+}
+
+// Dispatch sends req with the notifier for req.MigratorUrl.
+func (n *Notifier) Dispatch(ctx context.Context, req api.DispatchStepRequest) error {
+	if migrations.IsGRPCURL(req.MigratorUrl) {
+		return n.grpc.Dispatch(ctx, req)
+	}
+	return n.http.Dispatch(ctx, req)
+}
+
+// DryRunner sends each dry run over the transport its migrator URL names,
+// like Notifier.
+type DryRunner struct {
+	http migrations.DryRunner
+	grpc migrations.DryRunner
+}
+
+// NewDryRunner creates a DryRunner from one dry runner per transport.
+func NewDryRunner(http, grpc migrations.DryRunner) *DryRunner {
+	return &DryRunner{http: http, grpc: grpc}
+}
+
+// DryRun runs req with the dry runner for migratorUrl.
+func (d *DryRunner) DryRun(ctx context.Context, migratorUrl string, req api.DryRunRequest) (*api.DryRunResult, error) {
+	if migrations.IsGRPCURL(migratorUrl) {
+		return d.grpc.DryRun(ctx, migratorUrl, req)
+	}
+	return d.http.DryRun(ctx, migratorUrl, req)
+}
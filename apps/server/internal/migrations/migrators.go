@@ -0,0 +1,140 @@
+package migrations
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// DefaultHealthPath is appended to a migrator's HTTP URL when it registers
+// without a healthUrl. A gRPC migrator's healthUrl defaults to its URL, for
+// the gRPC health checking protocol.
+const DefaultHealthPath = "/health"
+
+// URL schemes of migrators served over gRPC: plaintext and TLS.
+const (
+	SchemeGRPC  = "grpc"
+	SchemeGRPCS = "grpcs"
+)
+
+// IsGRPCURL reports whether a migrator URL names a gRPC migrator.
+func IsGRPCURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == SchemeGRPC || u.Scheme == SchemeGRPCS) && u.Host != ""
+}
+
+// MigratorDryRun is the part of a dry run that is sent to one migrator URL.
+type MigratorDryRun struct {
+	MigratorURL string
+	Request     api.DryRunRequest
+}
+
+// SplitDryRun splits req into one dry run per migrator URL, in the order each
+// URL first handles a step. A step goes to the URL its migratorApp registered,
+// or to fallbackURL. When every step goes to one URL, req is sent whole.
+func SplitDryRun(req api.DryRunRequest, fallbackURL string, registered map[string]string) []MigratorDryRun {
+	urlOf := func(app string) string {
+		if u, ok := registered[app]; ok {
+			return u
+		}
+		return fallbackURL
+	}
+	if len(req.Steps) == 0 {
+		return []MigratorDryRun{{MigratorURL: fallbackURL, Request: req}}
+	}
+
+	var runs []MigratorDryRun
+	index := map[string]int{}
+	for _, step := range req.Steps {
+		u := urlOf(step.MigratorApp)
+		i, ok := index[u]
+		if !ok {
+			i = len(runs)
+			index[u] = i
+			runs = append(runs, MigratorDryRun{
+				MigratorURL: u,
+				Request:     api.DryRunRequest{MigrationId: req.MigrationId, Candidate: req.Candidate},
+			})
+		}
+		runs[i].Request.Steps = append(runs[i].Request.Steps, step)
+	}
+	if len(runs) == 1 {
+		runs[0].Request = req
+	}
+	return runs
+}
+
+// MergeDryRunResults combines the results of a split dry run, ordering the
+// step results as steps are ordered. Results for steps that are not in steps
+// follow in the order they were returned.
+func MergeDryRunResults(steps []api.StepDefinition, results []api.DryRunResult) api.DryRunResult {
+	byName := map[string]api.StepDryRunResult{}
+	var unknown []api.StepDryRunResult
+	known := make(map[string]bool, len(steps))
+	for _, step := range steps {
+		known[step.Name] = true
+	}
+	for _, r := range results {
+		for _, sr := range r.Steps {
+			if known[sr.StepName] {
+				byName[sr.StepName] = sr
+			} else {
+				unknown = append(unknown, sr)
+			}
+		}
+	}
+
+	merged := api.DryRunResult{Steps: make([]api.StepDryRunResult, 0, len(byName)+len(unknown))}
+	for _, step := range steps {
+		if sr, ok := byName[step.Name]; ok {
+			merged.Steps = append(merged.Steps, sr)
+		}
+	}
+	merged.Steps = append(merged.Steps, unknown...)
+	return merged
+}
+
+// newRegisteredMigrator validates a registration and fills in its defaults:
+// trailing slashes are trimmed from url, and healthUrl defaults to
+// url + DefaultHealthPath, or to url for a gRPC migrator.
+func newRegisteredMigrator(req api.MigratorRegistration, now time.Time) (api.RegisteredMigrator, error) {
+	if req.MigratorApp == "" {
+		return api.RegisteredMigrator{}, InvalidMigratorRegistrationError{Reason: "migratorApp is required"}
+	}
+	base := strings.TrimRight(req.Url, "/")
+	if !isMigratorURL(base) {
+		return api.RegisteredMigrator{}, InvalidMigratorRegistrationError{
+			MigratorApp: req.MigratorApp,
+			Reason:      "url must be an absolute http, https, grpc or grpcs URL",
+		}
+	}
+	health := base + DefaultHealthPath
+	if IsGRPCURL(base) {
+		health = base
+	}
+	if req.HealthUrl != nil && *req.HealthUrl != "" {
+		if !isMigratorURL(*req.HealthUrl) {
+			return api.RegisteredMigrator{}, InvalidMigratorRegistrationError{
+				MigratorApp: req.MigratorApp,
+				Reason:      "healthUrl must be an absolute http, https, grpc or grpcs URL",
+			}
+		}
+		health = *req.HealthUrl
+	}
+	return api.RegisteredMigrator{
+		MigratorApp:  req.MigratorApp,
+		Url:          base,
+		HealthUrl:    health,
+		RegisteredAt: now,
+	}, nil
+}
+
+func isMigratorURL(s string) bool {
+	if IsGRPCURL(s) {
+		return true
+	}
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,62 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+func TestSplitDryRun(t *testing.T) {
+	req := api.DryRunRequest{
+		MigrationId: "m1",
+		Candidate:   api.Candidate{Id: "repo-a"},
+		Steps: []api.StepDefinition{
+			{Name: "swap-chart", MigratorApp: "app-chart-migrator"},
+			{Name: "rotate-secret", MigratorApp: "secrets-migrator"},
+			{Name: "cleanup", MigratorApp: "app-chart-migrator"},
+		},
+	}
+
+	runs := migrations.SplitDryRun(req, "http://charts:3001", nil)
+	require.Len(t, runs, 1)
+	assert.Equal(t, migrations.MigratorDryRun{MigratorURL: "http://charts:3001", Request: req},
+		runs[0], "without registrations every step goes to the fallback")
+
+	registered := map[string]string{"secrets-migrator": "http://secrets:3002"}
+	runs = migrations.SplitDryRun(req, "http://charts:3001", registered)
+	require.Len(t, runs, 2)
+	assert.Equal(t, "http://charts:3001", runs[0].MigratorURL)
+	assert.Equal(t, []api.StepDefinition{req.Steps[0], req.Steps[2]}, runs[0].Request.Steps)
+	assert.Equal(t, "repo-a", runs[0].Request.Candidate.Id)
+	assert.Equal(t, "http://secrets:3002", runs[1].MigratorURL)
+	assert.Equal(t, []api.StepDefinition{req.Steps[1]}, runs[1].Request.Steps)
+}
+
+func TestMergeDryRunResults(t *testing.T) {
+	steps := []api.StepDefinition{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	errMsg := "boom"
+
+	merged := migrations.MergeDryRunResults(steps, []api.DryRunResult{
+		{Steps: []api.StepDryRunResult{{StepName: "c"}, {StepName: "a", Error: &errMsg}}},
+		{Steps: []api.StepDryRunResult{{StepName: "extra"}, {StepName: "b", Skipped: true}}},
+	})
+
+	require.Len(t, merged.Steps, 4)
+	assert.Equal(t, "a", merged.Steps[0].StepName)
+	assert.Equal(t, &errMsg, merged.Steps[0].Error)
+	assert.Equal(t, "b", merged.Steps[1].StepName)
+	assert.Equal(t, "c", merged.Steps[2].StepName)
+	assert.Equal(t, "extra", merged.Steps[3].StepName, "results for unknown steps come last")
+}
+
+func TestIsGRPCURL(t *testing.T) {
+	assert.True(t, migrations.IsGRPCURL("grpc://app-chart-migrator:9090"))
+	assert.True(t, migrations.IsGRPCURL("grpcs://app-chart-migrator"))
+	assert.False(t, migrations.IsGRPCURL("http://app-chart-migrator:3001"))
+	assert.False(t, migrations.IsGRPCURL("grpc://"))
+	assert.False(t, migrations.IsGRPCURL(""))
+}
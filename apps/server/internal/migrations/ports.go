@@ -2,6 +2,7 @@ package migrations
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/tilsley/loom/pkg/api"
@@ -12,9 +13,31 @@ const (
 	EventStepDispatched = "step_dispatched"
 	EventStepCompleted  = "step_completed"
 	EventStepRetried    = "step_retried"
+	EventStepSkipped    = "step_skipped"
+	EventStepPending    = "step_pending"
+	EventStepTimedOut   = "step_timed_out"
 	EventRunStarted     = "run_started"
 	EventRunCompleted   = "run_completed"
 	EventRunCancelled   = "run_cancelled"
+	EventRunPaused      = "run_paused"
+	EventRunResumed     = "run_resumed"
+
+	// EventCallbackIgnored records a step callback that was discarded as a
+	// duplicate or as answering an earlier attempt of the step.
+	EventCallbackIgnored = "callback_ignored"
+
+	// EventStepWaitingForWindow records a step held back by a freeze window.
+	EventStepWaitingForWindow = "step_waiting_for_window"
+	// EventStepQueued records a step held back by its migrator app's dispatch limits.
+	EventStepQueued = "step_queued"
+
+	EventRollbackStarted   = "rollback_started"
+	EventRollbackCompleted = "rollback_completed"
+	EventRollbackCancelled = "rollback_cancelled"
+
+	// EventCandidateReconciled records a candidate status the reconciler
+	// corrected because it disagreed with the execution engine.
+	EventCandidateReconciled = "candidate_reconciled"
 )
 
 // StepEvent represents a lifecycle event recorded into the event store.
@@ -57,6 +80,17 @@ type TimelinePoint struct {
 	Failed    int    `json:"failed"`
 }
 
+// StuckStep is a step that has been waiting on a migrator callback for longer
+// than a caller-supplied threshold.
+type StuckStep struct {
+	MigrationID string            `json:"migrationId"`
+	CandidateID string            `json:"candidateId"`
+	StepName    string            `json:"stepName"`
+	Status      string            `json:"status"` // in_progress or pending
+	Since       time.Time         `json:"since"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
 // EventStore records and queries workflow lifecycle events.
 type EventStore interface {
 	RecordEvent(ctx context.Context, event StepEvent) error
@@ -64,6 +98,279 @@ type EventStore interface {
 	GetStepMetrics(ctx context.Context) ([]StepMetrics, error)
 	GetTimeline(ctx context.Context, days int) ([]TimelinePoint, error)
 	GetRecentFailures(ctx context.Context, limit int) ([]StepEvent, error)
+	// GetStuckSteps returns steps of unfinished runs whose latest event left them
+	// in_progress or pending before the given time, oldest first.
+	GetStuckSteps(ctx context.Context, before time.Time) ([]StuckStep, error)
+	// ClaimCallback records that a run received the step callback with the given
+	// event ID. Returns false when the ID had already been claimed for the run.
+	ClaimCallback(ctx context.Context, runID, eventID string) (bool, error)
+	// ReleaseCallback forgets a claimed event ID so that a resend of a callback
+	// that could not be delivered is not mistaken for a duplicate.
+	ReleaseCallback(ctx context.Context, runID, eventID string) error
+}
+
+// AuditEntry records one mutating API call: who made it, what they asked for
+// and how it ended.
+type AuditEntry struct {
+	ID          int64           `json:"id"`
+	Actor       string          `json:"actor"`  // authenticated subject, or "anonymous" when auth is disabled
+	Action      string          `json:"action"` // e.g. "start", "cancel", "announce"
+	MigrationID string          `json:"migrationId,omitempty"`
+	CandidateID string          `json:"candidateId,omitempty"`
+	Payload     json.RawMessage `json:"payload,omitempty"` // request body, when it was JSON
+	StatusCode  int             `json:"statusCode"`
+	Outcome     string          `json:"outcome"` // AuditSucceeded or AuditFailed
+	Error       string          `json:"error,omitempty"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+// Audit outcomes.
+const (
+	AuditSucceeded = "succeeded"
+	AuditFailed    = "failed"
+)
+
+// AuditFilter selects audit entries. Empty fields match everything; Before,
+// when non-zero, only matches entries with a lower ID (the previous page's
+// NextBefore).
+type AuditFilter struct {
+	MigrationID string
+	CandidateID string
+	Actor       string
+	Before      int64
+	Limit       int
+}
+
+// AuditPage is one page of audit entries, newest first. NextBefore is set when
+// older entries remain.
+type AuditPage struct {
+	Entries    []AuditEntry `json:"entries"`
+	NextBefore *int64       `json:"nextBefore,omitempty"`
+}
+
+// AuditLog is the append-only record of mutating API calls.
+type AuditLog interface {
+	Append(ctx context.Context, entry AuditEntry) error
+	// List returns up to filter.Limit matching entries, newest first.
+	List(ctx context.Context, filter AuditFilter) ([]AuditEntry, error)
+}
+
+// EventCandidateStatusChanged is the progress event for a change of candidate
+// status. Its Status is the new status; metadata["from"] holds the old one.
+const EventCandidateStatusChanged = "candidate_status_changed"
+
+// EventProgressResync is the progress event sent in place of events that were
+// pruned before a resuming client received them. Its ID is the last pruned
+// ID; the client should reload the migration's state.
+const EventProgressResync = "resync_required"
+
+// ProgressEvent is a step transition or candidate status change, streamed to
+// clients watching a migration or candidate. IDs increase as events are
+// recorded, so a client can resume after the last ID it received.
+type ProgressEvent struct {
+	ID          int64             `json:"id"`
+	MigrationID string            `json:"migrationId"`
+	CandidateID string            `json:"candidateId"`
+	EventType   string            `json:"eventType"` // a step event type or EventCandidateStatusChanged
+	StepName    string            `json:"stepName,omitempty"`
+	Status      string            `json:"status,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
+}
+
+// ProgressFilter selects the progress events of a migration, or of one of its
+// candidates when CandidateID is set.
+type ProgressFilter struct {
+	MigrationID string
+	CandidateID string
+}
+
+// ProgressFeed streams progress events as they are recorded.
+type ProgressFeed interface {
+	// Subscribe delivers the matching events recorded after afterID (none when
+	// afterID is 0), then new ones as they arrive. When some of those events
+	// have been pruned, an EventProgressResync event comes first. The channel is
+	// closed when ctx ends or the subscriber falls too far behind; the client
+	// should then subscribe again from the last ID it received.
+	Subscribe(ctx context.Context, filter ProgressFilter, afterID int64) (<-chan ProgressEvent, error)
+}
+
+// WebhookSubscription sends the chosen events of one migration to a URL.
+type WebhookSubscription struct {
+	ID          int64             `json:"id"`
+	MigrationID string            `json:"migrationId"`
+	URL         string            `json:"url"`
+	Events      []string          `json:"events"`              // webhook events, e.g. WebhookPROpened
+	Templates   map[string]string `json:"templates,omitempty"` // text/template per event, overriding the default
+	// Secret signs each delivery (pkg/signing). It is only returned when the
+	// subscription is created.
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Webhook delivery states.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed" // gave up after the last attempt
+)
+
+// WebhookDelivery is one event queued for, or sent to, a webhook.
+type WebhookDelivery struct {
+	ID             int64             `json:"id"`
+	WebhookID      int64             `json:"webhookId"`
+	Event          string            `json:"event"`
+	MigrationID    string            `json:"migrationId"`
+	CandidateID    string            `json:"candidateId"`
+	StepName       string            `json:"stepName,omitempty"`
+	Status         string            `json:"status,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+	OccurredAt     time.Time         `json:"occurredAt"`
+	State          string            `json:"state"`
+	Attempts       int               `json:"attempts"`
+	LastStatusCode *int              `json:"lastStatusCode,omitempty"`
+	LastError      string            `json:"lastError,omitempty"`
+	NextAttemptAt  *time.Time        `json:"nextAttemptAt,omitempty"` // while pending
+	DeliveredAt    *time.Time        `json:"deliveredAt,omitempty"`
+}
+
+// WebhookAttempt is the outcome of sending a delivery once. NextAttemptAt is
+// when to try again after a failure; nil gives up.
+type WebhookAttempt struct {
+	Delivered     bool
+	StatusCode    int // 0 when no response was received
+	Error         string
+	NextAttemptAt *time.Time
+}
+
+// ClaimedDelivery is a due delivery together with the webhook it is for.
+type ClaimedDelivery struct {
+	Delivery WebhookDelivery
+	Webhook  WebhookSubscription
+}
+
+// WebhookStore persists webhook subscriptions and their delivery history.
+type WebhookStore interface {
+	// CreateWebhook saves w, setting its ID and CreatedAt.
+	CreateWebhook(ctx context.Context, w *WebhookSubscription) error
+	ListWebhooks(ctx context.Context, migrationID string) ([]WebhookSubscription, error)
+	// GetWebhook returns nil when the migration has no such webhook.
+	GetWebhook(ctx context.Context, migrationID string, id int64) (*WebhookSubscription, error)
+	// DeleteWebhook removes the webhook and its deliveries. Returns false when
+	// the migration has no such webhook.
+	DeleteWebhook(ctx context.Context, migrationID string, id int64) (bool, error)
+	// ListDeliveries returns the webhook's latest deliveries, newest first.
+	ListDeliveries(ctx context.Context, webhookID int64, limit int) ([]WebhookDelivery, error)
+}
+
+// WebhookOutbox hands queued deliveries to the webhook dispatcher. Deliveries
+// are queued by EventStore.RecordEvent, in the same transaction as the step
+// event they announce.
+type WebhookOutbox interface {
+	// ClaimDeliveries returns up to limit pending deliveries that are due and
+	// counts an attempt for each. A claimed delivery is not due again until
+	// lease has passed, so a dispatcher that dies mid-send does not lose it.
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]ClaimedDelivery, error)
+	RecordAttempt(ctx context.Context, deliveryID int64, attempt WebhookAttempt) error
+}
+
+// FreezeWindow is a period during which steps are not dispatched, e.g. a
+// change freeze on production. A window without a MigrationID covers every
+// migration; one with a StepConfig only covers steps whose config has each of
+// its key/value pairs (e.g. env=prod).
+type FreezeWindow struct {
+	ID          int64             `json:"id"`
+	MigrationID string            `json:"migrationId,omitempty"`
+	Reason      string            `json:"reason"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+	StepConfig  map[string]string `json:"stepConfig,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
+}
+
+// FreezeWindowFilter narrows a listing of freeze windows.
+type FreezeWindowFilter struct {
+	// MigrationID keeps the migration's windows and the global ones; empty keeps all.
+	MigrationID string
+	// EndsAfter drops windows that ended at or before it; zero keeps them.
+	EndsAfter time.Time
+}
+
+// FreezeCalendar stores the freeze windows that gate step dispatch.
+type FreezeCalendar interface {
+	CreateFreezeWindow(ctx context.Context, w *FreezeWindow) error
+	// ListFreezeWindows returns the matching windows ordered by start time.
+	ListFreezeWindows(ctx context.Context, filter FreezeWindowFilter) ([]FreezeWindow, error)
+	// GetFreezeWindow returns the window, or nil when it does not exist.
+	GetFreezeWindow(ctx context.Context, id int64) (*FreezeWindow, error)
+	// DeleteFreezeWindow removes the window, reporting whether it existed.
+	DeleteFreezeWindow(ctx context.Context, id int64) (bool, error)
+}
+
+// ScheduleStore persists scheduled starts.
+type ScheduleStore interface {
+	CreateScheduledStart(ctx context.Context, s api.ScheduledStart) error
+	// ListScheduledStarts returns the migration's scheduled starts, soonest first.
+	ListScheduledStarts(ctx context.Context, migrationID string) ([]api.ScheduledStart, error)
+	// GetScheduledStart returns the scheduled start, or nil when it does not exist.
+	GetScheduledStart(ctx context.Context, id string) (*api.ScheduledStart, error)
+	// TransitionScheduledStart moves the start from status from to status to,
+	// recording runID and errMsg when set. It reports false, changing nothing,
+	// when the start is not in status from, so it fires or is cancelled once.
+	TransitionScheduledStart(
+		ctx context.Context,
+		id string,
+		from, to api.ScheduledStartStatus,
+		runID, errMsg string,
+	) (bool, error)
+}
+
+// DispatchLimiter enforces migrator apps' dispatch limits across all runs.
+type DispatchLimiter interface {
+	// SetDispatchLimits replaces the limits of each app in limits on behalf of
+	// migrationID, and drops the limits migrationID owns of apps absent from
+	// limits. An app's limits are shared by every migration that dispatches to
+	// it and owned by the migration that declared them; when another migration
+	// owns different limits for one of the apps, nothing is changed and
+	// DispatchLimitConflictError is returned.
+	SetDispatchLimits(ctx context.Context, migrationID string, limits []api.DispatchLimit) error
+	// AcquireDispatchSlot takes a slot for holder when the app's limits allow
+	// another dispatch. Asking again for a holder that already has a slot
+	// grants the same slot.
+	AcquireDispatchSlot(ctx context.Context, migratorApp, holder string) (DispatchSlotGrant, error)
+	// RenewDispatchSlot extends holder's slot to DispatchSlotLease from now;
+	// renewing a released slot is a no-op.
+	RenewDispatchSlot(ctx context.Context, migratorApp, holder string) error
+	// ReleaseDispatchSlot frees holder's slot; releasing it twice is a no-op.
+	ReleaseDispatchSlot(ctx context.Context, migratorApp, holder string) error
+}
+
+// MigratorRegistry stores the base URLs migrator apps register, so steps are
+// dispatched to the migrator that handles them.
+type MigratorRegistry interface {
+	// RegisterMigrator inserts m, or replaces the app's earlier registration.
+	RegisterMigrator(ctx context.Context, m api.RegisteredMigrator) error
+	// GetMigrator returns the app's registration, or nil when it has not registered.
+	GetMigrator(ctx context.Context, migratorApp string) (*api.RegisteredMigrator, error)
+	// ListMigrators returns every registration ordered by app-id.
+	ListMigrators(ctx context.Context) ([]api.RegisteredMigrator, error)
+}
+
+// StepEscalation describes a step that passed its timeoutSeconds deadline
+// without a terminal callback.
+type StepEscalation struct {
+	MigrationID    string            `json:"migrationId"`
+	CandidateID    string            `json:"candidateId"`
+	StepName       string            `json:"stepName"`
+	RunID          string            `json:"runId"`
+	TimeoutSeconds int               `json:"timeoutSeconds"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+}
+
+// StepEscalator notifies people that a step is stuck, e.g. by calling a
+// chat or paging webhook. Implementations live in the adapters layer.
+type StepEscalator interface {
+	Escalate(ctx context.Context, e StepEscalation) error
 }
 
 // MigratorNotifier dispatches step requests to external migrators.
@@ -78,6 +385,49 @@ type ExecutionEngine interface {
 	GetStatus(ctx context.Context, instanceID string) (*RunStatus, error)
 	RaiseEvent(ctx context.Context, instanceID, eventName string, payload any) error
 	CancelRun(ctx context.Context, instanceID string) error
+	// QueryRun decodes the result of the named query against a run into out.
+	// Returns RunNotFoundError when the run does not exist.
+	QueryRun(ctx context.Context, instanceID, queryType string, out any) error
+}
+
+// RunLister lists the runs an execution engine has in flight, in one call
+// rather than one status lookup per run. The listing may lag a little behind
+// runs that have just started or closed.
+type RunLister interface {
+	// ListOpenRuns returns the instance IDs of the running runs of the given types.
+	ListOpenRuns(ctx context.Context, runTypes ...string) ([]string, error)
+}
+
+// LeaderLock elects one server replica to run a background job that must not
+// run on several replicas at once.
+type LeaderLock interface {
+	// TryLead reports whether this replica holds the lock, taking it when no
+	// replica does. A replica keeps the lock until Release, or until its hold
+	// lapses, e.g. because its database connection dropped.
+	TryLead(ctx context.Context) (bool, error)
+	// Release gives up the lock if this replica holds it.
+	Release(ctx context.Context) error
+}
+
+// ClosedRun is what the in-process execution engine keeps of a run once it
+// has ended: its runtime status, its result when it completed, and the last
+// answer to each of its queries.
+type ClosedRun struct {
+	ID            string
+	RunType       string
+	RuntimeStatus string
+	Result        json.RawMessage
+	Queries       map[string]json.RawMessage
+	ClosedAt      time.Time
+}
+
+// ClosedRunStore keeps the closed runs of the in-process execution engine, so
+// their status and step results can still be read once the run is gone.
+type ClosedRunStore interface {
+	// SaveClosedRun inserts r, or replaces an earlier run with the same ID.
+	SaveClosedRun(ctx context.Context, r ClosedRun) error
+	// GetClosedRun returns the closed run, or nil when there is none with that ID.
+	GetClosedRun(ctx context.Context, id string) (*ClosedRun, error)
 }
 
 // DryRunner simulates a full migration run and returns per-step file diffs.
@@ -94,4 +444,18 @@ type MigrationStore interface {
 	SaveCandidates(ctx context.Context, migrationID string, candidates []api.Candidate) error
 	GetCandidates(ctx context.Context, migrationID string) ([]api.Candidate, error)
 	UpdateCandidateMetadata(ctx context.Context, migrationID, candidateID string, metadata map[string]string) error
+	// SaveVersion records an immutable version of a migration's definition.
+	// The migration must already be saved.
+	SaveVersion(ctx context.Context, v api.MigrationVersion) error
+	// ListVersions returns every version of a migration's definition, oldest first.
+	ListVersions(ctx context.Context, migrationID string) ([]api.MigrationVersion, error)
+	// GetVersion returns one version of a migration's definition, or nil when it does not exist.
+	GetVersion(ctx context.Context, migrationID string, version int) (*api.MigrationVersion, error)
+	// CreateRun records a new run attempt for a candidate.
+	CreateRun(ctx context.Context, r api.RunAttempt) error
+	// FinishRun records the outcome and final step results of a run attempt.
+	// Finishing a run that was never recorded is a no-op.
+	FinishRun(ctx context.Context, runID string, status api.RunAttemptStatus, steps []api.StepState) error
+	// ListRuns returns a candidate's run attempts, newest first.
+	ListRuns(ctx context.Context, migrationID, candidateID string) ([]api.RunAttempt, error)
 }
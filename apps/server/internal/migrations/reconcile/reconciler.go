@@ -0,0 +1,285 @@
+// Package reconcile repairs drift between the candidates table and the runs
+// the execution engine has in flight.
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+const instrName = "github.com/tilsley/loom"
+
+// Interval is how often the leading replica reconciles.
+const Interval = time.Minute
+
+// candidateRunTypes are the run types that drive a candidate's status.
+var candidateRunTypes = []string{"MigrationOrchestrator", migrations.RollbackRunType}
+
+// Repair is a candidate status the reconciler corrected.
+type Repair struct {
+	MigrationID string
+	CandidateID string
+	RunID       string
+	From        api.CandidateStatus
+	To          api.CandidateStatus
+	Reason      string
+}
+
+// Reconciler compares candidate statuses with the engine's open runs and
+// repairs them where they disagree:
+//   - a running candidate whose run is no longer open is set to the status
+//     its run attempt ended with, or reset to not_started when the attempt
+//     never recorded an outcome;
+//   - a candidate that is not running while its current run attempt is open
+//     is set back to running.
+//
+// Starting, cancelling and finishing a run update the candidate and the run
+// one after the other, and the engine's listing may lag, so a drift is only
+// repaired once two passes in a row have seen it.
+type Reconciler struct {
+	runs   migrations.RunLister
+	store  migrations.MigrationStore
+	events migrations.EventStore
+	lock   migrations.LeaderLock
+	log    *slog.Logger
+
+	repairs  metric.Int64Counter
+	suspects map[string]bool // drifts seen by the previous pass
+}
+
+// NewReconciler creates a Reconciler. events may be nil, in which case repairs
+// are not recorded as events. Only the replica holding lock reconciles.
+func NewReconciler(
+	runs migrations.RunLister,
+	store migrations.MigrationStore,
+	events migrations.EventStore,
+	lock migrations.LeaderLock,
+	log *slog.Logger,
+) *Reconciler {
+	repairs, _ := otel.Meter(instrName).Int64Counter("loom.reconciler.repairs",
+		metric.WithDescription("Number of candidate statuses repaired by the reconciler"))
+	return &Reconciler{runs: runs, store: store, events: events, lock: lock, log: log, repairs: repairs}
+}
+
+// Run reconciles every Interval while this replica leads, until ctx is
+// cancelled. The lock is released on the way out so another replica can
+// take over without waiting for the connection to drop.
+func (r *Reconciler) Run(ctx context.Context) {
+	defer func() {
+		releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		if err := r.lock.Release(releaseCtx); err != nil {
+			r.log.Warn("failed to release reconciler lock", "error", err)
+		}
+	}()
+
+	ticker := time.NewTicker(Interval)
+	defer ticker.Stop()
+	for {
+		lead, err := r.lock.TryLead(ctx)
+		switch {
+		case err != nil:
+			if ctx.Err() == nil {
+				r.log.Error("reconciler leader election failed", "error", err)
+			}
+			r.suspects = nil
+		case !lead:
+			// Drifts seen while leading may be stale by the time this replica leads again.
+			r.suspects = nil
+		default:
+			if _, err := r.Reconcile(ctx); err != nil && ctx.Err() == nil {
+				r.log.Error("reconcile failed", "error", err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile runs one pass and returns the repairs it made.
+func (r *Reconciler) Reconcile(ctx context.Context) ([]Repair, error) {
+	openIDs, err := r.runs.ListOpenRuns(ctx, candidateRunTypes...)
+	if err != nil {
+		return nil, fmt.Errorf("list open runs: %w", err)
+	}
+	migs, err := r.store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list migrations: %w", err)
+	}
+
+	// mayHaveOpen holds the RunIDs of the candidates an open run may belong
+	// to; checkOpen settles which from the runs table.
+	open := make(map[string]bool, len(openIDs))
+	mayHaveOpen := make(map[string]bool, len(openIDs))
+	for _, id := range openIDs {
+		open[id] = true
+		for _, owner := range migrations.RunAttemptOwners(id) {
+			mayHaveOpen[owner] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	var repairs []Repair
+	for _, m := range migs {
+		for _, c := range m.Candidates {
+			var repair *Repair
+			var err error
+			if c.Status == api.CandidateStatusRunning {
+				repair, err = r.checkRunning(ctx, m.Id, c, open, seen)
+			} else if mayHaveOpen[migrations.RunID(m.Id, c.Id)] {
+				repair, err = r.checkOpen(ctx, m.Id, c, open, seen)
+			}
+			if err != nil {
+				return repairs, err
+			}
+			if repair != nil {
+				repairs = append(repairs, *repair)
+			}
+		}
+	}
+	r.suspects = seen
+	return repairs, nil
+}
+
+// checkRunning repairs a running candidate whose current run is not open.
+func (r *Reconciler) checkRunning(
+	ctx context.Context,
+	migrationID string,
+	c api.Candidate,
+	open, seen map[string]bool,
+) (*Repair, error) {
+	latest, err := r.latestRun(ctx, migrationID, c.Id)
+	if err != nil {
+		return nil, err
+	}
+	runID := migrations.RunID(migrationID, c.Id)
+	if latest != nil {
+		runID = latest.RunId
+	}
+	if open[runID] || !r.confirm(seen, "closed:"+runID) {
+		return nil, nil //nolint:nilnil
+	}
+
+	repair := Repair{
+		MigrationID: migrationID,
+		CandidateID: c.Id,
+		RunID:       runID,
+		From:        c.Status,
+		To:          api.CandidateStatusNotStarted,
+		Reason:      "run is no longer running",
+	}
+	switch {
+	case latest != nil && latest.Status == api.RunAttemptStatusCompleted:
+		repair.Reason = "run completed"
+		if latest.Type == api.RunAttemptTypeMigration {
+			repair.To = api.CandidateStatusCompleted
+		}
+	case latest != nil && latest.Status == api.RunAttemptStatusRunning:
+		// The run ended without recording how; it is counted as failed, as
+		// Service.GetCandidates does for runs the engine no longer knows.
+		if err := r.store.FinishRun(ctx, runID, api.RunAttemptStatusFailed, nil); err != nil {
+			return nil, fmt.Errorf("finish run %q: %w", runID, err)
+		}
+	}
+	return &repair, r.apply(ctx, repair)
+}
+
+// checkOpen repairs a candidate that is not running while its current run is
+// open.
+func (r *Reconciler) checkOpen(
+	ctx context.Context,
+	migrationID string,
+	c api.Candidate,
+	open, seen map[string]bool,
+) (*Repair, error) {
+	latest, err := r.latestRun(ctx, migrationID, c.Id)
+	if err != nil {
+		return nil, err
+	}
+	// Only the current attempt drives the candidate, and only until it has
+	// recorded an outcome.
+	runID := migrations.RunID(migrationID, c.Id)
+	if latest != nil {
+		if latest.Status != api.RunAttemptStatusRunning {
+			return nil, nil //nolint:nilnil
+		}
+		runID = latest.RunId
+	}
+	if !open[runID] || !r.confirm(seen, "open:"+runID+":"+string(c.Status)) {
+		return nil, nil //nolint:nilnil
+	}
+
+	repair := Repair{
+		MigrationID: migrationID,
+		CandidateID: c.Id,
+		RunID:       runID,
+		From:        c.Status,
+		To:          api.CandidateStatusRunning,
+		Reason:      "run is still running",
+	}
+	return &repair, r.apply(ctx, repair)
+}
+
+// confirm marks key as seen by this pass and reports whether the previous
+// pass saw it too.
+func (r *Reconciler) confirm(seen map[string]bool, key string) bool {
+	seen[key] = true
+	return r.suspects[key]
+}
+
+// apply writes the repaired status and reports the repair.
+func (r *Reconciler) apply(ctx context.Context, repair Repair) error {
+	if err := r.store.SetCandidateStatus(ctx, repair.MigrationID, repair.CandidateID, repair.To); err != nil {
+		return fmt.Errorf("set candidate %q status: %w", repair.CandidateID, err)
+	}
+	r.log.Warn("reconciled candidate status",
+		"migrationId", repair.MigrationID, "candidate", repair.CandidateID, "runId", repair.RunID,
+		"from", repair.From, "to", repair.To, "reason", repair.Reason)
+	r.repairs.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("from", string(repair.From)),
+		attribute.String("to", string(repair.To)),
+	))
+	if r.events == nil {
+		return nil
+	}
+	err := r.events.RecordEvent(ctx, migrations.StepEvent{
+		MigrationID: repair.MigrationID,
+		CandidateID: repair.CandidateID,
+		EventType:   migrations.EventCandidateReconciled,
+		Status:      string(repair.To),
+		Metadata: map[string]string{
+			"from":   string(repair.From),
+			"runId":  repair.RunID,
+			"reason": repair.Reason,
+		},
+	})
+	if err != nil {
+		r.log.Warn("failed to record reconcile event", "candidate", repair.CandidateID, "error", err)
+	}
+	return nil
+}
+
+// latestRun returns the candidate's most recent run attempt, or nil when none
+// has been recorded.
+func (r *Reconciler) latestRun(ctx context.Context, migrationID, candidateID string) (*api.RunAttempt, error) {
+	runs, err := r.store.ListRuns(ctx, migrationID, candidateID)
+	if err != nil {
+		return nil, fmt.Errorf("list runs of %q: %w", candidateID, err)
+	}
+	if len(runs) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+	return &runs[0], nil
+}
@@ -0,0 +1,307 @@
+package reconcile_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/migrations/reconcile"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// fakeStore serves one migration and its run attempts. The rest of
+// MigrationStore is never called by the reconciler.
+type fakeStore struct {
+	migrations.MigrationStore
+
+	mu        sync.Mutex
+	migration api.Migration
+	runs      map[string][]api.RunAttempt // newest first, by candidate
+	finished  map[string]api.RunAttemptStatus
+	lists     int
+}
+
+func newFakeStore(candidates ...api.Candidate) *fakeStore {
+	return &fakeStore{
+		migration: api.Migration{Id: "mig-abc", Candidates: candidates},
+		runs:      make(map[string][]api.RunAttempt),
+		finished:  make(map[string]api.RunAttemptStatus),
+	}
+}
+
+func (s *fakeStore) List(context.Context) ([]api.Migration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lists++
+	m := s.migration
+	m.Candidates = append([]api.Candidate(nil), s.migration.Candidates...)
+	return []api.Migration{m}, nil
+}
+
+func (s *fakeStore) ListRuns(_ context.Context, _, candidateID string) ([]api.RunAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.runs[candidateID], nil
+}
+
+func (s *fakeStore) SetCandidateStatus(_ context.Context, _, candidateID string, status api.CandidateStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, c := range s.migration.Candidates {
+		if c.Id == candidateID {
+			s.migration.Candidates[i].Status = status
+		}
+	}
+	return nil
+}
+
+func (s *fakeStore) FinishRun(_ context.Context, runID string, status api.RunAttemptStatus, _ []api.StepState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.finished[runID] = status
+	return nil
+}
+
+func (s *fakeStore) status(candidateID string) api.CandidateStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.migration.Candidates {
+		if c.Id == candidateID {
+			return c.Status
+		}
+	}
+	return ""
+}
+
+// openRuns is a RunLister with a fixed set of open runs.
+type openRuns []string
+
+func (o openRuns) ListOpenRuns(context.Context, ...string) ([]string, error) { return o, nil }
+
+// recordingEvents records the events the reconciler writes.
+type recordingEvents struct {
+	migrations.EventStore
+	events []migrations.StepEvent
+}
+
+func (e *recordingEvents) RecordEvent(_ context.Context, event migrations.StepEvent) error {
+	e.events = append(e.events, event)
+	return nil
+}
+
+// fakeLock leads when lead is set and counts releases.
+type fakeLock struct {
+	lead     bool
+	mu       sync.Mutex
+	released int
+}
+
+func (l *fakeLock) TryLead(context.Context) (bool, error) { return l.lead, nil }
+
+func (l *fakeLock) Release(context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.released++
+	return nil
+}
+
+var quiet = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func attempt(candidateID string, n int, typ api.RunAttemptType, status api.RunAttemptStatus) api.RunAttempt {
+	return api.RunAttempt{
+		RunId:       migrations.RunAttemptID("mig-abc", candidateID, n),
+		MigrationId: "mig-abc",
+		CandidateId: candidateID,
+		Attempt:     n,
+		Type:        typ,
+		Status:      status,
+	}
+}
+
+// reconcileTwice runs two passes, the first of which must not repair anything.
+func reconcileTwice(t *testing.T, r *reconcile.Reconciler) []reconcile.Repair {
+	t.Helper()
+	first, err := r.Reconcile(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, first, "a drift is only repaired once a second pass confirms it")
+	second, err := r.Reconcile(context.Background())
+	require.NoError(t, err)
+	return second
+}
+
+// ─── Running candidates without an open run ─────────────────────────────────
+
+func TestReconcile_CompletedRun_SetsFinalStatus(t *testing.T) {
+	store := newFakeStore(api.Candidate{Id: "billing-api", Status: api.CandidateStatusRunning})
+	store.runs["billing-api"] = []api.RunAttempt{
+		attempt("billing-api", 2, api.RunAttemptTypeMigration, api.RunAttemptStatusCompleted),
+	}
+	events := &recordingEvents{}
+	r := reconcile.NewReconciler(openRuns{}, store, events, &fakeLock{}, quiet)
+
+	repairs := reconcileTwice(t, r)
+
+	require.Len(t, repairs, 1)
+	assert.Equal(t, api.CandidateStatusCompleted, repairs[0].To)
+	assert.Equal(t, "mig-abc__billing-api__2", repairs[0].RunID)
+	assert.Equal(t, api.CandidateStatusCompleted, store.status("billing-api"))
+	require.Len(t, events.events, 1)
+	assert.Equal(t, migrations.EventCandidateReconciled, events.events[0].EventType)
+	assert.Equal(t, "running", events.events[0].Metadata["from"])
+}
+
+func TestReconcile_CompletedRollback_ResetsCandidate(t *testing.T) {
+	store := newFakeStore(api.Candidate{Id: "billing-api", Status: api.CandidateStatusRunning})
+	store.runs["billing-api"] = []api.RunAttempt{
+		attempt("billing-api", 2, api.RunAttemptTypeRollback, api.RunAttemptStatusCompleted),
+	}
+	r := reconcile.NewReconciler(openRuns{}, store, nil, &fakeLock{}, quiet)
+
+	repairs := reconcileTwice(t, r)
+
+	require.Len(t, repairs, 1)
+	assert.Equal(t, api.CandidateStatusNotStarted, store.status("billing-api"))
+}
+
+func TestReconcile_VanishedRun_ResetsCandidateAndFailsAttempt(t *testing.T) {
+	store := newFakeStore(api.Candidate{Id: "billing-api", Status: api.CandidateStatusRunning})
+	store.runs["billing-api"] = []api.RunAttempt{
+		attempt("billing-api", 1, api.RunAttemptTypeMigration, api.RunAttemptStatusRunning),
+	}
+	r := reconcile.NewReconciler(openRuns{}, store, nil, &fakeLock{}, quiet)
+
+	repairs := reconcileTwice(t, r)
+
+	require.Len(t, repairs, 1)
+	assert.Equal(t, api.CandidateStatusNotStarted, store.status("billing-api"))
+	assert.Equal(t, api.RunAttemptStatusFailed, store.finished["mig-abc__billing-api"])
+}
+
+func TestReconcile_RunningCandidateWithOpenRun_IsLeftAlone(t *testing.T) {
+	store := newFakeStore(api.Candidate{Id: "billing-api", Status: api.CandidateStatusRunning})
+	store.runs["billing-api"] = []api.RunAttempt{
+		attempt("billing-api", 3, api.RunAttemptTypeMigration, api.RunAttemptStatusRunning),
+	}
+	r := reconcile.NewReconciler(openRuns{"mig-abc__billing-api__3"}, store, nil, &fakeLock{}, quiet)
+
+	repairs := reconcileTwice(t, r)
+
+	assert.Empty(t, repairs)
+	assert.Equal(t, api.CandidateStatusRunning, store.status("billing-api"))
+}
+
+// ─── Open runs of candidates that are not running ───────────────────────────
+
+func TestReconcile_OpenRun_SetsCandidateRunning(t *testing.T) {
+	store := newFakeStore(api.Candidate{Id: "billing-api", Status: api.CandidateStatusNotStarted})
+	store.runs["billing-api"] = []api.RunAttempt{
+		attempt("billing-api", 2, api.RunAttemptTypeMigration, api.RunAttemptStatusRunning),
+	}
+	r := reconcile.NewReconciler(openRuns{"mig-abc__billing-api__2"}, store, nil, &fakeLock{}, quiet)
+
+	repairs := reconcileTwice(t, r)
+
+	require.Len(t, repairs, 1)
+	assert.Equal(t, api.CandidateStatusNotStarted, repairs[0].From)
+	assert.Equal(t, api.CandidateStatusRunning, store.status("billing-api"))
+}
+
+func TestReconcile_OpenRunWithRecordedOutcome_IsLeftAlone(t *testing.T) {
+	// A run that has recorded its outcome and updated the candidate, but not yet closed.
+	store := newFakeStore(api.Candidate{Id: "billing-api", Status: api.CandidateStatusCompleted})
+	store.runs["billing-api"] = []api.RunAttempt{
+		attempt("billing-api", 1, api.RunAttemptTypeMigration, api.RunAttemptStatusCompleted),
+	}
+	r := reconcile.NewReconciler(openRuns{"mig-abc__billing-api"}, store, nil, &fakeLock{}, quiet)
+
+	repairs := reconcileTwice(t, r)
+
+	assert.Empty(t, repairs)
+}
+
+func TestReconcile_DriftGoneByNextPass_IsNotRepaired(t *testing.T) {
+	// A run that has started but whose candidate is not yet marked running.
+	store := newFakeStore(api.Candidate{Id: "billing-api", Status: api.CandidateStatusNotStarted})
+	store.runs["billing-api"] = []api.RunAttempt{
+		attempt("billing-api", 1, api.RunAttemptTypeMigration, api.RunAttemptStatusRunning),
+	}
+	r := reconcile.NewReconciler(openRuns{"mig-abc__billing-api"}, store, nil, &fakeLock{}, quiet)
+	repairs, err := r.Reconcile(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, repairs)
+
+	require.NoError(t, store.SetCandidateStatus(context.Background(), "mig-abc", "billing-api", api.CandidateStatusRunning))
+	repairs, err = r.Reconcile(context.Background())
+	require.NoError(t, err)
+
+	assert.Empty(t, repairs)
+}
+
+func TestReconcile_CandidateIDEndingInAttemptSuffix_OwnsItsRun(t *testing.T) {
+	// "mig-abc__billing-api__2" is the first attempt of "billing-api__2", not
+	// the second of "billing-api", which has never been started.
+	store := newFakeStore(
+		api.Candidate{Id: "billing-api", Status: api.CandidateStatusNotStarted},
+		api.Candidate{Id: "billing-api__2", Status: api.CandidateStatusNotStarted},
+	)
+	store.runs["billing-api__2"] = []api.RunAttempt{
+		attempt("billing-api__2", 1, api.RunAttemptTypeMigration, api.RunAttemptStatusRunning),
+	}
+	r := reconcile.NewReconciler(openRuns{"mig-abc__billing-api__2"}, store, nil, &fakeLock{}, quiet)
+
+	repairs := reconcileTwice(t, r)
+
+	require.Len(t, repairs, 1)
+	assert.Equal(t, "billing-api__2", repairs[0].CandidateID)
+	assert.Equal(t, "mig-abc__billing-api__2", repairs[0].RunID)
+	assert.Equal(t, api.CandidateStatusRunning, store.status("billing-api__2"))
+	assert.Equal(t, api.CandidateStatusNotStarted, store.status("billing-api"))
+}
+
+func TestReconcile_LaterAttemptOfCandidate_IsNotClaimedBySuffixedCandidate(t *testing.T) {
+	store := newFakeStore(
+		api.Candidate{Id: "billing-api", Status: api.CandidateStatusNotStarted},
+		api.Candidate{Id: "billing-api__2", Status: api.CandidateStatusNotStarted},
+	)
+	store.runs["billing-api"] = []api.RunAttempt{
+		attempt("billing-api", 2, api.RunAttemptTypeMigration, api.RunAttemptStatusRunning),
+	}
+	store.runs["billing-api__2"] = []api.RunAttempt{
+		attempt("billing-api__2", 1, api.RunAttemptTypeMigration, api.RunAttemptStatusCompleted),
+	}
+	r := reconcile.NewReconciler(openRuns{"mig-abc__billing-api__2"}, store, nil, &fakeLock{}, quiet)
+
+	repairs := reconcileTwice(t, r)
+
+	require.Len(t, repairs, 1)
+	assert.Equal(t, "billing-api", repairs[0].CandidateID)
+	assert.Equal(t, api.CandidateStatusRunning, store.status("billing-api"))
+	assert.Equal(t, api.CandidateStatusNotStarted, store.status("billing-api__2"))
+}
+
+// ─── Leader election ─────────────────────────────────────────────────────────
+
+func TestRun_OnlyLeaderReconciles(t *testing.T) {
+	store := newFakeStore()
+	lock := &fakeLock{lead: false}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		reconcile.NewReconciler(openRuns{}, store, nil, lock, quiet).Run(ctx)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	<-done
+
+	assert.Zero(t, store.lists, "a replica that does not lead does not reconcile")
+	assert.Equal(t, 1, lock.released, "the lock is released on shutdown")
+}
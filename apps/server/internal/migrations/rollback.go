@@ -0,0 +1,64 @@
+package migrations
+
+import (
+	"slices"
+
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// RollbackRunType is the engine run type that dispatches a candidate's
+// compensating steps.
+const RollbackRunType = "RollbackOrchestrator"
+
+// RollbackStepName returns the name of the compensating step for stepName.
+func RollbackStepName(stepName string) string {
+	return "rollback-" + stepName
+}
+
+// BuildRollbackManifest builds the manifest of a rollback run for a candidate
+// from the results of its last run; m must hold the definition that run was
+// started from. Every step that succeeded or merged and
+// names a compensationType gets a compensating step: same migrator and config,
+// the compensation as its type, and the original step name under the
+// "compensates" config key. Compensations run one at a time in reverse
+// dependency order, so later changes are undone first.
+func BuildRollbackManifest(m api.Migration, candidate api.Candidate, results []api.StepState) api.MigrationManifest {
+	manifest := BuildManifest(m, candidate, nil)
+
+	done := make(map[string]bool, len(results))
+	for _, r := range results {
+		if r.Candidate.Id == candidate.Id &&
+			(r.Status == api.StepStateStatusSucceeded || r.Status == api.StepStateStatusMerged) {
+			done[r.StepName] = true
+		}
+	}
+
+	order := TopologicalOrder(manifest.Steps)
+	slices.Reverse(order)
+
+	var steps []api.StepDefinition
+	for _, s := range order {
+		if !done[s.Name] || s.CompensationType == nil {
+			continue
+		}
+		config := map[string]string{"compensates": s.Name}
+		if s.Config != nil {
+			for k, v := range *s.Config {
+				config[k] = v
+			}
+		}
+		description := "Roll back " + s.Name
+		steps = append(steps, api.StepDefinition{
+			Name:           RollbackStepName(s.Name),
+			Description:    &description,
+			MigratorApp:    s.MigratorApp,
+			Type:           s.CompensationType,
+			Config:         &config,
+			RetryPolicy:    s.RetryPolicy,
+			DispatchRetry:  s.DispatchRetry,
+			TimeoutSeconds: s.TimeoutSeconds,
+		})
+	}
+	manifest.Steps = steps
+	return manifest
+}
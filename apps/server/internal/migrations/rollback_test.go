@@ -0,0 +1,49 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+func TestBuildRollbackManifest(t *testing.T) {
+	compensated := func(s api.StepDefinition, compensation string) api.StepDefinition {
+		s.CompensationType = &compensation
+		s.Config = &map[string]string{"env": "prod"}
+		return s
+	}
+	candidate := api.Candidate{Id: "billing-api"}
+	result := func(step string, status api.StepStateStatus) api.StepState {
+		return api.StepState{StepName: step, Candidate: candidate, Status: status}
+	}
+
+	m := api.Migration{
+		Id: "app-chart-migration",
+		Steps: []api.StepDefinition{
+			compensated(stepDef("disable-sync-prune"), "enable-sync-prune"),
+			compensated(stepDef("swap-chart", "disable-sync-prune"), "revert-chart"),
+			stepDef("review", "swap-chart"),
+			compensated(stepDef("cleanup", "review"), "restore-values"),
+		},
+	}
+	results := []api.StepState{
+		result("disable-sync-prune", api.StepStateStatusSucceeded),
+		result("swap-chart", api.StepStateStatusMerged),
+		result("review", api.StepStateStatusSucceeded),
+		result("cleanup", api.StepStateStatusFailed),
+	}
+
+	manifest := migrations.BuildRollbackManifest(m, candidate, results)
+
+	require.Len(t, manifest.Steps, 2, "only completed steps with a compensation are rolled back")
+	assert.Equal(t, "rollback-swap-chart", manifest.Steps[0].Name, "later steps are undone first")
+	assert.Equal(t, "revert-chart", *manifest.Steps[0].Type)
+	assert.Equal(t, map[string]string{"env": "prod", "compensates": "swap-chart"}, *manifest.Steps[0].Config)
+	assert.Equal(t, "rollback-disable-sync-prune", manifest.Steps[1].Name)
+	assert.Nil(t, manifest.Steps[1].DependsOn)
+	assert.Equal(t, []api.Candidate{candidate}, manifest.Candidates)
+}
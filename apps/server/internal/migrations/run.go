@@ -2,7 +2,9 @@ package migrations
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/tilsley/loom/pkg/api"
 )
@@ -19,16 +21,52 @@ const (
 type RunStatus struct {
 	RuntimeStatus string
 	Steps         []api.StepState // Step results from the run; populated for both running and completed runs.
+	Paused        bool            // True while a running run is holding its next dispatch for an operator resume.
 }
 
 const runIDSep = "__"
 
-// RunID returns the deterministic run instance ID for a migration+candidate pair.
-// Since each candidate runs at most once per migration, the ID is stable and recoverable.
+// RunID returns the deterministic run instance ID of a candidate's first attempt.
+// Runs started before attempts were recorded also use this ID.
 func RunID(migrationID, candidateID string) string {
 	return migrationID + runIDSep + candidateID
 }
 
+// RunAttemptID returns the run instance ID for the given attempt of a candidate.
+// The first attempt keeps the plain RunID so that earlier runs stay addressable;
+// later attempts append the attempt number.
+func RunAttemptID(migrationID, candidateID string, attempt int) string {
+	if attempt <= 1 {
+		return RunID(migrationID, candidateID)
+	}
+	return RunID(migrationID, candidateID) + runIDSep + strconv.Itoa(attempt)
+}
+
+// NextRunAttempt returns a new running attempt numbered after the latest of
+// previous, which holds the candidate's earlier attempts.
+func NextRunAttempt(
+	previous []api.RunAttempt,
+	migrationID, candidateID string,
+	runType api.RunAttemptType,
+	version *int,
+	now time.Time,
+) api.RunAttempt {
+	attempt := 1
+	for _, r := range previous {
+		attempt = max(attempt, r.Attempt+1)
+	}
+	return api.RunAttempt{
+		RunId:       RunAttemptID(migrationID, candidateID, attempt),
+		MigrationId: migrationID,
+		CandidateId: candidateID,
+		Attempt:     attempt,
+		Type:        runType,
+		Status:      api.RunAttemptStatusRunning,
+		Version:     version,
+		StartedAt:   now,
+	}
+}
+
 // ParseRunID splits a run ID back into its migrationID and candidateID components.
 func ParseRunID(runID string) (migrationID, candidateID string, err error) {
 	parts := strings.SplitN(runID, runIDSep, 2)
@@ -38,6 +76,71 @@ func ParseRunID(runID string) (migrationID, candidateID string, err error) {
 	return parts[0], parts[1], nil
 }
 
+// RunAttemptOwners returns the RunIDs of the candidates a run ID made by
+// RunAttemptID may belong to. A candidate ID may itself end in "__<n>", so
+// "mig__api__2" is the second attempt of "api" or the first of "api__2"; only
+// the candidates' recorded run attempts tell which. Returns nil when runID is
+// not a run ID.
+func RunAttemptOwners(runID string) []string {
+	migrationID, candidateID, err := ParseRunID(runID)
+	if err != nil {
+		return nil
+	}
+	owners := []string{runID}
+	if i := strings.LastIndex(candidateID, runIDSep); i > 0 {
+		if n, convErr := strconv.Atoi(candidateID[i+len(runIDSep):]); convErr == nil && n > 1 {
+			owners = append(owners, RunID(migrationID, candidateID[:i]))
+		}
+	}
+	return owners
+}
+
+// reservedIDSep ends the prefix of instance IDs that are not a candidate's run.
+// Migration IDs may not contain it, so such an ID never reads as the RunID of
+// a candidate of a migration called like the prefix.
+const reservedIDSep = ":"
+
+// validateMigrationID checks that id can be used as a migration ID.
+func validateMigrationID(id string) error {
+	if strings.Contains(id, reservedIDSep) {
+		return InvalidMigrationIDError{ID: id, Reason: fmt.Sprintf("it must not contain %q", reservedIDSep)}
+	}
+	return nil
+}
+
+const (
+	bulkStartPrefix = "bulk" + reservedIDSep
+	// legacyBulkStartPrefix began the IDs of bulk starts before reservedIDSep.
+	legacyBulkStartPrefix = "bulk" + runIDSep
+)
+
+// BulkStartID returns the instance ID of a bulk start run for the given migration.
+// The suffix distinguishes successive bulk starts of the same migration.
+func BulkStartID(migrationID, suffix string) string {
+	return bulkStartPrefix + migrationID + runIDSep + suffix
+}
+
+// IsBulkStartOf reports whether id was produced by BulkStartID for migrationID,
+// now or before bulk start IDs took reservedIDSep.
+func IsBulkStartOf(id, migrationID string) bool {
+	for _, prefix := range []string{bulkStartPrefix, legacyBulkStartPrefix} {
+		prefix += migrationID + runIDSep
+		if strings.HasPrefix(id, prefix) && len(id) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+const scheduledStartPrefix = "schedule" + reservedIDSep
+
+// ScheduledStartID returns the instance ID of the run that waits for a
+// scheduled start of the given migration. It doubles as the start's ID.
+// Starts scheduled before it took reservedIDSep keep their "schedule__" IDs.
+func ScheduledStartID(migrationID, suffix string) string {
+	return scheduledStartPrefix + migrationID + runIDSep + suffix
+}
+
 // StepEventName returns the deterministic signal name the run listens on
 // for a given step+candidate combination. Workers receive this in DispatchStepRequest.EventName.
 func StepEventName(stepName, candidateId string) string {
@@ -50,8 +153,40 @@ func RetryStepEventName(stepName, candidateId string) string {
 	return fmt.Sprintf("retry-step:%s:%s", stepName, candidateId)
 }
 
+// SkipStepEventName returns the deterministic signal name the run listens on
+// for an operator skipping a failed or pending step.
+func SkipStepEventName(stepName, candidateId string) string {
+	return fmt.Sprintf("skip-step:%s:%s", stepName, candidateId)
+}
+
 // UpdateInputsEventName returns the signal name used to push updated metadata
 // into a running workflow for the given candidate.
 func UpdateInputsEventName(candidateId string) string {
 	return fmt.Sprintf("update-inputs:%s", candidateId)
 }
+
+// PauseState is the payload of a PauseStateEventName signal.
+type PauseState struct {
+	Paused bool `json:"paused"`
+}
+
+// PauseStateEventName returns the signal name that pauses or resumes the run of
+// the given candidate, as its PauseState payload says. Pauses and resumes share
+// one signal so that a run applies them in the order they were sent.
+func PauseStateEventName(candidateId string) string {
+	return fmt.Sprintf("pause-state:%s", candidateId)
+}
+
+// PauseEventName returns the signal name that told a run to stop dispatching
+// further steps for the given candidate until it was resumed. Runs still listen
+// for it so that signals raised before PauseStateEventName replay as they were.
+func PauseEventName(candidateId string) string {
+	return fmt.Sprintf("pause:%s", candidateId)
+}
+
+// ResumeEventName returns the signal name that let a paused run continue
+// dispatching steps for the given candidate. Runs still listen for it so that
+// signals raised before PauseStateEventName replay as they were.
+func ResumeEventName(candidateId string) string {
+	return fmt.Sprintf("resume:%s", candidateId)
+}
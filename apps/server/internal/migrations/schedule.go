@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// ScheduledStartRunType is the engine run type that waits for a scheduled
+// start's time and then fires it.
+const ScheduledStartRunType = "ScheduledStartOrchestrator"
+
+// ScheduledStartInput is the input passed to a scheduled start run. The run
+// only carries the start's ID; what to start is read when it fires.
+type ScheduledStartInput struct {
+	ID      string    `json:"id"`
+	StartAt time.Time `json:"startAt"`
+}
+
+// validateScheduleRequest checks a scheduled start request against now.
+func validateScheduleRequest(req api.ScheduleStartRequest, now time.Time) error {
+	hasCandidate := req.CandidateId != nil && *req.CandidateId != ""
+	if hasCandidate == (req.Bulk != nil) {
+		return InvalidScheduleError{Reason: "exactly one of candidateId and bulk must be set"}
+	}
+	if req.Bulk != nil && req.Inputs != nil {
+		return InvalidScheduleError{Reason: "inputs of a bulk start go in bulk.inputs"}
+	}
+	if !req.StartAt.After(now) {
+		return InvalidScheduleError{Reason: "startAt must be in the future"}
+	}
+	return nil
+}
@@ -2,8 +2,12 @@ package migrations
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -23,17 +27,42 @@ type Service struct {
 	store      MigrationStore
 	dryRunner  DryRunner
 	eventStore EventStore
+	audit      AuditLog
+	progress   ProgressFeed
+	webhooks   WebhookStore
+	freezes    FreezeCalendar
+	schedules  ScheduleStore
+	limiter    DispatchLimiter
+	migrators  MigratorRegistry
 
 	// metrics
 	runsStarted      metric.Int64Counter
 	runsCancelled    metric.Int64Counter
 	candidatesSubmit metric.Int64Counter
 	dryRunsTotal     metric.Int64Counter
+	bulkStarts       metric.Int64Counter
 }
 
 // NewService creates a new Service. eventStore may be nil — metrics queries
-// return empty results when it is not configured.
-func NewService(engine ExecutionEngine, store MigrationStore, dryRunner DryRunner, eventStore EventStore) *Service {
+// return empty results when it is not configured. audit may be nil too, in
+// which case nothing is audited, and so may progress, webhooks, freezes and
+// schedules, which disable progress streaming, webhooks, freeze windows and
+// scheduled starts. limiter may be nil, in which case announced dispatch limits
+// are not enforced, and so may migrators, in which case every step is sent to
+// its migration's migratorUrl.
+func NewService(
+	engine ExecutionEngine,
+	store MigrationStore,
+	dryRunner DryRunner,
+	eventStore EventStore,
+	audit AuditLog,
+	progress ProgressFeed,
+	webhooks WebhookStore,
+	freezes FreezeCalendar,
+	schedules ScheduleStore,
+	limiter DispatchLimiter,
+	migrators MigratorRegistry,
+) *Service {
 	m := otel.Meter(instrName)
 
 	runsStarted, _ := m.Int64Counter("loom.runs.started",
@@ -44,23 +73,43 @@ func NewService(engine ExecutionEngine, store MigrationStore, dryRunner DryRunne
 		metric.WithDescription("Number of candidates submitted"))
 	dryRunsTotal, _ := m.Int64Counter("loom.dry_runs.total",
 		metric.WithDescription("Number of dry runs executed"))
+	bulkStarts, _ := m.Int64Counter("loom.bulk_starts.started",
+		metric.WithDescription("Number of bulk starts launched"))
 
 	return &Service{
 		engine:           engine,
 		store:            store,
 		dryRunner:        dryRunner,
 		eventStore:       eventStore,
+		audit:            audit,
+		progress:         progress,
+		webhooks:         webhooks,
+		freezes:          freezes,
+		schedules:        schedules,
+		limiter:          limiter,
+		migrators:        migrators,
 		runsStarted:      runsStarted,
 		runsCancelled:    runsCancelled,
 		candidatesSubmit: candidatesSubmit,
 		dryRunsTotal:     dryRunsTotal,
+		bulkStarts:       bulkStarts,
 	}
 }
 
 // GetCandidateSteps returns the step execution progress for a candidate's run.
 // Returns nil (no error) when the run does not exist.
 func (s *Service) GetCandidateSteps(ctx context.Context, migrationID, candidateID string) (*api.CandidateStepsResponse, error) {
+	latest, err := s.latestRun(ctx, migrationID, candidateID)
+	if err != nil {
+		return nil, err
+	}
 	runID := RunID(migrationID, candidateID)
+	var attempt *int
+	if latest != nil {
+		runID = latest.RunId
+		attempt = &latest.Attempt
+	}
+
 	ws, err := s.engine.GetStatus(ctx, runID)
 	if err != nil {
 		var notFound RunNotFoundError
@@ -79,26 +128,78 @@ func (s *Service) GetCandidateSteps(ctx context.Context, migrationID, candidateI
 	// the run output's status field. This correctly handles terminated and
 	// cancelled runs, where ws.Output is nil and the output field would be empty.
 	status := api.CandidateStepsResponseStatusRunning
-	if ws.RuntimeStatus != RuntimeStatusRunning {
+	switch {
+	case ws.RuntimeStatus != RuntimeStatusRunning:
 		status = api.CandidateStepsResponseStatusCompleted
+	case ws.Paused:
+		status = api.CandidateStepsResponseStatusPaused
 	}
 
-	return &api.CandidateStepsResponse{Status: status, Steps: steps}, nil
+	return &api.CandidateStepsResponse{Status: status, Steps: steps, RunId: &runID, Attempt: attempt}, nil
 }
 
 // HandleEvent raises a StepCompleted signal into the active run,
 // unblocking the signal wait for the matching step+candidate.
+// An event whose eventId the run has already received is recorded as ignored
+// and reported as a DuplicateEventError instead of being raised again.
 func (s *Service) HandleEvent(ctx context.Context, instanceID string, event api.StepStatusEvent) error {
+	dedupe := event.EventId != nil && s.eventStore != nil
+	if dedupe {
+		first, err := s.eventStore.ClaimCallback(ctx, instanceID, *event.EventId)
+		if err != nil {
+			return fmt.Errorf("claim event %q: %w", *event.EventId, err)
+		}
+		if !first {
+			s.recordIgnoredCallback(ctx, instanceID, event, IgnoredDuplicate)
+			return DuplicateEventError{RunID: instanceID, EventID: *event.EventId}
+		}
+	}
+
 	eventName := StepEventName(event.StepName, event.CandidateId)
 	if err := s.engine.RaiseEvent(ctx, instanceID, eventName, event); err != nil {
+		if dedupe {
+			// Let the migrator's resend through: this delivery never reached the run.
+			if relErr := s.eventStore.ReleaseCallback(ctx, instanceID, *event.EventId); relErr != nil {
+				err = errors.Join(err, relErr)
+			}
+		}
 		return fmt.Errorf("raise event %q: %w", eventName, err)
 	}
 	return nil
 }
 
+// recordIgnoredCallback records a discarded callback in the event store. Like
+// the workflow's own event recording it is best-effort: the callback has been
+// discarded either way.
+func (s *Service) recordIgnoredCallback(ctx context.Context, runID string, event api.StepStatusEvent, reason string) {
+	migrationID, _, err := ParseRunID(runID)
+	if err != nil {
+		migrationID = runID
+	}
+	_ = s.eventStore.RecordEvent(ctx, IgnoredCallbackEvent(migrationID, event, reason))
+}
+
 // Announce upserts a migration from a migrator announcement (pub/sub discovery).
 // The worker owns the ID (deterministic slug). Existing state and createdAt are preserved.
+// An announcement that changes the definition is recorded as a new version.
 func (s *Service) Announce(ctx context.Context, ann api.MigrationAnnouncement) (*api.Migration, error) {
+	if err := validateMigrationID(ann.Id); err != nil {
+		return nil, err
+	}
+	if err := validateSteps(ann.Steps); err != nil {
+		return nil, err
+	}
+	if err := validateCandidateSteps(ann.Candidates); err != nil {
+		return nil, err
+	}
+	var limits []api.DispatchLimit
+	if ann.DispatchLimits != nil {
+		limits = *ann.DispatchLimits
+	}
+	if err := validateDispatchLimits(limits); err != nil {
+		return nil, err
+	}
+
 	existing, err := s.store.Get(ctx, ann.Id)
 	if err != nil {
 		return nil, fmt.Errorf("get migration %q: %w", ann.Id, err)
@@ -112,8 +213,11 @@ func (s *Service) Announce(ctx context.Context, ann api.MigrationAnnouncement) (
 		existing.RequiredInputs = ann.RequiredInputs
 		existing.Steps = ann.Steps
 		existing.MigratorUrl = ann.MigratorUrl
-		if err := s.store.Save(ctx, *existing); err != nil {
-			return nil, fmt.Errorf("save migration: %w", err)
+		if err := s.saveVersioned(ctx, existing); err != nil {
+			return nil, err
+		}
+		if err := s.setDispatchLimits(ctx, ann.Id, limits); err != nil {
+			return nil, err
 		}
 		return existing, nil
 	}
@@ -129,12 +233,110 @@ func (s *Service) Announce(ctx context.Context, ann api.MigrationAnnouncement) (
 		CreatedAt:      time.Now().UTC(),
 		MigratorUrl:    ann.MigratorUrl,
 	}
-	if err := s.store.Save(ctx, m); err != nil {
-		return nil, fmt.Errorf("save migration: %w", err)
+	if err := s.saveVersioned(ctx, &m); err != nil {
+		return nil, err
+	}
+	if err := s.setDispatchLimits(ctx, ann.Id, limits); err != nil {
+		return nil, err
 	}
 	return &m, nil
 }
 
+// setDispatchLimits replaces the dispatch limits migrationID declares, so that
+// limits left out of an announcement are dropped.
+func (s *Service) setDispatchLimits(ctx context.Context, migrationID string, limits []api.DispatchLimit) error {
+	if s.limiter == nil {
+		return nil
+	}
+	if err := s.limiter.SetDispatchLimits(ctx, migrationID, limits); err != nil {
+		return fmt.Errorf("set dispatch limits: %w", err)
+	}
+	return nil
+}
+
+// saveVersioned saves m, first recording its definition as a new version when
+// it differs from m's current version or m has none yet. m.Version is updated
+// to the version the saved definition belongs to.
+func (s *Service) saveVersioned(ctx context.Context, m *api.Migration) error {
+	next := VersionOf(*m, 1, time.Now().UTC())
+	if m.Version != nil {
+		current, err := s.store.GetVersion(ctx, m.Id, *m.Version)
+		if err != nil {
+			return fmt.Errorf("get version %d of migration %q: %w", *m.Version, m.Id, err)
+		}
+		switch {
+		case current == nil:
+			// The version row was never written; fill the gap.
+			next.Version = *m.Version
+		case SameDefinition(*current, next):
+			if err := s.store.Save(ctx, *m); err != nil {
+				return fmt.Errorf("save migration: %w", err)
+			}
+			return nil
+		default:
+			next.Version = *m.Version + 1
+		}
+	}
+
+	m.Version = &next.Version
+	if err := s.store.Save(ctx, *m); err != nil {
+		return fmt.Errorf("save migration: %w", err)
+	}
+	if err := s.store.SaveVersion(ctx, next); err != nil {
+		return fmt.Errorf("save version %d of migration %q: %w", next.Version, m.Id, err)
+	}
+	return nil
+}
+
+// ListVersions returns every recorded version of a migration's definition, oldest first.
+func (s *Service) ListVersions(ctx context.Context, migrationID string) ([]api.MigrationVersion, error) {
+	m, err := s.store.Get(ctx, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("get migration %q: %w", migrationID, err)
+	}
+	if m == nil {
+		return nil, MigrationNotFoundError{ID: migrationID}
+	}
+	versions, err := s.store.ListVersions(ctx, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("list versions of migration %q: %w", migrationID, err)
+	}
+	if versions == nil {
+		versions = []api.MigrationVersion{}
+	}
+	return versions, nil
+}
+
+// DiffVersions compares two versions of a migration's definition.
+func (s *Service) DiffVersions(
+	ctx context.Context,
+	migrationID string,
+	from, to int,
+) (*api.MigrationVersionDiff, error) {
+	m, err := s.store.Get(ctx, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("get migration %q: %w", migrationID, err)
+	}
+	if m == nil {
+		return nil, MigrationNotFoundError{ID: migrationID}
+	}
+
+	var pair [2]api.MigrationVersion
+	for i, n := range []int{from, to} {
+		v, err := s.store.GetVersion(ctx, migrationID, n)
+		if err != nil {
+			return nil, fmt.Errorf("get version %d of migration %q: %w", n, migrationID, err)
+		}
+		if v == nil {
+			return nil, MigrationVersionNotFoundError{MigrationID: migrationID, Version: n}
+		}
+		pair[i] = *v
+	}
+
+	diff := DiffVersions(pair[0], pair[1])
+	return &diff, nil
+}
+
 // List returns all migrations.
 func (s *Service) List(ctx context.Context) ([]api.Migration, error) {
 	migrations, err := s.store.List(ctx)
@@ -162,6 +364,9 @@ func (s *Service) SubmitCandidates(ctx context.Context, migrationID string, req
 	if m == nil {
 		return MigrationNotFoundError{ID: migrationID}
 	}
+	if err := validateCandidateSteps(req.Candidates); err != nil {
+		return err
+	}
 	if err := s.store.SaveCandidates(ctx, migrationID, req.Candidates); err != nil {
 		return err
 	}
@@ -170,6 +375,29 @@ func (s *Service) SubmitCandidates(ctx context.Context, migrationID string, req
 	return nil
 }
 
+// validateSteps checks that a step list forms a valid graph and that its when
+// expressions are well-formed.
+func validateSteps(steps []api.StepDefinition) error {
+	if err := ValidateStepGraph(steps); err != nil {
+		return err
+	}
+	return ValidateStepConditions(steps)
+}
+
+// validateCandidateSteps validates the steps of every candidate that overrides
+// the migration-level steps.
+func validateCandidateSteps(candidates []api.Candidate) error {
+	for _, c := range candidates {
+		if c.Steps == nil {
+			continue
+		}
+		if err := validateSteps(*c.Steps); err != nil {
+			return fmt.Errorf("candidate %q: %w", c.Id, err)
+		}
+	}
+	return nil
+}
+
 // GetCandidates returns the candidate list for a migration with their current status.
 // Any candidate whose stored status is "running" but whose run no longer exists in
 // the engine (e.g. after a Temporal restart) is automatically reset to "not_started".
@@ -183,12 +411,16 @@ func (s *Service) GetCandidates(ctx context.Context, migrationID string) ([]api.
 		if c.Status != api.CandidateStatusRunning {
 			continue
 		}
-		runID := RunID(migrationID, c.Id)
+		runID, err := s.currentRunID(ctx, migrationID, c.Id)
+		if err != nil {
+			return nil, err
+		}
 		if _, err := s.engine.GetStatus(ctx, runID); err != nil {
 			var notFound RunNotFoundError
 			if errors.As(err, &notFound) {
 				// Stale run — reset to not_started so the Preview button becomes active again.
 				_ = s.store.SetCandidateStatus(ctx, migrationID, c.Id, api.CandidateStatusNotStarted)
+				_ = s.store.FinishRun(ctx, runID, api.RunAttemptStatusFailed, nil)
 				candidates[i].Status = api.CandidateStatusNotStarted
 			}
 		}
@@ -222,7 +454,10 @@ func (s *Service) RetryStep(ctx context.Context, migrationID, candidateID, stepN
 		return CandidateNotFoundError{MigrationID: migrationID, CandidateID: candidateID}
 	}
 
-	runID := RunID(migrationID, candidateID)
+	runID, err := s.currentRunID(ctx, migrationID, candidateID)
+	if err != nil {
+		return err
+	}
 	eventName := RetryStepEventName(stepName, candidateID)
 	if err := s.engine.RaiseEvent(ctx, runID, eventName, nil); err != nil {
 		return fmt.Errorf("raise retry event: %w", err)
@@ -255,13 +490,20 @@ func (s *Service) Cancel(ctx context.Context, migrationID, candidateID string) e
 		return CandidateNotFoundError{MigrationID: migrationID, CandidateID: candidateID}
 	}
 
-	runID := RunID(migrationID, candidateID)
+	runID, err := s.currentRunID(ctx, migrationID, candidateID)
+	if err != nil {
+		return err
+	}
 
 	if err := s.engine.CancelRun(ctx, runID); err != nil {
 		var notFound RunNotFoundError
 		if !errors.As(err, &notFound) {
 			return fmt.Errorf("cancel run: %w", err)
 		}
+		// No run left to record its own outcome.
+		if err := s.store.FinishRun(ctx, runID, api.RunAttemptStatusCancelled, nil); err != nil {
+			return fmt.Errorf("finish run: %w", err)
+		}
 	}
 
 	if err := s.store.SetCandidateStatus(ctx, migrationID, candidateID, api.CandidateStatusNotStarted); err != nil {
@@ -271,6 +513,331 @@ func (s *Service) Cancel(ctx context.Context, migrationID, candidateID string) e
 	return nil
 }
 
+// Pause raises a pause signal into the active run. The step currently in flight
+// is left to finish; the run then holds before dispatching the next step until
+// Resume is called. Returns CandidateNotRunningError if the candidate is not running.
+func (s *Service) Pause(ctx context.Context, migrationID, candidateID string) error {
+	return s.signalRunningCandidate(ctx, migrationID, candidateID, PauseStateEventName(candidateID), PauseState{Paused: true})
+}
+
+// Resume raises a resume signal into a paused run so it continues dispatching
+// from the step where it stopped. Resuming a run that is not paused is a no-op.
+func (s *Service) Resume(ctx context.Context, migrationID, candidateID string) error {
+	return s.signalRunningCandidate(ctx, migrationID, candidateID, PauseStateEventName(candidateID), PauseState{Paused: false})
+}
+
+// SkipStep raises a skip-step signal into the active run, recording the step as
+// skipped with the operator's reason and advancing to the next step. Only a step
+// that is waiting on a migrator callback (in_progress, pending or timed_out) or on
+// a retry (failed) can be skipped; any other state returns StepNotSkippableError.
+func (s *Service) SkipStep(ctx context.Context, migrationID, candidateID string, req api.SkipStepRequest) error {
+	if err := s.requireRunningCandidate(ctx, migrationID, candidateID); err != nil {
+		return err
+	}
+
+	runID, err := s.currentRunID(ctx, migrationID, candidateID)
+	if err != nil {
+		return err
+	}
+	ws, err := s.engine.GetStatus(ctx, runID)
+	if err != nil {
+		return fmt.Errorf("get run status: %w", err)
+	}
+	var status api.StepStateStatus
+	for _, st := range ws.Steps {
+		if st.StepName == req.StepName && st.Candidate.Id == candidateID {
+			status = st.Status
+			break
+		}
+	}
+	skippable := status == api.StepStateStatusInProgress ||
+		status == api.StepStateStatusPending ||
+		status == api.StepStateStatusTimedOut ||
+		status == api.StepStateStatusFailed
+	if !skippable {
+		return StepNotSkippableError{StepName: req.StepName, Status: string(status)}
+	}
+
+	eventName := SkipStepEventName(req.StepName, candidateID)
+	if err := s.engine.RaiseEvent(ctx, runID, eventName, req); err != nil {
+		return fmt.Errorf("raise skip event: %w", err)
+	}
+	return nil
+}
+
+// CompleteStep reports a step's outcome into the candidate's current run on
+// behalf of an operator, exactly as if the migrator had called back — e.g. to
+// approve a manual review step from the console.
+func (s *Service) CompleteStep(ctx context.Context, migrationID, candidateID string, req api.CompleteStepRequest) error {
+	if err := s.requireRunningCandidate(ctx, migrationID, candidateID); err != nil {
+		return err
+	}
+	runID, err := s.currentRunID(ctx, migrationID, candidateID)
+	if err != nil {
+		return err
+	}
+	return s.HandleEvent(ctx, runID, api.StepStatusEvent{
+		StepName:    req.StepName,
+		CandidateId: candidateID,
+		Status:      api.StepStatusEventStatus(req.Status),
+		Metadata:    req.Metadata,
+	})
+}
+
+// signalRunningCandidate raises a signal carrying payload into the candidate's run
+// after checking that the migration and candidate exist and the candidate is running.
+func (s *Service) signalRunningCandidate(
+	ctx context.Context,
+	migrationID, candidateID, eventName string,
+	payload any,
+) error {
+	if err := s.requireRunningCandidate(ctx, migrationID, candidateID); err != nil {
+		return err
+	}
+	runID, err := s.currentRunID(ctx, migrationID, candidateID)
+	if err != nil {
+		return err
+	}
+	if err := s.engine.RaiseEvent(ctx, runID, eventName, payload); err != nil {
+		return fmt.Errorf("raise %q: %w", eventName, err)
+	}
+	return nil
+}
+
+// requireRunningCandidate returns MigrationNotFoundError, CandidateNotFoundError
+// or CandidateNotRunningError unless the candidate exists and is running.
+func (s *Service) requireRunningCandidate(ctx context.Context, migrationID, candidateID string) error {
+	m, err := s.store.Get(ctx, migrationID)
+	if err != nil {
+		return fmt.Errorf("get migration %q: %w", migrationID, err)
+	}
+	if m == nil {
+		return MigrationNotFoundError{ID: migrationID}
+	}
+
+	for _, c := range m.Candidates {
+		if c.Id == candidateID {
+			if c.Status != api.CandidateStatusRunning {
+				return CandidateNotRunningError{ID: candidateID}
+			}
+			return nil
+		}
+	}
+	return CandidateNotFoundError{MigrationID: migrationID, CandidateID: candidateID}
+}
+
+// Rollback starts a rollback run for a candidate whose last run has finished,
+// been cancelled or failed. The rollback dispatches the compensation of every
+// step that run completed (see BuildRollbackManifest) and is recorded as the
+// candidate's next run attempt, so its progress, callbacks and operator
+// actions work exactly like a normal run. The candidate is running until the
+// rollback finishes, then returns to not_started.
+func (s *Service) Rollback(ctx context.Context, migrationID, candidateID string) error {
+	m, err := s.store.Get(ctx, migrationID)
+	if err != nil {
+		return fmt.Errorf("get migration %q: %w", migrationID, err)
+	}
+	if m == nil {
+		return MigrationNotFoundError{ID: migrationID}
+	}
+	var candidate api.Candidate
+	found := false
+	for _, c := range m.Candidates {
+		if c.Id == candidateID {
+			candidate = c
+			found = true
+			break
+		}
+	}
+	if !found {
+		return CandidateNotFoundError{MigrationID: migrationID, CandidateID: candidateID}
+	}
+
+	latest, err := s.latestRun(ctx, migrationID, candidateID)
+	if err != nil {
+		return err
+	}
+	runID := RunID(migrationID, candidateID)
+	if latest != nil {
+		runID = latest.RunId
+	}
+	ws, err := s.engine.GetStatus(ctx, runID)
+	if err != nil {
+		var notFound RunNotFoundError
+		if errors.As(err, &notFound) {
+			return RollbackNotAllowedError{CandidateID: candidateID, Reason: "it has never been run"}
+		}
+		return fmt.Errorf("get run status: %w", err)
+	}
+	if ws.RuntimeStatus == RuntimeStatusRunning {
+		return RollbackNotAllowedError{CandidateID: candidateID, Reason: "its run is still in progress; cancel it first"}
+	}
+
+	// Cancelled runs have no output, but their progress query still answers.
+	var progress struct {
+		Results []api.StepState `json:"results"`
+	}
+	if err := s.engine.QueryRun(ctx, runID, "progress", &progress); err != nil {
+		return fmt.Errorf("get run results: %w", err)
+	}
+
+	// Compensate the steps the run dispatched: those of the definition it was
+	// started from, not whatever the migration holds now.
+	def := *m
+	if latest != nil && latest.Version != nil {
+		v, err := s.store.GetVersion(ctx, migrationID, *latest.Version)
+		if err != nil {
+			return fmt.Errorf("get version %d of migration %q: %w", *latest.Version, migrationID, err)
+		}
+		if v == nil {
+			return MigrationVersionNotFoundError{MigrationID: migrationID, Version: *latest.Version}
+		}
+		def = AtVersion(*m, *v)
+	}
+
+	manifest := BuildRollbackManifest(def, candidate, progress.Results)
+	if len(manifest.Steps) == 0 {
+		return RollbackNotAllowedError{CandidateID: candidateID, Reason: "no completed step has a compensation"}
+	}
+
+	if _, err := s.startAttempt(ctx, RollbackRunType, api.RunAttemptTypeRollback, candidateID, manifest); err != nil {
+		return fmt.Errorf("start rollback: %w", err)
+	}
+	if err := s.store.SetCandidateStatus(ctx, migrationID, candidateID, api.CandidateStatusRunning); err != nil {
+		return fmt.Errorf("set candidate status: %w", err)
+	}
+	return nil
+}
+
+// Reset returns a completed candidate to not_started so that it can be started
+// again. The next start runs as a new attempt; earlier attempts are kept.
+func (s *Service) Reset(ctx context.Context, migrationID, candidateID string) error {
+	m, err := s.store.Get(ctx, migrationID)
+	if err != nil {
+		return fmt.Errorf("get migration %q: %w", migrationID, err)
+	}
+	if m == nil {
+		return MigrationNotFoundError{ID: migrationID}
+	}
+
+	var found bool
+	for _, c := range m.Candidates {
+		if c.Id == candidateID {
+			found = true
+			if c.Status != api.CandidateStatusCompleted {
+				return CandidateNotCompletedError{ID: candidateID, Status: string(c.Status)}
+			}
+			break
+		}
+	}
+	if !found {
+		return CandidateNotFoundError{MigrationID: migrationID, CandidateID: candidateID}
+	}
+
+	if err := s.store.SetCandidateStatus(ctx, migrationID, candidateID, api.CandidateStatusNotStarted); err != nil {
+		return fmt.Errorf("reset candidate: %w", err)
+	}
+	return nil
+}
+
+// ListRuns returns every run attempt of a candidate, newest first. Attempts
+// that are still running carry their live step results.
+func (s *Service) ListRuns(ctx context.Context, migrationID, candidateID string) ([]api.RunAttempt, error) {
+	m, err := s.store.Get(ctx, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("get migration %q: %w", migrationID, err)
+	}
+	if m == nil {
+		return nil, MigrationNotFoundError{ID: migrationID}
+	}
+	var found bool
+	for _, c := range m.Candidates {
+		if c.Id == candidateID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, CandidateNotFoundError{MigrationID: migrationID, CandidateID: candidateID}
+	}
+
+	runs, err := s.store.ListRuns(ctx, migrationID, candidateID)
+	if err != nil {
+		return nil, fmt.Errorf("list runs: %w", err)
+	}
+	if runs == nil {
+		runs = []api.RunAttempt{}
+	}
+	for i, r := range runs {
+		if r.Status != api.RunAttemptStatusRunning {
+			continue
+		}
+		ws, err := s.engine.GetStatus(ctx, r.RunId)
+		if err != nil {
+			var notFound RunNotFoundError
+			if errors.As(err, &notFound) {
+				continue
+			}
+			return nil, fmt.Errorf("get run status: %w", err)
+		}
+		if ws.Steps != nil {
+			runs[i].Steps = &ws.Steps
+		}
+	}
+	return runs, nil
+}
+
+// latestRun returns the candidate's most recent run attempt, or nil when none
+// has been recorded.
+func (s *Service) latestRun(ctx context.Context, migrationID, candidateID string) (*api.RunAttempt, error) {
+	runs, err := s.store.ListRuns(ctx, migrationID, candidateID)
+	if err != nil {
+		return nil, fmt.Errorf("list runs: %w", err)
+	}
+	if len(runs) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+	return &runs[0], nil
+}
+
+// currentRunID returns the run ID of the candidate's most recent attempt.
+// Candidates whose only run predates attempt records fall back to RunID.
+func (s *Service) currentRunID(ctx context.Context, migrationID, candidateID string) (string, error) {
+	latest, err := s.latestRun(ctx, migrationID, candidateID)
+	if err != nil {
+		return "", err
+	}
+	if latest == nil {
+		return RunID(migrationID, candidateID), nil
+	}
+	return latest.RunId, nil
+}
+
+// startAttempt records the candidate's next run attempt and starts it on the
+// engine under the attempt's own run ID. When the engine refuses the run the
+// attempt is recorded as failed.
+func (s *Service) startAttempt(
+	ctx context.Context,
+	runType string,
+	attemptType api.RunAttemptType,
+	candidateID string,
+	manifest api.MigrationManifest,
+) (api.RunAttempt, error) {
+	previous, err := s.store.ListRuns(ctx, manifest.MigrationId, candidateID)
+	if err != nil {
+		return api.RunAttempt{}, fmt.Errorf("list runs: %w", err)
+	}
+	attempt := NextRunAttempt(previous, manifest.MigrationId, candidateID, attemptType, manifest.Version, time.Now().UTC())
+	if err := s.store.CreateRun(ctx, attempt); err != nil {
+		return api.RunAttempt{}, fmt.Errorf("record run attempt: %w", err)
+	}
+	if _, err := s.engine.StartRun(ctx, runType, attempt.RunId, manifest); err != nil {
+		_ = s.store.FinishRun(ctx, attempt.RunId, api.RunAttemptStatusFailed, nil)
+		return api.RunAttempt{}, err
+	}
+	return attempt, nil
+}
+
 // DryRun simulates a full migration run for a single candidate, returning
 // per-step file diffs from the worker without creating any real PRs.
 func (s *Service) DryRun(ctx context.Context, migrationID string, candidate api.Candidate) (*api.DryRunResult, error) {
@@ -301,7 +868,7 @@ func (s *Service) DryRun(ctx context.Context, migrationID string, candidate api.
 		Candidate:   candidate,
 		Steps:       steps,
 	}
-	result, err := s.dryRunner.DryRun(ctx, m.MigratorUrl, req)
+	result, err := s.dryRunAcrossMigrators(ctx, m.MigratorUrl, req)
 	status := "ok"
 	if err != nil {
 		status = "error"
@@ -311,6 +878,41 @@ func (s *Service) DryRun(ctx context.Context, migrationID string, candidate api.
 	return result, err
 }
 
+// dryRunAcrossMigrators sends each migrator the steps it handles and merges
+// their results. Steps go to the URL their migratorApp registered, or to
+// migratorURL.
+func (s *Service) dryRunAcrossMigrators(
+	ctx context.Context,
+	migratorURL string,
+	req api.DryRunRequest,
+) (*api.DryRunResult, error) {
+	registered := map[string]string{}
+	if s.migrators != nil {
+		migrators, err := s.migrators.ListMigrators(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list migrators: %w", err)
+		}
+		for _, m := range migrators {
+			registered[m.MigratorApp] = m.Url
+		}
+	}
+
+	runs := SplitDryRun(req, migratorURL, registered)
+	if len(runs) == 1 {
+		return s.dryRunner.DryRun(ctx, runs[0].MigratorURL, runs[0].Request)
+	}
+	results := make([]api.DryRunResult, 0, len(runs))
+	for _, run := range runs {
+		result, err := s.dryRunner.DryRun(ctx, run.MigratorURL, run.Request)
+		if err != nil {
+			return nil, fmt.Errorf("dry run at %s: %w", run.MigratorURL, err)
+		}
+		results = append(results, *result)
+	}
+	merged := MergeDryRunResults(req.Steps, results)
+	return &merged, nil
+}
+
 // UpdateInputs validates that all keys are declared in requiredInputs, then
 // merges the values into the candidate's metadata.
 func (s *Service) UpdateInputs(ctx context.Context, migrationID, candidateID string, inputs map[string]string) error {
@@ -339,7 +941,10 @@ func (s *Service) UpdateInputs(ctx context.Context, migrationID, candidateID str
 	// Signal the running workflow so updated inputs take effect on the next dispatch.
 	for _, c := range m.Candidates {
 		if c.Id == candidateID && c.Status == api.CandidateStatusRunning {
-			runID := RunID(migrationID, candidateID)
+			runID, err := s.currentRunID(ctx, migrationID, candidateID)
+			if err != nil {
+				return err
+			}
 			if err := s.engine.RaiseEvent(ctx, runID, UpdateInputsEventName(candidateID), inputs); err != nil {
 				var notFound RunNotFoundError
 				if !errors.As(err, &notFound) {
@@ -362,7 +967,8 @@ func derefInputs(p *[]api.InputDefinition) []api.InputDefinition {
 }
 
 // Start atomically looks up the candidate, merges any operator-supplied inputs,
-// and starts a Run for the given migration+candidate pair.
+// and starts a Run for the given migration+candidate pair as its next attempt.
+// Returns the new attempt's run ID.
 func (s *Service) Start(ctx context.Context, migrationID, candidateID string, inputs map[string]string) (string, error) {
 	ctx, span := otel.Tracer(instrName).Start(ctx, "Service.Start",
 		trace.WithAttributes(
@@ -395,13 +1001,17 @@ func (s *Service) Start(ctx context.Context, migrationID, candidateID string, in
 		return "", CandidateNotFoundError{MigrationID: migrationID, CandidateID: candidateID}
 	}
 
-	runID := RunID(migrationID, candidateID)
-
 	// Guard: block if candidate is already running or completed AND the run
 	// is still running or successfully completed. If the run has failed,
 	// been cancelled, or terminated, allow re-execution even if Redis still
-	// shows the old status (handles stale state after crash/cancel).
+	// shows the old status (handles stale state after crash/cancel). A
+	// completed candidate is run again by resetting it first.
 	if candidate.Status == api.CandidateStatusRunning || candidate.Status == api.CandidateStatusCompleted {
+		runID, err := s.currentRunID(ctx, migrationID, candidateID)
+		if err != nil {
+			span.RecordError(err)
+			return "", err
+		}
 		ws, err := s.engine.GetStatus(ctx, runID)
 		if err == nil && (ws.RuntimeStatus == RuntimeStatusRunning || ws.RuntimeStatus == RuntimeStatusCompleted) {
 			return "", CandidateAlreadyRunError{ID: candidateID, Status: string(candidate.Status)}
@@ -410,40 +1020,249 @@ func (s *Service) Start(ctx context.Context, migrationID, candidateID string, in
 	}
 
 	// Merge operator-supplied inputs into candidate metadata.
-	if len(inputs) > 0 {
-		if candidate.Metadata == nil {
-			candidate.Metadata = &map[string]string{}
+	manifest := BuildManifest(*m, candidate, inputs)
+
+	attempt, err := s.startAttempt(ctx, "MigrationOrchestrator", api.RunAttemptTypeMigration, candidateID, manifest)
+	if err != nil {
+		span.RecordError(err)
+		return "", fmt.Errorf("start run: %w", err)
+	}
+
+	if err := s.store.SetCandidateStatus(ctx, migrationID, candidateID, api.CandidateStatusRunning); err != nil {
+		span.RecordError(err)
+		return "", fmt.Errorf("set candidate status: %w", err)
+	}
+
+	s.runsStarted.Add(ctx, 1,
+		metric.WithAttributes(attribute.String("migration_id", migrationID)))
+	return attempt.RunId, nil
+}
+
+// BulkStart queues every candidate matching the selector for a wave rollout.
+// Candidates that are already running or completed are skipped up front; the
+// rest are started by a durable bulk start run that keeps at most
+// req.MaxInFlight of them running at once.
+func (s *Service) BulkStart(ctx context.Context, migrationID string, req api.BulkStartRequest) (*api.BulkStartResponse, error) {
+	ctx, span := otel.Tracer(instrName).Start(ctx, "Service.BulkStart",
+		trace.WithAttributes(attribute.String("migration.id", migrationID)),
+	)
+	defer span.End()
+
+	m, err := s.store.Get(ctx, migrationID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("get migration %q: %w", migrationID, err)
+	}
+	if m == nil {
+		return nil, MigrationNotFoundError{ID: migrationID}
+	}
+
+	queued := []string{}
+	var skipped []string
+	for _, c := range SelectCandidates(m.Candidates, req.Selector) {
+		if c.Status == api.CandidateStatusRunning || c.Status == api.CandidateStatusCompleted {
+			skipped = append(skipped, c.Id)
+			continue
 		}
-		for k, v := range inputs {
-			(*candidate.Metadata)[k] = v
+		queued = append(queued, c.Id)
+	}
+	if len(queued) == 0 {
+		return nil, NoCandidatesSelectedError{MigrationID: migrationID}
+	}
+
+	maxInFlight := max(req.MaxInFlight, 1)
+	var inputs map[string]string
+	if req.Inputs != nil {
+		inputs = *req.Inputs
+	}
+
+	bulkID := BulkStartID(migrationID, strconv.FormatInt(time.Now().UnixNano(), 36))
+	input := BulkStartInput{
+		MigrationID:  migrationID,
+		CandidateIDs: queued,
+		MaxInFlight:  maxInFlight,
+		Inputs:       inputs,
+	}
+	if _, err := s.engine.StartRun(ctx, BulkStartRunType, bulkID, input); err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("start bulk run: %w", err)
+	}
+
+	s.bulkStarts.Add(ctx, 1,
+		metric.WithAttributes(attribute.String("migration_id", migrationID)))
+
+	resp := &api.BulkStartResponse{Id: bulkID, CandidateIds: queued}
+	if len(skipped) > 0 {
+		resp.Skipped = &skipped
+	}
+	return resp, nil
+}
+
+// GetBulkStart returns the progress of a bulk start.
+// Returns nil (no error) when the bulk start does not exist for this migration.
+func (s *Service) GetBulkStart(ctx context.Context, migrationID, bulkID string) (*api.BulkStartProgress, error) {
+	if !IsBulkStartOf(bulkID, migrationID) {
+		return nil, nil //nolint:nilnil
+	}
+	var progress api.BulkStartProgress
+	if err := s.engine.QueryRun(ctx, bulkID, "progress", &progress); err != nil {
+		var notFound RunNotFoundError
+		if errors.As(err, &notFound) {
+			return nil, nil //nolint:nilnil
 		}
+		return nil, fmt.Errorf("query bulk start: %w", err)
 	}
+	return &progress, nil
+}
 
-	manifestSteps := m.Steps
-	if candidate.Steps != nil && len(*candidate.Steps) > 0 {
-		manifestSteps = *candidate.Steps
+// ScheduleStart queues a start of one candidate, or of a bulk selection, for
+// req.StartAt. A durable run waits for the time and then calls
+// FireScheduledStart.
+func (s *Service) ScheduleStart(
+	ctx context.Context,
+	migrationID string,
+	req api.ScheduleStartRequest,
+) (*api.ScheduledStart, error) {
+	if s.schedules == nil {
+		return nil, SchedulesUnavailableError{}
+	}
+	now := time.Now().UTC()
+	if err := validateScheduleRequest(req, now); err != nil {
+		return nil, err
+	}
+	m, err := s.store.Get(ctx, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("get migration %q: %w", migrationID, err)
+	}
+	if m == nil {
+		return nil, MigrationNotFoundError{ID: migrationID}
+	}
+	if req.CandidateId != nil {
+		candidateID := *req.CandidateId
+		if !slices.ContainsFunc(m.Candidates, func(c api.Candidate) bool { return c.Id == candidateID }) {
+			return nil, CandidateNotFoundError{MigrationID: migrationID, CandidateID: candidateID}
+		}
 	}
 
-	manifest := api.MigrationManifest{
+	scheduled := api.ScheduledStart{
+		Id:          ScheduledStartID(migrationID, strconv.FormatInt(now.UnixNano(), 36)),
 		MigrationId: migrationID,
-		Candidates:  []api.Candidate{candidate},
-		Steps:       manifestSteps,
-		MigratorUrl: m.MigratorUrl,
+		CandidateId: req.CandidateId,
+		Inputs:      req.Inputs,
+		Bulk:        req.Bulk,
+		StartAt:     req.StartAt.UTC(),
+		Status:      api.ScheduledStartStatusScheduled,
+		CreatedAt:   now,
+	}
+	if err := s.schedules.CreateScheduledStart(ctx, scheduled); err != nil {
+		return nil, fmt.Errorf("create scheduled start: %w", err)
 	}
+	input := ScheduledStartInput{ID: scheduled.Id, StartAt: scheduled.StartAt}
+	if _, err := s.engine.StartRun(ctx, ScheduledStartRunType, scheduled.Id, input); err != nil {
+		_, _ = s.schedules.TransitionScheduledStart(ctx, scheduled.Id,
+			api.ScheduledStartStatusScheduled, api.ScheduledStartStatusFailed, "", err.Error())
+		return nil, fmt.Errorf("start scheduled start run: %w", err)
+	}
+	return &scheduled, nil
+}
 
-	if _, err := s.engine.StartRun(ctx, "MigrationOrchestrator", runID, manifest); err != nil {
-		span.RecordError(err)
-		return "", fmt.Errorf("start run: %w", err)
+// ListScheduledStarts returns a migration's scheduled starts, soonest first.
+func (s *Service) ListScheduledStarts(ctx context.Context, migrationID string) ([]api.ScheduledStart, error) {
+	if s.schedules == nil {
+		return nil, SchedulesUnavailableError{}
+	}
+	m, err := s.store.Get(ctx, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("get migration %q: %w", migrationID, err)
+	}
+	if m == nil {
+		return nil, MigrationNotFoundError{ID: migrationID}
 	}
+	starts, err := s.schedules.ListScheduledStarts(ctx, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("list scheduled starts: %w", err)
+	}
+	return starts, nil
+}
 
-	if err := s.store.SetCandidateStatus(ctx, migrationID, candidateID, api.CandidateStatusRunning); err != nil {
-		span.RecordError(err)
-		return "", fmt.Errorf("set candidate status: %w", err)
+// CancelScheduledStart cancels a scheduled start that has not fired yet.
+// Returns ScheduledStartNotPendingError once it has fired or been cancelled.
+func (s *Service) CancelScheduledStart(ctx context.Context, migrationID, id string) error {
+	if s.schedules == nil {
+		return SchedulesUnavailableError{}
+	}
+	scheduled, err := s.schedules.GetScheduledStart(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get scheduled start %q: %w", id, err)
 	}
+	if scheduled == nil || scheduled.MigrationId != migrationID {
+		return ScheduledStartNotFoundError{MigrationID: migrationID, ID: id}
+	}
+	cancelled, err := s.schedules.TransitionScheduledStart(ctx, id,
+		api.ScheduledStartStatusScheduled, api.ScheduledStartStatusCancelled, "", "")
+	if err != nil {
+		return fmt.Errorf("cancel scheduled start %q: %w", id, err)
+	}
+	if !cancelled {
+		current, err := s.schedules.GetScheduledStart(ctx, id)
+		if err != nil {
+			return fmt.Errorf("get scheduled start %q: %w", id, err)
+		}
+		return ScheduledStartNotPendingError{ID: id, Status: string(current.Status)}
+	}
+	// The start is cancelled now; stopping its run only saves the wait. A run
+	// that cannot be cancelled finds the start cancelled when it fires.
+	_ = s.engine.CancelRun(ctx, id)
+	return nil
+}
 
-	s.runsStarted.Add(ctx, 1,
-		metric.WithAttributes(attribute.String("migration_id", migrationID)))
-	return runID, nil
+// FireScheduledStart starts what a scheduled start names, through Start or
+// BulkStart so the same guards apply as when an operator starts it. A start
+// that is refused is recorded as failed; only store errors are returned.
+// Starts that were cancelled or have already fired are left alone.
+func (s *Service) FireScheduledStart(ctx context.Context, id string) error {
+	if s.schedules == nil {
+		return SchedulesUnavailableError{}
+	}
+	claimed, err := s.schedules.TransitionScheduledStart(ctx, id,
+		api.ScheduledStartStatusScheduled, api.ScheduledStartStatusStarting, "", "")
+	if err != nil {
+		return fmt.Errorf("claim scheduled start %q: %w", id, err)
+	}
+	scheduled, err := s.schedules.GetScheduledStart(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get scheduled start %q: %w", id, err)
+	}
+	// A start left in starting was claimed by an earlier attempt that did not
+	// get to record the outcome; try it again. Start's guard refuses a second
+	// run if that attempt got as far as starting one.
+	if scheduled == nil || (!claimed && scheduled.Status != api.ScheduledStartStatusStarting) {
+		return nil
+	}
+
+	var runID string
+	if scheduled.Bulk != nil {
+		var resp *api.BulkStartResponse
+		if resp, err = s.BulkStart(ctx, scheduled.MigrationId, *scheduled.Bulk); err == nil {
+			runID = resp.Id
+		}
+	} else if scheduled.CandidateId != nil {
+		var inputs map[string]string
+		if scheduled.Inputs != nil {
+			inputs = *scheduled.Inputs
+		}
+		runID, err = s.Start(ctx, scheduled.MigrationId, *scheduled.CandidateId, inputs)
+	}
+
+	to, errMsg := api.ScheduledStartStatusStarted, ""
+	if err != nil {
+		to, errMsg = api.ScheduledStartStatusFailed, err.Error()
+	}
+	if _, err := s.schedules.TransitionScheduledStart(ctx, id,
+		api.ScheduledStartStatusStarting, to, runID, errMsg); err != nil {
+		return fmt.Errorf("record scheduled start %q: %w", id, err)
+	}
+	return nil
 }
 
 // --- Metrics query methods (nil-safe) ---
@@ -479,3 +1298,293 @@ func (s *Service) GetRecentFailures(ctx context.Context, limit int) ([]StepEvent
 	}
 	return s.eventStore.GetRecentFailures(ctx, limit)
 }
+
+// GetStuckSteps returns steps that have been in_progress or pending for longer
+// than olderThan. Returns empty slice if no event store.
+func (s *Service) GetStuckSteps(ctx context.Context, olderThan time.Duration) ([]StuckStep, error) {
+	if s.eventStore == nil {
+		return []StuckStep{}, nil
+	}
+	return s.eventStore.GetStuckSteps(ctx, time.Now().Add(-olderThan))
+}
+
+// maxAuditPage caps how many audit entries one ListAudit call returns.
+const maxAuditPage = 200
+
+// RecordAudit appends an entry to the audit log. A no-op without an audit log.
+func (s *Service) RecordAudit(ctx context.Context, entry AuditEntry) error {
+	if s.audit == nil {
+		return nil
+	}
+	if err := s.audit.Append(ctx, entry); err != nil {
+		return fmt.Errorf("append audit entry: %w", err)
+	}
+	return nil
+}
+
+// ListAudit returns one page of audit entries matching filter, newest first.
+// Limit defaults to 50 and is capped at 200. Returns an empty page without an
+// audit log.
+func (s *Service) ListAudit(ctx context.Context, filter AuditFilter) (*AuditPage, error) {
+	page := &AuditPage{Entries: []AuditEntry{}}
+	if s.audit == nil {
+		return page, nil
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 50
+	}
+	filter.Limit = min(filter.Limit, maxAuditPage)
+
+	// Ask for one more than the page holds to learn whether another page follows.
+	limit := filter.Limit
+	filter.Limit++
+	entries, err := s.audit.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list audit entries: %w", err)
+	}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		next := entries[limit-1].ID
+		page.NextBefore = &next
+	}
+	page.Entries = append(page.Entries, entries...)
+	return page, nil
+}
+
+// SubscribeProgress streams the step transitions and candidate status changes
+// of a migration, or of one candidate when filter.CandidateID is set, starting
+// after afterID. Returns MigrationNotFoundError or CandidateNotFoundError for
+// unknown IDs and ProgressFeedUnavailableError without a progress feed.
+func (s *Service) SubscribeProgress(
+	ctx context.Context,
+	filter ProgressFilter,
+	afterID int64,
+) (<-chan ProgressEvent, error) {
+	if s.progress == nil {
+		return nil, ProgressFeedUnavailableError{}
+	}
+	m, err := s.store.Get(ctx, filter.MigrationID)
+	if err != nil {
+		return nil, fmt.Errorf("get migration %q: %w", filter.MigrationID, err)
+	}
+	if m == nil {
+		return nil, MigrationNotFoundError{ID: filter.MigrationID}
+	}
+	if filter.CandidateID != "" && !slices.ContainsFunc(m.Candidates, func(c api.Candidate) bool {
+		return c.Id == filter.CandidateID
+	}) {
+		return nil, CandidateNotFoundError{MigrationID: filter.MigrationID, CandidateID: filter.CandidateID}
+	}
+	events, err := s.progress.Subscribe(ctx, filter, afterID)
+	if err != nil {
+		return nil, fmt.Errorf("subscribe to progress: %w", err)
+	}
+	return events, nil
+}
+
+// maxWebhookDeliveries caps the delivery history returned for a webhook.
+const maxWebhookDeliveries = 200
+
+// CreateWebhook subscribes w.URL to w.Events of a migration. A signing secret
+// is generated when w.Secret is empty; the returned subscription is the only
+// place it is shown. Returns InvalidWebhookError for a bad URL, event or
+// template and MigrationNotFoundError for an unknown migration.
+func (s *Service) CreateWebhook(
+	ctx context.Context,
+	migrationID string,
+	w WebhookSubscription,
+) (*WebhookSubscription, error) {
+	if s.webhooks == nil {
+		return nil, WebhooksUnavailableError{}
+	}
+	if err := validateWebhook(w); err != nil {
+		return nil, err
+	}
+	m, err := s.store.Get(ctx, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("get migration %q: %w", migrationID, err)
+	}
+	if m == nil {
+		return nil, MigrationNotFoundError{ID: migrationID}
+	}
+
+	w.MigrationID = migrationID
+	if w.Secret == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return nil, fmt.Errorf("generate webhook secret: %w", err)
+		}
+		w.Secret = hex.EncodeToString(b)
+	}
+	if err := s.webhooks.CreateWebhook(ctx, &w); err != nil {
+		return nil, fmt.Errorf("create webhook: %w", err)
+	}
+	return &w, nil
+}
+
+// ListWebhooks returns the migration's webhook subscriptions, without their secrets.
+func (s *Service) ListWebhooks(ctx context.Context, migrationID string) ([]WebhookSubscription, error) {
+	if s.webhooks == nil {
+		return nil, WebhooksUnavailableError{}
+	}
+	m, err := s.store.Get(ctx, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("get migration %q: %w", migrationID, err)
+	}
+	if m == nil {
+		return nil, MigrationNotFoundError{ID: migrationID}
+	}
+	webhooks, err := s.webhooks.ListWebhooks(ctx, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("list webhooks: %w", err)
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook removes a webhook subscription and its delivery history.
+// Returns WebhookNotFoundError when the migration has no such webhook.
+func (s *Service) DeleteWebhook(ctx context.Context, migrationID string, id int64) error {
+	if s.webhooks == nil {
+		return WebhooksUnavailableError{}
+	}
+	deleted, err := s.webhooks.DeleteWebhook(ctx, migrationID, id)
+	if err != nil {
+		return fmt.Errorf("delete webhook %d: %w", id, err)
+	}
+	if !deleted {
+		return WebhookNotFoundError{MigrationID: migrationID, ID: id}
+	}
+	return nil
+}
+
+// ListWebhookDeliveries returns the latest deliveries to a webhook, newest
+// first. limit defaults to 50 and is capped at 200. Returns
+// WebhookNotFoundError when the migration has no such webhook.
+func (s *Service) ListWebhookDeliveries(
+	ctx context.Context,
+	migrationID string,
+	id int64,
+	limit int,
+) ([]WebhookDelivery, error) {
+	if s.webhooks == nil {
+		return nil, WebhooksUnavailableError{}
+	}
+	w, err := s.webhooks.GetWebhook(ctx, migrationID, id)
+	if err != nil {
+		return nil, fmt.Errorf("get webhook %d: %w", id, err)
+	}
+	if w == nil {
+		return nil, WebhookNotFoundError{MigrationID: migrationID, ID: id}
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	deliveries, err := s.webhooks.ListDeliveries(ctx, id, min(limit, maxWebhookDeliveries))
+	if err != nil {
+		return nil, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// CreateFreezeWindow adds a freeze window to the calendar. A window with a
+// migrationId only covers that migration, which must exist.
+func (s *Service) CreateFreezeWindow(ctx context.Context, w FreezeWindow) (*FreezeWindow, error) {
+	if s.freezes == nil {
+		return nil, FreezeCalendarUnavailableError{}
+	}
+	if err := validateFreezeWindow(w); err != nil {
+		return nil, err
+	}
+	if w.MigrationID != "" {
+		m, err := s.store.Get(ctx, w.MigrationID)
+		if err != nil {
+			return nil, fmt.Errorf("get migration %q: %w", w.MigrationID, err)
+		}
+		if m == nil {
+			return nil, MigrationNotFoundError{ID: w.MigrationID}
+		}
+	}
+	if err := s.freezes.CreateFreezeWindow(ctx, &w); err != nil {
+		return nil, fmt.Errorf("create freeze window: %w", err)
+	}
+	return &w, nil
+}
+
+// ListFreezeWindows returns the freeze windows matching filter, ordered by
+// start time.
+func (s *Service) ListFreezeWindows(ctx context.Context, filter FreezeWindowFilter) ([]FreezeWindow, error) {
+	if s.freezes == nil {
+		return nil, FreezeCalendarUnavailableError{}
+	}
+	windows, err := s.freezes.ListFreezeWindows(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list freeze windows: %w", err)
+	}
+	return windows, nil
+}
+
+// GetFreezeWindow returns a freeze window, or FreezeWindowNotFoundError.
+func (s *Service) GetFreezeWindow(ctx context.Context, id int64) (*FreezeWindow, error) {
+	if s.freezes == nil {
+		return nil, FreezeCalendarUnavailableError{}
+	}
+	w, err := s.freezes.GetFreezeWindow(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get freeze window %d: %w", id, err)
+	}
+	if w == nil {
+		return nil, FreezeWindowNotFoundError{ID: id}
+	}
+	return w, nil
+}
+
+// DeleteFreezeWindow removes a freeze window. Steps it holds back are
+// dispatched the next time their run checks the calendar.
+func (s *Service) DeleteFreezeWindow(ctx context.Context, id int64) error {
+	if s.freezes == nil {
+		return FreezeCalendarUnavailableError{}
+	}
+	deleted, err := s.freezes.DeleteFreezeWindow(ctx, id)
+	if err != nil {
+		return fmt.Errorf("delete freeze window %d: %w", id, err)
+	}
+	if !deleted {
+		return FreezeWindowNotFoundError{ID: id}
+	}
+	return nil
+}
+
+// RegisterMigrator records the base URL and health endpoint a migrator app
+// announces. Steps of the app are dispatched, and dry-run, against the URL
+// from then on, whichever migration they belong to.
+func (s *Service) RegisterMigrator(
+	ctx context.Context,
+	req api.MigratorRegistration,
+) (*api.RegisteredMigrator, error) {
+	if s.migrators == nil {
+		return nil, MigratorRegistryUnavailableError{}
+	}
+	m, err := newRegisteredMigrator(req, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.migrators.RegisterMigrator(ctx, m); err != nil {
+		return nil, fmt.Errorf("register migrator %q: %w", m.MigratorApp, err)
+	}
+	return &m, nil
+}
+
+// ListMigrators returns the registered migrator apps ordered by app-id.
+func (s *Service) ListMigrators(ctx context.Context) ([]api.RegisteredMigrator, error) {
+	if s.migrators == nil {
+		return nil, MigratorRegistryUnavailableError{}
+	}
+	migrators, err := s.migrators.ListMigrators(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list migrators: %w", err)
+	}
+	return migrators, nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,9 +18,14 @@ import (
 
 // Compile-time interface compliance checks.
 var (
-	_ migrations.MigrationStore  = (*memStore)(nil)
-	_ migrations.ExecutionEngine = (*stubEngine)(nil)
-	_ migrations.DryRunner       = (*stubDryRunner)(nil)
+	_ migrations.MigrationStore   = (*memStore)(nil)
+	_ migrations.ExecutionEngine  = (*stubEngine)(nil)
+	_ migrations.DryRunner        = (*stubDryRunner)(nil)
+	_ migrations.EventStore       = (*memEventStore)(nil)
+	_ migrations.AuditLog         = (*stubAuditLog)(nil)
+	_ migrations.ProgressFeed     = (*stubProgressFeed)(nil)
+	_ migrations.DispatchLimiter  = (*stubDispatchLimiter)(nil)
+	_ migrations.MigratorRegistry = (*stubMigratorRegistry)(nil)
 )
 
 // ─── stubEngine ───────────────────────────────────────────────────────────────
@@ -29,6 +35,7 @@ type stubEngine struct {
 	getStatusFn  func(ctx context.Context, id string) (*migrations.RunStatus, error)
 	raiseEventFn func(ctx context.Context, id, event string, payload any) error
 	cancelFn     func(ctx context.Context, id string) error
+	queryFn      func(ctx context.Context, id, queryType string, out any) error
 }
 
 func (e *stubEngine) StartRun(ctx context.Context, name, id string, input any) (string, error) {
@@ -59,6 +66,13 @@ func (e *stubEngine) CancelRun(ctx context.Context, id string) error {
 	return nil
 }
 
+func (e *stubEngine) QueryRun(ctx context.Context, id, queryType string, out any) error {
+	if e.queryFn != nil {
+		return e.queryFn(ctx, id, queryType, out)
+	}
+	return migrations.RunNotFoundError{InstanceID: id}
+}
+
 // ─── stubDryRunner ────────────────────────────────────────────────────────────
 
 type stubDryRunner struct {
@@ -66,18 +80,82 @@ type stubDryRunner struct {
 	err           error
 	lastReq       api.DryRunRequest
 	lastWorkerUrl string
+	// byURL, when set, answers each worker URL with its own result.
+	byURL map[string]*api.DryRunResult
+	reqs  map[string]api.DryRunRequest
 }
 
 func (d *stubDryRunner) DryRun(_ context.Context, workerUrl string, req api.DryRunRequest) (*api.DryRunResult, error) {
 	d.lastWorkerUrl = workerUrl
 	d.lastReq = req
+	if d.byURL != nil {
+		if d.reqs == nil {
+			d.reqs = map[string]api.DryRunRequest{}
+		}
+		d.reqs[workerUrl] = req
+		return d.byURL[workerUrl], d.err
+	}
 	return d.result, d.err
 }
 
+// ─── memEventStore ────────────────────────────────────────────────────────────
+
+// memEventStore records events and claimed callback IDs. The metrics queries
+// are not used by the service tests and panic via the nil embedded interface.
+type memEventStore struct {
+	migrations.EventStore
+	events  []migrations.StepEvent
+	claimed map[string]bool
+}
+
+func newMemEventStore() *memEventStore {
+	return &memEventStore{claimed: make(map[string]bool)}
+}
+
+func (s *memEventStore) RecordEvent(_ context.Context, event migrations.StepEvent) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *memEventStore) ClaimCallback(_ context.Context, runID, eventID string) (bool, error) {
+	key := runID + "/" + eventID
+	if s.claimed[key] {
+		return false, nil
+	}
+	s.claimed[key] = true
+	return true, nil
+}
+
+func (s *memEventStore) ReleaseCallback(_ context.Context, runID, eventID string) error {
+	delete(s.claimed, runID+"/"+eventID)
+	return nil
+}
+
+// ─── stubAuditLog ─────────────────────────────────────────────────────────────
+
+// stubAuditLog returns its entries (newest first) up to the requested limit and
+// remembers the last filter it was asked for.
+type stubAuditLog struct {
+	entries []migrations.AuditEntry
+	filter  migrations.AuditFilter
+}
+
+func (l *stubAuditLog) Append(_ context.Context, e migrations.AuditEntry) error {
+	l.entries = append([]migrations.AuditEntry{e}, l.entries...)
+	return nil
+}
+
+func (l *stubAuditLog) List(_ context.Context, f migrations.AuditFilter) ([]migrations.AuditEntry, error) {
+	l.filter = f
+	return l.entries[:min(f.Limit, len(l.entries))], nil
+}
+
 // ─── memStore ─────────────────────────────────────────────────────────────────
 
 type memStore struct {
-	data map[string]api.Migration
+	data     map[string]api.Migration
+	versions map[string][]api.MigrationVersion
+	runs     []api.RunAttempt
 
 	// per-method error stubs
 	errSave                    error
@@ -87,11 +165,13 @@ type memStore struct {
 	errSaveCandidates          error
 	errGetCandidates           error
 	errUpdateCandidateMetadata error
+	errSaveVersion             error
 }
 
 func newMemStore() *memStore {
 	return &memStore{
-		data: make(map[string]api.Migration),
+		data:     make(map[string]api.Migration),
+		versions: make(map[string][]api.MigrationVersion),
 	}
 }
 
@@ -196,10 +276,110 @@ func (s *memStore) UpdateCandidateMetadata(_ context.Context, migrationID, candi
 	return nil
 }
 
+func (s *memStore) SaveVersion(_ context.Context, v api.MigrationVersion) error {
+	if s.errSaveVersion != nil {
+		return s.errSaveVersion
+	}
+	s.versions[v.MigrationId] = append(s.versions[v.MigrationId], v)
+	return nil
+}
+
+func (s *memStore) ListVersions(_ context.Context, migrationID string) ([]api.MigrationVersion, error) {
+	return s.versions[migrationID], nil
+}
+
+func (s *memStore) GetVersion(_ context.Context, migrationID string, version int) (*api.MigrationVersion, error) {
+	for _, v := range s.versions[migrationID] {
+		if v.Version == version {
+			return &v, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *memStore) CreateRun(_ context.Context, r api.RunAttempt) error {
+	s.runs = append(s.runs, r)
+	return nil
+}
+
+func (s *memStore) FinishRun(_ context.Context, runID string, status api.RunAttemptStatus, steps []api.StepState) error {
+	for i := range s.runs {
+		if s.runs[i].RunId == runID {
+			s.runs[i].Status = status
+			if steps != nil {
+				s.runs[i].Steps = &steps
+			}
+		}
+	}
+	return nil
+}
+
+func (s *memStore) ListRuns(_ context.Context, migrationID, candidateID string) ([]api.RunAttempt, error) {
+	var out []api.RunAttempt
+	for i := len(s.runs) - 1; i >= 0; i-- {
+		if s.runs[i].MigrationId == migrationID && s.runs[i].CandidateId == candidateID {
+			out = append(out, s.runs[i])
+		}
+	}
+	return out, nil
+}
+
+// ─── stubDispatchLimiter ──────────────────────────────────────────────────────
+
+type stubDispatchLimiter struct {
+	limits []api.DispatchLimit
+	calls  int
+}
+
+func (l *stubDispatchLimiter) SetDispatchLimits(_ context.Context, _ string, limits []api.DispatchLimit) error {
+	l.limits = limits
+	l.calls++
+	return nil
+}
+
+func (l *stubDispatchLimiter) AcquireDispatchSlot(
+	_ context.Context,
+	_, _ string,
+) (migrations.DispatchSlotGrant, error) {
+	return migrations.DispatchSlotGrant{Acquired: true}, nil
+}
+
+func (l *stubDispatchLimiter) RenewDispatchSlot(_ context.Context, _, _ string) error {
+	return nil
+}
+
+func (l *stubDispatchLimiter) ReleaseDispatchSlot(_ context.Context, _, _ string) error {
+	return nil
+}
+
+// ─── stubMigratorRegistry ─────────────────────────────────────────────────────
+
+type stubMigratorRegistry struct {
+	migrators []api.RegisteredMigrator
+}
+
+func (r *stubMigratorRegistry) RegisterMigrator(_ context.Context, m api.RegisteredMigrator) error {
+	r.migrators = append(r.migrators, m)
+	return nil
+}
+
+func (r *stubMigratorRegistry) GetMigrator(_ context.Context, app string) (*api.RegisteredMigrator, error) {
+	for i := range r.migrators {
+		if r.migrators[i].MigratorApp == app {
+			return &r.migrators[i], nil
+		}
+	}
+	return nil, nil //nolint:nilnil
+}
+
+func (r *stubMigratorRegistry) ListMigrators(_ context.Context) ([]api.RegisteredMigrator, error) {
+	return r.migrators, nil
+}
+
 // ─── constructor helper ───────────────────────────────────────────────────────
 
 func newSvc(store *memStore, engine *stubEngine, dr *stubDryRunner) *migrations.Service {
-	return migrations.NewService(engine, store, dr, nil)
+	return migrations.NewService(engine, store, dr, nil, nil, nil, nil, nil, nil, nil, nil)
 }
 
 // ─── tests ────────────────────────────────────────────────────────────────────
@@ -245,6 +425,154 @@ func TestService_Announce(t *testing.T) {
 		assert.Len(t, m.Steps, 1, "steps must be updated")
 	})
 
+	t.Run("records a new version only when the definition changes", func(t *testing.T) {
+		store := newMemStore()
+		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
+		ctx := context.Background()
+		ann := api.MigrationAnnouncement{
+			Id:    "app-chart-migration",
+			Name:  "App Chart Migration",
+			Steps: []api.StepDefinition{{Name: "step-1", MigratorApp: "app"}},
+		}
+
+		m, err := svc.Announce(ctx, ann)
+		require.NoError(t, err)
+		require.NotNil(t, m.Version)
+		assert.Equal(t, 1, *m.Version)
+
+		m, err = svc.Announce(ctx, ann)
+		require.NoError(t, err)
+		assert.Equal(t, 1, *m.Version, "identical announcement must not create a version")
+
+		ann.Steps = append(ann.Steps, api.StepDefinition{Name: "step-2", MigratorApp: "app"})
+		m, err = svc.Announce(ctx, ann)
+		require.NoError(t, err)
+		assert.Equal(t, 2, *m.Version)
+
+		versions, err := svc.ListVersions(ctx, "app-chart-migration")
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+		assert.Len(t, versions[0].Steps, 1)
+		assert.Len(t, versions[1].Steps, 2)
+		assert.NotEqual(t, versions[0].StepsHash, versions[1].StepsHash)
+	})
+
+	t.Run("propagates store SaveVersion error", func(t *testing.T) {
+		store := newMemStore()
+		store.errSaveVersion = errors.New("write failed")
+		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
+
+		_, err := svc.Announce(context.Background(), api.MigrationAnnouncement{Id: "new"})
+		require.ErrorContains(t, err, "write failed")
+	})
+
+	t.Run("rejects a step graph with a cycle without saving", func(t *testing.T) {
+		store := newMemStore()
+		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
+		a, b := []string{"b"}, []string{"a"}
+
+		_, err := svc.Announce(context.Background(), api.MigrationAnnouncement{
+			Id: "cyclic",
+			Steps: []api.StepDefinition{
+				{Name: "a", MigratorApp: "app", DependsOn: &a},
+				{Name: "b", MigratorApp: "app", DependsOn: &b},
+			},
+		})
+
+		var graphErr migrations.InvalidStepGraphError
+		require.ErrorAs(t, err, &graphErr)
+		m, _ := store.Get(context.Background(), "cyclic")
+		assert.Nil(t, m)
+	})
+
+	t.Run("rejects a migration ID with a reserved character without saving", func(t *testing.T) {
+		store := newMemStore()
+		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
+
+		_, err := svc.Announce(context.Background(), api.MigrationAnnouncement{Id: "bulk:m1"})
+
+		var idErr migrations.InvalidMigrationIDError
+		require.ErrorAs(t, err, &idErr)
+		m, _ := store.Get(context.Background(), "bulk:m1")
+		assert.Nil(t, m)
+	})
+
+	t.Run("rejects a when expression that does not parse", func(t *testing.T) {
+		store := newMemStore()
+		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
+		when := `metadata.team = "payments"`
+
+		_, err := svc.Announce(context.Background(), api.MigrationAnnouncement{
+			Id:    "conditional",
+			Steps: []api.StepDefinition{{Name: "a", MigratorApp: "app", When: &when}},
+		})
+
+		var condErr migrations.InvalidStepConditionError
+		require.ErrorAs(t, err, &condErr)
+		assert.Equal(t, "a", condErr.StepName)
+	})
+
+	t.Run("stores declared dispatch limits", func(t *testing.T) {
+		limiter := &stubDispatchLimiter{}
+		svc := migrations.NewService(
+			&stubEngine{}, newMemStore(), &stubDryRunner{}, nil, nil, nil, nil, nil, nil, limiter, nil,
+		)
+		maxInFlight := 5
+		limits := []api.DispatchLimit{{MigratorApp: "app", MaxInFlight: &maxInFlight}}
+
+		_, err := svc.Announce(context.Background(), api.MigrationAnnouncement{
+			Id: "limited", DispatchLimits: &limits,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, limits, limiter.limits)
+
+		_, err = svc.Announce(context.Background(), api.MigrationAnnouncement{Id: "limited"})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, limiter.calls)
+		assert.Empty(t, limiter.limits, "limits left out of an announcement are dropped")
+	})
+
+	t.Run("leaves dispatch limits alone when the migration is not saved", func(t *testing.T) {
+		store := newMemStore()
+		store.errSave = errors.New("write failed")
+		limiter := &stubDispatchLimiter{}
+		svc := migrations.NewService(
+			&stubEngine{}, store, &stubDryRunner{}, nil, nil, nil, nil, nil, nil, limiter, nil,
+		)
+		maxInFlight := 5
+		limits := []api.DispatchLimit{{MigratorApp: "app", MaxInFlight: &maxInFlight}}
+
+		_, err := svc.Announce(context.Background(), api.MigrationAnnouncement{
+			Id: "limited", DispatchLimits: &limits,
+		})
+
+		require.Error(t, err)
+		assert.Zero(t, limiter.calls)
+	})
+
+	t.Run("rejects invalid dispatch limits without saving", func(t *testing.T) {
+		store := newMemStore()
+		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
+		zero := 0
+		cases := map[string][]api.DispatchLimit{
+			"no app":        {{PerMinute: &zero}},
+			"zero per min":  {{MigratorApp: "app", PerMinute: &zero}},
+			"zero inflight": {{MigratorApp: "app", MaxInFlight: &zero}},
+			"duplicate app": {{MigratorApp: "app"}, {MigratorApp: "app"}},
+		}
+		for name, limits := range cases {
+			_, err := svc.Announce(context.Background(), api.MigrationAnnouncement{
+				Id: "limited", DispatchLimits: &limits,
+			})
+			var limitErr migrations.InvalidDispatchLimitError
+			assert.ErrorAs(t, err, &limitErr, name)
+		}
+		m, _ := store.Get(context.Background(), "limited")
+		assert.Nil(t, m)
+	})
+
 	t.Run("propagates store Get error", func(t *testing.T) {
 		store := newMemStore()
 		store.errGet = errors.New("connection refused")
@@ -264,6 +592,53 @@ func TestService_Announce(t *testing.T) {
 	})
 }
 
+func TestService_DiffVersions(t *testing.T) {
+	announceTwice := func(t *testing.T) *migrations.Service {
+		t.Helper()
+		svc := newSvc(newMemStore(), &stubEngine{}, &stubDryRunner{})
+		ann := api.MigrationAnnouncement{
+			Id:    "app-chart-migration",
+			Steps: []api.StepDefinition{{Name: "step-1", MigratorApp: "app"}},
+		}
+		_, err := svc.Announce(context.Background(), ann)
+		require.NoError(t, err)
+		ann.Steps = []api.StepDefinition{{Name: "step-2", MigratorApp: "app"}}
+		_, err = svc.Announce(context.Background(), ann)
+		require.NoError(t, err)
+		return svc
+	}
+
+	t.Run("compares two versions", func(t *testing.T) {
+		svc := announceTwice(t)
+
+		diff, err := svc.DiffVersions(context.Background(), "app-chart-migration", 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 1, diff.From)
+		assert.Equal(t, 2, diff.To)
+		require.Len(t, diff.StepsAdded, 1)
+		assert.Equal(t, "step-2", diff.StepsAdded[0].Name)
+		require.Len(t, diff.StepsRemoved, 1)
+		assert.Equal(t, "step-1", diff.StepsRemoved[0].Name)
+	})
+
+	t.Run("unknown version returns MigrationVersionNotFoundError", func(t *testing.T) {
+		svc := announceTwice(t)
+
+		_, err := svc.DiffVersions(context.Background(), "app-chart-migration", 1, 3)
+		var notFound migrations.MigrationVersionNotFoundError
+		require.ErrorAs(t, err, &notFound)
+		assert.Equal(t, 3, notFound.Version)
+	})
+
+	t.Run("unknown migration returns MigrationNotFoundError", func(t *testing.T) {
+		svc := newSvc(newMemStore(), &stubEngine{}, &stubDryRunner{})
+
+		_, err := svc.DiffVersions(context.Background(), "missing", 1, 2)
+		var notFound migrations.MigrationNotFoundError
+		require.ErrorAs(t, err, &notFound)
+	})
+}
+
 func TestService_List(t *testing.T) {
 	t.Run("returns all migrations from store", func(t *testing.T) {
 		store := newMemStore()
@@ -468,6 +843,20 @@ func TestService_GetCandidateSteps(t *testing.T) {
 		assert.Empty(t, resp.Steps)
 	})
 
+	t.Run("returns paused status when running run is paused", func(t *testing.T) {
+		engine := &stubEngine{
+			getStatusFn: func(_ context.Context, _ string) (*migrations.RunStatus, error) {
+				return &migrations.RunStatus{RuntimeStatus: "RUNNING", Paused: true}, nil
+			},
+		}
+		svc := newSvc(newMemStore(), engine, &stubDryRunner{})
+
+		resp, err := svc.GetCandidateSteps(ctx, "m1", "repo-a")
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		assert.Equal(t, api.CandidateStepsResponseStatusPaused, resp.Status)
+	})
+
 	t.Run("returns nil when run not found", func(t *testing.T) {
 		engine := &stubEngine{
 			getStatusFn: func(_ context.Context, id string) (*migrations.RunStatus, error) {
@@ -566,7 +955,7 @@ func TestService_RetryStep(t *testing.T) {
 	})
 }
 
-func TestService_Cancel(t *testing.T) {
+func TestService_PauseResume(t *testing.T) {
 	ctx := context.Background()
 
 	setup := func(store *memStore) {
@@ -576,70 +965,48 @@ func TestService_Cancel(t *testing.T) {
 		})
 	}
 
-	t.Run("cancels run and resets candidate to not_started", func(t *testing.T) {
+	t.Run("pause raises pause signal on the candidate's run", func(t *testing.T) {
 		store := newMemStore()
 		setup(store)
-		var cancelledID string
+		var raisedID, raisedEvent string
+		var raisedPayload any
 		engine := &stubEngine{
-			cancelFn: func(_ context.Context, id string) error {
-				cancelledID = id
+			raiseEventFn: func(_ context.Context, id, event string, payload any) error {
+				raisedID, raisedEvent, raisedPayload = id, event, payload
 				return nil
 			},
 		}
 		svc := newSvc(store, engine, &stubDryRunner{})
 
-		err := svc.Cancel(ctx, "m1", "repo-a")
-		require.NoError(t, err)
-		assert.Equal(t, "m1__repo-a", cancelledID)
-		m, _ := store.Get(ctx, "m1")
-		assert.Equal(t, api.CandidateStatusNotStarted, m.Candidates[0].Status)
-	})
-
-	t.Run("run not found is tolerated; reset still proceeds", func(t *testing.T) {
-		store := newMemStore()
-		setup(store)
-		engine := &stubEngine{
-			cancelFn: func(_ context.Context, id string) error {
-				return migrations.RunNotFoundError{InstanceID: id}
-			},
-		}
-		svc := newSvc(store, engine, &stubDryRunner{})
-
-		err := svc.Cancel(ctx, "m1", "repo-a")
-		require.NoError(t, err)
-		m, _ := store.Get(ctx, "m1")
-		assert.Equal(t, api.CandidateStatusNotStarted, m.Candidates[0].Status)
+		require.NoError(t, svc.Pause(ctx, "m1", "repo-a"))
+		assert.Equal(t, migrations.RunID("m1", "repo-a"), raisedID)
+		assert.Equal(t, migrations.PauseStateEventName("repo-a"), raisedEvent)
+		assert.Equal(t, migrations.PauseState{Paused: true}, raisedPayload)
 	})
 
-	t.Run("other engine error is returned immediately", func(t *testing.T) {
+	t.Run("resume raises resume signal", func(t *testing.T) {
 		store := newMemStore()
 		setup(store)
+		var raisedEvent string
+		var raisedPayload any
 		engine := &stubEngine{
-			cancelFn: func(_ context.Context, _ string) error {
-				return errors.New("temporal unavailable")
+			raiseEventFn: func(_ context.Context, _, event string, payload any) error {
+				raisedEvent, raisedPayload = event, payload
+				return nil
 			},
 		}
 		svc := newSvc(store, engine, &stubDryRunner{})
 
-		err := svc.Cancel(ctx, "m1", "repo-a")
-		require.ErrorContains(t, err, "temporal unavailable")
-	})
-
-	t.Run("propagates SetCandidateStatus error", func(t *testing.T) {
-		store := newMemStore()
-		setup(store)
-		store.errSetCandidateStatus = errors.New("delete failed")
-		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
-
-		err := svc.Cancel(ctx, "m1", "repo-a")
-		require.ErrorContains(t, err, "delete failed")
+		require.NoError(t, svc.Resume(ctx, "m1", "repo-a"))
+		assert.Equal(t, migrations.PauseStateEventName("repo-a"), raisedEvent)
+		assert.Equal(t, migrations.PauseState{Paused: false}, raisedPayload)
 	})
 
 	t.Run("migration not found returns error", func(t *testing.T) {
 		svc := newSvc(newMemStore(), &stubEngine{}, &stubDryRunner{})
 
-		err := svc.Cancel(ctx, "unknown", "repo-a")
-		require.ErrorContains(t, err, "not found")
+		var notFound migrations.MigrationNotFoundError
+		require.ErrorAs(t, svc.Pause(ctx, "unknown", "repo-a"), &notFound)
 	})
 
 	t.Run("candidate not found returns error", func(t *testing.T) {
@@ -647,38 +1014,376 @@ func TestService_Cancel(t *testing.T) {
 		_ = store.Save(ctx, api.Migration{Id: "m1", Candidates: []api.Candidate{{Id: "other"}}})
 		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
 
-		err := svc.Cancel(ctx, "m1", "repo-a")
-		require.ErrorContains(t, err, "not found")
+		var notFound migrations.CandidateNotFoundError
+		require.ErrorAs(t, svc.Resume(ctx, "m1", "repo-a"), &notFound)
 	})
 
 	t.Run("candidate not running returns CandidateNotRunningError", func(t *testing.T) {
 		store := newMemStore()
 		_ = store.Save(ctx, api.Migration{
 			Id:         "m1",
-			Candidates: []api.Candidate{{Id: "repo-a", Status: api.CandidateStatusNotStarted}},
+			Candidates: []api.Candidate{{Id: "repo-a", Status: api.CandidateStatusCompleted}},
 		})
 		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
 
-		err := svc.Cancel(ctx, "m1", "repo-a")
 		var notRunning migrations.CandidateNotRunningError
-		require.ErrorAs(t, err, &notRunning)
-		assert.Equal(t, "repo-a", notRunning.ID)
+		require.ErrorAs(t, svc.Pause(ctx, "m1", "repo-a"), &notRunning)
+	})
+
+	t.Run("propagates engine error", func(t *testing.T) {
+		store := newMemStore()
+		setup(store)
+		engine := &stubEngine{
+			raiseEventFn: func(_ context.Context, _, _ string, _ any) error {
+				return errors.New("signal failed")
+			},
+		}
+		svc := newSvc(store, engine, &stubDryRunner{})
+
+		require.ErrorContains(t, svc.Pause(ctx, "m1", "repo-a"), "signal failed")
 	})
 }
 
-func TestService_DryRun(t *testing.T) {
+func TestService_SkipStep(t *testing.T) {
 	ctx := context.Background()
 
-	t.Run("passes migration steps to dry runner and returns result", func(t *testing.T) {
-		store := newMemStore()
+	setup := func(store *memStore) {
 		_ = store.Save(ctx, api.Migration{
-			Id:    "m1",
-			Steps: []api.StepDefinition{{Name: "step-1", MigratorApp: "app"}},
+			Id:         "m1",
+			Candidates: []api.Candidate{{Id: "repo-a", Status: api.CandidateStatusRunning}},
 		})
-		dr := &stubDryRunner{
-			result: &api.DryRunResult{Steps: []api.StepDryRunResult{{StepName: "step-1"}}},
+	}
+	withStep := func(status api.StepStateStatus) func(context.Context, string) (*migrations.RunStatus, error) {
+		return func(_ context.Context, _ string) (*migrations.RunStatus, error) {
+			return &migrations.RunStatus{
+				RuntimeStatus: "RUNNING",
+				Steps:         []api.StepState{{StepName: "step-1", Candidate: api.Candidate{Id: "repo-a"}, Status: status}},
+			}, nil
 		}
-		svc := newSvc(store, &stubEngine{}, dr)
+	}
+	req := api.SkipStepRequest{StepName: "step-1", Reason: "done by hand"}
+
+	for _, status := range []api.StepStateStatus{
+		api.StepStateStatusFailed, api.StepStateStatusPending, api.StepStateStatusInProgress,
+	} {
+		t.Run("raises skip signal for "+string(status)+" step", func(t *testing.T) {
+			store := newMemStore()
+			setup(store)
+			var raisedEvent string
+			var payload any
+			engine := &stubEngine{
+				getStatusFn: withStep(status),
+				raiseEventFn: func(_ context.Context, _, event string, p any) error {
+					raisedEvent, payload = event, p
+					return nil
+				},
+			}
+			svc := newSvc(store, engine, &stubDryRunner{})
+
+			require.NoError(t, svc.SkipStep(ctx, "m1", "repo-a", req))
+			assert.Equal(t, migrations.SkipStepEventName("step-1", "repo-a"), raisedEvent)
+			assert.Equal(t, req, payload)
+		})
+	}
+
+	t.Run("succeeded step is not skippable", func(t *testing.T) {
+		store := newMemStore()
+		setup(store)
+		svc := newSvc(store, &stubEngine{getStatusFn: withStep(api.StepStateStatusSucceeded)}, &stubDryRunner{})
+
+		var notSkippable migrations.StepNotSkippableError
+		require.ErrorAs(t, svc.SkipStep(ctx, "m1", "repo-a", req), &notSkippable)
+		assert.Equal(t, "succeeded", notSkippable.Status)
+	})
+
+	t.Run("step not yet reached is not skippable", func(t *testing.T) {
+		store := newMemStore()
+		setup(store)
+		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
+
+		var notSkippable migrations.StepNotSkippableError
+		require.ErrorAs(t, svc.SkipStep(ctx, "m1", "repo-a", req), &notSkippable)
+		assert.Empty(t, notSkippable.Status)
+	})
+
+	t.Run("candidate not running returns CandidateNotRunningError", func(t *testing.T) {
+		store := newMemStore()
+		_ = store.Save(ctx, api.Migration{
+			Id:         "m1",
+			Candidates: []api.Candidate{{Id: "repo-a", Status: api.CandidateStatusNotStarted}},
+		})
+		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
+
+		var notRunning migrations.CandidateNotRunningError
+		require.ErrorAs(t, svc.SkipStep(ctx, "m1", "repo-a", req), &notRunning)
+	})
+
+	t.Run("migration not found returns error", func(t *testing.T) {
+		svc := newSvc(newMemStore(), &stubEngine{}, &stubDryRunner{})
+
+		var notFound migrations.MigrationNotFoundError
+		require.ErrorAs(t, svc.SkipStep(ctx, "unknown", "repo-a", req), &notFound)
+	})
+}
+
+func TestService_Rollback(t *testing.T) {
+	ctx := context.Background()
+	revert := "revert-chart"
+
+	setup := func(store *memStore, status api.CandidateStatus) {
+		_ = store.Save(ctx, api.Migration{
+			Id:         "m1",
+			Candidates: []api.Candidate{{Id: "repo-a", Status: status}},
+			Steps: []api.StepDefinition{
+				{Name: "swap-chart", MigratorApp: "app", CompensationType: &revert},
+				{Name: "open-pr", MigratorApp: "app"},
+			},
+		})
+	}
+	finishedRun := func(status api.StepStateStatus) *stubEngine {
+		return &stubEngine{
+			getStatusFn: func(_ context.Context, _ string) (*migrations.RunStatus, error) {
+				return &migrations.RunStatus{RuntimeStatus: migrations.RuntimeStatusCompleted}, nil
+			},
+			queryFn: func(_ context.Context, _, _ string, out any) error {
+				raw, _ := json.Marshal(map[string]any{"results": []api.StepState{
+					{StepName: "swap-chart", Candidate: api.Candidate{Id: "repo-a"}, Status: status},
+				}})
+				return json.Unmarshal(raw, out)
+			},
+		}
+	}
+
+	t.Run("starts a rollback run as the candidate's next attempt", func(t *testing.T) {
+		store := newMemStore()
+		setup(store, api.CandidateStatusCompleted)
+		store.runs = []api.RunAttempt{{
+			RunId: migrations.RunID("m1", "repo-a"), MigrationId: "m1", CandidateId: "repo-a",
+			Attempt: 1, Type: api.RunAttemptTypeMigration, Status: api.RunAttemptStatusCompleted,
+		}}
+		engine := finishedRun(api.StepStateStatusSucceeded)
+		var startedType, startedID string
+		var manifest api.MigrationManifest
+		engine.startFn = func(_ context.Context, name, id string, input any) (string, error) {
+			startedType, startedID = name, id
+			manifest = input.(api.MigrationManifest)
+			return id, nil
+		}
+		svc := newSvc(store, engine, &stubDryRunner{})
+
+		require.NoError(t, svc.Rollback(ctx, "m1", "repo-a"))
+		assert.Equal(t, migrations.RollbackRunType, startedType)
+		assert.Equal(t, migrations.RunAttemptID("m1", "repo-a", 2), startedID)
+		require.Len(t, store.runs, 2)
+		assert.Equal(t, api.RunAttemptTypeRollback, store.runs[1].Type)
+		require.Len(t, manifest.Steps, 1)
+		assert.Equal(t, "rollback-swap-chart", manifest.Steps[0].Name)
+		assert.Equal(t, &revert, manifest.Steps[0].Type)
+		m, _ := store.Get(ctx, "m1")
+		assert.Equal(t, api.CandidateStatusRunning, m.Candidates[0].Status)
+	})
+
+	t.Run("compensates the steps of the version the run started from", func(t *testing.T) {
+		store := newMemStore()
+		setup(store, api.CandidateStatusCompleted)
+		undo := "undo-chart"
+		pinned := api.Migration{
+			Id: "m1",
+			Steps: []api.StepDefinition{
+				{Name: "swap-chart", MigratorApp: "old-app", CompensationType: &undo},
+			},
+		}
+		_ = store.SaveVersion(ctx, migrations.VersionOf(pinned, 1, time.Now()))
+		version := 1
+		store.runs = []api.RunAttempt{{
+			RunId: migrations.RunID("m1", "repo-a"), MigrationId: "m1", CandidateId: "repo-a",
+			Attempt: 1, Type: api.RunAttemptTypeMigration, Status: api.RunAttemptStatusCompleted,
+			Version: &version,
+		}}
+		engine := finishedRun(api.StepStateStatusSucceeded)
+		var manifest api.MigrationManifest
+		engine.startFn = func(_ context.Context, _, id string, input any) (string, error) {
+			manifest = input.(api.MigrationManifest)
+			return id, nil
+		}
+		svc := newSvc(store, engine, &stubDryRunner{})
+
+		require.NoError(t, svc.Rollback(ctx, "m1", "repo-a"))
+		require.Len(t, manifest.Steps, 1)
+		assert.Equal(t, "old-app", manifest.Steps[0].MigratorApp)
+		assert.Equal(t, &undo, manifest.Steps[0].Type)
+		assert.Equal(t, &version, manifest.Version)
+	})
+
+	t.Run("version the run started from missing returns MigrationVersionNotFoundError", func(t *testing.T) {
+		store := newMemStore()
+		setup(store, api.CandidateStatusCompleted)
+		version := 3
+		store.runs = []api.RunAttempt{{
+			RunId: migrations.RunID("m1", "repo-a"), MigrationId: "m1", CandidateId: "repo-a",
+			Attempt: 1, Type: api.RunAttemptTypeMigration, Status: api.RunAttemptStatusCompleted,
+			Version: &version,
+		}}
+		svc := newSvc(store, finishedRun(api.StepStateStatusSucceeded), &stubDryRunner{})
+
+		var notFound migrations.MigrationVersionNotFoundError
+		require.ErrorAs(t, svc.Rollback(ctx, "m1", "repo-a"), &notFound)
+		assert.Equal(t, 3, notFound.Version)
+	})
+
+	t.Run("run still in progress returns RollbackNotAllowedError", func(t *testing.T) {
+		store := newMemStore()
+		setup(store, api.CandidateStatusRunning)
+		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
+
+		var notAllowed migrations.RollbackNotAllowedError
+		require.ErrorAs(t, svc.Rollback(ctx, "m1", "repo-a"), &notAllowed)
+	})
+
+	t.Run("nothing to compensate returns RollbackNotAllowedError", func(t *testing.T) {
+		store := newMemStore()
+		setup(store, api.CandidateStatusNotStarted)
+		svc := newSvc(store, finishedRun(api.StepStateStatusFailed), &stubDryRunner{})
+
+		var notAllowed migrations.RollbackNotAllowedError
+		require.ErrorAs(t, svc.Rollback(ctx, "m1", "repo-a"), &notAllowed)
+	})
+
+	t.Run("candidate never run returns RollbackNotAllowedError", func(t *testing.T) {
+		store := newMemStore()
+		setup(store, api.CandidateStatusNotStarted)
+		engine := &stubEngine{getStatusFn: func(_ context.Context, id string) (*migrations.RunStatus, error) {
+			return nil, migrations.RunNotFoundError{InstanceID: id}
+		}}
+		svc := newSvc(store, engine, &stubDryRunner{})
+
+		var notAllowed migrations.RollbackNotAllowedError
+		require.ErrorAs(t, svc.Rollback(ctx, "m1", "repo-a"), &notAllowed)
+	})
+
+	t.Run("candidate not found returns CandidateNotFoundError", func(t *testing.T) {
+		store := newMemStore()
+		setup(store, api.CandidateStatusCompleted)
+		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
+
+		var notFound migrations.CandidateNotFoundError
+		require.ErrorAs(t, svc.Rollback(ctx, "m1", "repo-b"), &notFound)
+	})
+}
+
+func TestService_Cancel(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(store *memStore) {
+		_ = store.Save(ctx, api.Migration{
+			Id:         "m1",
+			Candidates: []api.Candidate{{Id: "repo-a", Status: api.CandidateStatusRunning}},
+		})
+	}
+
+	t.Run("cancels run and resets candidate to not_started", func(t *testing.T) {
+		store := newMemStore()
+		setup(store)
+		var cancelledID string
+		engine := &stubEngine{
+			cancelFn: func(_ context.Context, id string) error {
+				cancelledID = id
+				return nil
+			},
+		}
+		svc := newSvc(store, engine, &stubDryRunner{})
+
+		err := svc.Cancel(ctx, "m1", "repo-a")
+		require.NoError(t, err)
+		assert.Equal(t, "m1__repo-a", cancelledID)
+		m, _ := store.Get(ctx, "m1")
+		assert.Equal(t, api.CandidateStatusNotStarted, m.Candidates[0].Status)
+	})
+
+	t.Run("run not found is tolerated; reset still proceeds", func(t *testing.T) {
+		store := newMemStore()
+		setup(store)
+		engine := &stubEngine{
+			cancelFn: func(_ context.Context, id string) error {
+				return migrations.RunNotFoundError{InstanceID: id}
+			},
+		}
+		svc := newSvc(store, engine, &stubDryRunner{})
+
+		err := svc.Cancel(ctx, "m1", "repo-a")
+		require.NoError(t, err)
+		m, _ := store.Get(ctx, "m1")
+		assert.Equal(t, api.CandidateStatusNotStarted, m.Candidates[0].Status)
+	})
+
+	t.Run("other engine error is returned immediately", func(t *testing.T) {
+		store := newMemStore()
+		setup(store)
+		engine := &stubEngine{
+			cancelFn: func(_ context.Context, _ string) error {
+				return errors.New("temporal unavailable")
+			},
+		}
+		svc := newSvc(store, engine, &stubDryRunner{})
+
+		err := svc.Cancel(ctx, "m1", "repo-a")
+		require.ErrorContains(t, err, "temporal unavailable")
+	})
+
+	t.Run("propagates SetCandidateStatus error", func(t *testing.T) {
+		store := newMemStore()
+		setup(store)
+		store.errSetCandidateStatus = errors.New("delete failed")
+		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
+
+		err := svc.Cancel(ctx, "m1", "repo-a")
+		require.ErrorContains(t, err, "delete failed")
+	})
+
+	t.Run("migration not found returns error", func(t *testing.T) {
+		svc := newSvc(newMemStore(), &stubEngine{}, &stubDryRunner{})
+
+		err := svc.Cancel(ctx, "unknown", "repo-a")
+		require.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("candidate not found returns error", func(t *testing.T) {
+		store := newMemStore()
+		_ = store.Save(ctx, api.Migration{Id: "m1", Candidates: []api.Candidate{{Id: "other"}}})
+		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
+
+		err := svc.Cancel(ctx, "m1", "repo-a")
+		require.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("candidate not running returns CandidateNotRunningError", func(t *testing.T) {
+		store := newMemStore()
+		_ = store.Save(ctx, api.Migration{
+			Id:         "m1",
+			Candidates: []api.Candidate{{Id: "repo-a", Status: api.CandidateStatusNotStarted}},
+		})
+		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
+
+		err := svc.Cancel(ctx, "m1", "repo-a")
+		var notRunning migrations.CandidateNotRunningError
+		require.ErrorAs(t, err, &notRunning)
+		assert.Equal(t, "repo-a", notRunning.ID)
+	})
+}
+
+func TestService_DryRun(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("passes migration steps to dry runner and returns result", func(t *testing.T) {
+		store := newMemStore()
+		_ = store.Save(ctx, api.Migration{
+			Id:    "m1",
+			Steps: []api.StepDefinition{{Name: "step-1", MigratorApp: "app"}},
+		})
+		dr := &stubDryRunner{
+			result: &api.DryRunResult{Steps: []api.StepDryRunResult{{StepName: "step-1"}}},
+		}
+		svc := newSvc(store, &stubEngine{}, dr)
 
 		result, err := svc.DryRun(ctx, "m1", api.Candidate{Id: "repo-a"})
 		require.NoError(t, err)
@@ -715,6 +1420,42 @@ func TestService_DryRun(t *testing.T) {
 		_, err := svc.DryRun(ctx, "m1", api.Candidate{Id: "repo-a"})
 		require.ErrorContains(t, err, "store unavailable")
 	})
+
+	t.Run("sends each registered migrator its own steps", func(t *testing.T) {
+		store := newMemStore()
+		_ = store.Save(ctx, api.Migration{
+			Id:          "m1",
+			MigratorUrl: "http://charts:3001",
+			Steps: []api.StepDefinition{
+				{Name: "rotate-secret", MigratorApp: "secrets-migrator"},
+				{Name: "swap-chart", MigratorApp: "app-chart-migrator"},
+				{Name: "verify-secret", MigratorApp: "secrets-migrator"},
+			},
+		})
+		dr := &stubDryRunner{byURL: map[string]*api.DryRunResult{
+			"http://secrets:3002": {
+				Steps: []api.StepDryRunResult{{StepName: "rotate-secret"}, {StepName: "verify-secret"}},
+			},
+			"http://charts:3001": {Steps: []api.StepDryRunResult{{StepName: "swap-chart"}}},
+		}}
+		migrators := &stubMigratorRegistry{migrators: []api.RegisteredMigrator{
+			{MigratorApp: "secrets-migrator", Url: "http://secrets:3002"},
+		}}
+		svc := migrations.NewService(&stubEngine{}, store, dr, nil, nil, nil, nil, nil, nil, nil, migrators)
+
+		result, err := svc.DryRun(ctx, "m1", api.Candidate{Id: "repo-a"})
+		require.NoError(t, err)
+
+		require.Len(t, dr.reqs, 2)
+		assert.Len(t, dr.reqs["http://secrets:3002"].Steps, 2)
+		assert.Equal(t, "swap-chart", dr.reqs["http://charts:3001"].Steps[0].Name,
+			"unregistered apps go to the migration's migratorUrl")
+		names := make([]string, 0, len(result.Steps))
+		for _, s := range result.Steps {
+			names = append(names, s.StepName)
+		}
+		assert.Equal(t, []string{"rotate-secret", "swap-chart", "verify-secret"}, names)
+	})
 }
 
 func TestService_Start(t *testing.T) {
@@ -788,6 +1529,31 @@ func TestService_Start(t *testing.T) {
 		assert.Equal(t, "m1", capturedManifest.MigrationId)
 	})
 
+	t.Run("manifest records the definition version the run started from", func(t *testing.T) {
+		store := newMemStore()
+		version := 3
+		_ = store.Save(ctx, api.Migration{
+			Id:         "m1",
+			Steps:      []api.StepDefinition{{Name: "step-1"}},
+			Candidates: []api.Candidate{{Id: "repo-a"}},
+			Version:    &version,
+		})
+		var capturedManifest api.MigrationManifest
+		engine := &stubEngine{
+			startFn: func(_ context.Context, _, _ string, input any) (string, error) {
+				b, _ := json.Marshal(input)
+				_ = json.Unmarshal(b, &capturedManifest)
+				return "id", nil
+			},
+		}
+		svc := newSvc(store, engine, &stubDryRunner{})
+
+		_, err := svc.Start(ctx, "m1", "repo-a", nil)
+		require.NoError(t, err)
+		require.NotNil(t, capturedManifest.Version)
+		assert.Equal(t, 3, *capturedManifest.Version)
+	})
+
 	t.Run("blocks when candidate is already running and run still exists", func(t *testing.T) {
 		store := newMemStore()
 		saveMigration(store, []api.Candidate{{Id: "repo-a", Status: api.CandidateStatusRunning}})
@@ -1017,4 +1783,1023 @@ func TestService_HandleEvent(t *testing.T) {
 		err := svc.HandleEvent(ctx, "run-123", api.StepStatusEvent{})
 		require.ErrorContains(t, err, "signal failed")
 	})
+
+	t.Run("discards and records a resent event ID", func(t *testing.T) {
+		raised := 0
+		engine := &stubEngine{
+			raiseEventFn: func(_ context.Context, _, _ string, _ any) error {
+				raised++
+				return nil
+			},
+		}
+		events := newMemEventStore()
+		svc := migrations.NewService(engine, newMemStore(), &stubDryRunner{}, events, nil, nil, nil, nil, nil, nil, nil)
+		eventID := "evt-1"
+		event := api.StepStatusEvent{
+			StepName:    "step-1",
+			CandidateId: "repo-a",
+			Status:      api.StepStatusEventStatusMerged,
+			EventId:     &eventID,
+		}
+
+		require.NoError(t, svc.HandleEvent(ctx, "m1__repo-a", event))
+		err := svc.HandleEvent(ctx, "m1__repo-a", event)
+
+		var duplicate migrations.DuplicateEventError
+		require.ErrorAs(t, err, &duplicate)
+		assert.Equal(t, 1, raised)
+		require.Len(t, events.events, 1)
+		assert.Equal(t, migrations.EventCallbackIgnored, events.events[0].EventType)
+		assert.Equal(t, "m1", events.events[0].MigrationID)
+		assert.Equal(t, "merged", events.events[0].Status)
+		assert.Equal(t, map[string]string{"reason": "duplicate", "eventId": "evt-1"}, events.events[0].Metadata)
+	})
+
+	t.Run("accepts a resend of an event that could not be raised", func(t *testing.T) {
+		fail := true
+		engine := &stubEngine{
+			raiseEventFn: func(_ context.Context, _, _ string, _ any) error {
+				if fail {
+					fail = false
+					return errors.New("signal failed")
+				}
+				return nil
+			},
+		}
+		svc := migrations.NewService(
+			engine, newMemStore(), &stubDryRunner{}, newMemEventStore(), nil, nil, nil, nil, nil, nil, nil,
+		)
+		eventID := "evt-1"
+		event := api.StepStatusEvent{StepName: "step-1", CandidateId: "repo-a", EventId: &eventID}
+
+		require.Error(t, svc.HandleEvent(ctx, "m1__repo-a", event))
+		require.NoError(t, svc.HandleEvent(ctx, "m1__repo-a", event))
+	})
+}
+
+func TestService_CompleteStep(t *testing.T) {
+	ctx := context.Background()
+	req := api.CompleteStepRequest{StepName: "review", Status: "succeeded"}
+
+	t.Run("raises the step event into the candidate's current run", func(t *testing.T) {
+		store := newMemStore()
+		_ = store.Save(ctx, api.Migration{
+			Id:         "m1",
+			Candidates: []api.Candidate{{Id: "repo-a", Status: api.CandidateStatusRunning}},
+		})
+		var runID, raisedEvent string
+		var payload any
+		engine := &stubEngine{
+			raiseEventFn: func(_ context.Context, id, event string, p any) error {
+				runID, raisedEvent, payload = id, event, p
+				return nil
+			},
+		}
+		svc := newSvc(store, engine, &stubDryRunner{})
+
+		require.NoError(t, svc.CompleteStep(ctx, "m1", "repo-a", req))
+		assert.Equal(t, migrations.RunID("m1", "repo-a"), runID)
+		assert.Equal(t, migrations.StepEventName("review", "repo-a"), raisedEvent)
+		assert.Equal(t, api.StepStatusEvent{
+			StepName:    "review",
+			CandidateId: "repo-a",
+			Status:      api.StepStatusEventStatusSucceeded,
+		}, payload)
+	})
+
+	t.Run("returns CandidateNotRunningError when not running", func(t *testing.T) {
+		store := newMemStore()
+		_ = store.Save(ctx, api.Migration{
+			Id:         "m1",
+			Candidates: []api.Candidate{{Id: "repo-a", Status: api.CandidateStatusNotStarted}},
+		})
+		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
+
+		err := svc.CompleteStep(ctx, "m1", "repo-a", req)
+		var notRunning migrations.CandidateNotRunningError
+		require.ErrorAs(t, err, &notRunning)
+	})
+}
+
+func TestService_BulkStart(t *testing.T) {
+	ctx := context.Background()
+
+	saveMigration := func(store *memStore) {
+		prod := map[string]string{"env": "prod"}
+		dev := map[string]string{"env": "dev"}
+		_ = store.Save(ctx, api.Migration{
+			Id:    "m1",
+			Steps: []api.StepDefinition{{Name: "step-1"}},
+			Candidates: []api.Candidate{
+				{Id: "repo-a", Kind: "application", Metadata: &prod},
+				{Id: "repo-b", Kind: "application", Metadata: &dev},
+				{Id: "repo-c", Kind: "application", Metadata: &prod, Status: api.CandidateStatusCompleted},
+				{Id: "topic-a", Kind: "kafka-topic", Metadata: &prod},
+			},
+		})
+	}
+
+	t.Run("starts a bulk run for matching candidates and skips completed ones", func(t *testing.T) {
+		store := newMemStore()
+		saveMigration(store)
+		var startedType, startedID string
+		var input migrations.BulkStartInput
+		engine := &stubEngine{
+			startFn: func(_ context.Context, name, id string, in any) (string, error) {
+				startedType, startedID = name, id
+				input = in.(migrations.BulkStartInput)
+				return id, nil
+			},
+		}
+		svc := newSvc(store, engine, &stubDryRunner{})
+
+		kind := "application"
+		md := map[string]string{"env": "prod"}
+		resp, err := svc.BulkStart(ctx, "m1", api.BulkStartRequest{
+			Selector:    api.CandidateSelector{Kind: &kind, Metadata: &md},
+			MaxInFlight: 5,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, migrations.BulkStartRunType, startedType)
+		assert.Equal(t, resp.Id, startedID)
+		assert.True(t, migrations.IsBulkStartOf(resp.Id, "m1"))
+		assert.Equal(t, []string{"repo-a"}, resp.CandidateIds)
+		require.NotNil(t, resp.Skipped)
+		assert.Equal(t, []string{"repo-c"}, *resp.Skipped)
+		assert.Equal(t, []string{"repo-a"}, input.CandidateIDs)
+		assert.Equal(t, 5, input.MaxInFlight)
+	})
+
+	t.Run("selects by explicit candidate IDs", func(t *testing.T) {
+		store := newMemStore()
+		saveMigration(store)
+		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
+
+		ids := []string{"repo-b", "topic-a"}
+		resp, err := svc.BulkStart(ctx, "m1", api.BulkStartRequest{
+			Selector:    api.CandidateSelector{Ids: &ids},
+			MaxInFlight: 1,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"repo-b", "topic-a"}, resp.CandidateIds)
+		assert.Nil(t, resp.Skipped)
+	})
+
+	t.Run("returns NoCandidatesSelectedError when nothing is startable", func(t *testing.T) {
+		store := newMemStore()
+		saveMigration(store)
+		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
+
+		ids := []string{"repo-c"}
+		_, err := svc.BulkStart(ctx, "m1", api.BulkStartRequest{
+			Selector:    api.CandidateSelector{Ids: &ids},
+			MaxInFlight: 1,
+		})
+		var noneSelected migrations.NoCandidatesSelectedError
+		require.ErrorAs(t, err, &noneSelected)
+	})
+
+	t.Run("migration not found returns error", func(t *testing.T) {
+		svc := newSvc(newMemStore(), &stubEngine{}, &stubDryRunner{})
+
+		_, err := svc.BulkStart(ctx, "unknown", api.BulkStartRequest{MaxInFlight: 1})
+		var notFound migrations.MigrationNotFoundError
+		require.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("propagates engine error", func(t *testing.T) {
+		store := newMemStore()
+		saveMigration(store)
+		engine := &stubEngine{
+			startFn: func(_ context.Context, _, _ string, _ any) (string, error) {
+				return "", errors.New("temporal unavailable")
+			},
+		}
+		svc := newSvc(store, engine, &stubDryRunner{})
+
+		_, err := svc.BulkStart(ctx, "m1", api.BulkStartRequest{MaxInFlight: 1})
+		require.ErrorContains(t, err, "temporal unavailable")
+	})
+}
+
+func TestService_GetBulkStart(t *testing.T) {
+	ctx := context.Background()
+	bulkID := migrations.BulkStartID("m1", "abc")
+
+	t.Run("returns progress from the bulk run query", func(t *testing.T) {
+		var queried string
+		engine := &stubEngine{
+			queryFn: func(_ context.Context, id, queryType string, out any) error {
+				queried = id
+				assert.Equal(t, "progress", queryType)
+				*out.(*api.BulkStartProgress) = api.BulkStartProgress{Id: id, Total: 3}
+				return nil
+			},
+		}
+		svc := newSvc(newMemStore(), engine, &stubDryRunner{})
+
+		progress, err := svc.GetBulkStart(ctx, "m1", bulkID)
+		require.NoError(t, err)
+		require.NotNil(t, progress)
+		assert.Equal(t, bulkID, queried)
+		assert.Equal(t, 3, progress.Total)
+	})
+
+	t.Run("returns nil when the bulk start belongs to another migration", func(t *testing.T) {
+		svc := newSvc(newMemStore(), &stubEngine{}, &stubDryRunner{})
+
+		progress, err := svc.GetBulkStart(ctx, "m2", bulkID)
+		require.NoError(t, err)
+		assert.Nil(t, progress)
+	})
+
+	t.Run("finds bulk starts begun under the old ID prefix", func(t *testing.T) {
+		engine := &stubEngine{
+			queryFn: func(_ context.Context, id, _ string, out any) error {
+				*out.(*api.BulkStartProgress) = api.BulkStartProgress{Id: id}
+				return nil
+			},
+		}
+		svc := newSvc(newMemStore(), engine, &stubDryRunner{})
+
+		progress, err := svc.GetBulkStart(ctx, "m1", "bulk__m1__abc")
+		require.NoError(t, err)
+		require.NotNil(t, progress)
+		assert.Equal(t, "bulk__m1__abc", progress.Id)
+	})
+
+	t.Run("returns nil when the run does not exist", func(t *testing.T) {
+		svc := newSvc(newMemStore(), &stubEngine{}, &stubDryRunner{})
+
+		progress, err := svc.GetBulkStart(ctx, "m1", bulkID)
+		require.NoError(t, err)
+		assert.Nil(t, progress)
+	})
+}
+
+func TestService_RunAttempts(t *testing.T) {
+	ctx := context.Background()
+
+	// setup saves m1 with repo-a in the given status and one finished attempt.
+	setup := func(status api.CandidateStatus) *memStore {
+		store := newMemStore()
+		_ = store.Save(ctx, api.Migration{
+			Id:         "m1",
+			Steps:      []api.StepDefinition{{Name: "step-1"}},
+			Candidates: []api.Candidate{{Id: "repo-a", Status: status}},
+		})
+		steps := []api.StepState{{StepName: "step-1", Status: api.StepStateStatusSucceeded}}
+		store.runs = []api.RunAttempt{{
+			RunId: migrations.RunID("m1", "repo-a"), MigrationId: "m1", CandidateId: "repo-a",
+			Attempt: 1, Type: api.RunAttemptTypeMigration, Status: api.RunAttemptStatusCompleted, Steps: &steps,
+		}}
+		return store
+	}
+
+	t.Run("start after a finished attempt runs under a new run ID", func(t *testing.T) {
+		store := setup(api.CandidateStatusNotStarted)
+		var startedID string
+		engine := &stubEngine{
+			startFn: func(_ context.Context, _, id string, _ any) (string, error) {
+				startedID = id
+				return id, nil
+			},
+		}
+		svc := newSvc(store, engine, &stubDryRunner{})
+
+		runID, err := svc.Start(ctx, "m1", "repo-a", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "m1__repo-a__2", runID)
+		assert.Equal(t, runID, startedID)
+		require.Len(t, store.runs, 2)
+		assert.Equal(t, 2, store.runs[1].Attempt)
+		assert.Equal(t, api.RunAttemptStatusRunning, store.runs[1].Status)
+	})
+
+	t.Run("engine refusing the run records the attempt as failed", func(t *testing.T) {
+		store := setup(api.CandidateStatusNotStarted)
+		engine := &stubEngine{
+			startFn: func(_ context.Context, _, _ string, _ any) (string, error) {
+				return "", errors.New("temporal unavailable")
+			},
+		}
+		svc := newSvc(store, engine, &stubDryRunner{})
+
+		_, err := svc.Start(ctx, "m1", "repo-a", nil)
+		require.ErrorContains(t, err, "temporal unavailable")
+		require.Len(t, store.runs, 2)
+		assert.Equal(t, api.RunAttemptStatusFailed, store.runs[1].Status)
+	})
+
+	t.Run("reset returns a completed candidate to not_started", func(t *testing.T) {
+		store := setup(api.CandidateStatusCompleted)
+		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
+
+		require.NoError(t, svc.Reset(ctx, "m1", "repo-a"))
+		m, _ := store.Get(ctx, "m1")
+		assert.Equal(t, api.CandidateStatusNotStarted, m.Candidates[0].Status)
+		assert.Len(t, store.runs, 1, "earlier attempts must be kept")
+	})
+
+	t.Run("reset of a running candidate returns CandidateNotCompletedError", func(t *testing.T) {
+		store := setup(api.CandidateStatusRunning)
+		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
+
+		var notCompleted migrations.CandidateNotCompletedError
+		require.ErrorAs(t, svc.Reset(ctx, "m1", "repo-a"), &notCompleted)
+	})
+
+	t.Run("reset of an unknown candidate returns CandidateNotFoundError", func(t *testing.T) {
+		store := setup(api.CandidateStatusCompleted)
+		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
+
+		var notFound migrations.CandidateNotFoundError
+		require.ErrorAs(t, svc.Reset(ctx, "m1", "unknown"), &notFound)
+	})
+
+	t.Run("lists attempts newest first with live steps for the running one", func(t *testing.T) {
+		store := setup(api.CandidateStatusRunning)
+		store.runs = append(store.runs, api.RunAttempt{
+			RunId: migrations.RunAttemptID("m1", "repo-a", 2), MigrationId: "m1", CandidateId: "repo-a",
+			Attempt: 2, Type: api.RunAttemptTypeMigration, Status: api.RunAttemptStatusRunning,
+		})
+		engine := &stubEngine{
+			getStatusFn: func(_ context.Context, id string) (*migrations.RunStatus, error) {
+				assert.Equal(t, "m1__repo-a__2", id)
+				return &migrations.RunStatus{
+					RuntimeStatus: migrations.RuntimeStatusRunning,
+					Steps:         []api.StepState{{StepName: "step-1", Status: api.StepStateStatusInProgress}},
+				}, nil
+			},
+		}
+		svc := newSvc(store, engine, &stubDryRunner{})
+
+		runs, err := svc.ListRuns(ctx, "m1", "repo-a")
+		require.NoError(t, err)
+		require.Len(t, runs, 2)
+		assert.Equal(t, 2, runs[0].Attempt)
+		require.NotNil(t, runs[0].Steps)
+		assert.Equal(t, api.StepStateStatusInProgress, (*runs[0].Steps)[0].Status)
+		assert.Equal(t, api.RunAttemptStatusCompleted, runs[1].Status)
+	})
+
+	t.Run("steps come from the latest attempt", func(t *testing.T) {
+		store := setup(api.CandidateStatusRunning)
+		store.runs = append(store.runs, api.RunAttempt{
+			RunId: migrations.RunAttemptID("m1", "repo-a", 2), MigrationId: "m1", CandidateId: "repo-a",
+			Attempt: 2, Type: api.RunAttemptTypeMigration, Status: api.RunAttemptStatusRunning,
+		})
+		var queried string
+		engine := &stubEngine{
+			getStatusFn: func(_ context.Context, id string) (*migrations.RunStatus, error) {
+				queried = id
+				return &migrations.RunStatus{RuntimeStatus: migrations.RuntimeStatusRunning}, nil
+			},
+		}
+		svc := newSvc(store, engine, &stubDryRunner{})
+
+		resp, err := svc.GetCandidateSteps(ctx, "m1", "repo-a")
+		require.NoError(t, err)
+		assert.Equal(t, "m1__repo-a__2", queried)
+		require.NotNil(t, resp.RunId)
+		assert.Equal(t, "m1__repo-a__2", *resp.RunId)
+		require.NotNil(t, resp.Attempt)
+		assert.Equal(t, 2, *resp.Attempt)
+	})
+}
+
+func TestService_ListAudit(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("empty page without an audit log", func(t *testing.T) {
+		svc := newSvc(newMemStore(), &stubEngine{}, &stubDryRunner{})
+
+		page, err := svc.ListAudit(ctx, migrations.AuditFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, page.Entries)
+		assert.Nil(t, page.NextBefore)
+	})
+
+	t.Run("sets nextBefore when more entries follow", func(t *testing.T) {
+		audit := &stubAuditLog{}
+		for id := int64(1); id <= 3; id++ {
+			require.NoError(t, audit.Append(ctx, migrations.AuditEntry{ID: id, Action: "start"}))
+		}
+		svc := migrations.NewService(
+			&stubEngine{}, newMemStore(), &stubDryRunner{}, nil, audit, nil, nil, nil, nil, nil, nil,
+		)
+
+		page, err := svc.ListAudit(ctx, migrations.AuditFilter{Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, page.Entries, 2)
+		require.NotNil(t, page.NextBefore)
+		assert.Equal(t, int64(2), *page.NextBefore)
+
+		page, err = svc.ListAudit(ctx, migrations.AuditFilter{Limit: 3})
+		require.NoError(t, err)
+		assert.Len(t, page.Entries, 3)
+		assert.Nil(t, page.NextBefore)
+	})
+
+	t.Run("defaults and caps the page size", func(t *testing.T) {
+		audit := &stubAuditLog{}
+		svc := migrations.NewService(
+			&stubEngine{}, newMemStore(), &stubDryRunner{}, nil, audit, nil, nil, nil, nil, nil, nil,
+		)
+
+		_, err := svc.ListAudit(ctx, migrations.AuditFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, 51, audit.filter.Limit, "one past the default page size")
+
+		_, err = svc.ListAudit(ctx, migrations.AuditFilter{Limit: 10_000})
+		require.NoError(t, err)
+		assert.Equal(t, 201, audit.filter.Limit, "one past the maximum page size")
+	})
+}
+
+type stubProgressFeed struct {
+	filter  migrations.ProgressFilter
+	afterID int64
+}
+
+func (f *stubProgressFeed) Subscribe(
+	_ context.Context,
+	filter migrations.ProgressFilter,
+	afterID int64,
+) (<-chan migrations.ProgressEvent, error) {
+	f.filter, f.afterID = filter, afterID
+	ch := make(chan migrations.ProgressEvent)
+	close(ch)
+	return ch, nil
+}
+
+func TestService_SubscribeProgress(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	require.NoError(t, store.Save(ctx, api.Migration{Id: "m1", Candidates: []api.Candidate{{Id: "repo-a"}}}))
+
+	t.Run("unavailable without a progress feed", func(t *testing.T) {
+		svc := newSvc(store, &stubEngine{}, &stubDryRunner{})
+
+		_, err := svc.SubscribeProgress(ctx, migrations.ProgressFilter{MigrationID: "m1"}, 0)
+		var unavailable migrations.ProgressFeedUnavailableError
+		require.ErrorAs(t, err, &unavailable)
+	})
+
+	t.Run("rejects unknown migration and candidate", func(t *testing.T) {
+		svc := migrations.NewService(
+			&stubEngine{}, store, &stubDryRunner{}, nil, nil, &stubProgressFeed{}, nil, nil, nil, nil, nil,
+		)
+
+		_, err := svc.SubscribeProgress(ctx, migrations.ProgressFilter{MigrationID: "unknown"}, 0)
+		var migNotFound migrations.MigrationNotFoundError
+		require.ErrorAs(t, err, &migNotFound)
+
+		_, err = svc.SubscribeProgress(ctx, migrations.ProgressFilter{MigrationID: "m1", CandidateID: "repo-b"}, 0)
+		var candNotFound migrations.CandidateNotFoundError
+		require.ErrorAs(t, err, &candNotFound)
+	})
+
+	t.Run("subscribes from the last event ID", func(t *testing.T) {
+		feed := &stubProgressFeed{}
+		svc := migrations.NewService(&stubEngine{}, store, &stubDryRunner{}, nil, nil, feed, nil, nil, nil, nil, nil)
+
+		_, err := svc.SubscribeProgress(ctx, migrations.ProgressFilter{MigrationID: "m1", CandidateID: "repo-a"}, 42)
+		require.NoError(t, err)
+		assert.Equal(t, "repo-a", feed.filter.CandidateID)
+		assert.Equal(t, int64(42), feed.afterID)
+	})
+}
+
+// stubWebhookStore keeps webhooks in memory.
+type stubWebhookStore struct {
+	webhooks []migrations.WebhookSubscription
+}
+
+func (s *stubWebhookStore) CreateWebhook(_ context.Context, w *migrations.WebhookSubscription) error {
+	w.ID = int64(len(s.webhooks) + 1)
+	s.webhooks = append(s.webhooks, *w)
+	return nil
+}
+
+func (s *stubWebhookStore) ListWebhooks(
+	_ context.Context,
+	migrationID string,
+) ([]migrations.WebhookSubscription, error) {
+	var out []migrations.WebhookSubscription
+	for _, w := range s.webhooks {
+		if w.MigrationID == migrationID {
+			out = append(out, w)
+		}
+	}
+	return out, nil
+}
+
+func (s *stubWebhookStore) GetWebhook(
+	_ context.Context,
+	migrationID string,
+	id int64,
+) (*migrations.WebhookSubscription, error) {
+	for _, w := range s.webhooks {
+		if w.MigrationID == migrationID && w.ID == id {
+			return &w, nil
+		}
+	}
+	return nil, nil //nolint:nilnil
+}
+
+func (s *stubWebhookStore) DeleteWebhook(_ context.Context, migrationID string, id int64) (bool, error) {
+	for i, w := range s.webhooks {
+		if w.MigrationID == migrationID && w.ID == id {
+			s.webhooks = append(s.webhooks[:i], s.webhooks[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *stubWebhookStore) ListDeliveries(_ context.Context, _ int64, _ int) ([]migrations.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func TestService_Webhooks(t *testing.T) {
+	ctx := context.Background()
+	newWebhookSvc := func() (*migrations.Service, *stubWebhookStore) {
+		store := newMemStore()
+		require.NoError(t, store.Save(ctx, api.Migration{Id: "mig-1"}))
+		webhooks := &stubWebhookStore{}
+		svc := migrations.NewService(
+			&stubEngine{}, store, &stubDryRunner{}, nil, nil, nil, webhooks, nil, nil, nil, nil,
+		)
+		return svc, webhooks
+	}
+	valid := migrations.WebhookSubscription{
+		URL:    "https://hooks.example.com/loom",
+		Events: []string{migrations.WebhookPROpened, migrations.WebhookStepFailed},
+	}
+
+	t.Run("generates a secret and hides it when listing", func(t *testing.T) {
+		svc, _ := newWebhookSvc()
+
+		w, err := svc.CreateWebhook(ctx, "mig-1", valid)
+		require.NoError(t, err)
+		assert.Equal(t, "mig-1", w.MigrationID)
+		assert.Len(t, w.Secret, 64)
+
+		list, err := svc.ListWebhooks(ctx, "mig-1")
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Empty(t, list[0].Secret)
+	})
+
+	t.Run("keeps a given secret", func(t *testing.T) {
+		svc, webhooks := newWebhookSvc()
+		w := valid
+		w.Secret = "shared"
+
+		_, err := svc.CreateWebhook(ctx, "mig-1", w)
+		require.NoError(t, err)
+		assert.Equal(t, "shared", webhooks.webhooks[0].Secret)
+	})
+
+	t.Run("rejects invalid subscriptions", func(t *testing.T) {
+		svc, _ := newWebhookSvc()
+		cases := map[string]migrations.WebhookSubscription{
+			"relative url":     {URL: "/loom", Events: valid.Events},
+			"no events":        {URL: valid.URL},
+			"unknown event":    {URL: valid.URL, Events: []string{"step_dispatched"}},
+			"unknown template": {URL: valid.URL, Events: valid.Events, Templates: map[string]string{"nope": "x"}},
+			"broken template": {URL: valid.URL, Events: valid.Events,
+				Templates: map[string]string{migrations.WebhookPROpened: "{{.Missing"}},
+		}
+		for name, w := range cases {
+			_, err := svc.CreateWebhook(ctx, "mig-1", w)
+			var invalid migrations.InvalidWebhookError
+			assert.ErrorAs(t, err, &invalid, name)
+		}
+	})
+
+	t.Run("unknown migration", func(t *testing.T) {
+		svc, _ := newWebhookSvc()
+
+		_, err := svc.CreateWebhook(ctx, "unknown", valid)
+		var notFound migrations.MigrationNotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("delete and deliveries of an unknown webhook", func(t *testing.T) {
+		svc, _ := newWebhookSvc()
+		w, err := svc.CreateWebhook(ctx, "mig-1", valid)
+		require.NoError(t, err)
+
+		var notFound migrations.WebhookNotFoundError
+		_, err = svc.ListWebhookDeliveries(ctx, "other", w.ID, 0)
+		require.ErrorAs(t, err, &notFound)
+
+		require.NoError(t, svc.DeleteWebhook(ctx, "mig-1", w.ID))
+		require.ErrorAs(t, svc.DeleteWebhook(ctx, "mig-1", w.ID), &notFound)
+	})
+
+	t.Run("unavailable without a store", func(t *testing.T) {
+		svc := newSvc(newMemStore(), &stubEngine{}, &stubDryRunner{})
+
+		_, err := svc.CreateWebhook(ctx, "mig-1", valid)
+		var unavailable migrations.WebhooksUnavailableError
+		assert.ErrorAs(t, err, &unavailable)
+	})
+}
+
+// stubFreezeCalendar keeps freeze windows in memory.
+type stubFreezeCalendar struct {
+	windows []migrations.FreezeWindow
+}
+
+func (s *stubFreezeCalendar) CreateFreezeWindow(_ context.Context, w *migrations.FreezeWindow) error {
+	w.ID = int64(len(s.windows) + 1)
+	s.windows = append(s.windows, *w)
+	return nil
+}
+
+func (s *stubFreezeCalendar) ListFreezeWindows(
+	_ context.Context,
+	_ migrations.FreezeWindowFilter,
+) ([]migrations.FreezeWindow, error) {
+	return s.windows, nil
+}
+
+func (s *stubFreezeCalendar) GetFreezeWindow(_ context.Context, id int64) (*migrations.FreezeWindow, error) {
+	for _, w := range s.windows {
+		if w.ID == id {
+			return &w, nil
+		}
+	}
+	return nil, nil //nolint:nilnil
+}
+
+func (s *stubFreezeCalendar) DeleteFreezeWindow(_ context.Context, id int64) (bool, error) {
+	for i, w := range s.windows {
+		if w.ID == id {
+			s.windows = append(s.windows[:i], s.windows[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestService_FreezeWindows(t *testing.T) {
+	ctx := context.Background()
+	newFreezeSvc := func() (*migrations.Service, *stubFreezeCalendar) {
+		store := newMemStore()
+		require.NoError(t, store.Save(ctx, api.Migration{Id: "mig-1"}))
+		freezes := &stubFreezeCalendar{}
+		svc := migrations.NewService(&stubEngine{}, store, &stubDryRunner{}, nil, nil, nil, nil, freezes, nil, nil, nil)
+		return svc, freezes
+	}
+	start := time.Date(2026, 12, 20, 0, 0, 0, 0, time.UTC)
+	valid := migrations.FreezeWindow{Reason: "holiday freeze", StartsAt: start, EndsAt: start.Add(72 * time.Hour)}
+
+	t.Run("creates global and per-migration windows", func(t *testing.T) {
+		svc, freezes := newFreezeSvc()
+
+		_, err := svc.CreateFreezeWindow(ctx, valid)
+		require.NoError(t, err)
+		scoped := valid
+		scoped.MigrationID = "mig-1"
+		scoped.StepConfig = map[string]string{"env": "prod"}
+		w, err := svc.CreateFreezeWindow(ctx, scoped)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(2), w.ID)
+		require.Len(t, freezes.windows, 2)
+		assert.Equal(t, "prod", freezes.windows[1].StepConfig["env"])
+	})
+
+	t.Run("rejects invalid windows", func(t *testing.T) {
+		svc, _ := newFreezeSvc()
+		cases := map[string]migrations.FreezeWindow{
+			"no reason":      {StartsAt: start, EndsAt: valid.EndsAt},
+			"no start":       {Reason: "x", EndsAt: valid.EndsAt},
+			"ends too early": {Reason: "x", StartsAt: start, EndsAt: start},
+			"empty key": {Reason: "x", StartsAt: start, EndsAt: valid.EndsAt,
+				StepConfig: map[string]string{"": "prod"}},
+		}
+		for name, w := range cases {
+			_, err := svc.CreateFreezeWindow(ctx, w)
+			var invalid migrations.InvalidFreezeWindowError
+			assert.ErrorAs(t, err, &invalid, name)
+		}
+	})
+
+	t.Run("unknown migration", func(t *testing.T) {
+		svc, _ := newFreezeSvc()
+		w := valid
+		w.MigrationID = "unknown"
+
+		_, err := svc.CreateFreezeWindow(ctx, w)
+		var notFound migrations.MigrationNotFoundError
+		assert.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("get and delete an unknown window", func(t *testing.T) {
+		svc, _ := newFreezeSvc()
+		w, err := svc.CreateFreezeWindow(ctx, valid)
+		require.NoError(t, err)
+
+		require.NoError(t, svc.DeleteFreezeWindow(ctx, w.ID))
+		var notFound migrations.FreezeWindowNotFoundError
+		require.ErrorAs(t, svc.DeleteFreezeWindow(ctx, w.ID), &notFound)
+		_, err = svc.GetFreezeWindow(ctx, w.ID)
+		require.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("unavailable without a calendar", func(t *testing.T) {
+		svc := newSvc(newMemStore(), &stubEngine{}, &stubDryRunner{})
+
+		_, err := svc.CreateFreezeWindow(ctx, valid)
+		var unavailable migrations.FreezeCalendarUnavailableError
+		assert.ErrorAs(t, err, &unavailable)
+	})
+}
+
+// stubScheduleStore keeps scheduled starts in memory.
+type stubScheduleStore struct {
+	starts []api.ScheduledStart
+}
+
+func (s *stubScheduleStore) CreateScheduledStart(_ context.Context, start api.ScheduledStart) error {
+	s.starts = append(s.starts, start)
+	return nil
+}
+
+func (s *stubScheduleStore) ListScheduledStarts(_ context.Context, _ string) ([]api.ScheduledStart, error) {
+	return s.starts, nil
+}
+
+func (s *stubScheduleStore) GetScheduledStart(_ context.Context, id string) (*api.ScheduledStart, error) {
+	for _, start := range s.starts {
+		if start.Id == id {
+			return &start, nil
+		}
+	}
+	return nil, nil //nolint:nilnil
+}
+
+func (s *stubScheduleStore) TransitionScheduledStart(
+	_ context.Context,
+	id string,
+	from, to api.ScheduledStartStatus,
+	runID, errMsg string,
+) (bool, error) {
+	for i := range s.starts {
+		if s.starts[i].Id != id || s.starts[i].Status != from {
+			continue
+		}
+		s.starts[i].Status = to
+		if runID != "" {
+			s.starts[i].RunId = &runID
+		}
+		if errMsg != "" {
+			s.starts[i].Error = &errMsg
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func TestService_ScheduledStarts(t *testing.T) {
+	ctx := context.Background()
+	startAt := time.Now().Add(time.Hour)
+	newScheduleSvc := func(
+		candidates []api.Candidate,
+	) (*migrations.Service, *stubScheduleStore, *stubEngine, *memStore) {
+		store := newMemStore()
+		require.NoError(t, store.Save(ctx, api.Migration{
+			Id:         "m1",
+			Steps:      []api.StepDefinition{{Name: "step-1"}},
+			Candidates: candidates,
+		}))
+		schedules := &stubScheduleStore{}
+		engine := &stubEngine{}
+		svc := migrations.NewService(engine, store, &stubDryRunner{}, nil, nil, nil, nil, nil, schedules, nil, nil)
+		return svc, schedules, engine, store
+	}
+	candidate := func(id string) *string { return &id }
+
+	t.Run("schedules a start on a durable run", func(t *testing.T) {
+		svc, schedules, engine, _ := newScheduleSvc([]api.Candidate{{Id: "repo-a"}})
+		var runType string
+		var input migrations.ScheduledStartInput
+		engine.startFn = func(_ context.Context, name, id string, in any) (string, error) {
+			runType, input = name, in.(migrations.ScheduledStartInput)
+			return id, nil
+		}
+
+		scheduled, err := svc.ScheduleStart(ctx, "m1", api.ScheduleStartRequest{
+			CandidateId: candidate("repo-a"), StartAt: startAt,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, api.ScheduledStartStatusScheduled, scheduled.Status)
+		assert.Equal(t, migrations.ScheduledStartRunType, runType)
+		assert.Equal(t, scheduled.Id, input.ID)
+		assert.True(t, strings.HasPrefix(scheduled.Id, "schedule:m1__"), "never the run ID of a migration called schedule")
+		assert.True(t, startAt.Equal(input.StartAt))
+		require.Len(t, schedules.starts, 1)
+	})
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		svc, _, _, _ := newScheduleSvc([]api.Candidate{{Id: "repo-a"}})
+		bulk := &api.BulkStartRequest{MaxInFlight: 1}
+		inputs := map[string]string{"k": "v"}
+		cases := map[string]api.ScheduleStartRequest{
+			"neither":       {StartAt: startAt},
+			"both":          {CandidateId: candidate("repo-a"), Bulk: bulk, StartAt: startAt},
+			"bulk inputs":   {Bulk: bulk, Inputs: &inputs, StartAt: startAt},
+			"in the past":   {CandidateId: candidate("repo-a"), StartAt: time.Now().Add(-time.Minute)},
+			"no start time": {CandidateId: candidate("repo-a")},
+		}
+		for name, req := range cases {
+			_, err := svc.ScheduleStart(ctx, "m1", req)
+			var invalid migrations.InvalidScheduleError
+			assert.ErrorAs(t, err, &invalid, name)
+		}
+	})
+
+	t.Run("unknown migration or candidate", func(t *testing.T) {
+		svc, _, _, _ := newScheduleSvc([]api.Candidate{{Id: "repo-a"}})
+
+		_, err := svc.ScheduleStart(ctx, "unknown", api.ScheduleStartRequest{
+			CandidateId: candidate("repo-a"), StartAt: startAt,
+		})
+		var migNotFound migrations.MigrationNotFoundError
+		require.ErrorAs(t, err, &migNotFound)
+
+		_, err = svc.ScheduleStart(ctx, "m1", api.ScheduleStartRequest{
+			CandidateId: candidate("unknown"), StartAt: startAt,
+		})
+		var candNotFound migrations.CandidateNotFoundError
+		assert.ErrorAs(t, err, &candNotFound)
+	})
+
+	t.Run("failed run start marks the schedule failed", func(t *testing.T) {
+		svc, schedules, engine, _ := newScheduleSvc([]api.Candidate{{Id: "repo-a"}})
+		engine.startFn = func(_ context.Context, _, _ string, _ any) (string, error) {
+			return "", errors.New("engine down")
+		}
+
+		_, err := svc.ScheduleStart(ctx, "m1", api.ScheduleStartRequest{
+			CandidateId: candidate("repo-a"), StartAt: startAt,
+		})
+
+		require.Error(t, err)
+		require.Len(t, schedules.starts, 1)
+		assert.Equal(t, api.ScheduledStartStatusFailed, schedules.starts[0].Status)
+	})
+
+	t.Run("firing starts the candidate and records its run", func(t *testing.T) {
+		svc, schedules, _, store := newScheduleSvc([]api.Candidate{{Id: "repo-a"}})
+		scheduled, err := svc.ScheduleStart(ctx, "m1", api.ScheduleStartRequest{
+			CandidateId: candidate("repo-a"), StartAt: startAt,
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, svc.FireScheduledStart(ctx, scheduled.Id))
+
+		fired := schedules.starts[0]
+		assert.Equal(t, api.ScheduledStartStatusStarted, fired.Status)
+		require.NotNil(t, fired.RunId)
+		assert.Equal(t, "m1__repo-a", *fired.RunId)
+		m, _ := store.Get(ctx, "m1")
+		assert.Equal(t, api.CandidateStatusRunning, m.Candidates[0].Status)
+	})
+
+	t.Run("firing applies the start guard", func(t *testing.T) {
+		svc, schedules, _, _ := newScheduleSvc([]api.Candidate{{Id: "repo-a"}})
+		scheduled, err := svc.ScheduleStart(ctx, "m1", api.ScheduleStartRequest{
+			CandidateId: candidate("repo-a"), StartAt: startAt,
+		})
+		require.NoError(t, err)
+		_, err = svc.Start(ctx, "m1", "repo-a", nil)
+		require.NoError(t, err, "the candidate was started by hand in the meantime")
+
+		require.NoError(t, svc.FireScheduledStart(ctx, scheduled.Id))
+
+		fired := schedules.starts[0]
+		assert.Equal(t, api.ScheduledStartStatusFailed, fired.Status)
+		require.NotNil(t, fired.Error)
+		assert.Contains(t, *fired.Error, "repo-a")
+	})
+
+	t.Run("firing a bulk start", func(t *testing.T) {
+		svc, schedules, _, _ := newScheduleSvc([]api.Candidate{{Id: "repo-a"}, {Id: "repo-b"}})
+		scheduled, err := svc.ScheduleStart(ctx, "m1", api.ScheduleStartRequest{
+			Bulk: &api.BulkStartRequest{MaxInFlight: 1}, StartAt: startAt,
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, svc.FireScheduledStart(ctx, scheduled.Id))
+
+		fired := schedules.starts[0]
+		assert.Equal(t, api.ScheduledStartStatusStarted, fired.Status)
+		require.NotNil(t, fired.RunId)
+		assert.True(t, migrations.IsBulkStartOf(*fired.RunId, "m1"))
+	})
+
+	t.Run("cancel before it fires", func(t *testing.T) {
+		svc, schedules, engine, store := newScheduleSvc([]api.Candidate{{Id: "repo-a"}})
+		scheduled, err := svc.ScheduleStart(ctx, "m1", api.ScheduleStartRequest{
+			CandidateId: candidate("repo-a"), StartAt: startAt,
+		})
+		require.NoError(t, err)
+		var cancelledRun string
+		engine.cancelFn = func(_ context.Context, id string) error {
+			cancelledRun = id
+			return nil
+		}
+
+		require.NoError(t, svc.CancelScheduledStart(ctx, "m1", scheduled.Id))
+		assert.Equal(t, scheduled.Id, cancelledRun)
+		assert.Equal(t, api.ScheduledStartStatusCancelled, schedules.starts[0].Status)
+
+		require.NoError(t, svc.FireScheduledStart(ctx, scheduled.Id), "a cancelled start does not fire")
+		m, _ := store.Get(ctx, "m1")
+		assert.Empty(t, m.Candidates[0].Status)
+
+		var notPending migrations.ScheduledStartNotPendingError
+		assert.ErrorAs(t, svc.CancelScheduledStart(ctx, "m1", scheduled.Id), &notPending)
+	})
+
+	t.Run("cancel after it fired", func(t *testing.T) {
+		svc, _, _, _ := newScheduleSvc([]api.Candidate{{Id: "repo-a"}})
+		scheduled, err := svc.ScheduleStart(ctx, "m1", api.ScheduleStartRequest{
+			CandidateId: candidate("repo-a"), StartAt: startAt,
+		})
+		require.NoError(t, err)
+		require.NoError(t, svc.FireScheduledStart(ctx, scheduled.Id))
+
+		var notPending migrations.ScheduledStartNotPendingError
+		require.ErrorAs(t, svc.CancelScheduledStart(ctx, "m1", scheduled.Id), &notPending)
+		assert.Equal(t, string(api.ScheduledStartStatusStarted), notPending.Status)
+	})
+
+	t.Run("cancel an unknown start", func(t *testing.T) {
+		svc, _, _, _ := newScheduleSvc(nil)
+
+		var notFound migrations.ScheduledStartNotFoundError
+		assert.ErrorAs(t, svc.CancelScheduledStart(ctx, "m1", "schedule:m1__nope"), &notFound)
+	})
+
+	t.Run("unavailable without a store", func(t *testing.T) {
+		svc := newSvc(newMemStore(), &stubEngine{}, &stubDryRunner{})
+
+		_, err := svc.ListScheduledStarts(ctx, "m1")
+		var unavailable migrations.SchedulesUnavailableError
+		assert.ErrorAs(t, err, &unavailable)
+	})
+}
+
+func TestService_RegisterMigrator(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("defaults the health endpoint", func(t *testing.T) {
+		migrators := &stubMigratorRegistry{}
+		svc := migrations.NewService(
+			&stubEngine{}, newMemStore(), &stubDryRunner{}, nil, nil, nil, nil, nil, nil, nil, migrators,
+		)
+
+		m, err := svc.RegisterMigrator(ctx, api.MigratorRegistration{
+			MigratorApp: "secrets-migrator", Url: "https://secrets.internal/",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "https://secrets.internal", m.Url)
+		assert.Equal(t, "https://secrets.internal/health", m.HealthUrl)
+		require.Len(t, migrators.migrators, 1)
+	})
+
+	t.Run("rejects invalid registrations", func(t *testing.T) {
+		migrators := &stubMigratorRegistry{}
+		svc := migrations.NewService(
+			&stubEngine{}, newMemStore(), &stubDryRunner{}, nil, nil, nil, nil, nil, nil, nil, migrators,
+		)
+		healthz := "/healthz"
+
+		for _, req := range []api.MigratorRegistration{
+			{Url: "http://secrets:3002"},
+			{MigratorApp: "secrets-migrator", Url: "ftp://secrets"},
+			{MigratorApp: "secrets-migrator", Url: "http://secrets:3002", HealthUrl: &healthz},
+		} {
+			_, err := svc.RegisterMigrator(ctx, req)
+			var invalid migrations.InvalidMigratorRegistrationError
+			assert.ErrorAs(t, err, &invalid, req)
+		}
+		assert.Empty(t, migrators.migrators)
+	})
+
+	t.Run("unavailable without a registry", func(t *testing.T) {
+		svc := newSvc(newMemStore(), &stubEngine{}, &stubDryRunner{})
+
+		_, err := svc.ListMigrators(ctx)
+		var unavailable migrations.MigratorRegistryUnavailableError
+		assert.ErrorAs(t, err, &unavailable)
+	})
 }
@@ -0,0 +1,100 @@
+package migrations
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// StepDependencies returns, for each step, the names of the steps that must
+// finish before it is dispatched.
+//
+// When no step declares dependsOn the steps form an implicit chain in list
+// order, so migrations written before dependsOn existed keep running one step
+// at a time. Otherwise a step without dependsOn has no dependencies.
+func StepDependencies(steps []api.StepDefinition) map[string][]string {
+	deps := make(map[string][]string, len(steps))
+	explicit := slices.ContainsFunc(steps, func(s api.StepDefinition) bool {
+		return s.DependsOn != nil
+	})
+	for i, s := range steps {
+		switch {
+		case explicit && s.DependsOn != nil:
+			deps[s.Name] = slices.Clone(*s.DependsOn)
+		case !explicit && i > 0:
+			deps[s.Name] = []string{steps[i-1].Name}
+		default:
+			deps[s.Name] = nil
+		}
+	}
+	return deps
+}
+
+// ValidateStepGraph checks that step names are unique and that dependsOn only
+// names other steps in the list without forming a cycle.
+func ValidateStepGraph(steps []api.StepDefinition) error {
+	names := make(map[string]bool, len(steps))
+	for _, s := range steps {
+		if names[s.Name] {
+			return InvalidStepGraphError{Reason: fmt.Sprintf("duplicate step name %q", s.Name)}
+		}
+		names[s.Name] = true
+	}
+
+	deps := StepDependencies(steps)
+	for _, s := range steps {
+		for _, d := range deps[s.Name] {
+			if d == s.Name {
+				return InvalidStepGraphError{Reason: fmt.Sprintf("step %q depends on itself", s.Name)}
+			}
+			if !names[d] {
+				return InvalidStepGraphError{Reason: fmt.Sprintf("step %q depends on unknown step %q", s.Name, d)}
+			}
+		}
+	}
+
+	order := TopologicalOrder(steps)
+	if len(order) < len(steps) {
+		placed := make(map[string]bool, len(order))
+		for _, s := range order {
+			placed[s.Name] = true
+		}
+		for _, s := range steps {
+			if !placed[s.Name] {
+				return InvalidStepGraphError{Reason: fmt.Sprintf("step %q is on or behind a dependency cycle", s.Name)}
+			}
+		}
+	}
+	return nil
+}
+
+// TopologicalOrder returns the steps ordered so that every step comes after
+// the steps it depends on, otherwise following list order. Steps on or behind
+// a dependency cycle are left out.
+func TopologicalOrder(steps []api.StepDefinition) []api.StepDefinition {
+	deps := StepDependencies(steps)
+	done := make(map[string]bool, len(steps))
+	order := make([]api.StepDefinition, 0, len(steps))
+	for progress := true; progress; {
+		progress = false
+		for _, s := range steps {
+			if !done[s.Name] && DependenciesDone(deps[s.Name], done) {
+				done[s.Name] = true
+				order = append(order, s)
+				progress = true
+			}
+		}
+	}
+	return order
+}
+
+// DependenciesDone reports whether every step named in deps is marked in done.
+func DependenciesDone(deps []string, done map[string]bool) bool {
+	for _, d := range deps {
+		if !done[d] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+func stepDef(name string, dependsOn ...string) api.StepDefinition {
+	s := api.StepDefinition{Name: name, MigratorApp: "app"}
+	if dependsOn != nil {
+		s.DependsOn = &dependsOn
+	}
+	return s
+}
+
+func TestStepDependencies(t *testing.T) {
+	t.Run("chains steps in list order when none declares dependsOn", func(t *testing.T) {
+		deps := migrations.StepDependencies([]api.StepDefinition{stepDef("a"), stepDef("b"), stepDef("c")})
+
+		assert.Empty(t, deps["a"])
+		assert.Equal(t, []string{"a"}, deps["b"])
+		assert.Equal(t, []string{"b"}, deps["c"])
+	})
+
+	t.Run("treats steps without dependsOn as roots once any step declares it", func(t *testing.T) {
+		deps := migrations.StepDependencies([]api.StepDefinition{
+			stepDef("app-chart"),
+			stepDef("disable-prune"),
+			stepDef("swap-chart", "app-chart", "disable-prune"),
+		})
+
+		assert.Empty(t, deps["app-chart"])
+		assert.Empty(t, deps["disable-prune"])
+		assert.Equal(t, []string{"app-chart", "disable-prune"}, deps["swap-chart"])
+	})
+}
+
+func TestValidateStepGraph(t *testing.T) {
+	t.Run("accepts a chain and a diamond", func(t *testing.T) {
+		require.NoError(t, migrations.ValidateStepGraph([]api.StepDefinition{stepDef("a"), stepDef("b")}))
+		require.NoError(t, migrations.ValidateStepGraph([]api.StepDefinition{
+			stepDef("a"),
+			stepDef("b", "a"),
+			stepDef("c", "a"),
+			stepDef("d", "b", "c"),
+		}))
+	})
+
+	cases := map[string][]api.StepDefinition{
+		"duplicate step name": {stepDef("a"), stepDef("a")},
+		"unknown dependency":  {stepDef("a", "missing")},
+		"self dependency":     {stepDef("a", "a")},
+		"cycle":               {stepDef("a", "c"), stepDef("b", "a"), stepDef("c", "b")},
+	}
+	for name, steps := range cases {
+		t.Run("rejects "+name, func(t *testing.T) {
+			var graphErr migrations.InvalidStepGraphError
+			require.ErrorAs(t, migrations.ValidateStepGraph(steps), &graphErr)
+		})
+	}
+}
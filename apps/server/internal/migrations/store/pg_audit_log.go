@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+)
+
+// Compile-time check: *PGAuditLog implements migrations.AuditLog.
+var _ migrations.AuditLog = (*PGAuditLog)(nil)
+
+// PGAuditLog implements migrations.AuditLog backed by PostgreSQL. The
+// audit_log table rejects updates and deletes, so entries cannot be rewritten.
+type PGAuditLog struct {
+	pool *pgxpool.Pool
+}
+
+// NewPGAuditLog creates a new PGAuditLog with the given connection pool.
+func NewPGAuditLog(pool *pgxpool.Pool) *PGAuditLog {
+	return &PGAuditLog{pool: pool}
+}
+
+// Append inserts an audit entry. ID and CreatedAt are assigned by the database.
+func (s *PGAuditLog) Append(ctx context.Context, e migrations.AuditEntry) error {
+	var payload []byte
+	if len(e.Payload) > 0 {
+		payload = e.Payload
+	}
+	_, err := s.pool.Exec(ctx,
+		`INSERT INTO audit_log (actor, action, migration_id, candidate_id, payload, status_code, outcome, error)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		e.Actor, e.Action, nilIfEmpty(e.MigrationID), nilIfEmpty(e.CandidateID),
+		payload, e.StatusCode, e.Outcome, nilIfEmpty(e.Error),
+	)
+	if err != nil {
+		return fmt.Errorf("insert audit_log: %w", err)
+	}
+	return nil
+}
+
+// List returns up to filter.Limit entries matching filter, newest first.
+func (s *PGAuditLog) List(ctx context.Context, filter migrations.AuditFilter) ([]migrations.AuditEntry, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, actor, action, migration_id, candidate_id, payload, status_code, outcome, error, created_at
+		FROM audit_log
+		WHERE ($1 = '' OR migration_id = $1)
+		  AND ($2 = '' OR candidate_id = $2)
+		  AND ($3 = '' OR actor = $3)
+		  AND ($4 = 0 OR id < $4)
+		ORDER BY id DESC
+		LIMIT $5
+	`, filter.MigrationID, filter.CandidateID, filter.Actor, filter.Before, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("audit log query: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]migrations.AuditEntry, 0)
+	for rows.Next() {
+		var e migrations.AuditEntry
+		var migrationID, candidateID, errMsg *string
+		if err := rows.Scan(&e.ID, &e.Actor, &e.Action, &migrationID, &candidateID, &e.Payload,
+			&e.StatusCode, &e.Outcome, &errMsg, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan audit entry: %w", err)
+		}
+		if migrationID != nil {
+			e.MigrationID = *migrationID
+		}
+		if candidateID != nil {
+			e.CandidateID = *candidateID
+		}
+		if errMsg != nil {
+			e.Error = *errMsg
+		}
+		result = append(result, e)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,88 @@
+package store_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/migrations/store"
+	"github.com/tilsley/loom/apps/server/internal/migrations/store/pgmigrations"
+	pgplatform "github.com/tilsley/loom/apps/server/internal/platform/postgres"
+)
+
+// newPGAuditLog creates a PGAuditLog backed by a real PostgreSQL instance.
+// Skips if POSTGRES_URL is not set.
+func newPGAuditLog(t *testing.T) (*store.PGAuditLog, func(query string) error) {
+	t.Helper()
+	pgURL := os.Getenv("POSTGRES_URL")
+	if pgURL == "" {
+		t.Skip("POSTGRES_URL not set — skipping Postgres integration tests")
+	}
+	pool, err := pgplatform.New(context.Background(), pgURL, pgmigrations.FS)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		// TRUNCATE bypasses the append-only row triggers.
+		_, err := pool.Exec(context.Background(), `TRUNCATE audit_log`)
+		require.NoError(t, err)
+		pool.Close()
+	})
+	exec := func(query string) error {
+		_, err := pool.Exec(context.Background(), query)
+		return err
+	}
+	return store.NewPGAuditLog(pool), exec
+}
+
+func TestPG_AuditLog_AppendAndFilter(t *testing.T) {
+	log, _ := newPGAuditLog(t)
+	ctx := context.Background()
+
+	for _, e := range []migrations.AuditEntry{
+		{Actor: "alice", Action: "start", MigrationID: "mig-a", CandidateID: "billing-api", StatusCode: 202},
+		{Actor: "bob", Action: "cancel", MigrationID: "mig-a", CandidateID: "billing-api", StatusCode: 202},
+		{
+			Actor: "alice", Action: "update-inputs", MigrationID: "mig-b", CandidateID: "payments-api",
+			Payload: json.RawMessage(`{"env":"prod"}`), StatusCode: 400, Outcome: migrations.AuditFailed, Error: "bad input",
+		},
+	} {
+		if e.Outcome == "" {
+			e.Outcome = migrations.AuditSucceeded
+		}
+		require.NoError(t, log.Append(ctx, e))
+	}
+
+	all, err := log.List(ctx, migrations.AuditFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "update-inputs", all[0].Action, "newest first")
+	assert.JSONEq(t, `{"env":"prod"}`, string(all[0].Payload))
+	assert.Equal(t, "bad input", all[0].Error)
+
+	byActor, err := log.List(ctx, migrations.AuditFilter{Actor: "alice", Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, byActor, 2)
+
+	byCandidate, err := log.List(ctx, migrations.AuditFilter{MigrationID: "mig-a", CandidateID: "billing-api", Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, byCandidate, 2)
+
+	older, err := log.List(ctx, migrations.AuditFilter{Before: all[0].ID, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, older, 1)
+	assert.Equal(t, "cancel", older[0].Action)
+}
+
+func TestPG_AuditLog_IsAppendOnly(t *testing.T) {
+	log, exec := newPGAuditLog(t)
+	require.NoError(t, log.Append(context.Background(), migrations.AuditEntry{
+		Actor: "alice", Action: "start", StatusCode: 202, Outcome: migrations.AuditSucceeded,
+	}))
+
+	require.Error(t, exec(`UPDATE audit_log SET actor = 'mallory'`))
+	require.Error(t, exec(`DELETE FROM audit_log`))
+}
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+)
+
+// Compile-time check: *PGClosedRunStore implements migrations.ClosedRunStore.
+var _ migrations.ClosedRunStore = (*PGClosedRunStore)(nil)
+
+// PGClosedRunStore implements migrations.ClosedRunStore backed by PostgreSQL.
+type PGClosedRunStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPGClosedRunStore creates a new PGClosedRunStore with the given connection pool.
+func NewPGClosedRunStore(pool *pgxpool.Pool) *PGClosedRunStore {
+	return &PGClosedRunStore{pool: pool}
+}
+
+// SaveClosedRun upserts the closed run.
+func (s *PGClosedRunStore) SaveClosedRun(ctx context.Context, r migrations.ClosedRun) error {
+	queries, err := json.Marshal(r.Queries)
+	if err != nil {
+		return fmt.Errorf("marshal queries: %w", err)
+	}
+	var result []byte
+	if len(r.Result) > 0 {
+		result = r.Result
+	}
+	_, err = s.pool.Exec(ctx,
+		`INSERT INTO closed_runs (run_id, run_type, runtime_status, result, queries, closed_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (run_id) DO UPDATE
+		 SET run_type = EXCLUDED.run_type, runtime_status = EXCLUDED.runtime_status,
+		     result = EXCLUDED.result, queries = EXCLUDED.queries, closed_at = EXCLUDED.closed_at`,
+		r.ID, r.RunType, r.RuntimeStatus, result, queries, r.ClosedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert closed run: %w", err)
+	}
+	return nil
+}
+
+// GetClosedRun returns the closed run, or nil when there is none with that ID.
+func (s *PGClosedRunStore) GetClosedRun(ctx context.Context, id string) (*migrations.ClosedRun, error) {
+	var r migrations.ClosedRun
+	var result, queries []byte
+	err := s.pool.QueryRow(ctx,
+		`SELECT run_id, run_type, runtime_status, result, queries, closed_at FROM closed_runs WHERE run_id = $1`, id,
+	).Scan(&r.ID, &r.RunType, &r.RuntimeStatus, &result, &queries, &r.ClosedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil //nolint:nilnil
+		}
+		return nil, fmt.Errorf("get closed run: %w", err)
+	}
+	if len(result) > 0 {
+		r.Result = result
+	}
+	if err := json.Unmarshal(queries, &r.Queries); err != nil {
+		return nil, fmt.Errorf("unmarshal queries: %w", err)
+	}
+	return &r, nil
+}
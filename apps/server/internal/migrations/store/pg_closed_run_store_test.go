@@ -0,0 +1,69 @@
+package store_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/migrations/store"
+	"github.com/tilsley/loom/apps/server/internal/migrations/store/pgmigrations"
+	pgplatform "github.com/tilsley/loom/apps/server/internal/platform/postgres"
+)
+
+// newPGClosedRunStore creates a PGClosedRunStore backed by a real PostgreSQL
+// instance. Skips if POSTGRES_URL is not set.
+func newPGClosedRunStore(t *testing.T) *store.PGClosedRunStore {
+	t.Helper()
+	pgURL := os.Getenv("POSTGRES_URL")
+	if pgURL == "" {
+		t.Skip("POSTGRES_URL not set — skipping Postgres integration tests")
+	}
+	pool, err := pgplatform.New(context.Background(), pgURL, pgmigrations.FS)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := pool.Exec(context.Background(), `DELETE FROM closed_runs`)
+		require.NoError(t, err)
+		pool.Close()
+	})
+	return store.NewPGClosedRunStore(pool)
+}
+
+func TestPG_ClosedRunStore_SaveGet(t *testing.T) {
+	s := newPGClosedRunStore(t)
+	ctx := context.Background()
+	at := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	missing, err := s.GetClosedRun(ctx, "mig-abc__billing-api")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	require.NoError(t, s.SaveClosedRun(ctx, migrations.ClosedRun{
+		ID:            "mig-abc__billing-api",
+		RunType:       "MigrationOrchestrator",
+		RuntimeStatus: migrations.RuntimeStatusFailed,
+		Queries:       map[string]json.RawMessage{"progress": json.RawMessage(`{"status":"running"}`)},
+		ClosedAt:      at,
+	}))
+	require.NoError(t, s.SaveClosedRun(ctx, migrations.ClosedRun{
+		ID:            "mig-abc__billing-api",
+		RunType:       "MigrationOrchestrator",
+		RuntimeStatus: migrations.RuntimeStatusCompleted,
+		Result:        json.RawMessage(`{"status": "completed"}`),
+		Queries:       map[string]json.RawMessage{"progress": json.RawMessage(`{"status": "running"}`)},
+		ClosedAt:      at.Add(time.Hour),
+	}))
+
+	got, err := s.GetClosedRun(ctx, "mig-abc__billing-api")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, migrations.RuntimeStatusCompleted, got.RuntimeStatus)
+	assert.JSONEq(t, `{"status":"completed"}`, string(got.Result))
+	assert.JSONEq(t, `{"status":"running"}`, string(got.Queries["progress"]))
+	assert.True(t, at.Add(time.Hour).Equal(got.ClosedAt))
+}
@@ -0,0 +1,210 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// Compile-time check: *PGDispatchLimiter implements migrations.DispatchLimiter.
+var _ migrations.DispatchLimiter = (*PGDispatchLimiter)(nil)
+
+// PGDispatchLimiter implements migrations.DispatchLimiter backed by PostgreSQL.
+// Acquiring a slot locks the app's dispatch_limits row, which serialises the
+// acquisitions of every run on every server replica.
+type PGDispatchLimiter struct {
+	pool *pgxpool.Pool
+}
+
+// NewPGDispatchLimiter creates a new PGDispatchLimiter with the given connection pool.
+func NewPGDispatchLimiter(pool *pgxpool.Pool) *PGDispatchLimiter {
+	return &PGDispatchLimiter{pool: pool}
+}
+
+// SetDispatchLimits upserts the limits of each app in limits, owned by
+// migrationID, and deletes the other limits migrationID owns. Limits another
+// migration owns are only accepted unchanged; the whole call is refused
+// otherwise.
+func (l *PGDispatchLimiter) SetDispatchLimits(
+	ctx context.Context,
+	migrationID string,
+	limits []api.DispatchLimit,
+) error {
+	tx, err := l.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	apps := make([]string, 0, len(limits))
+	for _, limit := range limits {
+		apps = append(apps, limit.MigratorApp)
+	}
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM dispatch_limits WHERE migration_id = $1 AND NOT (migrator_app = ANY($2))`,
+		migrationID, apps,
+	); err != nil {
+		return fmt.Errorf("delete dropped dispatch limits: %w", err)
+	}
+
+	for _, limit := range limits {
+		var updated bool
+		err := tx.QueryRow(ctx,
+			`INSERT INTO dispatch_limits (migrator_app, migration_id, max_in_flight, per_minute)
+			 VALUES ($1, $2, $3, $4)
+			 ON CONFLICT (migrator_app) DO UPDATE
+			 SET migration_id = EXCLUDED.migration_id, max_in_flight = EXCLUDED.max_in_flight,
+			     per_minute = EXCLUDED.per_minute, updated_at = NOW()
+			 WHERE dispatch_limits.migration_id IS NULL OR dispatch_limits.migration_id = EXCLUDED.migration_id
+			 RETURNING true`,
+			limit.MigratorApp, migrationID, limit.MaxInFlight, limit.PerMinute,
+		).Scan(&updated)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("upsert dispatch limits of %q: %w", limit.MigratorApp, err)
+		}
+
+		// Another migration owns the app's limits.
+		owned := api.DispatchLimit{MigratorApp: limit.MigratorApp}
+		var owner string
+		if err := tx.QueryRow(ctx,
+			`SELECT migration_id, max_in_flight, per_minute FROM dispatch_limits WHERE migrator_app = $1`,
+			limit.MigratorApp,
+		).Scan(&owner, &owned.MaxInFlight, &owned.PerMinute); err != nil {
+			return fmt.Errorf("get dispatch limits of %q: %w", limit.MigratorApp, err)
+		}
+		if !sameLimit(owned.MaxInFlight, limit.MaxInFlight) || !sameLimit(owned.PerMinute, limit.PerMinute) {
+			return migrations.DispatchLimitConflictError{MigratorApp: limit.MigratorApp, DeclaredBy: owner}
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+func sameLimit(a, b *int) bool {
+	return (a == nil) == (b == nil) && (a == nil || *a == *b)
+}
+
+// AcquireDispatchSlot takes a slot for holder when the app's limits allow it.
+// Apps without limits are granted without recording a slot.
+func (l *PGDispatchLimiter) AcquireDispatchSlot(
+	ctx context.Context,
+	migratorApp, holder string,
+) (migrations.DispatchSlotGrant, error) {
+	tx, err := l.pool.Begin(ctx)
+	if err != nil {
+		return migrations.DispatchSlotGrant{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	limit := api.DispatchLimit{MigratorApp: migratorApp}
+	err = tx.QueryRow(ctx,
+		`SELECT max_in_flight, per_minute FROM dispatch_limits WHERE migrator_app = $1 FOR UPDATE`,
+		migratorApp,
+	).Scan(&limit.MaxInFlight, &limit.PerMinute)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && limit.MaxInFlight == nil && limit.PerMinute == nil) {
+		return migrations.DispatchSlotGrant{Acquired: true}, nil
+	}
+	if err != nil {
+		return migrations.DispatchSlotGrant{}, fmt.Errorf("get dispatch limits: %w", err)
+	}
+
+	// A retried acquisition finds the slot it already took.
+	var exists bool
+	err = tx.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM dispatch_slots
+		                WHERE migrator_app = $1 AND holder = $2 AND released_at IS NULL)`,
+		migratorApp, holder,
+	).Scan(&exists)
+	if err != nil {
+		return migrations.DispatchSlotGrant{}, fmt.Errorf("get dispatch slot: %w", err)
+	}
+	if exists {
+		return migrations.DispatchSlotGrant{Acquired: true, Held: true}, nil
+	}
+
+	window := migrations.DispatchRateWindow.Seconds()
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM dispatch_slots
+		 WHERE migrator_app = $1 AND acquired_at < NOW() - make_interval(secs => $2)
+		   AND (released_at IS NOT NULL OR expires_at <= NOW())`,
+		migratorApp, window,
+	); err != nil {
+		return migrations.DispatchSlotGrant{}, fmt.Errorf("prune dispatch slots: %w", err)
+	}
+
+	var usage migrations.DispatchUsage
+	var oldestRecent *time.Time
+	var now time.Time
+	err = tx.QueryRow(ctx,
+		`SELECT COUNT(*) FILTER (WHERE released_at IS NULL AND expires_at > NOW()),
+		        COUNT(*) FILTER (WHERE acquired_at > NOW() - make_interval(secs => $2)),
+		        MIN(acquired_at) FILTER (WHERE acquired_at > NOW() - make_interval(secs => $2)),
+		        NOW()
+		 FROM dispatch_slots WHERE migrator_app = $1`,
+		migratorApp, window,
+	).Scan(&usage.InFlight, &usage.Recent, &oldestRecent, &now)
+	if err != nil {
+		return migrations.DispatchSlotGrant{}, fmt.Errorf("count dispatch slots: %w", err)
+	}
+	if oldestRecent != nil {
+		usage.OldestRecent = *oldestRecent
+	}
+
+	grant := migrations.CheckDispatchLimit(limit, usage, now)
+	if !grant.Acquired {
+		return grant, nil
+	}
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO dispatch_slots (migrator_app, holder, expires_at)
+		 VALUES ($1, $2, NOW() + make_interval(secs => $3))
+		 ON CONFLICT (migrator_app, holder) DO UPDATE
+		 SET acquired_at = NOW(), expires_at = EXCLUDED.expires_at, released_at = NULL`,
+		migratorApp, holder, migrations.DispatchSlotLease.Seconds(),
+	); err != nil {
+		return migrations.DispatchSlotGrant{}, fmt.Errorf("insert dispatch slot: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return migrations.DispatchSlotGrant{}, fmt.Errorf("commit tx: %w", err)
+	}
+	return grant, nil
+}
+
+// RenewDispatchSlot moves the expiry of holder's slot to a lease from now,
+// unless the slot has been released.
+func (l *PGDispatchLimiter) RenewDispatchSlot(ctx context.Context, migratorApp, holder string) error {
+	_, err := l.pool.Exec(ctx,
+		`UPDATE dispatch_slots SET expires_at = NOW() + make_interval(secs => $3)
+		 WHERE migrator_app = $1 AND holder = $2 AND released_at IS NULL`,
+		migratorApp, holder, migrations.DispatchSlotLease.Seconds(),
+	)
+	if err != nil {
+		return fmt.Errorf("renew dispatch slot: %w", err)
+	}
+	return nil
+}
+
+// ReleaseDispatchSlot marks holder's slot released. The row stays until it
+// leaves the per-minute window.
+func (l *PGDispatchLimiter) ReleaseDispatchSlot(ctx context.Context, migratorApp, holder string) error {
+	_, err := l.pool.Exec(ctx,
+		`UPDATE dispatch_slots SET released_at = NOW()
+		 WHERE migrator_app = $1 AND holder = $2 AND released_at IS NULL`,
+		migratorApp, holder,
+	)
+	if err != nil {
+		return fmt.Errorf("release dispatch slot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package store_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/migrations/store"
+	"github.com/tilsley/loom/apps/server/internal/migrations/store/pgmigrations"
+	pgplatform "github.com/tilsley/loom/apps/server/internal/platform/postgres"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// newPGDispatchLimiter creates a PGDispatchLimiter backed by a real PostgreSQL
+// instance. Skips if POSTGRES_URL is not set.
+func newPGDispatchLimiter(t *testing.T) *store.PGDispatchLimiter {
+	t.Helper()
+	pgURL := os.Getenv("POSTGRES_URL")
+	if pgURL == "" {
+		t.Skip("POSTGRES_URL not set — skipping Postgres integration tests")
+	}
+	pool, err := pgplatform.New(context.Background(), pgURL, pgmigrations.FS)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := pool.Exec(context.Background(), `DELETE FROM dispatch_slots; DELETE FROM dispatch_limits;`)
+		require.NoError(t, err)
+		pool.Close()
+	})
+	return store.NewPGDispatchLimiter(pool)
+}
+
+func TestPG_DispatchLimiter_MaxInFlight(t *testing.T) {
+	l := newPGDispatchLimiter(t)
+	ctx := context.Background()
+	two := 2
+	require.NoError(t, l.SetDispatchLimits(ctx, "mig-a", []api.DispatchLimit{{MigratorApp: "app", MaxInFlight: &two}}))
+
+	for _, holder := range []string{"run-1", "run-2"} {
+		grant, err := l.AcquireDispatchSlot(ctx, "app", holder)
+		require.NoError(t, err)
+		require.True(t, grant.Acquired, holder)
+		require.True(t, grant.Held, holder)
+	}
+
+	grant, err := l.AcquireDispatchSlot(ctx, "app", "run-1")
+	require.NoError(t, err)
+	assert.True(t, grant.Acquired, "a holder asking again gets its slot back")
+
+	grant, err = l.AcquireDispatchSlot(ctx, "app", "run-3")
+	require.NoError(t, err)
+	assert.False(t, grant.Acquired)
+	assert.Positive(t, grant.RetryAfter)
+	assert.Contains(t, grant.Reason, "in flight")
+
+	require.NoError(t, l.ReleaseDispatchSlot(ctx, "app", "run-1"))
+	require.NoError(t, l.ReleaseDispatchSlot(ctx, "app", "run-1"), "releasing twice is a no-op")
+	grant, err = l.AcquireDispatchSlot(ctx, "app", "run-3")
+	require.NoError(t, err)
+	assert.True(t, grant.Acquired)
+}
+
+func TestPG_DispatchLimiter_RenewKeepsSlotInFlight(t *testing.T) {
+	l := newPGDispatchLimiter(t)
+	ctx := context.Background()
+	one := 1
+	require.NoError(t, l.SetDispatchLimits(ctx, "mig-a", []api.DispatchLimit{{MigratorApp: "app", MaxInFlight: &one}}))
+
+	grant, err := l.AcquireDispatchSlot(ctx, "app", "run-1")
+	require.NoError(t, err)
+	require.True(t, grant.Held)
+	require.NoError(t, l.RenewDispatchSlot(ctx, "app", "run-1"))
+	grant, err = l.AcquireDispatchSlot(ctx, "app", "run-2")
+	require.NoError(t, err)
+	assert.False(t, grant.Acquired, "a renewed slot is still in flight")
+
+	require.NoError(t, l.ReleaseDispatchSlot(ctx, "app", "run-1"))
+	require.NoError(t, l.RenewDispatchSlot(ctx, "app", "run-1"), "renewing a released slot is a no-op")
+	grant, err = l.AcquireDispatchSlot(ctx, "app", "run-2")
+	require.NoError(t, err)
+	assert.True(t, grant.Acquired, "renewing does not take a released slot back")
+}
+
+func TestPG_DispatchLimiter_PerMinute(t *testing.T) {
+	l := newPGDispatchLimiter(t)
+	ctx := context.Background()
+	one := 1
+	require.NoError(t, l.SetDispatchLimits(ctx, "mig-a", []api.DispatchLimit{{MigratorApp: "app", PerMinute: &one}}))
+
+	grant, err := l.AcquireDispatchSlot(ctx, "app", "run-1")
+	require.NoError(t, err)
+	require.True(t, grant.Acquired)
+	require.NoError(t, l.ReleaseDispatchSlot(ctx, "app", "run-1"))
+
+	grant, err = l.AcquireDispatchSlot(ctx, "app", "run-2")
+	require.NoError(t, err)
+	assert.False(t, grant.Acquired, "released dispatches still count towards the minute")
+	assert.Contains(t, grant.Reason, "last minute")
+}
+
+func TestPG_DispatchLimiter_UnlimitedApps(t *testing.T) {
+	l := newPGDispatchLimiter(t)
+	ctx := context.Background()
+	one := 1
+	require.NoError(t, l.SetDispatchLimits(ctx, "mig-a", []api.DispatchLimit{{MigratorApp: "app", MaxInFlight: &one}}))
+	require.NoError(t, l.SetDispatchLimits(ctx, "mig-a", []api.DispatchLimit{{MigratorApp: "app"}}))
+
+	for _, app := range []string{"app", "unknown-app"} {
+		for _, holder := range []string{"run-1", "run-2"} {
+			grant, err := l.AcquireDispatchSlot(ctx, app, holder)
+			require.NoError(t, err)
+			assert.True(t, grant.Acquired)
+			assert.False(t, grant.Held, "no slot is recorded for apps without limits")
+		}
+	}
+}
+
+func TestPG_DispatchLimiter_LimitsOwnedByDeclaringMigration(t *testing.T) {
+	l := newPGDispatchLimiter(t)
+	ctx := context.Background()
+	one, two := 1, 2
+	require.NoError(t, l.SetDispatchLimits(ctx, "mig-a", []api.DispatchLimit{{MigratorApp: "app", MaxInFlight: &one}}))
+
+	require.NoError(t, l.SetDispatchLimits(ctx, "mig-b", []api.DispatchLimit{{MigratorApp: "app", MaxInFlight: &one}}),
+		"another migration may declare the same limits")
+	err := l.SetDispatchLimits(ctx, "mig-b", []api.DispatchLimit{
+		{MigratorApp: "other-app", MaxInFlight: &one},
+		{MigratorApp: "app", MaxInFlight: &two},
+	})
+	var conflict migrations.DispatchLimitConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, "mig-a", conflict.DeclaredBy)
+
+	grant, err := l.AcquireDispatchSlot(ctx, "other-app", "run-1")
+	require.NoError(t, err)
+	assert.False(t, grant.Held, "a refused announcement changes no app's limits")
+
+	require.NoError(t, l.SetDispatchLimits(ctx, "mig-a", []api.DispatchLimit{{MigratorApp: "app", MaxInFlight: &two}}),
+		"the owner may change them")
+}
+
+func TestPG_DispatchLimiter_DropsLimitsLeftOutOfAnnouncement(t *testing.T) {
+	l := newPGDispatchLimiter(t)
+	ctx := context.Background()
+	one := 1
+	require.NoError(t, l.SetDispatchLimits(ctx, "mig-a", []api.DispatchLimit{
+		{MigratorApp: "app", MaxInFlight: &one},
+		{MigratorApp: "other-app", MaxInFlight: &one},
+	}))
+	require.NoError(t, l.SetDispatchLimits(ctx, "mig-b", []api.DispatchLimit{{MigratorApp: "b-app", MaxInFlight: &one}}))
+
+	limited := func(app string) bool {
+		grant, err := l.AcquireDispatchSlot(ctx, app, "probe")
+		require.NoError(t, err)
+		require.NoError(t, l.ReleaseDispatchSlot(ctx, app, "probe"))
+		return grant.Held
+	}
+
+	require.NoError(t, l.SetDispatchLimits(ctx, "mig-a", []api.DispatchLimit{{MigratorApp: "app", MaxInFlight: &one}}))
+	assert.True(t, limited("app"))
+	assert.False(t, limited("other-app"))
+
+	require.NoError(t, l.SetDispatchLimits(ctx, "mig-a", nil))
+	assert.False(t, limited("app"))
+	assert.True(t, limited("b-app"), "another migration's limits are kept")
+}
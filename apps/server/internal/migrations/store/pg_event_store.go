@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.opentelemetry.io/otel"
@@ -54,7 +55,8 @@ func NewPGEventStore(pool *pgxpool.Pool) *PGEventStore {
 	}
 }
 
-// RecordEvent inserts an event row and emits OTel business metrics.
+// RecordEvent inserts an event row, queues deliveries to the webhooks subscribed
+// to it and emits OTel business metrics.
 func (s *PGEventStore) RecordEvent(ctx context.Context, event migrations.StepEvent) error {
 	var metadataJSON []byte
 	if event.Metadata != nil {
@@ -65,7 +67,13 @@ func (s *PGEventStore) RecordEvent(ctx context.Context, event migrations.StepEve
 		}
 	}
 
-	_, err := s.pool.Exec(ctx,
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	_, err = tx.Exec(ctx,
 		`INSERT INTO step_events (migration_id, candidate_id, step_name, event_type, status, duration_ms, metadata)
 		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
 		event.MigrationID, event.CandidateID, nilIfEmpty(event.StepName),
@@ -75,6 +83,26 @@ func (s *PGEventStore) RecordEvent(ctx context.Context, event migrations.StepEve
 		return fmt.Errorf("insert step_event: %w", err)
 	}
 
+	// Queue a delivery for each webhook subscribed to the event, in the same
+	// transaction so that none is lost if the process stops.
+	if webhookEvent, ok := migrations.WebhookEventFor(event); ok {
+		_, err = tx.Exec(ctx,
+			`INSERT INTO webhook_deliveries (webhook_id, event, migration_id, candidate_id, step_name, status, metadata)
+			 SELECT id, $1::text, $2::text, $3::text, $4::text, $5::text, $6::jsonb
+			 FROM webhooks
+			 WHERE migration_id = $2 AND $1 = ANY(events)`,
+			webhookEvent, event.MigrationID, event.CandidateID, nilIfEmpty(event.StepName),
+			nilIfEmpty(event.Status), metadataJSON,
+		)
+		if err != nil {
+			return fmt.Errorf("queue webhook deliveries: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
 	// Emit OTel metrics based on event type.
 	s.emitMetrics(ctx, event)
 
@@ -241,6 +269,83 @@ func (s *PGEventStore) GetRecentFailures(ctx context.Context, limit int) ([]migr
 	return result, rows.Err()
 }
 
+// GetStuckSteps returns steps whose latest event (step_dispatched or step_pending)
+// is older than before, skipping runs that have since completed or been cancelled.
+// step_timed_out is ignored when finding the latest event: a timed-out step is
+// still waiting, and is reported with the time it entered its current state.
+// callback_ignored is skipped for the same reason.
+func (s *PGEventStore) GetStuckSteps(ctx context.Context, before time.Time) ([]migrations.StuckStep, error) {
+	rows, err := s.pool.Query(ctx, `
+		WITH latest AS (
+			SELECT DISTINCT ON (migration_id, candidate_id, step_name)
+			       migration_id, candidate_id, step_name, event_type, metadata, created_at
+			FROM step_events
+			WHERE step_name IS NOT NULL AND event_type NOT IN ('step_timed_out', 'callback_ignored')
+			ORDER BY migration_id, candidate_id, step_name, created_at DESC, id DESC
+		)
+		SELECT l.migration_id, l.candidate_id, l.step_name, l.event_type, l.metadata, l.created_at
+		FROM latest l
+		WHERE l.event_type IN ('step_dispatched', 'step_pending')
+		  AND l.created_at < $1
+		  AND NOT EXISTS (
+			SELECT 1 FROM step_events r
+			WHERE r.migration_id = l.migration_id
+			  AND r.candidate_id = l.candidate_id
+			  AND r.event_type IN ('run_completed', 'run_cancelled', 'rollback_completed', 'rollback_cancelled')
+			  AND r.created_at >= l.created_at
+		  )
+		ORDER BY l.created_at
+	`, before)
+	if err != nil {
+		return nil, fmt.Errorf("stuck steps query: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]migrations.StuckStep, 0)
+	for rows.Next() {
+		var st migrations.StuckStep
+		var eventType string
+		var metadataJSON []byte
+		if err := rows.Scan(&st.MigrationID, &st.CandidateID, &st.StepName, &eventType, &metadataJSON, &st.Since); err != nil {
+			return nil, fmt.Errorf("scan stuck step: %w", err)
+		}
+		st.Status = "in_progress"
+		if eventType == migrations.EventStepPending {
+			st.Status = "pending"
+		}
+		if metadataJSON != nil {
+			_ = json.Unmarshal(metadataJSON, &st.Metadata)
+		}
+		result = append(result, st)
+	}
+	return result, rows.Err()
+}
+
+// ClaimCallback records eventID as received for runID. Returns false when the
+// run had already received it.
+func (s *PGEventStore) ClaimCallback(ctx context.Context, runID, eventID string) (bool, error) {
+	tag, err := s.pool.Exec(ctx,
+		`INSERT INTO callback_events (run_id, event_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+		runID, eventID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("insert callback_event: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// ReleaseCallback deletes a claimed event ID so it can be claimed again.
+func (s *PGEventStore) ReleaseCallback(ctx context.Context, runID, eventID string) error {
+	_, err := s.pool.Exec(ctx,
+		`DELETE FROM callback_events WHERE run_id = $1 AND event_id = $2`,
+		runID, eventID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete callback_event: %w", err)
+	}
+	return nil
+}
+
 // Compile-time check.
 var _ migrations.EventStore = (*PGEventStore)(nil)
 
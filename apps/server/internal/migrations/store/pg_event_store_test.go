@@ -0,0 +1,108 @@
+package store_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations/store"
+	"github.com/tilsley/loom/apps/server/internal/migrations/store/pgmigrations"
+	pgplatform "github.com/tilsley/loom/apps/server/internal/platform/postgres"
+)
+
+// newPGEventStore creates a PGEventStore backed by a real PostgreSQL instance,
+// returning the pool as well so tests can backdate events. Skips if POSTGRES_URL is not set.
+func newPGEventStore(t *testing.T) (*store.PGEventStore, *pgxpool.Pool) {
+	t.Helper()
+	pgURL := os.Getenv("POSTGRES_URL")
+	if pgURL == "" {
+		t.Skip("POSTGRES_URL not set — skipping Postgres integration tests")
+	}
+	pool, err := pgplatform.New(context.Background(), pgURL, pgmigrations.FS)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := pool.Exec(context.Background(), `DELETE FROM step_events; DELETE FROM callback_events;`)
+		require.NoError(t, err)
+		pool.Close()
+	})
+	return store.NewPGEventStore(pool), pool
+}
+
+// insertEvent writes a step_events row with an explicit created_at.
+func insertEvent(t *testing.T, pool *pgxpool.Pool, candidateID, stepName, eventType string, at time.Time) {
+	t.Helper()
+	var step *string
+	if stepName != "" {
+		step = &stepName
+	}
+	_, err := pool.Exec(context.Background(),
+		`INSERT INTO step_events (migration_id, candidate_id, step_name, event_type, created_at)
+		 VALUES ('mig-a', $1, $2, $3, $4)`,
+		candidateID, step, eventType, at)
+	require.NoError(t, err)
+}
+
+// ─── GetStuckSteps ───────────────────────────────────────────────────────────
+
+func TestPG_GetStuckSteps(t *testing.T) {
+	s, pool := newPGEventStore(t)
+	now := time.Now()
+	old := now.Add(-2 * time.Hour)
+
+	// Dispatched long ago, still waiting; a discarded duplicate callback since does not count.
+	insertEvent(t, pool, "waiting", "open-pr", "step_dispatched", old)
+	insertEvent(t, pool, "waiting", "open-pr", "callback_ignored", now.Add(-time.Minute))
+	// Pending long ago, then timed out: still stuck, reported as pending.
+	insertEvent(t, pool, "timed-out", "open-pr", "step_dispatched", old.Add(-time.Minute))
+	insertEvent(t, pool, "timed-out", "open-pr", "step_pending", old)
+	insertEvent(t, pool, "timed-out", "open-pr", "step_timed_out", now.Add(-time.Hour))
+	// Completed afterwards.
+	insertEvent(t, pool, "done", "open-pr", "step_dispatched", old)
+	insertEvent(t, pool, "done", "open-pr", "step_completed", old.Add(time.Minute))
+	// Run cancelled while the step was waiting.
+	insertEvent(t, pool, "cancelled", "open-pr", "step_dispatched", old)
+	insertEvent(t, pool, "cancelled", "", "run_cancelled", old.Add(time.Minute))
+	// Dispatched recently.
+	insertEvent(t, pool, "recent", "open-pr", "step_dispatched", now.Add(-time.Minute))
+
+	stuck, err := s.GetStuckSteps(context.Background(), now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, stuck, 2)
+
+	byCandidate := map[string]string{}
+	for _, st := range stuck {
+		assert.Equal(t, "mig-a", st.MigrationID)
+		assert.Equal(t, "open-pr", st.StepName)
+		byCandidate[st.CandidateID] = st.Status
+	}
+	assert.Equal(t, map[string]string{"waiting": "in_progress", "timed-out": "pending"}, byCandidate)
+}
+
+// ─── ClaimCallback ───────────────────────────────────────────────────────────
+
+func TestPG_ClaimCallback(t *testing.T) {
+	s, _ := newPGEventStore(t)
+	ctx := context.Background()
+
+	first, err := s.ClaimCallback(ctx, "mig-a__svc", "evt-1")
+	require.NoError(t, err)
+	assert.True(t, first)
+
+	again, err := s.ClaimCallback(ctx, "mig-a__svc", "evt-1")
+	require.NoError(t, err)
+	assert.False(t, again, "same event ID for the same run is a duplicate")
+
+	otherRun, err := s.ClaimCallback(ctx, "mig-a__svc__2", "evt-1")
+	require.NoError(t, err)
+	assert.True(t, otherRun, "event IDs are scoped to the run")
+
+	require.NoError(t, s.ReleaseCallback(ctx, "mig-a__svc", "evt-1"))
+	reclaimed, err := s.ClaimCallback(ctx, "mig-a__svc", "evt-1")
+	require.NoError(t, err)
+	assert.True(t, reclaimed, "a released event ID can be claimed again")
+}
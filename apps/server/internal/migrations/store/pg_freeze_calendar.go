@@ -0,0 +1,121 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+)
+
+// Compile-time check: *PGFreezeCalendar implements migrations.FreezeCalendar.
+var _ migrations.FreezeCalendar = (*PGFreezeCalendar)(nil)
+
+// PGFreezeCalendar implements migrations.FreezeCalendar backed by PostgreSQL.
+type PGFreezeCalendar struct {
+	pool *pgxpool.Pool
+}
+
+// NewPGFreezeCalendar creates a new PGFreezeCalendar with the given connection pool.
+func NewPGFreezeCalendar(pool *pgxpool.Pool) *PGFreezeCalendar {
+	return &PGFreezeCalendar{pool: pool}
+}
+
+const freezeWindowColumns = `id, migration_id, reason, starts_at, ends_at, step_config, created_at`
+
+// CreateFreezeWindow inserts w and sets its ID and CreatedAt.
+func (s *PGFreezeCalendar) CreateFreezeWindow(ctx context.Context, w *migrations.FreezeWindow) error {
+	var stepConfigJSON []byte
+	if len(w.StepConfig) > 0 {
+		var err error
+		stepConfigJSON, err = json.Marshal(w.StepConfig)
+		if err != nil {
+			return fmt.Errorf("marshal step config: %w", err)
+		}
+	}
+	err := s.pool.QueryRow(ctx,
+		`INSERT INTO freeze_windows (migration_id, reason, starts_at, ends_at, step_config)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING id, created_at`,
+		nilIfEmpty(w.MigrationID), w.Reason, w.StartsAt, w.EndsAt, stepConfigJSON,
+	).Scan(&w.ID, &w.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert freeze window: %w", err)
+	}
+	return nil
+}
+
+// ListFreezeWindows returns the windows matching filter, ordered by start time.
+func (s *PGFreezeCalendar) ListFreezeWindows(
+	ctx context.Context,
+	filter migrations.FreezeWindowFilter,
+) ([]migrations.FreezeWindow, error) {
+	var endsAfter *time.Time
+	if !filter.EndsAfter.IsZero() {
+		endsAfter = &filter.EndsAfter
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+freezeWindowColumns+`
+		FROM freeze_windows
+		WHERE ($1 = '' OR migration_id IS NULL OR migration_id = $1)
+		  AND ($2::timestamptz IS NULL OR ends_at > $2)
+		ORDER BY starts_at, id
+	`, filter.MigrationID, endsAfter)
+	if err != nil {
+		return nil, fmt.Errorf("freeze windows query: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]migrations.FreezeWindow, 0)
+	for rows.Next() {
+		w, err := scanFreezeWindow(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, w)
+	}
+	return result, rows.Err()
+}
+
+// GetFreezeWindow returns the window, or nil when it does not exist.
+func (s *PGFreezeCalendar) GetFreezeWindow(ctx context.Context, id int64) (*migrations.FreezeWindow, error) {
+	w, err := scanFreezeWindow(s.pool.QueryRow(ctx,
+		`SELECT `+freezeWindowColumns+` FROM freeze_windows WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil //nolint:nilnil
+		}
+		return nil, err
+	}
+	return &w, nil
+}
+
+// DeleteFreezeWindow removes the window, reporting whether it existed.
+func (s *PGFreezeCalendar) DeleteFreezeWindow(ctx context.Context, id int64) (bool, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM freeze_windows WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("delete freeze window: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func scanFreezeWindow(row pgx.Row) (migrations.FreezeWindow, error) {
+	var w migrations.FreezeWindow
+	var migrationID *string
+	var stepConfigJSON []byte
+	if err := row.Scan(&w.ID, &migrationID, &w.Reason, &w.StartsAt, &w.EndsAt, &stepConfigJSON, &w.CreatedAt); err != nil {
+		return w, fmt.Errorf("scan freeze window: %w", err)
+	}
+	if migrationID != nil {
+		w.MigrationID = *migrationID
+	}
+	if stepConfigJSON != nil {
+		_ = json.Unmarshal(stepConfigJSON, &w.StepConfig)
+	}
+	return w, nil
+}
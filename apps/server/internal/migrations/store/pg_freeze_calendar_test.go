@@ -0,0 +1,97 @@
+package store_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/migrations/store"
+	"github.com/tilsley/loom/apps/server/internal/migrations/store/pgmigrations"
+	pgplatform "github.com/tilsley/loom/apps/server/internal/platform/postgres"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// newPGFreezeCalendar creates a PGFreezeCalendar backed by a real PostgreSQL
+// instance, with a migration to scope windows to. Skips if POSTGRES_URL is not
+// set.
+func newPGFreezeCalendar(t *testing.T) *store.PGFreezeCalendar {
+	t.Helper()
+	pgURL := os.Getenv("POSTGRES_URL")
+	if pgURL == "" {
+		t.Skip("POSTGRES_URL not set — skipping Postgres integration tests")
+	}
+	pool, err := pgplatform.New(context.Background(), pgURL, pgmigrations.FS)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := pool.Exec(context.Background(), `DELETE FROM freeze_windows; DELETE FROM migrations;`)
+		require.NoError(t, err)
+		pool.Close()
+	})
+	for _, id := range []string{"mig-a", "mig-b"} {
+		require.NoError(t, store.NewPGMigrationStore(pool).Save(context.Background(), api.Migration{Id: id}))
+	}
+	return store.NewPGFreezeCalendar(pool)
+}
+
+func TestPG_FreezeCalendar_CRUD(t *testing.T) {
+	s := newPGFreezeCalendar(t)
+	ctx := context.Background()
+	start := time.Now().Truncate(time.Second)
+
+	w := migrations.FreezeWindow{
+		MigrationID: "mig-a", Reason: "prod freeze", StartsAt: start, EndsAt: start.Add(time.Hour),
+		StepConfig: map[string]string{"env": "prod"},
+	}
+	require.NoError(t, s.CreateFreezeWindow(ctx, &w))
+	assert.NotZero(t, w.ID)
+
+	got, err := s.GetFreezeWindow(ctx, w.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "mig-a", got.MigrationID)
+	assert.Equal(t, w.StepConfig, got.StepConfig)
+	assert.True(t, got.EndsAt.Equal(w.EndsAt))
+
+	deleted, err := s.DeleteFreezeWindow(ctx, w.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	got, err = s.GetFreezeWindow(ctx, w.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+	deleted, err = s.DeleteFreezeWindow(ctx, w.ID)
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
+
+func TestPG_FreezeCalendar_ListFilters(t *testing.T) {
+	s := newPGFreezeCalendar(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	windows := []migrations.FreezeWindow{
+		{Reason: "global", StartsAt: now, EndsAt: now.Add(time.Hour)},
+		{MigrationID: "mig-a", Reason: "a", StartsAt: now.Add(time.Minute), EndsAt: now.Add(time.Hour)},
+		{MigrationID: "mig-b", Reason: "b", StartsAt: now.Add(2 * time.Minute), EndsAt: now.Add(time.Hour)},
+		{Reason: "over", StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour)},
+	}
+	for i := range windows {
+		require.NoError(t, s.CreateFreezeWindow(ctx, &windows[i]))
+	}
+	reasons := func(filter migrations.FreezeWindowFilter) []string {
+		list, err := s.ListFreezeWindows(ctx, filter)
+		require.NoError(t, err)
+		out := []string{}
+		for _, w := range list {
+			out = append(out, w.Reason)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"over", "global", "a", "b"}, reasons(migrations.FreezeWindowFilter{}))
+	assert.Equal(t, []string{"global", "a"}, reasons(migrations.FreezeWindowFilter{MigrationID: "mig-a", EndsAfter: now}))
+}
@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+)
+
+// Compile-time check: *PGLeaderLock implements migrations.LeaderLock.
+var _ migrations.LeaderLock = (*PGLeaderLock)(nil)
+
+// PGLeaderLock implements migrations.LeaderLock with a session-level Postgres
+// advisory lock keyed by name. The lock belongs to the connection that took
+// it, so that connection is taken out of the pool while the lock is held; if
+// the connection drops, Postgres releases the lock and another replica can
+// take over.
+type PGLeaderLock struct {
+	pool *pgxpool.Pool
+	name string
+
+	mu   sync.Mutex
+	conn *pgx.Conn // holds the lock; nil while not leading
+}
+
+// NewPGLeaderLock creates a PGLeaderLock for the lock called name.
+func NewPGLeaderLock(pool *pgxpool.Pool, name string) *PGLeaderLock {
+	return &PGLeaderLock{pool: pool, name: name}
+}
+
+// TryLead reports whether this replica holds the lock, taking it when it is
+// free. A held lock is confirmed by pinging its connection.
+func (l *PGLeaderLock) TryLead(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		if err := l.conn.Ping(ctx); err == nil {
+			return true, nil
+		}
+		// The session, and the lock with it, may be gone.
+		_ = l.conn.Close(ctx)
+		l.conn = nil
+	}
+
+	pc, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return false, fmt.Errorf("acquire connection: %w", err)
+	}
+	var acquired bool
+	if err := pc.QueryRow(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, l.name).Scan(&acquired); err != nil {
+		pc.Release()
+		return false, fmt.Errorf("try advisory lock %q: %w", l.name, err)
+	}
+	if !acquired {
+		pc.Release()
+		return false, nil
+	}
+	l.conn = pc.Hijack()
+	return true, nil
+}
+
+// Release gives up the lock by closing the connection that holds it.
+func (l *PGLeaderLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		return nil
+	}
+	err := l.conn.Close(ctx)
+	l.conn = nil
+	if err != nil {
+		return fmt.Errorf("close lock connection: %w", err)
+	}
+	return nil
+}
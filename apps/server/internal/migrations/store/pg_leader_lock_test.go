@@ -0,0 +1,46 @@
+package store_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations/store"
+	"github.com/tilsley/loom/apps/server/internal/migrations/store/pgmigrations"
+	pgplatform "github.com/tilsley/loom/apps/server/internal/platform/postgres"
+)
+
+func TestPG_LeaderLock_OneLeaderAtATime(t *testing.T) {
+	pgURL := os.Getenv("POSTGRES_URL")
+	if pgURL == "" {
+		t.Skip("POSTGRES_URL not set — skipping Postgres integration tests")
+	}
+	ctx := context.Background()
+	pool, err := pgplatform.New(ctx, pgURL, pgmigrations.FS)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	a := store.NewPGLeaderLock(pool, "test-leader-lock")
+	b := store.NewPGLeaderLock(pool, "test-leader-lock")
+	t.Cleanup(func() {
+		_ = a.Release(ctx)
+		_ = b.Release(ctx)
+	})
+
+	lead, err := a.TryLead(ctx)
+	require.NoError(t, err)
+	assert.True(t, lead)
+	lead, err = b.TryLead(ctx)
+	require.NoError(t, err)
+	assert.False(t, lead, "the lock is held by a")
+	lead, err = a.TryLead(ctx)
+	require.NoError(t, err)
+	assert.True(t, lead, "a keeps the lock")
+
+	require.NoError(t, a.Release(ctx))
+	lead, err = b.TryLead(ctx)
+	require.NoError(t, err)
+	assert.True(t, lead, "b takes over once a releases")
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/jackc/pgx/v5"
@@ -53,7 +54,7 @@ func (s *PGMigrationStore) Save(ctx context.Context, m api.Migration) error {
 // Get retrieves a migration by ID with its candidates. Returns nil, nil if not found.
 func (s *PGMigrationStore) Get(ctx context.Context, id string) (*api.Migration, error) {
 	row := s.pool.QueryRow(ctx,
-		`SELECT id, name, description, migrator_url, overview, required_inputs, steps, created_at
+		`SELECT id, name, description, migrator_url, overview, required_inputs, steps, version, created_at
 		 FROM migrations WHERE id = $1`, id)
 
 	m, err := scanMigration(row)
@@ -75,7 +76,7 @@ func (s *PGMigrationStore) Get(ctx context.Context, id string) (*api.Migration,
 // List returns all migrations with their candidates.
 func (s *PGMigrationStore) List(ctx context.Context) ([]api.Migration, error) {
 	rows, err := s.pool.Query(ctx,
-		`SELECT id, name, description, migrator_url, overview, required_inputs, steps, created_at
+		`SELECT id, name, description, migrator_url, overview, required_inputs, steps, version, created_at
 		 FROM migrations ORDER BY created_at`)
 	if err != nil {
 		return nil, fmt.Errorf("list migrations: %w", err)
@@ -245,6 +246,153 @@ func (s *PGMigrationStore) UpdateCandidateMetadata(
 	return nil
 }
 
+// SaveVersion inserts an immutable version of a migration's definition.
+// Saving a version number that already exists is an error.
+func (s *PGMigrationStore) SaveVersion(ctx context.Context, v api.MigrationVersion) error {
+	overviewJSON, err := jsonMarshalNullable(v.Overview)
+	if err != nil {
+		return fmt.Errorf("marshal overview: %w", err)
+	}
+	requiredInputsJSON, err := jsonMarshalNullable(v.RequiredInputs)
+	if err != nil {
+		return fmt.Errorf("marshal required_inputs: %w", err)
+	}
+	stepsJSON, err := json.Marshal(v.Steps)
+	if err != nil {
+		return fmt.Errorf("marshal steps: %w", err)
+	}
+
+	_, err = s.pool.Exec(ctx, `
+		INSERT INTO migration_versions
+			(migration_id, version, steps_hash, name, description, migrator_url, overview, required_inputs, steps, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		v.MigrationId, v.Version, v.StepsHash, v.Name, v.Description, v.MigratorUrl,
+		overviewJSON, requiredInputsJSON, stepsJSON, v.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert version %d of %q: %w", v.Version, v.MigrationId, err)
+	}
+	return nil
+}
+
+// ListVersions returns every version of a migration's definition, oldest first.
+func (s *PGMigrationStore) ListVersions(ctx context.Context, migrationID string) ([]api.MigrationVersion, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT migration_id, version, steps_hash, name, description, migrator_url,
+		        overview, required_inputs, steps, created_at
+		 FROM migration_versions WHERE migration_id = $1 ORDER BY version`, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("list versions: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []api.MigrationVersion
+	for rows.Next() {
+		v, err := scanVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, *v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("scan versions: %w", err)
+	}
+	return versions, nil
+}
+
+// GetVersion returns one version of a migration's definition. Returns nil, nil if not found.
+func (s *PGMigrationStore) GetVersion(
+	ctx context.Context,
+	migrationID string,
+	version int,
+) (*api.MigrationVersion, error) {
+	row := s.pool.QueryRow(ctx,
+		`SELECT migration_id, version, steps_hash, name, description, migrator_url,
+		        overview, required_inputs, steps, created_at
+		 FROM migration_versions WHERE migration_id = $1 AND version = $2`, migrationID, version)
+	return scanVersion(row)
+}
+
+// CreateRun inserts a new run attempt. Attempt numbers are unique per candidate,
+// so two concurrent starts of the same candidate cannot both succeed.
+func (s *PGMigrationStore) CreateRun(ctx context.Context, r api.RunAttempt) error {
+	stepsJSON, err := jsonMarshalNullable(r.Steps)
+	if err != nil {
+		return fmt.Errorf("marshal steps: %w", err)
+	}
+	_, err = s.pool.Exec(ctx, `
+		INSERT INTO runs (run_id, migration_id, candidate_id, attempt, type, status, version, steps, started_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		r.RunId, r.MigrationId, r.CandidateId, r.Attempt, string(r.Type), string(r.Status),
+		r.Version, stepsJSON, r.StartedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert run %q: %w", r.RunId, err)
+	}
+	return nil
+}
+
+// FinishRun sets a run attempt's outcome and final step results. Nil steps
+// keep whatever was stored. Runs started before attempts were recorded have no
+// row and are ignored.
+func (s *PGMigrationStore) FinishRun(
+	ctx context.Context,
+	runID string,
+	status api.RunAttemptStatus,
+	steps []api.StepState,
+) error {
+	var stepsJSON []byte
+	if steps != nil {
+		var err error
+		if stepsJSON, err = json.Marshal(steps); err != nil {
+			return fmt.Errorf("marshal steps: %w", err)
+		}
+	}
+	_, err := s.pool.Exec(ctx,
+		`UPDATE runs SET status = $1, steps = COALESCE($2, steps), finished_at = NOW() WHERE run_id = $3`,
+		string(status), stepsJSON, runID)
+	if err != nil {
+		return fmt.Errorf("finish run %q: %w", runID, err)
+	}
+	return nil
+}
+
+// ListRuns returns a candidate's run attempts, newest first.
+func (s *PGMigrationStore) ListRuns(ctx context.Context, migrationID, candidateID string) ([]api.RunAttempt, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT run_id, migration_id, candidate_id, attempt, type, status, version, steps, started_at, finished_at
+		 FROM runs WHERE migration_id = $1 AND candidate_id = $2 ORDER BY attempt DESC`,
+		migrationID, candidateID)
+	if err != nil {
+		return nil, fmt.Errorf("list runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []api.RunAttempt
+	for rows.Next() {
+		var r api.RunAttempt
+		var runType, status string
+		var stepsJSON []byte
+		if err := rows.Scan(&r.RunId, &r.MigrationId, &r.CandidateId, &r.Attempt, &runType, &status,
+			&r.Version, &stepsJSON, &r.StartedAt, &r.FinishedAt); err != nil {
+			return nil, fmt.Errorf("scan run: %w", err)
+		}
+		r.Type = api.RunAttemptType(runType)
+		r.Status = api.RunAttemptStatus(status)
+		if stepsJSON != nil {
+			r.Steps = new([]api.StepState)
+			if err := json.Unmarshal(stepsJSON, r.Steps); err != nil {
+				return nil, fmt.Errorf("unmarshal steps: %w", err)
+			}
+		}
+		runs = append(runs, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("scan runs: %w", err)
+	}
+	return runs, nil
+}
+
 // ── helpers ──────────────────────────────────────────────────────────────────
 
 // pgScanner is implemented by both *pgxpool.Row and pgx.Rows.
@@ -267,17 +415,18 @@ func upsertMigration(ctx context.Context, tx pgx.Tx, m api.Migration) error {
 	}
 
 	_, err = tx.Exec(ctx, `
-		INSERT INTO migrations (id, name, description, migrator_url, overview, required_inputs, steps, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO migrations (id, name, description, migrator_url, overview, required_inputs, steps, version, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		ON CONFLICT (id) DO UPDATE SET
 			name            = EXCLUDED.name,
 			description     = EXCLUDED.description,
 			migrator_url    = EXCLUDED.migrator_url,
 			overview        = EXCLUDED.overview,
 			required_inputs = EXCLUDED.required_inputs,
-			steps           = EXCLUDED.steps`,
+			steps           = EXCLUDED.steps,
+			version         = EXCLUDED.version`,
 		m.Id, m.Name, m.Description, m.MigratorUrl,
-		overviewJSON, requiredInputsJSON, stepsJSON, m.CreatedAt,
+		overviewJSON, requiredInputsJSON, stepsJSON, m.Version, m.CreatedAt,
 	)
 	return err
 }
@@ -322,7 +471,7 @@ func scanMigration(row pgScanner) (*api.Migration, error) {
 	var overviewJSON, requiredInputsJSON, stepsJSON []byte
 
 	err := row.Scan(&m.Id, &m.Name, &m.Description, &m.MigratorUrl,
-		&overviewJSON, &requiredInputsJSON, &stepsJSON, &m.CreatedAt)
+		&overviewJSON, &requiredInputsJSON, &stepsJSON, &m.Version, &m.CreatedAt)
 	if err != nil {
 		if err.Error() == "no rows in result set" {
 			return nil, nil //nolint:nilnil
@@ -351,6 +500,38 @@ func scanMigration(row pgScanner) (*api.Migration, error) {
 	return &m, nil
 }
 
+func scanVersion(row pgScanner) (*api.MigrationVersion, error) {
+	var v api.MigrationVersion
+	var overviewJSON, requiredInputsJSON, stepsJSON []byte
+
+	err := row.Scan(&v.MigrationId, &v.Version, &v.StepsHash, &v.Name, &v.Description, &v.MigratorUrl,
+		&overviewJSON, &requiredInputsJSON, &stepsJSON, &v.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil //nolint:nilnil
+		}
+		return nil, fmt.Errorf("scan version: %w", err)
+	}
+
+	if overviewJSON != nil {
+		v.Overview = new([]string)
+		if err := json.Unmarshal(overviewJSON, v.Overview); err != nil {
+			return nil, fmt.Errorf("unmarshal overview: %w", err)
+		}
+	}
+	if requiredInputsJSON != nil {
+		v.RequiredInputs = new([]api.InputDefinition)
+		if err := json.Unmarshal(requiredInputsJSON, v.RequiredInputs); err != nil {
+			return nil, fmt.Errorf("unmarshal required_inputs: %w", err)
+		}
+	}
+	if err := json.Unmarshal(stepsJSON, &v.Steps); err != nil {
+		return nil, fmt.Errorf("unmarshal steps: %w", err)
+	}
+
+	return &v, nil
+}
+
 func scanCandidate(row pgScanner) (api.Candidate, string, error) {
 	var c api.Candidate
 	var migrationID, status string
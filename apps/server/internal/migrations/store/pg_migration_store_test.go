@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/tilsley/loom/apps/server/internal/migrations"
 	"github.com/tilsley/loom/apps/server/internal/migrations/store"
 	"github.com/tilsley/loom/apps/server/internal/migrations/store/pgmigrations"
 	pgplatform "github.com/tilsley/loom/apps/server/internal/platform/postgres"
@@ -35,7 +36,7 @@ func newPGStore(t *testing.T) *store.PGMigrationStore {
 
 func cleanupPGStore(t *testing.T, pool *pgxpool.Pool) {
 	t.Helper()
-	_, err := pool.Exec(context.Background(), `DELETE FROM candidates; DELETE FROM migrations;`)
+	_, err := pool.Exec(context.Background(), `DELETE FROM runs; DELETE FROM candidates; DELETE FROM migration_versions; DELETE FROM migrations;`)
 	require.NoError(t, err)
 }
 
@@ -367,3 +368,123 @@ func TestPG_UpdateCandidateMetadata_NotFound(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+// ─── Versions ────────────────────────────────────────────────────────────────
+
+func TestPG_SaveVersion_ListAndGet(t *testing.T) {
+	s := newPGStore(t)
+	ctx := context.Background()
+	m := pgBaseMigration
+	require.NoError(t, s.Save(ctx, m))
+
+	v1 := migrations.VersionOf(m, 1, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	m.Steps = append(m.Steps, api.StepDefinition{Name: "open-pr", MigratorApp: "app-chart-migrator"})
+	v2 := migrations.VersionOf(m, 2, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, s.SaveVersion(ctx, v2))
+	require.NoError(t, s.SaveVersion(ctx, v1))
+
+	versions, err := s.ListVersions(ctx, m.Id)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, 1, versions[0].Version)
+	assert.Equal(t, v1.StepsHash, versions[0].StepsHash)
+	assert.Len(t, versions[1].Steps, 2)
+
+	got, err := s.GetVersion(ctx, m.Id, 2)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, v2.StepsHash, got.StepsHash)
+}
+
+func TestPG_SaveVersion_RejectsDuplicateVersion(t *testing.T) {
+	s := newPGStore(t)
+	ctx := context.Background()
+	m := pgBaseMigration
+	require.NoError(t, s.Save(ctx, m))
+
+	v := migrations.VersionOf(m, 1, time.Now().UTC())
+	require.NoError(t, s.SaveVersion(ctx, v))
+	assert.Error(t, s.SaveVersion(ctx, v))
+}
+
+func TestPG_GetVersion_NotFound_ReturnsNil(t *testing.T) {
+	s := newPGStore(t)
+	require.NoError(t, s.Save(context.Background(), pgBaseMigration))
+
+	got, err := s.GetVersion(context.Background(), pgBaseMigration.Id, 1)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestPG_SaveGet_PersistsVersion(t *testing.T) {
+	s := newPGStore(t)
+	m := pgBaseMigration
+	version := 4
+	m.Version = &version
+	require.NoError(t, s.Save(context.Background(), m))
+
+	got, err := s.Get(context.Background(), m.Id)
+	require.NoError(t, err)
+	require.NotNil(t, got.Version)
+	assert.Equal(t, 4, *got.Version)
+}
+
+// ─── Runs ────────────────────────────────────────────────────────────────────
+
+func TestPG_CreateRun_ListNewestFirst(t *testing.T) {
+	s := newPGStore(t)
+	ctx := context.Background()
+	require.NoError(t, s.Save(ctx, pgBaseMigration))
+
+	first := migrations.NextRunAttempt(nil, pgBaseMigration.Id, "billing-api",
+		api.RunAttemptTypeMigration, nil, time.Now().UTC())
+	require.NoError(t, s.CreateRun(ctx, first))
+	second := migrations.NextRunAttempt([]api.RunAttempt{first}, pgBaseMigration.Id, "billing-api",
+		api.RunAttemptTypeMigration, nil, time.Now().UTC())
+	require.NoError(t, s.CreateRun(ctx, second))
+
+	runs, err := s.ListRuns(ctx, pgBaseMigration.Id, "billing-api")
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, 2, runs[0].Attempt)
+	assert.Equal(t, "app-chart-migration__billing-api__2", runs[0].RunId)
+	assert.Equal(t, 1, runs[1].Attempt)
+	assert.Equal(t, api.RunAttemptStatusRunning, runs[1].Status)
+}
+
+func TestPG_CreateRun_RejectsDuplicateAttempt(t *testing.T) {
+	s := newPGStore(t)
+	ctx := context.Background()
+	require.NoError(t, s.Save(ctx, pgBaseMigration))
+
+	r := migrations.NextRunAttempt(nil, pgBaseMigration.Id, "billing-api",
+		api.RunAttemptTypeMigration, nil, time.Now().UTC())
+	require.NoError(t, s.CreateRun(ctx, r))
+	assert.Error(t, s.CreateRun(ctx, r))
+}
+
+func TestPG_FinishRun_RecordsOutcomeAndSteps(t *testing.T) {
+	s := newPGStore(t)
+	ctx := context.Background()
+	require.NoError(t, s.Save(ctx, pgBaseMigration))
+	r := migrations.NextRunAttempt(nil, pgBaseMigration.Id, "billing-api",
+		api.RunAttemptTypeMigration, nil, time.Now().UTC())
+	require.NoError(t, s.CreateRun(ctx, r))
+
+	steps := []api.StepState{{StepName: "update-chart", Status: api.StepStateStatusSucceeded}}
+	require.NoError(t, s.FinishRun(ctx, r.RunId, api.RunAttemptStatusCompleted, steps))
+
+	runs, err := s.ListRuns(ctx, pgBaseMigration.Id, "billing-api")
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, api.RunAttemptStatusCompleted, runs[0].Status)
+	assert.NotNil(t, runs[0].FinishedAt)
+	require.NotNil(t, runs[0].Steps)
+	assert.Equal(t, "update-chart", (*runs[0].Steps)[0].StepName)
+}
+
+func TestPG_FinishRun_UnknownRunIsNoop(t *testing.T) {
+	s := newPGStore(t)
+	err := s.FinishRun(context.Background(), "missing", api.RunAttemptStatusFailed, nil)
+	assert.NoError(t, err)
+}
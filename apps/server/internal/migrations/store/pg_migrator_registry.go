@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// Compile-time check: *PGMigratorRegistry implements migrations.MigratorRegistry.
+var _ migrations.MigratorRegistry = (*PGMigratorRegistry)(nil)
+
+// PGMigratorRegistry implements migrations.MigratorRegistry backed by PostgreSQL.
+type PGMigratorRegistry struct {
+	pool *pgxpool.Pool
+}
+
+// NewPGMigratorRegistry creates a new PGMigratorRegistry with the given connection pool.
+func NewPGMigratorRegistry(pool *pgxpool.Pool) *PGMigratorRegistry {
+	return &PGMigratorRegistry{pool: pool}
+}
+
+const migratorColumns = `migrator_app, url, health_url, registered_at`
+
+// RegisterMigrator upserts the app's registration.
+func (r *PGMigratorRegistry) RegisterMigrator(ctx context.Context, m api.RegisteredMigrator) error {
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO migrators (`+migratorColumns+`)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (migrator_app) DO UPDATE
+		 SET url = EXCLUDED.url, health_url = EXCLUDED.health_url, registered_at = EXCLUDED.registered_at`,
+		m.MigratorApp, m.Url, m.HealthUrl, m.RegisteredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert migrator: %w", err)
+	}
+	return nil
+}
+
+// GetMigrator returns the app's registration, or nil when it has not registered.
+func (r *PGMigratorRegistry) GetMigrator(ctx context.Context, migratorApp string) (*api.RegisteredMigrator, error) {
+	var m api.RegisteredMigrator
+	err := r.pool.QueryRow(ctx,
+		`SELECT `+migratorColumns+` FROM migrators WHERE migrator_app = $1`, migratorApp,
+	).Scan(&m.MigratorApp, &m.Url, &m.HealthUrl, &m.RegisteredAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil //nolint:nilnil
+		}
+		return nil, fmt.Errorf("get migrator: %w", err)
+	}
+	return &m, nil
+}
+
+// ListMigrators returns every registration ordered by app-id.
+func (r *PGMigratorRegistry) ListMigrators(ctx context.Context) ([]api.RegisteredMigrator, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+migratorColumns+` FROM migrators ORDER BY migrator_app`)
+	if err != nil {
+		return nil, fmt.Errorf("migrators query: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]api.RegisteredMigrator, 0)
+	for rows.Next() {
+		var m api.RegisteredMigrator
+		if err := rows.Scan(&m.MigratorApp, &m.Url, &m.HealthUrl, &m.RegisteredAt); err != nil {
+			return nil, fmt.Errorf("scan migrator: %w", err)
+		}
+		result = append(result, m)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,70 @@
+package store_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations/store"
+	"github.com/tilsley/loom/apps/server/internal/migrations/store/pgmigrations"
+	pgplatform "github.com/tilsley/loom/apps/server/internal/platform/postgres"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// newPGMigratorRegistry creates a PGMigratorRegistry backed by a real
+// PostgreSQL instance. Skips if POSTGRES_URL is not set.
+func newPGMigratorRegistry(t *testing.T) *store.PGMigratorRegistry {
+	t.Helper()
+	pgURL := os.Getenv("POSTGRES_URL")
+	if pgURL == "" {
+		t.Skip("POSTGRES_URL not set — skipping Postgres integration tests")
+	}
+	pool, err := pgplatform.New(context.Background(), pgURL, pgmigrations.FS)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := pool.Exec(context.Background(), `DELETE FROM migrators`)
+		require.NoError(t, err)
+		pool.Close()
+	})
+	return store.NewPGMigratorRegistry(pool)
+}
+
+func TestPG_MigratorRegistry_RegisterGetList(t *testing.T) {
+	r := newPGMigratorRegistry(t)
+	ctx := context.Background()
+	at := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	missing, err := r.GetMigrator(ctx, "secrets-migrator")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	require.NoError(t, r.RegisterMigrator(ctx, api.RegisteredMigrator{
+		MigratorApp: "secrets-migrator", Url: "http://secrets:3002", HealthUrl: "http://secrets:3002/health",
+		RegisteredAt: at,
+	}))
+	require.NoError(t, r.RegisterMigrator(ctx, api.RegisteredMigrator{
+		MigratorApp: "app-chart-migrator", Url: "http://charts:3001", HealthUrl: "http://charts:3001/health",
+		RegisteredAt: at,
+	}))
+	require.NoError(t, r.RegisterMigrator(ctx, api.RegisteredMigrator{
+		MigratorApp: "secrets-migrator", Url: "http://secrets-v2:3002", HealthUrl: "http://secrets-v2:9000/healthz",
+		RegisteredAt: at.Add(time.Hour),
+	}))
+
+	got, err := r.GetMigrator(ctx, "secrets-migrator")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "http://secrets-v2:3002", got.Url, "registering again replaces the URLs")
+	assert.Equal(t, "http://secrets-v2:9000/healthz", got.HealthUrl)
+	assert.True(t, got.RegisteredAt.Equal(at.Add(time.Hour)))
+
+	all, err := r.ListMigrators(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "app-chart-migrator", all[0].MigratorApp)
+	assert.Equal(t, "secrets-migrator", all[1].MigratorApp)
+}
@@ -0,0 +1,358 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+)
+
+// Compile-time check: *PGProgressFeed implements migrations.ProgressFeed.
+var _ migrations.ProgressFeed = (*PGProgressFeed)(nil)
+
+const (
+	// progressChannel is the NOTIFY channel new progress_events rows are announced on.
+	progressChannel = "loom_progress"
+	// subscriberBuffer is how many events a subscriber may fall behind by
+	// before it is dropped.
+	subscriberBuffer = 64
+	// backlogPage is how many events a resuming subscriber's backlog is read in
+	// at a time.
+	backlogPage = 500
+	// relistenDelay is how long Run waits before reconnecting a dropped listener.
+	relistenDelay = time.Second
+
+	// ProgressRetention is how long progress events are kept for resuming
+	// subscribers. A subscriber resuming from before that is told to resync.
+	ProgressRetention = 7 * 24 * time.Hour
+	pruneInterval     = time.Hour
+)
+
+// PGProgressFeed implements migrations.ProgressFeed with Postgres
+// LISTEN/NOTIFY. Triggers copy step events and candidate status changes into
+// progress_events and announce each row, so events recorded by any server
+// replica reach subscribers on every replica. Run must be running for
+// subscribers to receive new events.
+type PGProgressFeed struct {
+	pool *pgxpool.Pool
+	log  *slog.Logger
+
+	mu   sync.Mutex
+	subs map[*progressSub]struct{}
+}
+
+type progressSub struct {
+	filter migrations.ProgressFilter
+	ch     chan migrations.ProgressEvent
+}
+
+func (s *progressSub) wants(migrationID, candidateID string) bool {
+	return s.filter.MigrationID == migrationID &&
+		(s.filter.CandidateID == "" || s.filter.CandidateID == candidateID)
+}
+
+// NewPGProgressFeed creates a new PGProgressFeed with the given connection pool.
+func NewPGProgressFeed(pool *pgxpool.Pool, log *slog.Logger) *PGProgressFeed {
+	return &PGProgressFeed{pool: pool, log: log, subs: make(map[*progressSub]struct{})}
+}
+
+// Run listens for new progress events and hands them to subscribers until ctx
+// ends. When the listening connection drops, every subscription is closed so
+// that clients resume from their last event ID, and Run listens again. Every
+// hour it also prunes events older than ProgressRetention.
+func (f *PGProgressFeed) Run(ctx context.Context) {
+	pruned := make(chan struct{})
+	go func() {
+		defer close(pruned)
+		f.pruneEvery(ctx)
+	}()
+	defer func() { <-pruned }()
+
+	for {
+		err := f.listen(ctx)
+		f.closeAll()
+		if ctx.Err() != nil {
+			return
+		}
+		f.log.Warn("progress listener disconnected, reconnecting", "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(relistenDelay):
+		}
+	}
+}
+
+func (f *PGProgressFeed) listen(ctx context.Context) error {
+	conn, err := f.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire listener connection: %w", err)
+	}
+	// The connection keeps listening, so it must not go back to the pool.
+	pgConn := conn.Hijack()
+	defer pgConn.Close(context.Background()) //nolint:errcheck
+
+	if _, err := pgConn.Exec(ctx, "LISTEN "+progressChannel); err != nil {
+		return fmt.Errorf("listen %s: %w", progressChannel, err)
+	}
+	for {
+		n, err := pgConn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("wait for notification: %w", err)
+		}
+		f.notify(ctx, n.Payload)
+	}
+}
+
+func (f *PGProgressFeed) pruneEvery(ctx context.Context) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+	for {
+		n, err := f.Prune(ctx, time.Now().Add(-ProgressRetention))
+		switch {
+		case err != nil && ctx.Err() == nil:
+			f.log.Error("prune progress events failed", "error", err)
+		case n > 0:
+			f.log.Info("pruned progress events", "count", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Prune deletes the progress events recorded before cutoff and returns how
+// many it deleted. The highest deleted ID is kept so that subscribers resuming
+// from before it can be told to resync.
+func (f *PGProgressFeed) Prune(ctx context.Context, cutoff time.Time) (int64, error) {
+	var n int64
+	err := f.pool.QueryRow(ctx, `
+		WITH pruned AS (
+			DELETE FROM progress_events WHERE created_at < $1 RETURNING id
+		), mark AS (
+			UPDATE progress_retention
+			SET pruned_through = GREATEST(pruned_through, (SELECT MAX(id) FROM pruned))
+		)
+		SELECT COUNT(*) FROM pruned`, cutoff).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("prune progress events: %w", err)
+	}
+	return n, nil
+}
+
+// notify loads the announced event and passes it to the subscribers that want it.
+func (f *PGProgressFeed) notify(ctx context.Context, payload string) {
+	var note struct {
+		ID          int64  `json:"id"`
+		MigrationID string `json:"migrationId"`
+		CandidateID string `json:"candidateId"`
+	}
+	if err := json.Unmarshal([]byte(payload), &note); err != nil {
+		f.log.Warn("ignoring malformed progress notification", "payload", payload, "error", err)
+		return
+	}
+	if !f.wanted(note.MigrationID, note.CandidateID) {
+		return
+	}
+	events, err := f.query(ctx, `WHERE id = $1`, note.ID)
+	if err != nil || len(events) == 0 {
+		f.log.Error("load progress event failed", "id", note.ID, "error", err)
+		return
+	}
+	f.publish(events[0])
+}
+
+func (f *PGProgressFeed) wanted(migrationID, candidateID string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for s := range f.subs {
+		if s.wants(migrationID, candidateID) {
+			return true
+		}
+	}
+	return false
+}
+
+// publish hands e to each interested subscriber. A subscriber whose buffer is
+// full is dropped rather than left to stall the listener.
+func (f *PGProgressFeed) publish(e migrations.ProgressEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for s := range f.subs {
+		if !s.wants(e.MigrationID, e.CandidateID) {
+			continue
+		}
+		select {
+		case s.ch <- e:
+		default:
+			f.log.Warn("dropping slow progress subscriber", "migrationId", s.filter.MigrationID)
+			delete(f.subs, s)
+			close(s.ch)
+		}
+	}
+}
+
+func (f *PGProgressFeed) remove(s *progressSub) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.subs[s]; ok {
+		delete(f.subs, s)
+		close(s.ch)
+	}
+}
+
+func (f *PGProgressFeed) closeAll() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for s := range f.subs {
+		delete(f.subs, s)
+		close(s.ch)
+	}
+}
+
+// Subscribe replays the matching events recorded after afterID, a page at a
+// time, then delivers new ones until ctx ends or the subscriber is dropped.
+// When events after afterID have been pruned, it first sends an
+// EventProgressResync event and replays from the last pruned ID instead.
+func (f *PGProgressFeed) Subscribe(
+	ctx context.Context,
+	filter migrations.ProgressFilter,
+	afterID int64,
+) (<-chan migrations.ProgressEvent, error) {
+	sub := &progressSub{filter: filter, ch: make(chan migrations.ProgressEvent, subscriberBuffer)}
+	// Subscribe before reading the backlog so nothing recorded in between is missed.
+	f.mu.Lock()
+	f.subs[sub] = struct{}{}
+	f.mu.Unlock()
+
+	var resync *migrations.ProgressEvent
+	var page []migrations.ProgressEvent
+	if afterID > 0 {
+		var prunedThrough int64
+		if err := f.pool.QueryRow(ctx, `SELECT pruned_through FROM progress_retention`).Scan(&prunedThrough); err != nil {
+			f.remove(sub)
+			return nil, fmt.Errorf("read progress retention: %w", err)
+		}
+		if prunedThrough > afterID {
+			resync = &migrations.ProgressEvent{
+				ID:          prunedThrough,
+				MigrationID: filter.MigrationID,
+				CandidateID: filter.CandidateID,
+				EventType:   migrations.EventProgressResync,
+				CreatedAt:   time.Now(),
+			}
+			afterID = prunedThrough
+		}
+		var err error
+		if page, err = f.backlog(ctx, filter, afterID); err != nil {
+			f.remove(sub)
+			return nil, err
+		}
+	}
+
+	out := make(chan migrations.ProgressEvent)
+	go func() {
+		defer close(out)
+		defer f.remove(sub)
+		send := func(e migrations.ProgressEvent) bool {
+			select {
+			case out <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		if resync != nil && !send(*resync) {
+			return
+		}
+		// Events recorded while the backlog is read arrive both ways.
+		replayed := make(map[int64]bool)
+		for len(page) > 0 {
+			for _, e := range page {
+				replayed[e.ID] = true
+				if !send(e) {
+					return
+				}
+			}
+			if len(page) < backlogPage {
+				break
+			}
+			var err error
+			if page, err = f.backlog(ctx, filter, page[len(page)-1].ID); err != nil {
+				if ctx.Err() == nil {
+					f.log.Error("read progress backlog failed", "migrationId", filter.MigrationID, "error", err)
+				}
+				return
+			}
+		}
+		for {
+			select {
+			case e, ok := <-sub.ch:
+				if !ok {
+					return
+				}
+				if replayed[e.ID] {
+					continue
+				}
+				if !send(e) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// backlog reads the next page of matching events recorded after afterID.
+func (f *PGProgressFeed) backlog(
+	ctx context.Context,
+	filter migrations.ProgressFilter,
+	afterID int64,
+) ([]migrations.ProgressEvent, error) {
+	return f.query(ctx, `
+		WHERE migration_id = $1 AND ($2 = '' OR candidate_id = $2) AND id > $3
+		ORDER BY id
+		LIMIT $4`, filter.MigrationID, filter.CandidateID, afterID, backlogPage)
+}
+
+func (f *PGProgressFeed) query(ctx context.Context, where string, args ...any) ([]migrations.ProgressEvent, error) {
+	rows, err := f.pool.Query(ctx, `
+		SELECT id, migration_id, candidate_id, event_type, step_name, status, metadata, created_at
+		FROM progress_events `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("progress events query: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]migrations.ProgressEvent, 0)
+	for rows.Next() {
+		var e migrations.ProgressEvent
+		var metadataJSON []byte
+		var stepName, status *string
+		if err := rows.Scan(&e.ID, &e.MigrationID, &e.CandidateID, &e.EventType, &stepName, &status,
+			&metadataJSON, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan progress event: %w", err)
+		}
+		if stepName != nil {
+			e.StepName = *stepName
+		}
+		if status != nil {
+			e.Status = *status
+		}
+		if metadataJSON != nil {
+			_ = json.Unmarshal(metadataJSON, &e.Metadata)
+		}
+		result = append(result, e)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,202 @@
+package store_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/migrations/store"
+	"github.com/tilsley/loom/apps/server/internal/migrations/store/pgmigrations"
+	pgplatform "github.com/tilsley/loom/apps/server/internal/platform/postgres"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// newPGProgressFeed creates a running PGProgressFeed backed by a real
+// PostgreSQL instance, with a migration that has two not-started candidates.
+// Skips if POSTGRES_URL is not set.
+func newPGProgressFeed(t *testing.T) (*store.PGProgressFeed, *pgxpool.Pool) {
+	t.Helper()
+	pgURL := os.Getenv("POSTGRES_URL")
+	if pgURL == "" {
+		t.Skip("POSTGRES_URL not set — skipping Postgres integration tests")
+	}
+	pool, err := pgplatform.New(context.Background(), pgURL, pgmigrations.FS)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	feed := store.NewPGProgressFeed(pool, slog.Default())
+	done := make(chan struct{})
+	go func() {
+		feed.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		_, err := pool.Exec(context.Background(),
+			`DELETE FROM step_events; DELETE FROM progress_events; DELETE FROM candidates; DELETE FROM migrations;
+			UPDATE progress_retention SET pruned_through = 0;`)
+		require.NoError(t, err)
+		pool.Close()
+	})
+
+	migStore := store.NewPGMigrationStore(pool)
+	require.NoError(t, migStore.Save(context.Background(), api.Migration{Id: "mig-a", Name: "Mig A"}))
+	require.NoError(t, migStore.SaveCandidates(context.Background(), "mig-a", []api.Candidate{
+		{Id: "billing-api", Status: api.CandidateStatusNotStarted},
+		{Id: "payments-api", Status: api.CandidateStatusNotStarted},
+	}))
+	return feed, pool
+}
+
+// waitForListener records events until one reaches a subscriber, so that the
+// feed is known to be listening before the test proper starts.
+func waitForListener(t *testing.T, feed *store.PGProgressFeed, events *store.PGEventStore) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := feed.Subscribe(ctx, migrations.ProgressFilter{MigrationID: "mig-warmup"}, 0)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		require.NoError(t, events.RecordEvent(context.Background(), migrations.StepEvent{
+			MigrationID: "mig-warmup", CandidateID: "c", EventType: migrations.EventRunStarted,
+		}))
+		select {
+		case <-ch:
+			return true
+		case <-time.After(100 * time.Millisecond):
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func receive(t *testing.T, ch <-chan migrations.ProgressEvent) migrations.ProgressEvent {
+	t.Helper()
+	select {
+	case e, ok := <-ch:
+		require.True(t, ok, "subscription closed")
+		return e
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no progress event received")
+		return migrations.ProgressEvent{}
+	}
+}
+
+func TestPG_ProgressFeed_DeliversStepEventsAndStatusChanges(t *testing.T) {
+	feed, pool := newPGProgressFeed(t)
+	events := store.NewPGEventStore(pool)
+	migStore := store.NewPGMigrationStore(pool)
+	waitForListener(t, feed, events)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := feed.Subscribe(ctx, migrations.ProgressFilter{MigrationID: "mig-a", CandidateID: "billing-api"}, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, migStore.SetCandidateStatus(ctx, "mig-a", "payments-api", api.CandidateStatusRunning))
+	require.NoError(t, migStore.SetCandidateStatus(ctx, "mig-a", "billing-api", api.CandidateStatusRunning))
+	require.NoError(t, events.RecordEvent(ctx, migrations.StepEvent{
+		MigrationID: "mig-a", CandidateID: "billing-api", StepName: "update-chart",
+		EventType: migrations.EventStepDispatched, Status: "in_progress",
+	}))
+
+	status := receive(t, ch)
+	assert.Equal(t, migrations.EventCandidateStatusChanged, status.EventType, "other candidates are filtered out")
+	assert.Equal(t, "billing-api", status.CandidateID)
+	assert.Equal(t, "running", status.Status)
+	assert.Equal(t, "not_started", status.Metadata["from"])
+
+	step := receive(t, ch)
+	assert.Equal(t, migrations.EventStepDispatched, step.EventType)
+	assert.Equal(t, "update-chart", step.StepName)
+	assert.Greater(t, step.ID, status.ID)
+}
+
+func TestPG_ProgressFeed_ResumesAfterLastEventID(t *testing.T) {
+	feed, pool := newPGProgressFeed(t)
+	events := store.NewPGEventStore(pool)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, step := range []string{"a", "b", "c"} {
+		require.NoError(t, events.RecordEvent(ctx, migrations.StepEvent{
+			MigrationID: "mig-a", CandidateID: "billing-api", StepName: step,
+			EventType: migrations.EventStepCompleted, Status: "succeeded",
+		}))
+	}
+	var firstID int64
+	require.NoError(t, pool.QueryRow(ctx,
+		`SELECT MIN(id) FROM progress_events WHERE migration_id = 'mig-a'`).Scan(&firstID))
+
+	ch, err := feed.Subscribe(ctx, migrations.ProgressFilter{MigrationID: "mig-a"}, firstID)
+	require.NoError(t, err)
+
+	assert.Equal(t, "b", receive(t, ch).StepName)
+	assert.Equal(t, "c", receive(t, ch).StepName)
+}
+
+func TestPG_ProgressFeed_ReplaysWholeBacklog(t *testing.T) {
+	feed, pool := newPGProgressFeed(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const missed = 1200 // more than one backlog page
+	_, err := pool.Exec(ctx, `
+		INSERT INTO progress_events (migration_id, candidate_id, event_type)
+		SELECT 'mig-a', 'billing-api', 'step_completed' FROM generate_series(1, $1)`, missed+1)
+	require.NoError(t, err)
+	var firstID int64
+	require.NoError(t, pool.QueryRow(ctx,
+		`SELECT MIN(id) FROM progress_events WHERE migration_id = 'mig-a'`).Scan(&firstID))
+
+	ch, err := feed.Subscribe(ctx, migrations.ProgressFilter{MigrationID: "mig-a"}, firstID)
+	require.NoError(t, err)
+
+	last := firstID
+	for range missed {
+		e := receive(t, ch)
+		require.Greater(t, e.ID, last)
+		last = e.ID
+	}
+}
+
+func TestPG_ProgressFeed_PrunedBacklog_SendsResync(t *testing.T) {
+	feed, pool := newPGProgressFeed(t)
+	events := store.NewPGEventStore(pool)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, step := range []string{"a", "b"} {
+		require.NoError(t, events.RecordEvent(ctx, migrations.StepEvent{
+			MigrationID: "mig-a", CandidateID: "billing-api", StepName: step,
+			EventType: migrations.EventStepCompleted, Status: "succeeded",
+		}))
+	}
+	var firstID int64
+	require.NoError(t, pool.QueryRow(ctx,
+		`SELECT MIN(id) FROM progress_events WHERE migration_id = 'mig-a'`).Scan(&firstID))
+	_, err := pool.Exec(ctx, `UPDATE progress_events SET created_at = NOW() - INTERVAL '30 days' WHERE id = $1`, firstID+1)
+	require.NoError(t, err)
+	n, err := feed.Prune(ctx, time.Now().Add(-store.ProgressRetention))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+	require.NoError(t, events.RecordEvent(ctx, migrations.StepEvent{
+		MigrationID: "mig-a", CandidateID: "billing-api", StepName: "c",
+		EventType: migrations.EventStepCompleted, Status: "succeeded",
+	}))
+
+	ch, err := feed.Subscribe(ctx, migrations.ProgressFilter{MigrationID: "mig-a"}, firstID)
+	require.NoError(t, err)
+
+	resync := receive(t, ch)
+	assert.Equal(t, migrations.EventProgressResync, resync.EventType)
+	assert.Equal(t, firstID+1, resync.ID)
+	assert.Equal(t, "c", receive(t, ch).StepName)
+}
@@ -0,0 +1,134 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// Compile-time check: *PGScheduleStore implements migrations.ScheduleStore.
+var _ migrations.ScheduleStore = (*PGScheduleStore)(nil)
+
+// PGScheduleStore implements migrations.ScheduleStore backed by PostgreSQL.
+type PGScheduleStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPGScheduleStore creates a new PGScheduleStore with the given connection pool.
+func NewPGScheduleStore(pool *pgxpool.Pool) *PGScheduleStore {
+	return &PGScheduleStore{pool: pool}
+}
+
+const scheduledStartColumns = `id, migration_id, candidate_id, inputs, bulk, start_at, status, run_id, error,
+	created_at, fired_at`
+
+// CreateScheduledStart inserts a scheduled start.
+func (s *PGScheduleStore) CreateScheduledStart(ctx context.Context, st api.ScheduledStart) error {
+	var inputsJSON, bulkJSON []byte
+	var err error
+	if st.Inputs != nil {
+		if inputsJSON, err = json.Marshal(*st.Inputs); err != nil {
+			return fmt.Errorf("marshal inputs: %w", err)
+		}
+	}
+	if st.Bulk != nil {
+		if bulkJSON, err = json.Marshal(*st.Bulk); err != nil {
+			return fmt.Errorf("marshal bulk start: %w", err)
+		}
+	}
+	_, err = s.pool.Exec(ctx,
+		`INSERT INTO scheduled_starts (id, migration_id, candidate_id, inputs, bulk, start_at, status, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		st.Id, st.MigrationId, st.CandidateId, inputsJSON, bulkJSON, st.StartAt, st.Status, st.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert scheduled start: %w", err)
+	}
+	return nil
+}
+
+// ListScheduledStarts returns the migration's scheduled starts, soonest first.
+func (s *PGScheduleStore) ListScheduledStarts(ctx context.Context, migrationID string) ([]api.ScheduledStart, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+scheduledStartColumns+` FROM scheduled_starts WHERE migration_id = $1 ORDER BY start_at, id`,
+		migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("scheduled starts query: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]api.ScheduledStart, 0)
+	for rows.Next() {
+		st, err := scanScheduledStart(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, st)
+	}
+	return result, rows.Err()
+}
+
+// GetScheduledStart returns the scheduled start, or nil when it does not exist.
+func (s *PGScheduleStore) GetScheduledStart(ctx context.Context, id string) (*api.ScheduledStart, error) {
+	st, err := scanScheduledStart(s.pool.QueryRow(ctx,
+		`SELECT `+scheduledStartColumns+` FROM scheduled_starts WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil //nolint:nilnil
+		}
+		return nil, err
+	}
+	return &st, nil
+}
+
+// TransitionScheduledStart moves the start from status from to status to. The
+// status check is part of the UPDATE, so concurrent transitions cannot both
+// succeed. Reaching started or failed sets fired_at.
+func (s *PGScheduleStore) TransitionScheduledStart(
+	ctx context.Context,
+	id string,
+	from, to api.ScheduledStartStatus,
+	runID, errMsg string,
+) (bool, error) {
+	tag, err := s.pool.Exec(ctx,
+		`UPDATE scheduled_starts
+		 SET status = $3, run_id = COALESCE($4, run_id), error = COALESCE($5, error),
+		     fired_at = CASE WHEN $3 IN ('started', 'failed') THEN NOW() ELSE fired_at END
+		 WHERE id = $1 AND status = $2`,
+		id, from, to, nilIfEmpty(runID), nilIfEmpty(errMsg),
+	)
+	if err != nil {
+		return false, fmt.Errorf("update scheduled start: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func scanScheduledStart(row pgx.Row) (api.ScheduledStart, error) {
+	var st api.ScheduledStart
+	var inputsJSON, bulkJSON []byte
+	err := row.Scan(&st.Id, &st.MigrationId, &st.CandidateId, &inputsJSON, &bulkJSON, &st.StartAt, &st.Status,
+		&st.RunId, &st.Error, &st.CreatedAt, &st.FiredAt)
+	if err != nil {
+		return st, fmt.Errorf("scan scheduled start: %w", err)
+	}
+	if inputsJSON != nil {
+		var inputs map[string]string
+		if err := json.Unmarshal(inputsJSON, &inputs); err == nil {
+			st.Inputs = &inputs
+		}
+	}
+	if bulkJSON != nil {
+		var bulk api.BulkStartRequest
+		if err := json.Unmarshal(bulkJSON, &bulk); err == nil {
+			st.Bulk = &bulk
+		}
+	}
+	return st, nil
+}
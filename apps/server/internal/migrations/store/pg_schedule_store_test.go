@@ -0,0 +1,104 @@
+package store_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations/store"
+	"github.com/tilsley/loom/apps/server/internal/migrations/store/pgmigrations"
+	pgplatform "github.com/tilsley/loom/apps/server/internal/platform/postgres"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// newPGScheduleStore creates a PGScheduleStore backed by a real PostgreSQL
+// instance, with a migration to schedule starts for. Skips if POSTGRES_URL is
+// not set.
+func newPGScheduleStore(t *testing.T) *store.PGScheduleStore {
+	t.Helper()
+	pgURL := os.Getenv("POSTGRES_URL")
+	if pgURL == "" {
+		t.Skip("POSTGRES_URL not set — skipping Postgres integration tests")
+	}
+	pool, err := pgplatform.New(context.Background(), pgURL, pgmigrations.FS)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := pool.Exec(context.Background(), `DELETE FROM scheduled_starts; DELETE FROM migrations;`)
+		require.NoError(t, err)
+		pool.Close()
+	})
+	require.NoError(t, store.NewPGMigrationStore(pool).Save(context.Background(), api.Migration{Id: "mig-a"}))
+	return store.NewPGScheduleStore(pool)
+}
+
+func TestPG_ScheduleStore_CreateListGet(t *testing.T) {
+	s := newPGScheduleStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	candidate := "repo-a"
+	inputs := map[string]string{"env": "prod"}
+
+	require.NoError(t, s.CreateScheduledStart(ctx, api.ScheduledStart{
+		Id: "schedule:mig-a__2", MigrationId: "mig-a", Status: api.ScheduledStartStatusScheduled,
+		Bulk: &api.BulkStartRequest{MaxInFlight: 3}, StartAt: now.Add(2 * time.Hour), CreatedAt: now,
+	}))
+	require.NoError(t, s.CreateScheduledStart(ctx, api.ScheduledStart{
+		Id: "schedule:mig-a__1", MigrationId: "mig-a", Status: api.ScheduledStartStatusScheduled,
+		CandidateId: &candidate, Inputs: &inputs, StartAt: now.Add(time.Hour), CreatedAt: now,
+	}))
+
+	starts, err := s.ListScheduledStarts(ctx, "mig-a")
+	require.NoError(t, err)
+	require.Len(t, starts, 2)
+	assert.Equal(t, "schedule:mig-a__1", starts[0].Id, "soonest first")
+	require.NotNil(t, starts[0].Inputs)
+	assert.Equal(t, inputs, *starts[0].Inputs)
+	require.NotNil(t, starts[1].Bulk)
+	assert.Equal(t, 3, starts[1].Bulk.MaxInFlight)
+
+	got, err := s.GetScheduledStart(ctx, "schedule:mig-a__1")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "repo-a", *got.CandidateId)
+	assert.Nil(t, got.FiredAt)
+
+	missing, err := s.GetScheduledStart(ctx, "schedule:mig-a__3")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestPG_ScheduleStore_Transition(t *testing.T) {
+	s := newPGScheduleStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	require.NoError(t, s.CreateScheduledStart(ctx, api.ScheduledStart{
+		Id: "schedule:mig-a__1", MigrationId: "mig-a", Status: api.ScheduledStartStatusScheduled,
+		Bulk: &api.BulkStartRequest{MaxInFlight: 1}, StartAt: now.Add(time.Hour), CreatedAt: now,
+	}))
+
+	ok, err := s.TransitionScheduledStart(ctx, "schedule:mig-a__1",
+		api.ScheduledStartStatusScheduled, api.ScheduledStartStatusStarting, "", "")
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = s.TransitionScheduledStart(ctx, "schedule:mig-a__1",
+		api.ScheduledStartStatusScheduled, api.ScheduledStartStatusCancelled, "", "")
+	require.NoError(t, err)
+	assert.False(t, ok, "no longer scheduled")
+
+	ok, err = s.TransitionScheduledStart(ctx, "schedule:mig-a__1",
+		api.ScheduledStartStatusStarting, api.ScheduledStartStatusStarted, "bulk__mig-a__x", "")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	got, err := s.GetScheduledStart(ctx, "schedule:mig-a__1")
+	require.NoError(t, err)
+	assert.Equal(t, api.ScheduledStartStatusStarted, got.Status)
+	require.NotNil(t, got.RunId)
+	assert.Equal(t, "bulk__mig-a__x", *got.RunId)
+	assert.Nil(t, got.Error)
+	assert.NotNil(t, got.FiredAt)
+}
@@ -0,0 +1,272 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+)
+
+// Compile-time checks: *PGWebhookStore implements migrations.WebhookStore and
+// migrations.WebhookOutbox.
+var (
+	_ migrations.WebhookStore  = (*PGWebhookStore)(nil)
+	_ migrations.WebhookOutbox = (*PGWebhookStore)(nil)
+)
+
+// PGWebhookStore implements migrations.WebhookStore and migrations.WebhookOutbox
+// backed by PostgreSQL. Deliveries are queued by PGEventStore.RecordEvent.
+type PGWebhookStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPGWebhookStore creates a new PGWebhookStore with the given connection pool.
+func NewPGWebhookStore(pool *pgxpool.Pool) *PGWebhookStore {
+	return &PGWebhookStore{pool: pool}
+}
+
+const webhookColumns = `id, migration_id, url, events, templates, secret, created_at`
+
+const deliveryColumns = `id, webhook_id, event, migration_id, candidate_id, step_name, status, metadata,
+	occurred_at, state, attempts, last_status_code, last_error, next_attempt_at, delivered_at`
+
+// CreateWebhook inserts w and sets its ID and CreatedAt.
+func (s *PGWebhookStore) CreateWebhook(ctx context.Context, w *migrations.WebhookSubscription) error {
+	var templatesJSON []byte
+	if len(w.Templates) > 0 {
+		var err error
+		templatesJSON, err = json.Marshal(w.Templates)
+		if err != nil {
+			return fmt.Errorf("marshal templates: %w", err)
+		}
+	}
+	err := s.pool.QueryRow(ctx,
+		`INSERT INTO webhooks (migration_id, url, events, templates, secret)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING id, created_at`,
+		w.MigrationID, w.URL, w.Events, templatesJSON, w.Secret,
+	).Scan(&w.ID, &w.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert webhook: %w", err)
+	}
+	return nil
+}
+
+// ListWebhooks returns the migration's webhooks, oldest first.
+func (s *PGWebhookStore) ListWebhooks(
+	ctx context.Context,
+	migrationID string,
+) ([]migrations.WebhookSubscription, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+webhookColumns+` FROM webhooks WHERE migration_id = $1 ORDER BY id`, migrationID)
+	if err != nil {
+		return nil, fmt.Errorf("webhooks query: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]migrations.WebhookSubscription, 0)
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, w)
+	}
+	return result, rows.Err()
+}
+
+// GetWebhook returns the webhook, or nil when the migration has no such webhook.
+func (s *PGWebhookStore) GetWebhook(
+	ctx context.Context,
+	migrationID string,
+	id int64,
+) (*migrations.WebhookSubscription, error) {
+	w, err := scanWebhook(s.pool.QueryRow(ctx,
+		`SELECT `+webhookColumns+` FROM webhooks WHERE migration_id = $1 AND id = $2`, migrationID, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil //nolint:nilnil
+		}
+		return nil, err
+	}
+	return &w, nil
+}
+
+// DeleteWebhook removes the webhook; its deliveries go with it.
+func (s *PGWebhookStore) DeleteWebhook(ctx context.Context, migrationID string, id int64) (bool, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM webhooks WHERE migration_id = $1 AND id = $2`, migrationID, id)
+	if err != nil {
+		return false, fmt.Errorf("delete webhook: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ListDeliveries returns the webhook's latest deliveries, newest first.
+func (s *PGWebhookStore) ListDeliveries(
+	ctx context.Context,
+	webhookID int64,
+	limit int,
+) ([]migrations.WebhookDelivery, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE webhook_id = $1 ORDER BY id DESC LIMIT $2`,
+		webhookID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("webhook deliveries query: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]migrations.WebhookDelivery, 0)
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, d)
+	}
+	return result, rows.Err()
+}
+
+// ClaimDeliveries leases up to limit due deliveries, oldest first. SKIP LOCKED
+// lets dispatchers on several replicas claim batches side by side.
+func (s *PGWebhookStore) ClaimDeliveries(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]migrations.ClaimedDelivery, error) {
+	rows, err := s.pool.Query(ctx, `
+		WITH due AS (
+			SELECT id FROM webhook_deliveries
+			WHERE state = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		), claimed AS (
+			UPDATE webhook_deliveries d
+			SET attempts = d.attempts + 1, next_attempt_at = NOW() + make_interval(secs => $2)
+			FROM due
+			WHERE d.id = due.id
+			RETURNING d.*
+		)
+		SELECT c.id, c.webhook_id, c.event, c.migration_id, c.candidate_id, c.step_name, c.status, c.metadata,
+			c.occurred_at, c.state, c.attempts, c.last_status_code, c.last_error, c.next_attempt_at, c.delivered_at,
+			w.id, w.migration_id, w.url, w.events, w.templates, w.secret, w.created_at
+		FROM claimed c
+		JOIN webhooks w ON w.id = c.webhook_id
+		ORDER BY c.id
+	`, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]migrations.ClaimedDelivery, 0)
+	for rows.Next() {
+		var c migrations.ClaimedDelivery
+		deliveryDest, finishDelivery := deliveryScanner(&c.Delivery)
+		webhookDest, finishWebhook := webhookScanner(&c.Webhook)
+		if err := rows.Scan(append(deliveryDest, webhookDest...)...); err != nil {
+			return nil, fmt.Errorf("scan claimed delivery: %w", err)
+		}
+		finishDelivery()
+		finishWebhook()
+		result = append(result, c)
+	}
+	return result, rows.Err()
+}
+
+// RecordAttempt stores the outcome of an attempt. The delivery stays pending
+// while attempt.NextAttemptAt is set and has failed for good otherwise.
+func (s *PGWebhookStore) RecordAttempt(ctx context.Context, deliveryID int64, attempt migrations.WebhookAttempt) error {
+	state := migrations.WebhookDeliveryFailed
+	switch {
+	case attempt.Delivered:
+		state = migrations.WebhookDeliveryDelivered
+	case attempt.NextAttemptAt != nil:
+		state = migrations.WebhookDeliveryPending
+	}
+	var statusCode *int
+	if attempt.StatusCode != 0 {
+		statusCode = &attempt.StatusCode
+	}
+	_, err := s.pool.Exec(ctx,
+		`UPDATE webhook_deliveries
+		 SET state = $2, last_status_code = $3, last_error = $4,
+		     next_attempt_at = COALESCE($5, next_attempt_at),
+		     delivered_at = CASE WHEN $2 = 'delivered' THEN NOW() END
+		 WHERE id = $1`,
+		deliveryID, state, statusCode, nilIfEmpty(attempt.Error), attempt.NextAttemptAt,
+	)
+	if err != nil {
+		return fmt.Errorf("record webhook attempt: %w", err)
+	}
+	return nil
+}
+
+func scanWebhook(row pgx.Row) (migrations.WebhookSubscription, error) {
+	var w migrations.WebhookSubscription
+	dest, finish := webhookScanner(&w)
+	if err := row.Scan(dest...); err != nil {
+		return w, fmt.Errorf("scan webhook: %w", err)
+	}
+	finish()
+	return w, nil
+}
+
+// webhookScanner returns the scan destinations for webhookColumns and a
+// function that copies the nullable columns into w once scanned.
+func webhookScanner(w *migrations.WebhookSubscription) ([]any, func()) {
+	var templatesJSON []byte
+	dest := []any{&w.ID, &w.MigrationID, &w.URL, &w.Events, &templatesJSON, &w.Secret, &w.CreatedAt}
+	finish := func() {
+		if templatesJSON != nil {
+			_ = json.Unmarshal(templatesJSON, &w.Templates)
+		}
+	}
+	return dest, finish
+}
+
+func scanDelivery(row pgx.Row) (migrations.WebhookDelivery, error) {
+	var d migrations.WebhookDelivery
+	dest, finish := deliveryScanner(&d)
+	if err := row.Scan(dest...); err != nil {
+		return d, fmt.Errorf("scan webhook delivery: %w", err)
+	}
+	finish()
+	return d, nil
+}
+
+// deliveryScanner returns the scan destinations for deliveryColumns and a
+// function that copies the nullable columns into d once scanned.
+func deliveryScanner(d *migrations.WebhookDelivery) ([]any, func()) {
+	var stepName, status, lastError *string
+	var metadataJSON []byte
+	var nextAttemptAt time.Time
+	dest := []any{
+		&d.ID, &d.WebhookID, &d.Event, &d.MigrationID, &d.CandidateID, &stepName, &status, &metadataJSON,
+		&d.OccurredAt, &d.State, &d.Attempts, &d.LastStatusCode, &lastError, &nextAttemptAt, &d.DeliveredAt,
+	}
+	finish := func() {
+		if stepName != nil {
+			d.StepName = *stepName
+		}
+		if status != nil {
+			d.Status = *status
+		}
+		if lastError != nil {
+			d.LastError = *lastError
+		}
+		if metadataJSON != nil {
+			_ = json.Unmarshal(metadataJSON, &d.Metadata)
+		}
+		if d.State == migrations.WebhookDeliveryPending {
+			d.NextAttemptAt = &nextAttemptAt
+		}
+	}
+	return dest, finish
+}
@@ -0,0 +1,127 @@
+package store_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/migrations/store"
+	"github.com/tilsley/loom/apps/server/internal/migrations/store/pgmigrations"
+	pgplatform "github.com/tilsley/loom/apps/server/internal/platform/postgres"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// newPGWebhookStore creates a PGWebhookStore and a PGEventStore backed by a
+// real PostgreSQL instance, with a migration to subscribe to. Skips if
+// POSTGRES_URL is not set.
+func newPGWebhookStore(t *testing.T) (*store.PGWebhookStore, *store.PGEventStore) {
+	t.Helper()
+	pgURL := os.Getenv("POSTGRES_URL")
+	if pgURL == "" {
+		t.Skip("POSTGRES_URL not set — skipping Postgres integration tests")
+	}
+	pool, err := pgplatform.New(context.Background(), pgURL, pgmigrations.FS)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := pool.Exec(context.Background(),
+			`DELETE FROM webhooks; DELETE FROM step_events; DELETE FROM progress_events; DELETE FROM migrations;`)
+		require.NoError(t, err)
+		pool.Close()
+	})
+	require.NoError(t, store.NewPGMigrationStore(pool).Save(context.Background(), api.Migration{Id: "mig-a"}))
+	return store.NewPGWebhookStore(pool), store.NewPGEventStore(pool)
+}
+
+func TestPG_WebhookStore_CRUD(t *testing.T) {
+	s, _ := newPGWebhookStore(t)
+	ctx := context.Background()
+
+	w := migrations.WebhookSubscription{
+		MigrationID: "mig-a", URL: "https://hooks.example.com", Events: []string{"pr_opened"},
+		Templates: map[string]string{"pr_opened": "{{.CandidateID}}"}, Secret: "s3cret",
+	}
+	require.NoError(t, s.CreateWebhook(ctx, &w))
+	assert.NotZero(t, w.ID)
+
+	got, err := s.GetWebhook(ctx, "mig-a", w.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, w.Templates, got.Templates)
+	assert.Equal(t, "s3cret", got.Secret)
+
+	got, err = s.GetWebhook(ctx, "mig-b", w.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got, "webhooks are scoped to their migration")
+
+	list, err := s.ListWebhooks(ctx, "mig-a")
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	deleted, err := s.DeleteWebhook(ctx, "mig-a", w.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	deleted, err = s.DeleteWebhook(ctx, "mig-a", w.ID)
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
+
+func TestPG_WebhookStore_QueuesClaimsAndRecordsDeliveries(t *testing.T) {
+	s, events := newPGWebhookStore(t)
+	ctx := context.Background()
+
+	prs := migrations.WebhookSubscription{
+		MigrationID: "mig-a", URL: "https://hooks.example.com/prs", Events: []string{"pr_opened"}, Secret: "a",
+	}
+	failures := migrations.WebhookSubscription{
+		MigrationID: "mig-a", URL: "https://hooks.example.com/failures", Events: []string{"step_failed"}, Secret: "b",
+	}
+	require.NoError(t, s.CreateWebhook(ctx, &prs))
+	require.NoError(t, s.CreateWebhook(ctx, &failures))
+
+	require.NoError(t, events.RecordEvent(ctx, migrations.StepEvent{
+		MigrationID: "mig-a", CandidateID: "billing-api", StepName: "open-pr", EventType: migrations.EventStepPending,
+		Metadata: map[string]string{"prUrl": "https://github.com/org/repo/pull/1"},
+	}))
+	require.NoError(t, events.RecordEvent(ctx, migrations.StepEvent{
+		MigrationID: "mig-a", CandidateID: "billing-api", StepName: "open-pr",
+		EventType: migrations.EventStepDispatched,
+	}))
+
+	claimed, err := s.ClaimDeliveries(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1, "only the pr_opened subscriber is notified")
+	c := claimed[0]
+	assert.Equal(t, migrations.WebhookPROpened, c.Delivery.Event)
+	assert.Equal(t, "https://github.com/org/repo/pull/1", c.Delivery.Metadata["prUrl"])
+	assert.Equal(t, 1, c.Delivery.Attempts)
+	assert.Equal(t, prs.ID, c.Webhook.ID)
+	assert.Equal(t, "a", c.Webhook.Secret)
+
+	again, err := s.ClaimDeliveries(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, again, "claimed deliveries are leased")
+
+	retryAt := time.Now().Add(-time.Second)
+	require.NoError(t, s.RecordAttempt(ctx, c.Delivery.ID, migrations.WebhookAttempt{
+		StatusCode: 502, Error: "webhook returned HTTP 502", NextAttemptAt: &retryAt,
+	}))
+	claimed, err = s.ClaimDeliveries(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1, "due again")
+	assert.Equal(t, 2, claimed[0].Delivery.Attempts)
+
+	require.NoError(t, s.RecordAttempt(ctx, c.Delivery.ID, migrations.WebhookAttempt{Delivered: true, StatusCode: 204}))
+	history, err := s.ListDeliveries(ctx, prs.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, migrations.WebhookDeliveryDelivered, history[0].State)
+	require.NotNil(t, history[0].LastStatusCode)
+	assert.Equal(t, 204, *history[0].LastStatusCode)
+	assert.NotNil(t, history[0].DeliveredAt)
+	assert.Nil(t, history[0].NextAttemptAt)
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS migration_versions;
+ALTER TABLE migrations DROP COLUMN IF EXISTS version;
@@ -0,0 +1,15 @@
+ALTER TABLE migrations ADD COLUMN version INTEGER;
+
+CREATE TABLE migration_versions (
+    migration_id    TEXT        NOT NULL REFERENCES migrations(id),
+    version         INTEGER     NOT NULL,
+    steps_hash      TEXT        NOT NULL,
+    name            TEXT        NOT NULL DEFAULT '',
+    description     TEXT        NOT NULL DEFAULT '',
+    migrator_url    TEXT        NOT NULL DEFAULT '',
+    overview        JSONB,
+    required_inputs JSONB,
+    steps           JSONB       NOT NULL DEFAULT '[]',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (migration_id, version)
+);
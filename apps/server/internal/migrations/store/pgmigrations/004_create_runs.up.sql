@@ -0,0 +1,13 @@
+CREATE TABLE runs (
+    run_id       TEXT        PRIMARY KEY,
+    migration_id TEXT        NOT NULL REFERENCES migrations(id),
+    candidate_id TEXT        NOT NULL,
+    attempt      INTEGER     NOT NULL,
+    type         TEXT        NOT NULL DEFAULT 'migration',
+    status       TEXT        NOT NULL DEFAULT 'running',
+    version      INTEGER,
+    steps        JSONB,
+    started_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at  TIMESTAMPTZ,
+    UNIQUE (migration_id, candidate_id, attempt)
+);
@@ -0,0 +1,6 @@
+CREATE TABLE callback_events (
+    run_id      TEXT        NOT NULL,
+    event_id    TEXT        NOT NULL,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (run_id, event_id)
+);
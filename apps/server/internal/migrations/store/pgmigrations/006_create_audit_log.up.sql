@@ -0,0 +1,26 @@
+CREATE TABLE audit_log (
+    id           BIGSERIAL   PRIMARY KEY,
+    actor        TEXT        NOT NULL,
+    action       TEXT        NOT NULL,
+    migration_id TEXT,
+    candidate_id TEXT,
+    payload      JSONB,
+    status_code  INTEGER     NOT NULL,
+    outcome      TEXT        NOT NULL,
+    error        TEXT,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_migration ON audit_log (migration_id, candidate_id, id DESC);
+CREATE INDEX idx_audit_log_actor ON audit_log (actor, id DESC);
+
+-- The audit log is append-only: rows can be inserted but never changed or removed.
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
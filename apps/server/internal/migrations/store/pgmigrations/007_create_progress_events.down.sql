@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS progress_from_candidate_status ON candidates;
+DROP TRIGGER IF EXISTS progress_from_step_event ON step_events;
+DROP TABLE IF EXISTS progress_events;
+DROP FUNCTION IF EXISTS notify_progress_event();
+DROP FUNCTION IF EXISTS progress_from_candidate_status();
+DROP FUNCTION IF EXISTS progress_from_step_event();
@@ -0,0 +1,57 @@
+CREATE TABLE progress_events (
+    id           BIGSERIAL   PRIMARY KEY,
+    migration_id TEXT        NOT NULL,
+    candidate_id TEXT        NOT NULL,
+    event_type   TEXT        NOT NULL,
+    step_name    TEXT,
+    status       TEXT,
+    metadata     JSONB,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_progress_events_migration ON progress_events (migration_id, candidate_id, id);
+
+-- Step events and candidate status changes are copied into progress_events,
+-- whichever process wrote them, so that one ID sequence orders both.
+CREATE FUNCTION progress_from_step_event() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO progress_events (migration_id, candidate_id, event_type, step_name, status, metadata)
+    VALUES (NEW.migration_id, NEW.candidate_id, NEW.event_type, NEW.step_name, NEW.status, NEW.metadata);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER progress_from_step_event
+    AFTER INSERT ON step_events
+    FOR EACH ROW EXECUTE FUNCTION progress_from_step_event();
+
+CREATE FUNCTION progress_from_candidate_status() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO progress_events (migration_id, candidate_id, event_type, status, metadata)
+    VALUES (NEW.migration_id, NEW.id, 'candidate_status_changed', NEW.status, jsonb_build_object('from', OLD.status));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER progress_from_candidate_status
+    AFTER UPDATE OF status ON candidates
+    FOR EACH ROW
+    WHEN (OLD.status IS DISTINCT FROM NEW.status)
+    EXECUTE FUNCTION progress_from_candidate_status();
+
+-- Listeners are told which row to read; NOTIFY payloads are too small for the
+-- event itself.
+CREATE FUNCTION notify_progress_event() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('loom_progress', json_build_object(
+        'id', NEW.id,
+        'migrationId', NEW.migration_id,
+        'candidateId', NEW.candidate_id
+    )::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notify_progress_event
+    AFTER INSERT ON progress_events
+    FOR EACH ROW EXECUTE FUNCTION notify_progress_event();
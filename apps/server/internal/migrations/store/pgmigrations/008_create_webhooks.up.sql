@@ -0,0 +1,34 @@
+CREATE TABLE webhooks (
+    id           BIGSERIAL   PRIMARY KEY,
+    migration_id TEXT        NOT NULL REFERENCES migrations(id),
+    url          TEXT        NOT NULL,
+    events       TEXT[]      NOT NULL,
+    templates    JSONB,
+    secret       TEXT        NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_webhooks_migration ON webhooks (migration_id);
+
+-- The outbox: a row per webhook per event, queued in the transaction that
+-- recorded the event and worked off by the webhook dispatcher.
+CREATE TABLE webhook_deliveries (
+    id               BIGSERIAL   PRIMARY KEY,
+    webhook_id       BIGINT      NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event            TEXT        NOT NULL,
+    migration_id     TEXT        NOT NULL,
+    candidate_id     TEXT        NOT NULL,
+    step_name        TEXT,
+    status           TEXT,
+    metadata         JSONB,
+    occurred_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    state            TEXT        NOT NULL DEFAULT 'pending',
+    attempts         INTEGER     NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_status_code INTEGER,
+    last_error       TEXT,
+    delivered_at     TIMESTAMPTZ
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE state = 'pending';
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, id DESC);
@@ -0,0 +1,14 @@
+-- Change freezes: steps covered by a window are held back until it ends. A
+-- window without a migration covers every migration; one with step_config only
+-- covers steps whose config has each of its key/value pairs.
+CREATE TABLE freeze_windows (
+    id           BIGSERIAL   PRIMARY KEY,
+    migration_id TEXT        REFERENCES migrations(id),
+    reason       TEXT        NOT NULL,
+    starts_at    TIMESTAMPTZ NOT NULL,
+    ends_at      TIMESTAMPTZ NOT NULL CHECK (ends_at > starts_at),
+    step_config  JSONB,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_freeze_windows_ends_at ON freeze_windows (ends_at);
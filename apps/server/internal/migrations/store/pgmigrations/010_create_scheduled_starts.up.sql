@@ -0,0 +1,18 @@
+-- Starts queued for a later time. A Temporal run waits for start_at and then
+-- fires the start; status moves scheduled -> starting -> started or failed,
+-- or scheduled -> cancelled.
+CREATE TABLE scheduled_starts (
+    id           TEXT        PRIMARY KEY,
+    migration_id TEXT        NOT NULL REFERENCES migrations(id),
+    candidate_id TEXT,
+    inputs       JSONB,
+    bulk         JSONB,
+    start_at     TIMESTAMPTZ NOT NULL,
+    status       TEXT        NOT NULL,
+    run_id       TEXT,
+    error        TEXT,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    fired_at     TIMESTAMPTZ
+);
+
+CREATE INDEX idx_scheduled_starts_migration ON scheduled_starts (migration_id, start_at);
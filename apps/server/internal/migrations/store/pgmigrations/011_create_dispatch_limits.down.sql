@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS dispatch_slots;
+DROP TABLE IF EXISTS dispatch_limits;
@@ -0,0 +1,22 @@
+-- Dispatch limits per migrator app, declared in migration announcements. A
+-- NULL limit is not enforced.
+CREATE TABLE dispatch_limits (
+    migrator_app  TEXT        PRIMARY KEY,
+    max_in_flight INTEGER     CHECK (max_in_flight >= 1),
+    per_minute    INTEGER     CHECK (per_minute >= 1),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- One row per dispatch to a limited app. A slot is in flight until it is
+-- released or its lease expires; rows are kept for a minute after that so
+-- they still count towards per_minute.
+CREATE TABLE dispatch_slots (
+    migrator_app TEXT        NOT NULL,
+    holder       TEXT        NOT NULL,
+    acquired_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at   TIMESTAMPTZ NOT NULL,
+    released_at  TIMESTAMPTZ,
+    PRIMARY KEY (migrator_app, holder)
+);
+
+CREATE INDEX idx_dispatch_slots_acquired_at ON dispatch_slots (migrator_app, acquired_at);
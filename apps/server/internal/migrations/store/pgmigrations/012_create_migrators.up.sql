@@ -0,0 +1,8 @@
+-- Base URLs registered by migrator apps. Steps of a registered app are
+-- dispatched to its URL instead of their migration's migrator_url.
+CREATE TABLE migrators (
+    migrator_app  TEXT        PRIMARY KEY,
+    url           TEXT        NOT NULL,
+    health_url    TEXT        NOT NULL,
+    registered_at TIMESTAMPTZ NOT NULL
+);
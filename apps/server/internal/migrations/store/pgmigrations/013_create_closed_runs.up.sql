@@ -0,0 +1,10 @@
+-- Runs the in-process execution engine (LOOM_ENGINE=local) has closed, so
+-- their status and step results outlive the server process.
+CREATE TABLE closed_runs (
+    run_id         TEXT        PRIMARY KEY,
+    run_type       TEXT        NOT NULL,
+    runtime_status TEXT        NOT NULL,
+    result         JSONB,
+    queries        JSONB       NOT NULL DEFAULT '{}',
+    closed_at      TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_progress_events_created_at;
+DROP TABLE IF EXISTS progress_retention;
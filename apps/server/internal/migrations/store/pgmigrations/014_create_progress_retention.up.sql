@@ -0,0 +1,11 @@
+-- Progress events older than the retention period are pruned. The highest ID
+-- pruned so far tells a subscriber resuming from before it that events are
+-- missing.
+CREATE TABLE progress_retention (
+    singleton      BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (singleton),
+    pruned_through BIGINT  NOT NULL DEFAULT 0
+);
+
+INSERT INTO progress_retention DEFAULT VALUES;
+
+CREATE INDEX idx_progress_events_created_at ON progress_events (created_at);
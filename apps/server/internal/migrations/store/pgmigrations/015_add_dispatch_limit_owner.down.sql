@@ -0,0 +1 @@
+ALTER TABLE dispatch_limits DROP COLUMN IF EXISTS migration_id;
@@ -0,0 +1,4 @@
+-- Dispatch limits are shared by every migration that dispatches to the app;
+-- the migration that declared them owns them, and other migrations may not
+-- announce different ones.
+ALTER TABLE dispatch_limits ADD COLUMN migration_id TEXT;
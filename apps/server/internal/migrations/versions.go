@@ -0,0 +1,112 @@
+package migrations
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// StepsHash returns the hex-encoded SHA-256 of a step list's JSON encoding.
+// Two step lists hash the same exactly when they would be dispatched the same.
+func StepsHash(steps []api.StepDefinition) string {
+	// StepDefinition only holds strings, ints and string maps, so it always marshals.
+	b, _ := json.Marshal(steps)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// VersionOf snapshots the definition of m as the given version.
+func VersionOf(m api.Migration, version int, createdAt time.Time) api.MigrationVersion {
+	return api.MigrationVersion{
+		MigrationId:    m.Id,
+		Version:        version,
+		StepsHash:      StepsHash(m.Steps),
+		Name:           m.Name,
+		Description:    m.Description,
+		Overview:       m.Overview,
+		MigratorUrl:    m.MigratorUrl,
+		RequiredInputs: m.RequiredInputs,
+		Steps:          m.Steps,
+		CreatedAt:      createdAt,
+	}
+}
+
+// AtVersion returns m with the definition held by version v in place of its
+// current one. Candidates and everything else outside the definition are kept.
+func AtVersion(m api.Migration, v api.MigrationVersion) api.Migration {
+	m.Name = v.Name
+	m.Description = v.Description
+	m.Overview = v.Overview
+	m.MigratorUrl = v.MigratorUrl
+	m.RequiredInputs = v.RequiredInputs
+	m.Steps = v.Steps
+	m.Version = &v.Version
+	return m
+}
+
+// SameDefinition reports whether two versions hold the same definition,
+// ignoring their version numbers and creation times.
+func SameDefinition(a, b api.MigrationVersion) bool {
+	return a.StepsHash == b.StepsHash && len(changedFields(a, b)) == 0
+}
+
+// DiffVersions compares two versions of the same migration.
+func DiffVersions(from, to api.MigrationVersion) api.MigrationVersionDiff {
+	diff := api.MigrationVersionDiff{
+		MigrationId:   to.MigrationId,
+		From:          from.Version,
+		To:            to.Version,
+		StepsAdded:    []api.StepDefinition{},
+		StepsRemoved:  []api.StepDefinition{},
+		StepsChanged:  []api.StepChange{},
+		FieldsChanged: changedFields(from, to),
+	}
+
+	before := make(map[string]api.StepDefinition, len(from.Steps))
+	for _, s := range from.Steps {
+		before[s.Name] = s
+	}
+	after := make(map[string]bool, len(to.Steps))
+	for _, s := range to.Steps {
+		after[s.Name] = true
+		old, ok := before[s.Name]
+		if !ok {
+			diff.StepsAdded = append(diff.StepsAdded, s)
+			continue
+		}
+		if !reflect.DeepEqual(old, s) {
+			diff.StepsChanged = append(diff.StepsChanged, api.StepChange{Name: s.Name, Before: old, After: s})
+		}
+	}
+	for _, s := range from.Steps {
+		if !after[s.Name] {
+			diff.StepsRemoved = append(diff.StepsRemoved, s)
+		}
+	}
+	return diff
+}
+
+// changedFields lists the top-level definition fields that differ between a and b.
+func changedFields(a, b api.MigrationVersion) []string {
+	fields := []string{}
+	if a.Name != b.Name {
+		fields = append(fields, "name")
+	}
+	if a.Description != b.Description {
+		fields = append(fields, "description")
+	}
+	if !reflect.DeepEqual(a.Overview, b.Overview) {
+		fields = append(fields, "overview")
+	}
+	if a.MigratorUrl != b.MigratorUrl {
+		fields = append(fields, "migratorUrl")
+	}
+	if !reflect.DeepEqual(a.RequiredInputs, b.RequiredInputs) {
+		fields = append(fields, "requiredInputs")
+	}
+	return fields
+}
@@ -0,0 +1,79 @@
+package migrations_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+func TestStepsHash(t *testing.T) {
+	t.Run("is stable for equal step lists", func(t *testing.T) {
+		a := migrations.StepsHash([]api.StepDefinition{stepDef("a"), stepDef("b", "a")})
+		b := migrations.StepsHash([]api.StepDefinition{stepDef("a"), stepDef("b", "a")})
+
+		assert.Equal(t, a, b)
+		assert.Len(t, a, 64)
+	})
+
+	t.Run("changes when step order changes", func(t *testing.T) {
+		a := migrations.StepsHash([]api.StepDefinition{stepDef("a"), stepDef("b")})
+		b := migrations.StepsHash([]api.StepDefinition{stepDef("b"), stepDef("a")})
+
+		assert.NotEqual(t, a, b)
+	})
+}
+
+func TestSameDefinition(t *testing.T) {
+	m := api.Migration{Id: "m1", Name: "M1", Steps: []api.StepDefinition{stepDef("a")}}
+
+	t.Run("ignores version number and creation time", func(t *testing.T) {
+		a := migrations.VersionOf(m, 1, time.Now())
+		b := migrations.VersionOf(m, 2, time.Now().Add(time.Hour))
+
+		assert.True(t, migrations.SameDefinition(a, b))
+	})
+
+	t.Run("detects a changed migrator URL", func(t *testing.T) {
+		a := migrations.VersionOf(m, 1, time.Now())
+		m2 := m
+		m2.MigratorUrl = "http://elsewhere:3001"
+		b := migrations.VersionOf(m2, 2, time.Now())
+
+		assert.False(t, migrations.SameDefinition(a, b))
+	})
+}
+
+func TestDiffVersions(t *testing.T) {
+	configured := stepDef("b")
+	configured.Config = &map[string]string{"branch": "main"}
+
+	from := migrations.VersionOf(api.Migration{
+		Id:    "m1",
+		Name:  "Old",
+		Steps: []api.StepDefinition{stepDef("a"), stepDef("b"), stepDef("c")},
+	}, 1, time.Now())
+	to := migrations.VersionOf(api.Migration{
+		Id:    "m1",
+		Name:  "New",
+		Steps: []api.StepDefinition{stepDef("a"), configured, stepDef("d")},
+	}, 2, time.Now())
+
+	diff := migrations.DiffVersions(from, to)
+
+	assert.Equal(t, "m1", diff.MigrationId)
+	assert.Equal(t, 1, diff.From)
+	assert.Equal(t, 2, diff.To)
+	require.Len(t, diff.StepsAdded, 1)
+	assert.Equal(t, "d", diff.StepsAdded[0].Name)
+	require.Len(t, diff.StepsRemoved, 1)
+	assert.Equal(t, "c", diff.StepsRemoved[0].Name)
+	require.Len(t, diff.StepsChanged, 1)
+	assert.Equal(t, "b", diff.StepsChanged[0].Name)
+	assert.Nil(t, diff.StepsChanged[0].Before.Config)
+	assert.Equal(t, []string{"name"}, diff.FieldsChanged)
+}
@@ -0,0 +1,193 @@
+// Package webhook delivers the webhook outbox to subscribers over HTTP.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/signing"
+)
+
+const (
+	// EventHeader names the webhook event of a delivery.
+	EventHeader = "X-Loom-Event"
+	// WebhookIDHeader carries the ID of the webhook, which is also the
+	// signature target.
+	WebhookIDHeader = "X-Loom-Webhook-Id"
+	// DeliveryIDHeader carries the delivery ID; retries of a delivery reuse it.
+	DeliveryIDHeader = "X-Loom-Delivery-Id"
+)
+
+const (
+	pollInterval = 2 * time.Second
+	batchSize    = 50
+	// lease is how long a claimed delivery is hidden from other dispatchers. A
+	// dispatcher that dies mid-batch leaves its deliveries to be retried after it.
+	lease = time.Minute
+	// deliveryTimeout bounds each post. A batch is posted in parallel, so it is
+	// recorded well before its lease runs out and no other dispatcher claims
+	// and posts it again.
+	deliveryTimeout = 20 * time.Second
+
+	// MaxAttempts is how many times a delivery is tried before it is failed.
+	MaxAttempts = 10
+	baseBackoff = 30 * time.Second
+	maxBackoff  = time.Hour
+)
+
+// Payload is the JSON body posted to a webhook.
+type Payload struct {
+	DeliveryID  int64             `json:"deliveryId"`
+	WebhookID   int64             `json:"webhookId"`
+	Event       string            `json:"event"`
+	Text        string            `json:"text"`
+	MigrationID string            `json:"migrationId"`
+	CandidateID string            `json:"candidateId"`
+	StepName    string            `json:"stepName,omitempty"`
+	Status      string            `json:"status,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	OccurredAt  time.Time         `json:"occurredAt"`
+}
+
+// Dispatcher claims due deliveries from the outbox, posts them and records the
+// outcome. Failed deliveries are retried with exponential backoff.
+type Dispatcher struct {
+	outbox migrations.WebhookOutbox
+	client *http.Client
+	log    *slog.Logger
+}
+
+// NewDispatcher creates a new Dispatcher that posts with client.
+func NewDispatcher(outbox migrations.WebhookOutbox, client *http.Client, log *slog.Logger) *Dispatcher {
+	return &Dispatcher{outbox: outbox, client: client, log: log}
+}
+
+// Run dispatches due deliveries until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.DispatchDue(ctx); err != nil && ctx.Err() == nil {
+			d.log.Error("webhook dispatch failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue claims one batch of due deliveries, attempts them in parallel
+// and records each outcome, returning how many were claimed.
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	claimed, err := d.outbox.ClaimDeliveries(ctx, batchSize, lease)
+	if err != nil {
+		return 0, fmt.Errorf("claim deliveries: %w", err)
+	}
+	attempts := make([]migrations.WebhookAttempt, len(claimed))
+	var wg sync.WaitGroup
+	for i, c := range claimed {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			attempts[i] = d.deliver(ctx, c)
+		}()
+	}
+	wg.Wait()
+
+	for i, c := range claimed {
+		attempt := attempts[i]
+		if !attempt.Delivered {
+			d.log.Warn("webhook delivery failed",
+				"deliveryId", c.Delivery.ID, "webhookId", c.Webhook.ID, "attempt", c.Delivery.Attempts,
+				"statusCode", attempt.StatusCode, "error", attempt.Error, "retrying", attempt.NextAttemptAt != nil)
+		}
+		if err := d.outbox.RecordAttempt(ctx, c.Delivery.ID, attempt); err != nil {
+			return len(claimed), fmt.Errorf("record attempt for delivery %d: %w", c.Delivery.ID, err)
+		}
+	}
+	return len(claimed), nil
+}
+
+// deliver posts one delivery and describes the outcome. c.Delivery.Attempts
+// already counts this attempt.
+func (d *Dispatcher) deliver(ctx context.Context, c migrations.ClaimedDelivery) migrations.WebhookAttempt {
+	statusCode, err := d.post(ctx, c)
+	if err == nil {
+		return migrations.WebhookAttempt{Delivered: true, StatusCode: statusCode}
+	}
+	attempt := migrations.WebhookAttempt{StatusCode: statusCode, Error: err.Error()}
+	if c.Delivery.Attempts < MaxAttempts {
+		next := time.Now().Add(Backoff(c.Delivery.Attempts))
+		attempt.NextAttemptAt = &next
+	}
+	return attempt
+}
+
+func (d *Dispatcher) post(ctx context.Context, c migrations.ClaimedDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	text, err := migrations.RenderWebhookText(c.Webhook.Templates, c.Delivery)
+	if err != nil {
+		return 0, err
+	}
+	body, err := json.Marshal(Payload{
+		DeliveryID:  c.Delivery.ID,
+		WebhookID:   c.Webhook.ID,
+		Event:       c.Delivery.Event,
+		Text:        text,
+		MigrationID: c.Delivery.MigrationID,
+		CandidateID: c.Delivery.CandidateID,
+		StepName:    c.Delivery.StepName,
+		Status:      c.Delivery.Status,
+		Metadata:    c.Delivery.Metadata,
+		OccurredAt:  c.Delivery.OccurredAt,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("create webhook request: %w", err)
+	}
+	webhookID := strconv.FormatInt(c.Webhook.ID, 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, c.Delivery.Event)
+	req.Header.Set(WebhookIDHeader, webhookID)
+	req.Header.Set(DeliveryIDHeader, strconv.FormatInt(c.Delivery.ID, 10))
+	signing.SignRequest(req, c.Webhook.Secret, webhookID, body, time.Now())
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("post webhook: %w", err)
+	}
+	defer resp.Body.Close()               //nolint:errcheck
+	_, _ = io.Copy(io.Discard, resp.Body) // drain so the connection can be reused
+
+	if resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook returned HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Backoff returns the wait after the given failed attempt: 30s, doubling each
+// attempt, capped at an hour.
+func Backoff(attempt int) time.Duration {
+	wait := baseBackoff
+	for i := 1; i < attempt && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, maxBackoff)
+}
@@ -0,0 +1,173 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/migrations/webhook"
+	"github.com/tilsley/loom/pkg/signing"
+)
+
+// fakeOutbox hands out its claimed deliveries once and records the attempts.
+type fakeOutbox struct {
+	claimed  []migrations.ClaimedDelivery
+	attempts map[int64]migrations.WebhookAttempt
+}
+
+func (o *fakeOutbox) ClaimDeliveries(
+	_ context.Context,
+	_ int,
+	_ time.Duration,
+) ([]migrations.ClaimedDelivery, error) {
+	claimed := o.claimed
+	o.claimed = nil
+	return claimed, nil
+}
+
+func (o *fakeOutbox) RecordAttempt(_ context.Context, deliveryID int64, attempt migrations.WebhookAttempt) error {
+	if o.attempts == nil {
+		o.attempts = map[int64]migrations.WebhookAttempt{}
+	}
+	o.attempts[deliveryID] = attempt
+	return nil
+}
+
+func claimedDelivery(url string, attempts int) migrations.ClaimedDelivery {
+	return migrations.ClaimedDelivery{
+		Delivery: migrations.WebhookDelivery{
+			ID: 7, WebhookID: 3, Event: migrations.WebhookPROpened,
+			MigrationID: "app-chart-migration", CandidateID: "billing-api", StepName: "open-pr",
+			Metadata: map[string]string{"prUrl": "https://github.com/org/repo/pull/1"},
+			Attempts: attempts,
+		},
+		Webhook: migrations.WebhookSubscription{
+			ID: 3, MigrationID: "app-chart-migration", URL: url,
+			Events: []string{migrations.WebhookPROpened}, Secret: "s3cret",
+		},
+	}
+}
+
+func TestDispatchDue_PostsSignedPayload(t *testing.T) {
+	var header http.Header
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	outbox := &fakeOutbox{claimed: []migrations.ClaimedDelivery{claimedDelivery(srv.URL, 1)}}
+
+	n, err := webhook.NewDispatcher(outbox, http.DefaultClient, slog.Default()).DispatchDue(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, migrations.WebhookAttempt{Delivered: true, StatusCode: http.StatusNoContent}, outbox.attempts[7])
+
+	assert.Equal(t, "pr_opened", header.Get(webhook.EventHeader))
+	assert.Equal(t, "3", header.Get(webhook.WebhookIDHeader))
+	assert.Equal(t, "7", header.Get(webhook.DeliveryIDHeader))
+	require.NoError(t, signing.Verify("s3cret", "3", body,
+		header.Get(signing.TimestampHeader), header.Get(signing.SignatureHeader), time.Now(), signing.DefaultTolerance))
+
+	var payload webhook.Payload
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, "billing-api", payload.CandidateID)
+	assert.Equal(t,
+		"PR opened for billing-api at step open-pr of app-chart-migration: https://github.com/org/repo/pull/1",
+		payload.Text)
+}
+
+func TestDispatchDue_UsesSubscriptionTemplate(t *testing.T) {
+	var payload webhook.Payload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+	}))
+	defer srv.Close()
+	c := claimedDelivery(srv.URL, 1)
+	c.Webhook.Templates = map[string]string{migrations.WebhookPROpened: `:eyes: {{index .Metadata "prUrl"}}`}
+	outbox := &fakeOutbox{claimed: []migrations.ClaimedDelivery{c}}
+
+	_, err := webhook.NewDispatcher(outbox, http.DefaultClient, slog.Default()).DispatchDue(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, ":eyes: https://github.com/org/repo/pull/1", payload.Text)
+}
+
+func TestDispatchDue_Non2xx_SchedulesRetry(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	outbox := &fakeOutbox{claimed: []migrations.ClaimedDelivery{claimedDelivery(srv.URL, 2)}}
+
+	_, err := webhook.NewDispatcher(outbox, http.DefaultClient, slog.Default()).DispatchDue(context.Background())
+
+	require.NoError(t, err)
+	attempt := outbox.attempts[7]
+	assert.False(t, attempt.Delivered)
+	assert.Equal(t, http.StatusBadGateway, attempt.StatusCode)
+	assert.Contains(t, attempt.Error, "502")
+	require.NotNil(t, attempt.NextAttemptAt)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), *attempt.NextAttemptAt, 5*time.Second)
+}
+
+func TestDispatchDue_LastAttempt_GivesUp(t *testing.T) {
+	outbox := &fakeOutbox{claimed: []migrations.ClaimedDelivery{
+		claimedDelivery("http://127.0.0.1:1", webhook.MaxAttempts),
+	}}
+
+	_, err := webhook.NewDispatcher(outbox, http.DefaultClient, slog.Default()).DispatchDue(context.Background())
+
+	require.NoError(t, err)
+	attempt := outbox.attempts[7]
+	assert.False(t, attempt.Delivered)
+	assert.NotEmpty(t, attempt.Error)
+	assert.Nil(t, attempt.NextAttemptAt)
+}
+
+func TestDispatchDue_PostsBatchInParallel(t *testing.T) {
+	// Each post is held until all of them have arrived, so a sequential
+	// dispatcher would never get past the first.
+	const n = 3
+	var arrived sync.WaitGroup
+	arrived.Add(n)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		arrived.Done()
+		arrived.Wait()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	outbox := &fakeOutbox{}
+	for id := int64(1); id <= n; id++ {
+		c := claimedDelivery(srv.URL, 1)
+		c.Delivery.ID = id
+		outbox.claimed = append(outbox.claimed, c)
+	}
+
+	claimed, err := webhook.NewDispatcher(outbox, http.DefaultClient, slog.Default()).DispatchDue(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, n, claimed)
+	for id := int64(1); id <= n; id++ {
+		assert.True(t, outbox.attempts[id].Delivered)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, webhook.Backoff(1))
+	assert.Equal(t, time.Minute, webhook.Backoff(2))
+	assert.Equal(t, 4*time.Minute, webhook.Backoff(4))
+	assert.Equal(t, time.Hour, webhook.Backoff(9))
+}
@@ -0,0 +1,116 @@
+package migrations
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// Webhook events a subscription can filter on. WebhookEventFor derives them
+// from step events.
+const (
+	WebhookPROpened      = "pr_opened"      // a step is waiting on a pull request it opened
+	WebhookReviewWaiting = "review_waiting" // a step is waiting for someone to complete it
+	WebhookStepSucceeded = "step_succeeded"
+	WebhookStepFailed    = "step_failed"
+	WebhookStepTimedOut  = "step_timed_out"
+	WebhookRunStarted    = "run_started"
+	WebhookRunCompleted  = "run_completed"
+	WebhookRunCancelled  = "run_cancelled"
+)
+
+// WebhookEvents lists every webhook event, in the order they usually occur.
+var WebhookEvents = []string{
+	WebhookRunStarted,
+	WebhookPROpened,
+	WebhookReviewWaiting,
+	WebhookStepSucceeded,
+	WebhookStepFailed,
+	WebhookStepTimedOut,
+	WebhookRunCompleted,
+	WebhookRunCancelled,
+}
+
+// WebhookEventFor returns the webhook event a step event announces, if any.
+// A pending step that reported a prUrl has opened a PR; any other pending step
+// is waiting for a person.
+func WebhookEventFor(e StepEvent) (string, bool) {
+	switch e.EventType {
+	case EventStepPending:
+		if e.Metadata["prUrl"] != "" {
+			return WebhookPROpened, true
+		}
+		return WebhookReviewWaiting, true
+	case EventStepCompleted:
+		if e.Status == "failed" {
+			return WebhookStepFailed, true
+		}
+		return WebhookStepSucceeded, true
+	case EventStepTimedOut:
+		return WebhookStepTimedOut, true
+	case EventRunStarted, EventRunCompleted, EventRunCancelled:
+		return e.EventType, true
+	}
+	return "", false
+}
+
+// defaultWebhookTemplates render the text of each webhook event unless the
+// subscription overrides it. They are executed with the WebhookDelivery.
+var defaultWebhookTemplates = map[string]string{
+	WebhookPROpened: `PR opened for {{.CandidateID}} at step {{.StepName}} of {{.MigrationID}}: ` +
+		`{{index .Metadata "prUrl"}}`,
+	WebhookReviewWaiting: `{{.CandidateID}} is waiting for review at step {{.StepName}} of {{.MigrationID}}` +
+		`{{with index .Metadata "instructions"}}: {{.}}{{end}}`,
+	WebhookStepSucceeded: `Step {{.StepName}} {{.Status}} for {{.CandidateID}} in {{.MigrationID}}`,
+	WebhookStepFailed: `Step {{.StepName}} failed for {{.CandidateID}} in {{.MigrationID}}` +
+		`{{with index .Metadata "error"}}: {{.}}{{end}}`,
+	WebhookStepTimedOut: `Step {{.StepName}} for {{.CandidateID}} in {{.MigrationID}} passed its timeout`,
+	WebhookRunStarted:   `Run started for {{.CandidateID}} in {{.MigrationID}}`,
+	WebhookRunCompleted: `Run completed for {{.CandidateID}} in {{.MigrationID}}`,
+	WebhookRunCancelled: `Run cancelled for {{.CandidateID}} in {{.MigrationID}}`,
+}
+
+// RenderWebhookText renders the text of a delivery with the subscription's
+// template for its event, or the default one.
+func RenderWebhookText(templates map[string]string, d WebhookDelivery) (string, error) {
+	text, ok := templates[d.Event]
+	if !ok {
+		text = defaultWebhookTemplates[d.Event]
+	}
+	tmpl, err := template.New(d.Event).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parse %s template: %w", d.Event, err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, d); err != nil {
+		return "", fmt.Errorf("render %s template: %w", d.Event, err)
+	}
+	return b.String(), nil
+}
+
+// validateWebhook checks a subscription before it is saved.
+func validateWebhook(w WebhookSubscription) error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return InvalidWebhookError{Reason: "url must be an absolute http or https URL"}
+	}
+	if len(w.Events) == 0 {
+		return InvalidWebhookError{Reason: "events must name at least one event"}
+	}
+	for _, e := range w.Events {
+		if !slices.Contains(WebhookEvents, e) {
+			return InvalidWebhookError{Reason: fmt.Sprintf("unknown event %q", e)}
+		}
+	}
+	for event := range w.Templates {
+		if !slices.Contains(WebhookEvents, event) {
+			return InvalidWebhookError{Reason: fmt.Sprintf("template for unknown event %q", event)}
+		}
+		if _, err := RenderWebhookText(w.Templates, WebhookDelivery{Event: event}); err != nil {
+			return InvalidWebhookError{Reason: err.Error()}
+		}
+	}
+	return nil
+}
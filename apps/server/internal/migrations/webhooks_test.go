@@ -0,0 +1,56 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+)
+
+func TestWebhookEventFor(t *testing.T) {
+	cases := []struct {
+		event migrations.StepEvent
+		want  string
+	}{
+		{migrations.StepEvent{EventType: migrations.EventStepPending, Metadata: map[string]string{"prUrl": "u"}},
+			migrations.WebhookPROpened},
+		{migrations.StepEvent{EventType: migrations.EventStepPending}, migrations.WebhookReviewWaiting},
+		{migrations.StepEvent{EventType: migrations.EventStepCompleted, Status: "succeeded"},
+			migrations.WebhookStepSucceeded},
+		{migrations.StepEvent{EventType: migrations.EventStepCompleted, Status: "merged"},
+			migrations.WebhookStepSucceeded},
+		{migrations.StepEvent{EventType: migrations.EventStepCompleted, Status: "failed"},
+			migrations.WebhookStepFailed},
+		{migrations.StepEvent{EventType: migrations.EventStepTimedOut}, migrations.WebhookStepTimedOut},
+		{migrations.StepEvent{EventType: migrations.EventRunStarted}, migrations.WebhookRunStarted},
+		{migrations.StepEvent{EventType: migrations.EventRunCompleted}, migrations.WebhookRunCompleted},
+		{migrations.StepEvent{EventType: migrations.EventRunCancelled}, migrations.WebhookRunCancelled},
+	}
+	for _, tc := range cases {
+		got, ok := migrations.WebhookEventFor(tc.event)
+		assert.True(t, ok, tc.event.EventType)
+		assert.Equal(t, tc.want, got, tc.event.EventType)
+	}
+
+	_, ok := migrations.WebhookEventFor(migrations.StepEvent{EventType: migrations.EventStepDispatched})
+	assert.False(t, ok, "dispatches are not announced")
+}
+
+func TestRenderWebhookText(t *testing.T) {
+	d := migrations.WebhookDelivery{
+		Event: migrations.WebhookStepFailed, MigrationID: "app-chart-migration", CandidateID: "billing-api",
+		StepName: "update-chart", Status: "failed", Metadata: map[string]string{"error": "conflict"},
+	}
+
+	text, err := migrations.RenderWebhookText(nil, d)
+	require.NoError(t, err)
+	assert.Equal(t, "Step update-chart failed for billing-api in app-chart-migration: conflict", text)
+
+	text, err = migrations.RenderWebhookText(map[string]string{
+		migrations.WebhookStepFailed: "{{.CandidateID}} broke ({{index .Metadata \"missing\"}})",
+	}, d)
+	require.NoError(t, err)
+	assert.Equal(t, "billing-api broke ()", text)
+}
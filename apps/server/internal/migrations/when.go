@@ -0,0 +1,348 @@
+package migrations
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// When expressions gate a step on candidate metadata and the results of steps
+// that ran before it, e.g.
+//
+//	metadata.team == "payments" && steps.generate-app-chart.status != "skipped"
+//
+// Operands are string literals, true/false, and references:
+//
+//	metadata.<key>                    candidate metadata
+//	steps.<step>.status               status of an earlier step
+//	steps.<step>.metadata.<key>       metadata reported by an earlier step
+//
+// Operators are ==, !=, &&, ||, ! and parentheses. Every value is a string; a
+// missing reference is "". A value is true unless it is "" or "false".
+
+// WhenContext is what a when expression is evaluated against.
+type WhenContext struct {
+	Metadata map[string]string        // candidate metadata
+	Steps    map[string]api.StepState // results of earlier steps, by step name
+}
+
+// WhenExpr is a parsed when expression.
+type WhenExpr struct {
+	root whenNode
+	refs []string // steps referenced via steps.<step>...
+}
+
+// ParseWhen parses a when expression.
+func ParseWhen(expr string) (*WhenExpr, error) {
+	toks, err := tokenizeWhen(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &whenParser{toks: toks}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("unexpected %q", p.toks[p.pos].text)
+	}
+	return &WhenExpr{root: root, refs: p.refs}, nil
+}
+
+// Eval reports whether the expression holds for wc.
+func (e *WhenExpr) Eval(wc WhenContext) bool {
+	return truthy(e.root.eval(wc))
+}
+
+// ReferencedSteps returns the names of the steps the expression reads from.
+func (e *WhenExpr) ReferencedSteps() []string {
+	return e.refs
+}
+
+// EvaluateWhen parses and evaluates expr against wc.
+func EvaluateWhen(expr string, wc WhenContext) (bool, error) {
+	e, err := ParseWhen(expr)
+	if err != nil {
+		return false, err
+	}
+	return e.Eval(wc), nil
+}
+
+// ValidateStepConditions checks that every when expression parses and only
+// reads from steps that are guaranteed to have finished before its own step
+// starts, i.e. its direct or indirect dependencies.
+func ValidateStepConditions(steps []api.StepDefinition) error {
+	deps := StepDependencies(steps)
+	for _, s := range steps {
+		if s.When == nil {
+			continue
+		}
+		e, err := ParseWhen(*s.When)
+		if err != nil {
+			return InvalidStepConditionError{StepName: s.Name, Reason: err.Error()}
+		}
+		ancestors := stepAncestors(s.Name, deps)
+		for _, ref := range e.ReferencedSteps() {
+			if !ancestors[ref] {
+				return InvalidStepConditionError{
+					StepName: s.Name,
+					Reason:   fmt.Sprintf("references step %q, which does not run before it", ref),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// stepAncestors returns every step that name depends on, directly or not.
+func stepAncestors(name string, deps map[string][]string) map[string]bool {
+	seen := make(map[string]bool)
+	queue := append([]string(nil), deps[name]...)
+	for len(queue) > 0 {
+		d := queue[0]
+		queue = queue[1:]
+		if seen[d] {
+			continue
+		}
+		seen[d] = true
+		queue = append(queue, deps[d]...)
+	}
+	return seen
+}
+
+func truthy(v string) bool {
+	return v != "" && v != "false"
+}
+
+func boolString(b bool) string {
+	return strconv.FormatBool(b)
+}
+
+// ─── AST ─────────────────────────────────────────────────────────────────────
+
+type whenNode interface {
+	eval(wc WhenContext) string
+}
+
+type whenLiteral string
+
+func (n whenLiteral) eval(WhenContext) string { return string(n) }
+
+type whenMetadataRef struct{ key string }
+
+func (n whenMetadataRef) eval(wc WhenContext) string { return wc.Metadata[n.key] }
+
+type whenStepRef struct {
+	step string
+	key  string // metadata key; empty for the step's status
+}
+
+func (n whenStepRef) eval(wc WhenContext) string {
+	r, ok := wc.Steps[n.step]
+	if !ok {
+		return ""
+	}
+	if n.key == "" {
+		return string(r.Status)
+	}
+	if r.Metadata == nil {
+		return ""
+	}
+	return (*r.Metadata)[n.key]
+}
+
+type whenNot struct{ x whenNode }
+
+func (n whenNot) eval(wc WhenContext) string { return boolString(!truthy(n.x.eval(wc))) }
+
+type whenBinary struct {
+	op   string
+	l, r whenNode
+}
+
+func (n whenBinary) eval(wc WhenContext) string {
+	switch n.op {
+	case "&&":
+		return boolString(truthy(n.l.eval(wc)) && truthy(n.r.eval(wc)))
+	case "||":
+		return boolString(truthy(n.l.eval(wc)) || truthy(n.r.eval(wc)))
+	case "==":
+		return boolString(n.l.eval(wc) == n.r.eval(wc))
+	default: // "!="
+		return boolString(n.l.eval(wc) != n.r.eval(wc))
+	}
+}
+
+// ─── Parser ──────────────────────────────────────────────────────────────────
+
+type whenToken struct {
+	text    string
+	literal bool // a quoted string; text holds the unquoted value
+}
+
+// tokenizeWhen splits expr into operators, quoted strings and bare words.
+// Bare words run until whitespace or an operator character, so references may
+// contain the dots, dashes and slashes found in step names and metadata keys.
+func tokenizeWhen(expr string) ([]whenToken, error) {
+	var toks []whenToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(' || c == ')':
+			toks = append(toks, whenToken{text: string(c)})
+			i++
+		case strings.HasPrefix(expr[i:], "==") || strings.HasPrefix(expr[i:], "!=") ||
+			strings.HasPrefix(expr[i:], "&&") || strings.HasPrefix(expr[i:], "||"):
+			toks = append(toks, whenToken{text: expr[i : i+2]})
+			i += 2
+		case c == '!':
+			toks = append(toks, whenToken{text: "!"})
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(expr) && expr[end] != '"' {
+				if expr[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(expr) {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			s, err := strconv.Unquote(expr[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at offset %d: %w", i, err)
+			}
+			toks = append(toks, whenToken{text: s, literal: true})
+			i = end + 1
+		case c == '=' || c == '&' || c == '|':
+			return nil, fmt.Errorf("unexpected %q at offset %d", c, i)
+		default:
+			end := i
+			for end < len(expr) && !strings.ContainsRune(" \t\n()!=&|\"", rune(expr[end])) {
+				end++
+			}
+			toks = append(toks, whenToken{text: expr[i:end]})
+			i = end
+		}
+	}
+	return toks, nil
+}
+
+type whenParser struct {
+	toks []whenToken
+	pos  int
+	refs []string
+}
+
+func (p *whenParser) peek(op string) bool {
+	return p.pos < len(p.toks) && !p.toks[p.pos].literal && p.toks[p.pos].text == op
+}
+
+func (p *whenParser) parseOr() (whenNode, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek("||") {
+		p.pos++
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = whenBinary{op: "||", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *whenParser) parseAnd() (whenNode, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek("&&") {
+		p.pos++
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = whenBinary{op: "&&", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *whenParser) parseUnary() (whenNode, error) {
+	if p.peek("!") {
+		p.pos++
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return whenNot{x: x}, nil
+	}
+	l, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!="} {
+		if p.peek(op) {
+			p.pos++
+			r, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			return whenBinary{op: op, l: l, r: r}, nil
+		}
+	}
+	return l, nil
+}
+
+func (p *whenParser) parseOperand() (whenNode, error) {
+	if p.pos >= len(p.toks) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	tok := p.toks[p.pos]
+	p.pos++
+	if tok.literal {
+		return whenLiteral(tok.text), nil
+	}
+	switch tok.text {
+	case "(":
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek(")") {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return x, nil
+	case "true", "false":
+		return whenLiteral(tok.text), nil
+	}
+	return p.parseReference(tok.text)
+}
+
+func (p *whenParser) parseReference(ref string) (whenNode, error) {
+	if key, ok := strings.CutPrefix(ref, "metadata."); ok && key != "" {
+		return whenMetadataRef{key: key}, nil
+	}
+	if rest, ok := strings.CutPrefix(ref, "steps."); ok {
+		step, field, _ := strings.Cut(rest, ".")
+		if step != "" {
+			if field == "status" {
+				p.refs = append(p.refs, step)
+				return whenStepRef{step: step}, nil
+			}
+			if key, ok := strings.CutPrefix(field, "metadata."); ok && key != "" {
+				p.refs = append(p.refs, step)
+				return whenStepRef{step: step, key: key}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unknown reference %q: expected metadata.<key>, steps.<step>.status or steps.<step>.metadata.<key>", ref)
+}
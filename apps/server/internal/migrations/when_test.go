@@ -0,0 +1,100 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/pkg/api"
+)
+
+func TestEvaluateWhen(t *testing.T) {
+	chartMeta := map[string]string{"chartVersion": "2.1.0"}
+	wc := migrations.WhenContext{
+		Metadata: map[string]string{"team": "payments", "tier": "critical", "app.kubernetes.io/instance": "billing"},
+		Steps: map[string]api.StepState{
+			"generate-app-chart": {StepName: "generate-app-chart", Status: api.StepStateStatusSucceeded, Metadata: &chartMeta},
+			"disable-prune":      {StepName: "disable-prune", Status: api.StepStateStatusSkipped},
+		},
+	}
+
+	cases := map[string]bool{
+		`metadata.team == "payments"`:                                     true,
+		`metadata.team != "payments"`:                                     false,
+		`metadata.team == "payments" && metadata.tier == "low"`:           false,
+		`metadata.team == "search" || metadata.tier == "critical"`:        true,
+		`!(metadata.team == "search")`:                                    true,
+		`metadata.missing`:                                                false,
+		`metadata.team`:                                                   true,
+		`metadata.app.kubernetes.io/instance == "billing"`:                true,
+		`steps.generate-app-chart.status == "succeeded"`:                  true,
+		`steps.generate-app-chart.metadata.chartVersion == "2.1.0"`:       true,
+		`steps.disable-prune.status != "skipped"`:                         false,
+		`steps.not-run-yet.status == ""`:                                  true,
+		`true && !false`:                                                  true,
+		`metadata.team == "pay\"ments"`:                                   false,
+		`(metadata.tier == "low" || metadata.tier == "critical") && true`: true,
+	}
+	for expr, want := range cases {
+		t.Run(expr, func(t *testing.T) {
+			got, err := migrations.EvaluateWhen(expr, wc)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestParseWhen_Errors(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`team == "payments"`,
+		`metadata.team = "payments"`,
+		`metadata.team == "payments`,
+		`(metadata.team == "payments"`,
+		`metadata.team == "payments")`,
+		`steps.generate-app-chart.config`,
+		`metadata.team ==`,
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := migrations.ParseWhen(expr)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestValidateStepConditions(t *testing.T) {
+	withWhen := func(s api.StepDefinition, when string) api.StepDefinition {
+		s.When = &when
+		return s
+	}
+
+	t.Run("accepts references to earlier steps in a chain", func(t *testing.T) {
+		err := migrations.ValidateStepConditions([]api.StepDefinition{
+			stepDef("a"),
+			stepDef("b"),
+			withWhen(stepDef("c"), `steps.a.status == "succeeded" && metadata.team == "payments"`),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects a reference to a step that may still be running", func(t *testing.T) {
+		err := migrations.ValidateStepConditions([]api.StepDefinition{
+			stepDef("a"),
+			stepDef("b"),
+			withWhen(stepDef("c", "a"), `steps.b.status == "succeeded"`),
+		})
+		var condErr migrations.InvalidStepConditionError
+		require.ErrorAs(t, err, &condErr)
+		assert.Equal(t, "c", condErr.StepName)
+	})
+
+	t.Run("rejects an expression that does not parse", func(t *testing.T) {
+		err := migrations.ValidateStepConditions([]api.StepDefinition{
+			withWhen(stepDef("a"), `metadata.team ==`),
+		})
+		var condErr migrations.InvalidStepConditionError
+		require.ErrorAs(t, err, &condErr)
+	})
+}
@@ -0,0 +1,186 @@
+// Package auth authenticates API callers by bearer token and authorizes them
+// by role, per migration.
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Role is what a caller may do with a migration.
+type Role string
+
+const (
+	// RoleViewer may read migrations, candidates, runs and metrics.
+	RoleViewer Role = "viewer"
+	// RoleOperator may do everything a viewer can, and start, stop and steer runs.
+	RoleOperator Role = "operator"
+	// RoleMigrator is the service account of a migrator: it may announce
+	// migrations, submit candidates and post step events.
+	RoleMigrator Role = "migrator"
+)
+
+// Grant gives a role on a set of migrations. A Migrations entry of "*" matches
+// every migration and an entry ending in "*" matches by prefix; a grant with no
+// entries covers no migration at all.
+type Grant struct {
+	Role       Role     `yaml:"role"`
+	Migrations []string `yaml:"migrations"`
+}
+
+// Principal is an authenticated caller.
+type Principal struct {
+	Subject string
+	Grants  []Grant
+}
+
+// Can reports whether p holds role on migrationID. An empty migrationID asks
+// whether p holds role on any migration at all.
+func (p Principal) Can(role Role, migrationID string) bool {
+	for _, g := range p.Grants {
+		if !implies(g.Role, role) {
+			continue
+		}
+		if migrationID == "" && len(g.Migrations) > 0 {
+			return true
+		}
+		if matchesMigration(g.Migrations, migrationID) {
+			return true
+		}
+	}
+	return false
+}
+
+// implies reports whether holding granted satisfies a requirement for required.
+func implies(granted, required Role) bool {
+	return granted == required || (granted == RoleOperator && required == RoleViewer)
+}
+
+func matchesMigration(patterns []string, migrationID string) bool {
+	for _, p := range patterns {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(migrationID, prefix) {
+				return true
+			}
+			continue
+		}
+		if p == migrationID {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrUnknownToken is returned by an Authenticator for a token it does not
+// recognise, so the next authenticator can try it.
+var ErrUnknownToken = errors.New("unknown token")
+
+// Authenticator resolves a bearer token to the Principal it belongs to.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (Principal, error)
+}
+
+const principalKey = "auth.principal"
+
+// ErrMissingToken is returned by Authenticate for a request without a bearer token.
+var ErrMissingToken = errors.New("missing bearer token")
+
+// Authenticate resolves the bearer token in an Authorization header value,
+// trying each authenticator in turn. It returns ErrMissingToken when there is
+// no token, ErrUnknownToken when no authenticator recognises it, and the
+// error of the authenticator that rejected it otherwise.
+func Authenticate(ctx context.Context, authorization string, authenticators ...Authenticator) (Principal, error) {
+	token, ok := bearerToken(authorization)
+	if !ok {
+		return Principal{}, ErrMissingToken
+	}
+	for _, a := range authenticators {
+		p, err := a.Authenticate(ctx, token)
+		if errors.Is(err, ErrUnknownToken) {
+			continue
+		}
+		return p, err
+	}
+	return Principal{}, ErrUnknownToken
+}
+
+// Middleware authenticates every request by its bearer token with
+// Authenticate, and rejects it with 401 when none accepts it. The caller's
+// Principal is stored on the Gin context for Require and Allowed.
+func Middleware(authenticators ...Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, err := Authenticate(c.Request.Context(), c.GetHeader("Authorization"), authenticators...)
+		if err != nil {
+			if errors.Is(err, ErrMissingToken) {
+				c.Header("WWW-Authenticate", "Bearer")
+			} else {
+				c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.Set(principalKey, p)
+		c.Next()
+	}
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// PrincipalFrom returns the caller authenticated by Middleware. It returns
+// false when the request did not pass through Middleware, i.e. auth is disabled.
+func PrincipalFrom(c *gin.Context) (Principal, bool) {
+	v, ok := c.Get(principalKey)
+	if !ok {
+		return Principal{}, false
+	}
+	p, ok := v.(Principal)
+	return p, ok
+}
+
+// Allowed reports whether the caller holds role on migrationID. Every request
+// is allowed when auth is disabled.
+func Allowed(c *gin.Context, role Role, migrationID string) bool {
+	p, ok := PrincipalFrom(c)
+	if !ok {
+		return true
+	}
+	return p.Can(role, migrationID)
+}
+
+// Require builds a middleware that rejects the request with 403 unless the
+// caller holds role on the migration that migrationID reads from the request.
+// A nil migrationID requires the role on any migration; the handler is then
+// expected to narrow the response itself with Allowed.
+func Require(role Role, migrationID func(*gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := ""
+		if migrationID != nil {
+			id = migrationID(c)
+		}
+		if !Allowed(c, role, id) {
+			Forbid(c, role, id)
+			return
+		}
+		c.Next()
+	}
+}
+
+// Forbid aborts the request with 403, naming the missing role.
+func Forbid(c *gin.Context, role Role, migrationID string) {
+	msg := "requires role " + string(role)
+	if migrationID != "" {
+		msg += " on migration " + migrationID
+	}
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": msg})
+}
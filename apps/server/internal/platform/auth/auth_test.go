@@ -0,0 +1,127 @@
+package auth_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/platform/auth"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func sha(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	tokens, err := auth.NewStaticTokens([]auth.TokenConfig{
+		{Name: "viewer", SHA256: sha("viewer-token"), Grants: []auth.Grant{{Role: auth.RoleViewer, Migrations: []string{"*"}}}},
+		{
+			Name:   "payments-operator",
+			SHA256: sha("operator-token"),
+			Grants: []auth.Grant{{Role: auth.RoleOperator, Migrations: []string{"payments-*"}}},
+		},
+	})
+	require.NoError(t, err)
+
+	byParam := func(c *gin.Context) string { return c.Param("id") }
+	r := gin.New()
+	r.Use(auth.Middleware(tokens))
+	r.GET("/migrations/:id", auth.Require(auth.RoleViewer, byParam), func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/migrations/:id/start", auth.Require(auth.RoleOperator, byParam), func(c *gin.Context) {
+		c.Status(http.StatusAccepted)
+	})
+	return r
+}
+
+func do(r *gin.Engine, method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_MissingToken_Returns401(t *testing.T) {
+	w := do(newRouter(t), http.MethodGet, "/migrations/payments-v2", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "Bearer", w.Header().Get("WWW-Authenticate"))
+}
+
+func TestMiddleware_UnknownToken_Returns401(t *testing.T) {
+	w := do(newRouter(t), http.MethodGet, "/migrations/payments-v2", "nope")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRequire_ViewerCanRead(t *testing.T) {
+	w := do(newRouter(t), http.MethodGet, "/migrations/payments-v2", "viewer-token")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequire_ViewerCannotOperate_Returns403(t *testing.T) {
+	w := do(newRouter(t), http.MethodPost, "/migrations/payments-v2/start", "viewer-token")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestRequire_OperatorScopedToMigrations(t *testing.T) {
+	r := newRouter(t)
+
+	assert.Equal(t, http.StatusAccepted, do(r, http.MethodPost, "/migrations/payments-v2/start", "operator-token").Code)
+	assert.Equal(t, http.StatusOK, do(r, http.MethodGet, "/migrations/payments-v2", "operator-token").Code)
+	assert.Equal(t, http.StatusForbidden, do(r, http.MethodPost, "/migrations/billing-v2/start", "operator-token").Code)
+}
+
+func TestAllowed_AuthDisabled(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	assert.True(t, auth.Allowed(c, auth.RoleOperator, "anything"))
+}
+
+func TestPrincipal_Can(t *testing.T) {
+	p := auth.Principal{Grants: []auth.Grant{
+		{Role: auth.RoleMigrator, Migrations: []string{"app-chart-migration"}},
+	}}
+
+	assert.True(t, p.Can(auth.RoleMigrator, "app-chart-migration"))
+	assert.True(t, p.Can(auth.RoleMigrator, ""), "empty migration ID asks for the role on any migration")
+	assert.False(t, p.Can(auth.RoleMigrator, "other-migration"))
+	assert.False(t, p.Can(auth.RoleViewer, "app-chart-migration"), "migrator does not imply viewer")
+	assert.False(t, p.Can(auth.RoleOperator, "app-chart-migration"))
+}
+
+func TestPrincipal_Can_EmptyMigrationsGrantNothing(t *testing.T) {
+	p := auth.Principal{Grants: []auth.Grant{{Role: auth.RoleOperator}}}
+
+	assert.False(t, p.Can(auth.RoleOperator, "app-chart-migration"))
+	assert.False(t, p.Can(auth.RoleViewer, "app-chart-migration"))
+	assert.False(t, p.Can(auth.RoleOperator, ""), "a grant without migrations is not a role on any migration")
+}
+
+func TestPrincipal_Can_Wildcard(t *testing.T) {
+	p := auth.Principal{Grants: []auth.Grant{{Role: auth.RoleViewer, Migrations: []string{"*"}}}}
+
+	assert.True(t, p.Can(auth.RoleViewer, "app-chart-migration"))
+	assert.True(t, p.Can(auth.RoleViewer, ""))
+}
+
+func TestNewStaticTokens_RejectsBadConfig(t *testing.T) {
+	_, err := auth.NewStaticTokens([]auth.TokenConfig{{Name: "x", SHA256: "not-hex"}})
+	require.Error(t, err)
+
+	_, err = auth.NewStaticTokens([]auth.TokenConfig{
+		{Name: "x", SHA256: sha("t"), Grants: []auth.Grant{{Role: "admin"}}},
+	})
+	require.Error(t, err)
+}
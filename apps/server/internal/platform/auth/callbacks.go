@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/tilsley/loom/pkg/signing"
+)
+
+// CallbackSecretConfig is the secret a migrator signs its step callbacks with.
+// Secret may reference environment variables, e.g. ${APP_CHART_CALLBACK_SECRET}.
+// Migrations takes the same patterns as a Grant; a secret without any covers no
+// migration.
+type CallbackSecretConfig struct {
+	Migrator   string   `yaml:"migrator"`
+	Secret     string   `yaml:"secret"`
+	Migrations []string `yaml:"migrations"`
+}
+
+// CallbackVerifier rejects step callbacks that are unsigned, signed with the
+// wrong secret, or outside the timestamp tolerance.
+//
+// A callback that carries an eventId may be sent again: a migrator resends it
+// when a response is lost, and the resend can carry the same signature. Such
+// callbacks are deduplicated by event ID downstream, durably and across
+// replicas, and acknowledged as duplicates. A callback without an eventId is
+// rejected when its signature has been seen before; that check is held in
+// process memory, so it only stops replays sent to the same replica.
+type CallbackVerifier struct {
+	secrets   []CallbackSecretConfig
+	tolerance time.Duration
+	now       func() time.Time
+
+	mu   sync.Mutex
+	seen map[string]time.Time // signature -> when it stops being replayable
+}
+
+// NewCallbackVerifier builds a CallbackVerifier from the configured secrets.
+func NewCallbackVerifier(secrets []CallbackSecretConfig) (*CallbackVerifier, error) {
+	expanded := make([]CallbackSecretConfig, 0, len(secrets))
+	for _, s := range secrets {
+		s.Secret = os.ExpandEnv(s.Secret)
+		if s.Secret == "" {
+			return nil, fmt.Errorf("callback secret for migrator %q is empty", s.Migrator)
+		}
+		expanded = append(expanded, s)
+	}
+	return &CallbackVerifier{
+		secrets:   expanded,
+		tolerance: signing.DefaultTolerance,
+		now:       time.Now,
+		seen:      make(map[string]time.Time),
+	}, nil
+}
+
+// Middleware verifies the signature of each callback against the secret of
+// the migration that migrationID reads from the request. target reads what
+// the signature covers besides the body (the run ID for step callbacks).
+func (v *CallbackVerifier) Middleware(target, migrationID func(*gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		err = v.Verify(migrationID(c), target(c), body,
+			c.GetHeader(signing.TimestampHeader), c.GetHeader(signing.SignatureHeader), hasEventID(body))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.Next()
+	}
+}
+
+// Verify checks the signature of a callback for migrationID over target and
+// body. hasEventID reports whether the callback carries an eventId, which
+// exempts it from the replay check (see CallbackVerifier).
+func (v *CallbackVerifier) Verify(
+	migrationID, target string,
+	body []byte,
+	timestamp, signature string,
+	hasEventID bool,
+) error {
+	secret, ok := v.secretFor(migrationID)
+	if !ok {
+		return errors.New("no callback secret configured for migration " + migrationID)
+	}
+	now := v.now()
+	if err := signing.Verify(secret, target, body, timestamp, signature, now, v.tolerance); err != nil {
+		return err
+	}
+	if !hasEventID && !v.firstUse(signature, now) {
+		return errors.New("replayed callback")
+	}
+	return nil
+}
+
+func (v *CallbackVerifier) secretFor(migrationID string) (string, bool) {
+	for _, s := range v.secrets {
+		if matchesMigration(s.Migrations, migrationID) {
+			return s.Secret, true
+		}
+	}
+	return "", false
+}
+
+// hasEventID reports whether the callback body carries an eventId.
+func hasEventID(body []byte) bool {
+	var event struct {
+		EventID string `json:"eventId"`
+	}
+	return json.Unmarshal(body, &event) == nil && event.EventID != ""
+}
+
+// firstUse records sig and reports whether it had not been seen before. A
+// signature only needs remembering while its timestamp is within tolerance;
+// after that Verify rejects it anyway.
+func (v *CallbackVerifier) firstUse(sig string, now time.Time) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for s, expires := range v.seen {
+		if now.After(expires) {
+			delete(v.seen, s)
+		}
+	}
+	if _, ok := v.seen[sig]; ok {
+		return false
+	}
+	v.seen[sig] = now.Add(2 * v.tolerance)
+	return true
+}
@@ -0,0 +1,112 @@
+package auth_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/platform/auth"
+	"github.com/tilsley/loom/pkg/signing"
+)
+
+func newCallbackRouter(t *testing.T) (*gin.Engine, *string) {
+	t.Helper()
+	t.Setenv("TEST_CALLBACK_SECRET", "s3cret")
+	v, err := auth.NewCallbackVerifier([]auth.CallbackSecretConfig{
+		{Migrator: "app-chart-migrator", Secret: "${TEST_CALLBACK_SECRET}", Migrations: []string{"app-chart-migration"}},
+	})
+	require.NoError(t, err)
+
+	var received string
+	runID := func(c *gin.Context) string { return c.Param("id") }
+	migrationID := func(c *gin.Context) string { return strings.SplitN(c.Param("id"), "__", 2)[0] }
+	r := gin.New()
+	r.POST("/event/:id", v.Middleware(runID, migrationID), func(c *gin.Context) {
+		b, _ := io.ReadAll(c.Request.Body)
+		received = string(b)
+		c.Status(http.StatusAccepted)
+	})
+	return r, &received
+}
+
+func postCallback(r *gin.Engine, runID, body, secret string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/event/"+runID, bytes.NewBufferString(body))
+	if secret != "" {
+		signing.SignRequest(req, secret, runID, []byte(body), time.Now())
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCallbackVerifier_AcceptsSignedCallback(t *testing.T) {
+	r, received := newCallbackRouter(t)
+
+	w := postCallback(r, "app-chart-migration__billing-api", `{"status":"succeeded"}`, "s3cret")
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	assert.JSONEq(t, `{"status":"succeeded"}`, *received, "handler still reads the body")
+}
+
+func TestCallbackVerifier_RejectsUnsignedOrWronglySigned(t *testing.T) {
+	r, _ := newCallbackRouter(t)
+
+	assert.Equal(t, http.StatusUnauthorized, postCallback(r, "app-chart-migration__billing-api", `{}`, "").Code)
+	assert.Equal(t, http.StatusUnauthorized, postCallback(r, "app-chart-migration__billing-api", `{}`, "guess").Code)
+}
+
+// sendTwice sends the same signed callback twice and returns both responses.
+func sendTwice(r *gin.Engine, body string) (*httptest.ResponseRecorder, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodPost, "/event/app-chart-migration__billing-api", bytes.NewBufferString(body))
+	signing.SignRequest(req, "s3cret", "app-chart-migration__billing-api", []byte(body), time.Now())
+
+	replay := req.Clone(req.Context())
+	replay.Body = io.NopCloser(bytes.NewBufferString(body))
+
+	first := httptest.NewRecorder()
+	r.ServeHTTP(first, req)
+	second := httptest.NewRecorder()
+	r.ServeHTTP(second, replay)
+	return first, second
+}
+
+func TestCallbackVerifier_RejectsReplayWithoutEventID(t *testing.T) {
+	r, _ := newCallbackRouter(t)
+
+	first, second := sendTwice(r, `{"status":"merged"}`)
+
+	assert.Equal(t, http.StatusAccepted, first.Code)
+	assert.Equal(t, http.StatusUnauthorized, second.Code)
+}
+
+func TestCallbackVerifier_AcceptsResendWithEventID(t *testing.T) {
+	// A resend within the same second carries the same signature; the handler
+	// deduplicates it by event ID.
+	r, _ := newCallbackRouter(t)
+
+	first, second := sendTwice(r, `{"status":"merged","eventId":"evt-1"}`)
+
+	assert.Equal(t, http.StatusAccepted, first.Code)
+	assert.Equal(t, http.StatusAccepted, second.Code)
+}
+
+func TestCallbackVerifier_RejectsMigrationWithoutSecret(t *testing.T) {
+	r, _ := newCallbackRouter(t)
+
+	w := postCallback(r, "other-migration__billing-api", `{}`, "s3cret")
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestNewCallbackVerifier_RejectsEmptySecret(t *testing.T) {
+	_, err := auth.NewCallbackVerifier([]auth.CallbackSecretConfig{{Migrator: "m", Secret: "${UNSET_CALLBACK_SECRET}"}})
+	require.Error(t, err)
+}
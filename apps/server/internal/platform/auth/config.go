@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the auth config file: static API tokens and/or an OIDC issuer
+// for callers of the API, and the secrets migrators sign step callbacks with.
+//
+//	tokens:
+//	  - name: app-chart-migrator
+//	    sha256: 5e88...        # sha256 of the token, hex encoded
+//	    grants:
+//	      - role: migrator
+//	        migrations: [app-chart-migration]
+//	oidc:
+//	  issuer: https://login.example.com
+//	  audience: loom
+//	  bindings:
+//	    - group: platform-team
+//	      grants:
+//	        - role: operator
+//	          migrations: ["*"]
+//	callbackSecrets:
+//	  - migrator: app-chart-migrator
+//	    secret: ${APP_CHART_CALLBACK_SECRET}
+//	    migrations: [app-chart-migration]
+type Config struct {
+	Tokens          []TokenConfig          `yaml:"tokens"`
+	OIDC            *OIDCConfig            `yaml:"oidc"`
+	CallbackSecrets []CallbackSecretConfig `yaml:"callbackSecrets"`
+}
+
+// LoadConfig reads a Config from a YAML file.
+func LoadConfig(path string) (Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("read auth config: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parse auth config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Authenticators builds the authenticators cfg describes, static tokens first.
+// It returns none when cfg configures neither tokens nor OIDC.
+func Authenticators(ctx context.Context, cfg Config, client *http.Client) ([]Authenticator, error) {
+	var out []Authenticator
+	if len(cfg.Tokens) > 0 {
+		s, err := NewStaticTokens(cfg.Tokens)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	if cfg.OIDC != nil {
+		o, err := NewOIDC(ctx, *cfg.OIDC, client)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, o)
+	}
+	return out, nil
+}
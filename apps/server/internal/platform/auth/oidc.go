@@ -0,0 +1,295 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// OIDCConfig configures verification of OIDC/JWT bearer tokens.
+type OIDCConfig struct {
+	// Issuer must match the token's iss claim.
+	Issuer string `yaml:"issuer"`
+	// Audience must be one of the token's aud claim values.
+	Audience string `yaml:"audience"`
+	// JWKSURL is where the issuer publishes its signing keys. When empty it is
+	// discovered from the issuer's /.well-known/openid-configuration.
+	JWKSURL string `yaml:"jwksUrl"`
+	// GroupsClaim names the claim holding the caller's groups. Defaults to "groups".
+	GroupsClaim string `yaml:"groupsClaim"`
+	// Bindings map groups to grants.
+	Bindings []GroupBinding `yaml:"bindings"`
+}
+
+// GroupBinding gives everyone in Group the listed grants.
+type GroupBinding struct {
+	Group  string  `yaml:"group"`
+	Grants []Grant `yaml:"grants"`
+}
+
+// signingMethods are the asymmetric algorithms accepted in tokens. HMAC is
+// excluded so a public key can never be used as a shared secret.
+var signingMethods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+// jwksRefreshInterval limits how often an unknown key ID triggers a refetch
+// of the issuer's keys, e.g. after a key rotation.
+const jwksRefreshInterval = time.Minute
+
+// OIDC authenticates callers by JWTs issued by an OIDC provider, mapping the
+// groups in the token to grants.
+type OIDC struct {
+	cfg    OIDCConfig
+	client *http.Client
+	parser *jwt.Parser
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	lastFetched time.Time
+}
+
+// NewOIDC builds an OIDC authenticator and fetches the issuer's signing keys.
+func NewOIDC(ctx context.Context, cfg OIDCConfig, client *http.Client) (*OIDC, error) {
+	if cfg.Issuer == "" || cfg.Audience == "" {
+		return nil, errors.New("oidc: issuer and audience are required")
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = "groups"
+	}
+	for _, b := range cfg.Bindings {
+		if err := validateGrants(b.Grants); err != nil {
+			return nil, fmt.Errorf("oidc: group %q: %w", b.Group, err)
+		}
+	}
+	o := &OIDC{
+		cfg:    cfg,
+		client: client,
+		parser: jwt.NewParser(jwt.WithValidMethods(signingMethods)),
+	}
+	if o.cfg.JWKSURL == "" {
+		u, err := o.discoverJWKSURL(ctx)
+		if err != nil {
+			return nil, err
+		}
+		o.cfg.JWKSURL = u
+	}
+	if err := o.refreshKeys(ctx); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Authenticate verifies token and returns its Principal. Tokens that are not
+// JWTs, or were issued by another issuer, are reported as ErrUnknownToken.
+func (o *OIDC) Authenticate(ctx context.Context, token string) (Principal, error) {
+	if strings.Count(token, ".") != 2 {
+		return Principal{}, ErrUnknownToken
+	}
+	unverified := jwt.MapClaims{}
+	if _, _, err := o.parser.ParseUnverified(token, unverified); err != nil {
+		return Principal{}, ErrUnknownToken
+	}
+	if !unverified.VerifyIssuer(o.cfg.Issuer, true) {
+		return Principal{}, ErrUnknownToken
+	}
+
+	parsed, err := o.parser.Parse(token, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return o.key(ctx, kid)
+	})
+	if err != nil {
+		return Principal{}, fmt.Errorf("invalid token: %w", err)
+	}
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok {
+		return Principal{}, errors.New("invalid token: unexpected claims")
+	}
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return Principal{}, errors.New("invalid token: missing or expired exp")
+	}
+	if !claims.VerifyAudience(o.cfg.Audience, true) {
+		return Principal{}, errors.New("invalid token: audience mismatch")
+	}
+
+	sub, _ := claims["sub"].(string)
+	return Principal{Subject: sub, Grants: o.grantsFor(claimStrings(claims[o.cfg.GroupsClaim]))}, nil
+}
+
+func (o *OIDC) grantsFor(groups []string) []Grant {
+	var grants []Grant
+	for _, b := range o.cfg.Bindings {
+		for _, g := range groups {
+			if g == b.Group {
+				grants = append(grants, b.Grants...)
+				break
+			}
+		}
+	}
+	return grants
+}
+
+// claimStrings reads a claim that is either a string or a list of strings.
+func claimStrings(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, s := range v {
+			if s, ok := s.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// key returns the signing key with the given ID, refetching the issuer's keys
+// (at most once per jwksRefreshInterval) when it is not known yet.
+func (o *OIDC) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	o.mu.Lock()
+	k, ok := o.keys[kid]
+	stale := time.Since(o.lastFetched) > jwksRefreshInterval
+	o.mu.Unlock()
+	if ok {
+		return k, nil
+	}
+	if stale {
+		if err := o.refreshKeys(ctx); err != nil {
+			return nil, err
+		}
+		o.mu.Lock()
+		k, ok = o.keys[kid]
+		o.mu.Unlock()
+		if ok {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (o *OIDC) discoverJWKSURL(ctx context.Context) (string, error) {
+	var doc struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	u := strings.TrimSuffix(o.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := o.getJSON(ctx, u, &doc); err != nil {
+		return "", fmt.Errorf("oidc discovery: %w", err)
+	}
+	if doc.JWKSURI == "" {
+		return "", errors.New("oidc discovery: no jwks_uri")
+	}
+	return doc.JWKSURI, nil
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (o *OIDC) refreshKeys(ctx context.Context) error {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := o.getJSON(ctx, o.cfg.JWKSURL, &set); err != nil {
+		return fmt.Errorf("fetch jwks: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		pub, err := k.publicKey()
+		if err != nil {
+			// Skip keys we cannot use (e.g. encryption keys) rather than fail.
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+
+	o.mu.Lock()
+	o.keys = keys
+	o.lastFetched = time.Now()
+	o.mu.Unlock()
+	return nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func (o *OIDC) getJSON(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { //nolint:errcheck // response body close errors are non-actionable after reading
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,138 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/apps/server/internal/platform/auth"
+)
+
+type testIssuer struct {
+	srv *httptest.Server
+	key *rsa.PrivateKey
+}
+
+// newTestIssuer serves OIDC discovery and a JWKS holding one RSA key.
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	iss := &testIssuer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"jwks_uri": iss.srv.URL + "/keys"}) //nolint:errcheck
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{ //nolint:errcheck
+			"kid": "k1",
+			"kty": "RSA",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	iss.srv = httptest.NewServer(mux)
+	t.Cleanup(iss.srv.Close)
+	return iss
+}
+
+func (i *testIssuer) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	tok.Header["kid"] = "k1"
+	s, err := tok.SignedString(i.key)
+	require.NoError(t, err)
+	return s
+}
+
+func (i *testIssuer) claims(groups ...string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":    i.srv.URL,
+		"aud":    "loom",
+		"sub":    "alice@example.com",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"groups": groups,
+	}
+}
+
+func newOIDC(t *testing.T, iss *testIssuer) *auth.OIDC {
+	t.Helper()
+	o, err := auth.NewOIDC(context.Background(), auth.OIDCConfig{
+		Issuer:   iss.srv.URL,
+		Audience: "loom",
+		Bindings: []auth.GroupBinding{
+			{Group: "platform", Grants: []auth.Grant{{Role: auth.RoleOperator, Migrations: []string{"*"}}}},
+			{Group: "payments", Grants: []auth.Grant{{Role: auth.RoleViewer, Migrations: []string{"payments-v2"}}}},
+		},
+	}, iss.srv.Client())
+	require.NoError(t, err)
+	return o
+}
+
+func TestOIDC_MapsGroupsToGrants(t *testing.T) {
+	iss := newTestIssuer(t)
+	o := newOIDC(t, iss)
+
+	p, err := o.Authenticate(context.Background(), iss.sign(t, iss.claims("payments")))
+
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", p.Subject)
+	assert.True(t, p.Can(auth.RoleViewer, "payments-v2"))
+	assert.False(t, p.Can(auth.RoleViewer, "billing-v2"))
+	assert.False(t, p.Can(auth.RoleOperator, "payments-v2"))
+}
+
+func TestOIDC_RejectsInvalidTokens(t *testing.T) {
+	iss := newTestIssuer(t)
+	o := newOIDC(t, iss)
+
+	expired := iss.claims("platform")
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+	wrongAudience := iss.claims("platform")
+	wrongAudience["aud"] = "someone-else"
+	noExpiry := iss.claims("platform")
+	delete(noExpiry, "exp")
+
+	for name, claims := range map[string]jwt.MapClaims{
+		"expired":        expired,
+		"wrong audience": wrongAudience,
+		"no expiry":      noExpiry,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := o.Authenticate(context.Background(), iss.sign(t, claims))
+			require.Error(t, err)
+			assert.NotErrorIs(t, err, auth.ErrUnknownToken)
+		})
+	}
+
+	t.Run("signed by another key", func(t *testing.T) {
+		other := newTestIssuer(t)
+		claims := iss.claims("platform")
+		_, err := o.Authenticate(context.Background(), other.sign(t, claims))
+		require.Error(t, err)
+	})
+}
+
+func TestOIDC_LeavesForeignTokensToOtherAuthenticators(t *testing.T) {
+	iss := newTestIssuer(t)
+	o := newOIDC(t, iss)
+
+	_, err := o.Authenticate(context.Background(), "static-api-token")
+	require.ErrorIs(t, err, auth.ErrUnknownToken)
+
+	other := newTestIssuer(t)
+	_, err = o.Authenticate(context.Background(), other.sign(t, other.claims("platform")))
+	require.ErrorIs(t, err, auth.ErrUnknownToken)
+}
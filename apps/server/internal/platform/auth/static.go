@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// TokenConfig is a static API token. Only the SHA-256 of the token is
+// configured, so the config file holds no secrets.
+type TokenConfig struct {
+	Name   string  `yaml:"name"`
+	SHA256 string  `yaml:"sha256"`
+	Grants []Grant `yaml:"grants"`
+}
+
+type staticToken struct {
+	hash      []byte
+	principal Principal
+}
+
+// StaticTokens authenticates callers by long-lived API tokens, e.g. for
+// migrator service accounts.
+type StaticTokens struct {
+	tokens []staticToken
+}
+
+// NewStaticTokens builds a StaticTokens authenticator from tokens.
+func NewStaticTokens(tokens []TokenConfig) (*StaticTokens, error) {
+	s := &StaticTokens{}
+	for _, t := range tokens {
+		hash, err := hex.DecodeString(strings.TrimSpace(t.SHA256))
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("token %q: sha256 must be 64 hex characters", t.Name)
+		}
+		if err := validateGrants(t.Grants); err != nil {
+			return nil, fmt.Errorf("token %q: %w", t.Name, err)
+		}
+		s.tokens = append(s.tokens, staticToken{
+			hash:      hash,
+			principal: Principal{Subject: t.Name, Grants: t.Grants},
+		})
+	}
+	return s, nil
+}
+
+// Authenticate returns the Principal of the token, or ErrUnknownToken.
+func (s *StaticTokens) Authenticate(_ context.Context, token string) (Principal, error) {
+	sum := sha256.Sum256([]byte(token))
+	for _, t := range s.tokens {
+		if subtle.ConstantTimeCompare(sum[:], t.hash) == 1 {
+			return t.principal, nil
+		}
+	}
+	return Principal{}, ErrUnknownToken
+}
+
+func validateGrants(grants []Grant) error {
+	for _, g := range grants {
+		switch g.Role {
+		case RoleViewer, RoleOperator, RoleMigrator:
+		default:
+			return fmt.Errorf("unknown role %q", g.Role)
+		}
+	}
+	return nil
+}
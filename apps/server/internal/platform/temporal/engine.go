@@ -7,14 +7,18 @@ import (
 	"strings"
 
 	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/workflowservice/v1"
 	"go.temporal.io/sdk/client"
 
 	"github.com/tilsley/loom/apps/server/internal/migrations"
 	"github.com/tilsley/loom/pkg/api"
 )
 
-// Compile-time check: *Engine implements migrations.ExecutionEngine.
-var _ migrations.ExecutionEngine = (*Engine)(nil)
+// Compile-time checks: *Engine implements migrations.ExecutionEngine and migrations.RunLister.
+var (
+	_ migrations.ExecutionEngine = (*Engine)(nil)
+	_ migrations.RunLister       = (*Engine)(nil)
+)
 
 const taskQueue = "loom-migrations"
 
@@ -66,6 +70,7 @@ func (e *Engine) GetStatus(ctx context.Context, instanceID string) (*migrations.
 			var raw json.RawMessage
 			if err := val.Get(&raw); err == nil {
 				ws.Steps = parseStepResults(raw)
+				ws.Paused = parseRunStatus(raw) == "paused"
 			}
 		}
 		return ws, nil
@@ -100,6 +105,51 @@ func (e *Engine) CancelRun(ctx context.Context, instanceID string) error {
 	return nil
 }
 
+// QueryRun runs the named query against a workflow execution and decodes the result into out.
+// Temporal answers queries for closed workflows too, so this works after a run has finished.
+func (e *Engine) QueryRun(ctx context.Context, instanceID, queryType string, out any) error {
+	val, err := e.c.QueryWorkflow(ctx, instanceID, "", queryType)
+	if err != nil {
+		if isNotFound(err) {
+			return migrations.RunNotFoundError{InstanceID: instanceID}
+		}
+		return fmt.Errorf("query %q on %q: %w", queryType, instanceID, err)
+	}
+	if err := val.Get(out); err != nil {
+		return fmt.Errorf("decode %q result for %q: %w", queryType, instanceID, err)
+	}
+	return nil
+}
+
+// ListOpenRuns returns the IDs of the running workflows of the given types,
+// paging through Temporal's visibility store.
+func (e *Engine) ListOpenRuns(ctx context.Context, runTypes ...string) ([]string, error) {
+	quoted := make([]string, len(runTypes))
+	for i, t := range runTypes {
+		quoted[i] = "'" + t + "'"
+	}
+	query := fmt.Sprintf("ExecutionStatus = 'Running' AND WorkflowType IN (%s)", strings.Join(quoted, ", "))
+
+	var ids []string
+	var token []byte
+	for {
+		resp, err := e.c.ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+			Query:         query,
+			NextPageToken: token,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list open workflows: %w", err)
+		}
+		for _, exec := range resp.GetExecutions() {
+			ids = append(ids, exec.GetExecution().GetWorkflowId())
+		}
+		token = resp.GetNextPageToken()
+		if len(token) == 0 {
+			return ids, nil
+		}
+	}
+}
+
 // isNotFound reports whether err indicates a workflow execution was not found.
 // Temporal wraps gRPC NOT_FOUND errors; checking the message is the portable approach.
 func isNotFound(err error) bool {
@@ -120,6 +170,18 @@ func parseStepResults(raw json.RawMessage) []api.StepState {
 	return out.Results
 }
 
+// parseRunStatus extracts the status field from a MigrationOrchestrator result
+// or progress-query payload. Returns "" on any parse failure.
+func parseRunStatus(raw json.RawMessage) string {
+	var out struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return ""
+	}
+	return out.Status
+}
+
 func mapTemporalStatus(s enumspb.WorkflowExecutionStatus) string {
 	switch s {
 	case enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING:
@@ -3,29 +3,37 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.temporal.io/sdk/client"
 	otelcontrib "go.temporal.io/sdk/contrib/opentelemetry"
 	"go.temporal.io/sdk/interceptor"
 	"go.temporal.io/sdk/worker"
 	"go.temporal.io/sdk/workflow"
+	"google.golang.org/grpc"
 
 	"github.com/tilsley/loom/apps/server/internal/migrations"
+	"github.com/tilsley/loom/apps/server/internal/migrations/escalation"
 	"github.com/tilsley/loom/apps/server/internal/migrations/execution"
 	"github.com/tilsley/loom/apps/server/internal/migrations/handler"
 	"github.com/tilsley/loom/apps/server/internal/migrations/migrator"
+	"github.com/tilsley/loom/apps/server/internal/migrations/reconcile"
 	"github.com/tilsley/loom/apps/server/internal/migrations/store"
 	"github.com/tilsley/loom/apps/server/internal/migrations/store/pgmigrations"
+	"github.com/tilsley/loom/apps/server/internal/migrations/webhook"
+	"github.com/tilsley/loom/apps/server/internal/platform/auth"
 	"github.com/tilsley/loom/apps/server/internal/platform/logger"
 	pgplatform "github.com/tilsley/loom/apps/server/internal/platform/postgres"
 	"github.com/tilsley/loom/apps/server/internal/platform/telemetry"
 	temporalplatform "github.com/tilsley/loom/apps/server/internal/platform/temporal"
 	"github.com/tilsley/loom/apps/server/internal/platform/validation"
+	"github.com/tilsley/loom/pkg/migratorpb"
 	"github.com/tilsley/loom/schemas"
 )
 
@@ -56,19 +64,23 @@ func main() {
 
 	// --- Platform: Temporal ---
 
-	hostPort := os.Getenv("TEMPORAL_HOSTPORT")
-	if hostPort == "" {
-		hostPort = "localhost:7233"
-	}
+	// LOOM_ENGINE=local runs migrations in-process instead, so the server
+	// needs nothing but Postgres.
+	localEngine := os.Getenv("LOOM_ENGINE") == "local"
+	var tc client.Client
+	if !localEngine {
+		hostPort := os.Getenv("TEMPORAL_HOSTPORT")
+		if hostPort == "" {
+			hostPort = "localhost:7233"
+		}
 
-	tc, err := client.Dial(client.Options{HostPort: hostPort})
-	if err != nil {
-		slog.Error("temporal client init failed", "error", err)
-		os.Exit(1)
+		tc, err = client.Dial(client.Options{HostPort: hostPort})
+		if err != nil {
+			slog.Error("temporal client init failed", "error", err)
+			os.Exit(1)
+		}
+		defer tc.Close()
 	}
-	defer tc.Close()
-
-	engine := temporalplatform.NewEngine(tc)
 
 	// --- Platform: Postgres ---
 
@@ -86,44 +98,118 @@ func main() {
 
 	var eventStore migrations.EventStore = store.NewPGEventStore(pool)
 	slog.Info("event store enabled (postgres)")
+	auditLog := store.NewPGAuditLog(pool)
+	progressFeed := store.NewPGProgressFeed(pool, slog)
+	go progressFeed.Run(ctx)
+	webhookStore := store.NewPGWebhookStore(pool)
+	freezeCalendar := store.NewPGFreezeCalendar(pool)
+	scheduleStore := store.NewPGScheduleStore(pool)
+	dispatchLimiter := store.NewPGDispatchLimiter(pool)
+	migratorRegistry := store.NewPGMigratorRegistry(pool)
+
+	// --- Execution engine ---
+
+	var engine migrations.ExecutionEngine
+	var runLister migrations.RunLister
+	var local *execution.LocalEngine
+	if localEngine {
+		local = execution.NewLocalEngine(store.NewPGClosedRunStore(pool), slog)
+		engine, runLister = local, local
+		slog.Warn("running migrations in-process; runs in flight do not survive a restart")
+	} else {
+		temporalEngine := temporalplatform.NewEngine(tc)
+		engine, runLister = temporalEngine, temporalEngine
+	}
 
 	// --- Adapters ---
 
 	migrationStore := store.NewPGMigrationStore(pool)
 	httpClient := &http.Client{Timeout: 30 * time.Second}
-	notifier := migrator.NewHTTPMigratorNotifier(httpClient)
-	dryRunner := migrator.NewHTTPDryRunAdapter(httpClient)
-
-	// --- Temporal Worker ---
-
-	activities := execution.NewActivities(notifier, migrationStore, eventStore, slog)
-
-	workerOpts := worker.Options{}
-	if otelEnabled {
-		tracingInterceptor, err := otelcontrib.NewTracingInterceptor(otelcontrib.TracerOptions{})
-		if err != nil {
-			slog.Error("temporal tracing interceptor init failed", "error", err)
+	// Migrators at grpc:// and grpcs:// URLs are called over gRPC, the rest over HTTP.
+	grpcConns := migrator.NewGRPCConns()
+	defer grpcConns.Close() //nolint:errcheck
+	var notifier migrations.MigratorNotifier = migrator.NewNotifier(
+		migrator.NewHTTPMigratorNotifier(httpClient), migrator.NewGRPCMigratorNotifier(grpcConns),
+	)
+	// With a dispatch Redis, steps are queued on a stream per migrator app
+	// instead; dry runs still call the migrator directly.
+	if dispatchRedis := os.Getenv("DISPATCH_REDIS_ADDR"); dispatchRedis != "" {
+		rdb := redis.NewClient(&redis.Options{Addr: dispatchRedis})
+		defer rdb.Close() //nolint:errcheck
+		if err := rdb.Ping(ctx).Err(); err != nil {
+			slog.Error("dispatch redis unreachable", "addr", dispatchRedis, "error", err)
 			os.Exit(1)
 		}
-		workerOpts.Interceptors = []interceptor.WorkerInterceptor{tracingInterceptor}
+		notifier = migrator.NewRedisStreamNotifier(rdb)
+		slog.Info("dispatching steps to redis streams", "addr", dispatchRedis)
 	}
+	dryRunner := migrator.NewDryRunner(
+		migrator.NewHTTPDryRunAdapter(httpClient), migrator.NewGRPCDryRunAdapter(grpcConns),
+	)
+
+	var escalator migrations.StepEscalator
+	if hookURL := os.Getenv("ESCALATION_WEBHOOK_URL"); hookURL != "" {
+		escalator = escalation.NewHTTPHookEscalator(httpClient, hookURL)
+		slog.Info("step escalation hook enabled")
+	}
+
+	go webhook.NewDispatcher(webhookStore, httpClient, slog).Run(ctx)
+	// One replica at a time repairs candidate statuses that drifted from the engine's runs.
+	reconcileLock := store.NewPGLeaderLock(pool, "loom-reconciler")
+	go reconcile.NewReconciler(runLister, migrationStore, eventStore, reconcileLock, slog).Run(ctx)
 
-	w := worker.New(tc, temporalplatform.TaskQueue(), workerOpts)
-	w.RegisterWorkflowWithOptions(execution.MigrationOrchestrator, workflow.RegisterOptions{
-		Name: "MigrationOrchestrator",
-	})
-	w.RegisterActivity(activities)
+	// --- Service ---
 
-	go func() {
-		if err := w.Run(worker.InterruptCh()); err != nil {
-			log.Fatalf("temporal worker failed: %v", err)
+	svc := migrations.NewService(
+		engine, migrationStore, dryRunner, eventStore, auditLog, progressFeed, webhookStore, freezeCalendar,
+		scheduleStore, dispatchLimiter, migratorRegistry,
+	)
+
+	// --- Worker ---
+
+	// The service fires scheduled starts, so they pass the same guards as an
+	// operator's start.
+	activities := execution.NewActivities(
+		notifier, migrationStore, eventStore, escalator, freezeCalendar, dispatchLimiter, migratorRegistry, svc, slog,
+	)
+
+	if local != nil {
+		local.RegisterActivities(activities)
+	} else {
+		workerOpts := worker.Options{}
+		if otelEnabled {
+			tracingInterceptor, err := otelcontrib.NewTracingInterceptor(otelcontrib.TracerOptions{})
+			if err != nil {
+				slog.Error("temporal tracing interceptor init failed", "error", err)
+				os.Exit(1)
+			}
+			workerOpts.Interceptors = []interceptor.WorkerInterceptor{tracingInterceptor}
 		}
-	}()
-	slog.Info("temporal worker started", "taskQueue", temporalplatform.TaskQueue())
 
-	// --- Service + HTTP ---
+		w := worker.New(tc, temporalplatform.TaskQueue(), workerOpts)
+		w.RegisterWorkflowWithOptions(execution.MigrationOrchestrator, workflow.RegisterOptions{
+			Name: "MigrationOrchestrator",
+		})
+		w.RegisterWorkflowWithOptions(execution.BulkStartOrchestrator, workflow.RegisterOptions{
+			Name: migrations.BulkStartRunType,
+		})
+		w.RegisterWorkflowWithOptions(execution.RollbackOrchestrator, workflow.RegisterOptions{
+			Name: migrations.RollbackRunType,
+		})
+		w.RegisterWorkflowWithOptions(execution.ScheduledStartOrchestrator, workflow.RegisterOptions{
+			Name: migrations.ScheduledStartRunType,
+		})
+		w.RegisterActivity(activities)
+
+		go func() {
+			if err := w.Run(worker.InterruptCh()); err != nil {
+				log.Fatalf("temporal worker failed: %v", err)
+			}
+		}()
+		slog.Info("temporal worker started", "taskQueue", temporalplatform.TaskQueue())
+	}
 
-	svc := migrations.NewService(engine, migrationStore, dryRunner, eventStore)
+	// --- HTTP ---
 
 	router := gin.New()
 
@@ -133,8 +219,64 @@ func main() {
 		os.Exit(1)
 	}
 
-	router.Use(gin.Recovery(), otelgin.Middleware(os.Getenv("OTEL_SERVICE_NAME")), validator)
-	handler.RegisterRoutes(router, svc, slog)
+	middleware := []gin.HandlerFunc{gin.Recovery(), otelgin.Middleware(os.Getenv("OTEL_SERVICE_NAME"))}
+
+	// --- Auth ---
+
+	var authCfg auth.Config
+	if authPath := os.Getenv("AUTH_CONFIG"); authPath != "" {
+		authCfg, err = auth.LoadConfig(authPath)
+		if err != nil {
+			slog.Error("auth config load failed", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	authenticators, err := auth.Authenticators(ctx, authCfg, httpClient)
+	if err != nil {
+		slog.Error("auth init failed", "error", err)
+		os.Exit(1)
+	}
+	if len(authenticators) > 0 {
+		middleware = append(middleware, auth.Middleware(authenticators...))
+		slog.Info("api auth enabled", "tokens", len(authCfg.Tokens), "oidc", authCfg.OIDC != nil)
+	} else {
+		slog.Warn("no api tokens or oidc issuer configured: api auth disabled, every route is open")
+	}
+
+	var callbacks *auth.CallbackVerifier
+	if len(authCfg.CallbackSecrets) > 0 {
+		callbacks, err = auth.NewCallbackVerifier(authCfg.CallbackSecrets)
+		if err != nil {
+			slog.Error("callback verifier init failed", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("signed step callbacks required", "migrators", len(authCfg.CallbackSecrets))
+	} else {
+		slog.Warn("no callback secrets configured: step callbacks are accepted unsigned")
+	}
+
+	router.Use(append(middleware, validator)...)
+	handler.RegisterRoutes(router, svc, slog, callbacks)
+
+	// --- gRPC ---
+
+	// The Loom gRPC service makes the same auth and signature checks as the HTTP routes above.
+	if grpcPort := os.Getenv("GRPC_PORT"); grpcPort != "" {
+		lis, err := net.Listen("tcp", ":"+grpcPort)
+		if err != nil {
+			slog.Error("grpc listen failed", "error", err)
+			os.Exit(1)
+		}
+		grpcServer := grpc.NewServer()
+		migratorpb.RegisterLoomServer(grpcServer, handler.NewLoomGRPC(svc, slog, callbacks, authenticators...))
+		go func() {
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Fatalf("grpc server failed: %v", err)
+			}
+		}()
+		slog.Info("grpc server started", "port", grpcPort)
+	}
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -0,0 +1,199 @@
+// Package dispatchqueue is the Redis Streams dispatch queue between Loom and
+// migrators. The server appends each DispatchStepRequest to the stream of its
+// migrator app; migrators read their stream through a consumer group and
+// acknowledge and delete each entry once they have taken the step:
+//
+//	XADD loom:dispatch:<migratorApp> * request <DispatchStepRequest JSON>
+//
+// Streams are not trimmed by length, which would drop entries no consumer
+// has read or acknowledged yet. A stream holds only the steps still waiting
+// to be taken, so its length is the migrator's backlog. This relies on one
+// consumer group per stream, shared by all of the app's replicas.
+//
+// An entry a consumer read but never acknowledged, because it crashed or was
+// redeployed mid-step, stays pending in the group and is claimed by the next
+// consumer that finds it idle for ReclaimAfter. Delivery is at least once, as
+// with HTTP dispatch.
+package dispatchqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/tilsley/loom/pkg/api"
+)
+
+const (
+	// StreamPrefix is prepended to a migrator app to name its stream.
+	StreamPrefix = "loom:dispatch:"
+	// RequestField is the entry field holding the DispatchStepRequest JSON.
+	RequestField = "request"
+)
+
+// Defaults for the optional Consumer fields.
+const (
+	DefaultBlock        = 5 * time.Second
+	DefaultReclaimAfter = time.Minute
+	DefaultBatch        = 10
+)
+
+// Stream returns the name of migratorApp's stream.
+func Stream(migratorApp string) string {
+	return StreamPrefix + migratorApp
+}
+
+// Append adds req to the stream of req.MigratorApp and returns the entry ID.
+func Append(ctx context.Context, rdb redis.Cmdable, req api.DispatchStepRequest) (string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("marshal dispatch request: %w", err)
+	}
+	id, err := rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: Stream(req.MigratorApp),
+		Values: map[string]any{RequestField: body},
+	}).Result()
+	if err != nil {
+		return "", fmt.Errorf("append to %s: %w", Stream(req.MigratorApp), err)
+	}
+	return id, nil
+}
+
+// Handler takes one dispatched step. Returning nil acknowledges the entry;
+// an error leaves it pending, so it is delivered again after ReclaimAfter.
+type Handler func(ctx context.Context, req api.DispatchStepRequest) error
+
+// Consumer reads a migrator app's stream as one member of a consumer group.
+// Run several with the same Group and distinct Names to share the work.
+type Consumer struct {
+	RDB     redis.Cmdable
+	App     string // migrator app whose stream is read
+	Group   string // consumer group, shared by the app's replicas
+	Name    string // this consumer, unique within the group and stable across restarts
+	Handler Handler
+	Log     *slog.Logger
+
+	Block        time.Duration // how long a read waits for new entries; DefaultBlock when zero
+	ReclaimAfter time.Duration // idle time after which a pending entry is claimed; DefaultReclaimAfter when zero
+	Batch        int64         // entries per read; DefaultBatch when zero
+}
+
+// Run creates the group if needed and hands entries to Handler until ctx is
+// done. Each round first claims entries left pending too long, then reads new
+// ones. Redis errors are logged and retried after Block.
+func (c *Consumer) Run(ctx context.Context) error {
+	stream := Stream(c.App)
+	err := c.RDB.XGroupCreateMkStream(ctx, stream, c.Group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("create consumer group %s on %s: %w", c.Group, stream, err)
+	}
+
+	for ctx.Err() == nil {
+		if err := c.poll(ctx, stream); err != nil && ctx.Err() == nil {
+			c.Log.Warn("dispatch stream read failed", "stream", stream, "error", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(c.block()):
+			}
+		}
+	}
+	return nil
+}
+
+// poll handles one round of reclaimed and new entries.
+func (c *Consumer) poll(ctx context.Context, stream string) error {
+	reclaimed, _, err := c.RDB.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   stream,
+		Group:    c.Group,
+		Consumer: c.Name,
+		MinIdle:  c.reclaimAfter(),
+		Start:    "0-0",
+		Count:    c.batch(),
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("claim pending entries: %w", err)
+	}
+	for _, msg := range reclaimed {
+		c.Log.Info("reclaimed pending dispatch", "stream", stream, "id", msg.ID)
+		c.handle(ctx, stream, msg)
+	}
+
+	streams, err := c.RDB.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    c.Group,
+		Consumer: c.Name,
+		Streams:  []string{stream, ">"},
+		Count:    c.batch(),
+		Block:    c.block(),
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read new entries: %w", err)
+	}
+	for _, s := range streams {
+		for _, msg := range s.Messages {
+			c.handle(ctx, stream, msg)
+		}
+	}
+	return nil
+}
+
+// handle passes msg to Handler and acknowledges it on success. An entry that
+// does not hold a DispatchStepRequest is acknowledged and dropped too.
+func (c *Consumer) handle(ctx context.Context, stream string, msg redis.XMessage) {
+	var req api.DispatchStepRequest
+	raw, _ := msg.Values[RequestField].(string)
+	if err := json.Unmarshal([]byte(raw), &req); err != nil {
+		c.Log.Error("dropping malformed dispatch entry", "stream", stream, "id", msg.ID, "error", err)
+		c.ack(ctx, stream, msg.ID)
+		return
+	}
+	if err := c.Handler(ctx, req); err != nil {
+		c.Log.Warn("dispatch not taken, left pending", "stream", stream, "id", msg.ID,
+			"step", req.StepName, "candidate", req.Candidate.Id, "error", err)
+		return
+	}
+	c.ack(ctx, stream, msg.ID)
+}
+
+// ack acknowledges entry id and deletes it from the stream, which is never
+// trimmed. The two run in one MULTI so that an acknowledged entry is not left
+// behind in the stream.
+func (c *Consumer) ack(ctx context.Context, stream, id string) {
+	_, err := c.RDB.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAck(ctx, stream, c.Group, id)
+		pipe.XDel(ctx, stream, id)
+		return nil
+	})
+	if err != nil {
+		c.Log.Warn("dispatch ack failed", "stream", stream, "id", id, "error", err)
+	}
+}
+
+func (c *Consumer) block() time.Duration {
+	if c.Block > 0 {
+		return c.Block
+	}
+	return DefaultBlock
+}
+
+func (c *Consumer) reclaimAfter() time.Duration {
+	if c.ReclaimAfter > 0 {
+		return c.ReclaimAfter
+	}
+	return DefaultReclaimAfter
+}
+
+func (c *Consumer) batch() int64 {
+	if c.Batch > 0 {
+		return c.Batch
+	}
+	return DefaultBatch
+}
@@ -0,0 +1,232 @@
+package dispatchqueue_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/pkg/api"
+	"github.com/tilsley/loom/pkg/dispatchqueue"
+)
+
+// newRedis returns a client for the Redis at REDIS_ADDR and a migrator app
+// whose stream is deleted after the test. Skips if REDIS_ADDR is not set.
+func newRedis(t *testing.T) (*redis.Client, string) {
+	t.Helper()
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR not set — skipping Redis integration tests")
+	}
+	rdb := redis.NewClient(&redis.Options{Addr: addr})
+	app := "test-" + strings.ReplaceAll(t.Name(), "/", "-")
+	t.Cleanup(func() {
+		require.NoError(t, rdb.Del(context.Background(), dispatchqueue.Stream(app)).Err())
+		_ = rdb.Close()
+	})
+	return rdb, app
+}
+
+func dispatchReq(app, step string) api.DispatchStepRequest {
+	return api.DispatchStepRequest{
+		MigrationId: "mig-1",
+		StepName:    step,
+		MigratorApp: app,
+		Candidate:   api.Candidate{Id: "billing-api"},
+		CallbackId:  "mig-1__billing-api",
+	}
+}
+
+// recorder is a Handler that records each step and fails the first failures
+// calls.
+type recorder struct {
+	mu       sync.Mutex
+	steps    []string
+	failures int
+}
+
+func (r *recorder) handle(_ context.Context, req api.DispatchStepRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.steps = append(r.steps, req.StepName)
+	if r.failures > 0 {
+		r.failures--
+		return errors.New("not now")
+	}
+	return nil
+}
+
+func (r *recorder) seen() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.steps...)
+}
+
+// run starts c until the test ends.
+func run(t *testing.T, c *dispatchqueue.Consumer) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, c.Run(ctx))
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func pending(t *testing.T, rdb *redis.Client, app string) int64 {
+	t.Helper()
+	p, err := rdb.XPending(context.Background(), dispatchqueue.Stream(app), app).Result()
+	require.NoError(t, err)
+	return p.Count
+}
+
+func length(t *testing.T, rdb *redis.Client, app string) int64 {
+	t.Helper()
+	n, err := rdb.XLen(context.Background(), dispatchqueue.Stream(app)).Result()
+	require.NoError(t, err)
+	return n
+}
+
+var quiet = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// ─── Append ──────────────────────────────────────────────────────────────────
+
+func TestAppend_StoresRequestJSON(t *testing.T) {
+	rdb, app := newRedis(t)
+
+	id, err := dispatchqueue.Append(context.Background(), rdb, dispatchReq(app, "update-chart"))
+	require.NoError(t, err)
+
+	msgs, err := rdb.XRange(context.Background(), dispatchqueue.Stream(app), "-", "+").Result()
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, id, msgs[0].ID)
+	var got api.DispatchStepRequest
+	require.NoError(t, json.Unmarshal([]byte(msgs[0].Values[dispatchqueue.RequestField].(string)), &got))
+	assert.Equal(t, dispatchReq(app, "update-chart"), got)
+}
+
+// ─── Consumer ────────────────────────────────────────────────────────────────
+
+func TestConsumer_HandlesAndAcks(t *testing.T) {
+	rdb, app := newRedis(t)
+	ctx := context.Background()
+	_, err := dispatchqueue.Append(ctx, rdb, dispatchReq(app, "step-1"))
+	require.NoError(t, err)
+	rec := &recorder{}
+
+	run(t, &dispatchqueue.Consumer{
+		RDB: rdb, App: app, Group: app, Name: "c1", Handler: rec.handle, Log: quiet,
+		Block: 50 * time.Millisecond,
+	})
+	_, err = dispatchqueue.Append(ctx, rdb, dispatchReq(app, "step-2"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(rec.seen()) == 2 }, 5*time.Second, 20*time.Millisecond)
+	assert.Equal(t, []string{"step-1", "step-2"}, rec.seen(), "entries queued before the group existed are read too")
+	require.Eventually(t, func() bool { return pending(t, rdb, app) == 0 }, 5*time.Second, 20*time.Millisecond)
+	assert.Zero(t, length(t, rdb, app), "acknowledged entries are deleted")
+}
+
+func TestConsumer_NothingLostPastOldStreamLimit(t *testing.T) {
+	rdb, app := newRedis(t)
+	ctx := context.Background()
+	const n = 10050 // past the 10,000 entries streams used to be trimmed to
+	_, err := rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i := range n {
+			if _, err := dispatchqueue.Append(ctx, pipe, dispatchReq(app, strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(n), length(t, rdb, app), "no entry is trimmed before it is read")
+	rec := &recorder{}
+
+	run(t, &dispatchqueue.Consumer{
+		RDB: rdb, App: app, Group: app, Name: "c1", Handler: rec.handle, Log: quiet,
+		Block: 50 * time.Millisecond, Batch: 500,
+	})
+
+	require.Eventually(t, func() bool { return len(rec.seen()) == n }, 30*time.Second, 50*time.Millisecond)
+	assert.Equal(t, "0", rec.seen()[0])
+	assert.Equal(t, strconv.Itoa(n-1), rec.seen()[n-1])
+	require.Eventually(t, func() bool { return length(t, rdb, app) == 0 }, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestConsumer_FailedEntryIsReclaimed(t *testing.T) {
+	rdb, app := newRedis(t)
+	_, err := dispatchqueue.Append(context.Background(), rdb, dispatchReq(app, "step-1"))
+	require.NoError(t, err)
+	rec := &recorder{failures: 1}
+
+	run(t, &dispatchqueue.Consumer{
+		RDB: rdb, App: app, Group: app, Name: "c1", Handler: rec.handle, Log: quiet,
+		Block: 50 * time.Millisecond, ReclaimAfter: 100 * time.Millisecond,
+	})
+
+	require.Eventually(t, func() bool { return len(rec.seen()) == 2 }, 5*time.Second, 20*time.Millisecond)
+	assert.Equal(t, []string{"step-1", "step-1"}, rec.seen())
+	require.Eventually(t, func() bool { return pending(t, rdb, app) == 0 }, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestConsumer_ReclaimsEntryOfStoppedConsumer(t *testing.T) {
+	rdb, app := newRedis(t)
+	ctx := context.Background()
+	stream := dispatchqueue.Stream(app)
+	require.NoError(t, rdb.XGroupCreateMkStream(ctx, stream, app, "0").Err())
+	_, err := dispatchqueue.Append(ctx, rdb, dispatchReq(app, "step-1"))
+	require.NoError(t, err)
+	// A consumer that crashed after reading the entry.
+	_, err = rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    app,
+		Consumer: "gone",
+		Streams:  []string{stream, ">"},
+	}).Result()
+	require.NoError(t, err)
+	rec := &recorder{}
+
+	run(t, &dispatchqueue.Consumer{
+		RDB: rdb, App: app, Group: app, Name: "c2", Handler: rec.handle, Log: quiet,
+		Block: 50 * time.Millisecond, ReclaimAfter: 100 * time.Millisecond,
+	})
+
+	require.Eventually(t, func() bool { return len(rec.seen()) == 1 }, 5*time.Second, 20*time.Millisecond)
+	require.Eventually(t, func() bool { return pending(t, rdb, app) == 0 }, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestConsumer_DropsMalformedEntry(t *testing.T) {
+	rdb, app := newRedis(t)
+	ctx := context.Background()
+	require.NoError(t, rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: dispatchqueue.Stream(app),
+		Values: map[string]any{dispatchqueue.RequestField: "not json"},
+	}).Err())
+	_, err := dispatchqueue.Append(ctx, rdb, dispatchReq(app, "step-1"))
+	require.NoError(t, err)
+	rec := &recorder{}
+
+	run(t, &dispatchqueue.Consumer{
+		RDB: rdb, App: app, Group: app, Name: "c1", Handler: rec.handle, Log: quiet,
+		Block: 50 * time.Millisecond,
+	})
+
+	require.Eventually(t, func() bool { return len(rec.seen()) == 1 }, 5*time.Second, 20*time.Millisecond)
+	require.Eventually(t, func() bool { return pending(t, rdb, app) == 0 }, 5*time.Second, 20*time.Millisecond)
+}
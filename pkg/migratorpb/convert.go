@@ -0,0 +1,460 @@
+package migratorpb
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/tilsley/loom/pkg/api"
+)
+
+// This file converts between the messages and the OpenAPI types in pkg/api
+// they mirror. Each message has a New<Message> constructor from its API type
+// and an API method back. Empty repeated and map fields convert to nil, as
+// the OpenAPI fields they stand for are left out when empty.
+
+// CallbackPayload is what a SendCallback signature covers besides the
+// callback ID: event serialized deterministically.
+func CallbackPayload(event *StepStatusEvent) ([]byte, error) {
+	return proto.MarshalOptions{Deterministic: true}.Marshal(event)
+}
+
+// ─── Dispatch and dry run ────────────────────────────────────────────────────
+
+// NewDispatchStepRequest converts req to its message.
+func NewDispatchStepRequest(req api.DispatchStepRequest) *DispatchStepRequest {
+	return &DispatchStepRequest{
+		MigrationId: req.MigrationId,
+		StepName:    req.StepName,
+		Candidate:   NewCandidate(req.Candidate),
+		Config:      fromMap(req.Config),
+		Type:        req.Type,
+		CallbackId:  req.CallbackId,
+		EventName:   req.EventName,
+		MigratorApp: req.MigratorApp,
+		MigratorUrl: req.MigratorUrl,
+		Attempt:     fromInt(req.Attempt),
+	}
+}
+
+// API converts m to the request it mirrors.
+func (m *DispatchStepRequest) API() api.DispatchStepRequest {
+	return api.DispatchStepRequest{
+		MigrationId: m.GetMigrationId(),
+		StepName:    m.GetStepName(),
+		Candidate:   m.GetCandidate().API(),
+		Config:      toMap(m.GetConfig()),
+		Type:        m.Type,
+		CallbackId:  m.GetCallbackId(),
+		EventName:   m.GetEventName(),
+		MigratorApp: m.GetMigratorApp(),
+		MigratorUrl: m.GetMigratorUrl(),
+		Attempt:     toInt(m.Attempt),
+	}
+}
+
+// NewDryRunRequest converts req to its message.
+func NewDryRunRequest(req api.DryRunRequest) *DryRunRequest {
+	return &DryRunRequest{
+		MigrationId: req.MigrationId,
+		Candidate:   NewCandidate(req.Candidate),
+		Steps:       convertAll(req.Steps, NewStepDefinition),
+	}
+}
+
+// API converts m to the request it mirrors.
+func (m *DryRunRequest) API() api.DryRunRequest {
+	steps := make([]api.StepDefinition, 0, len(m.GetSteps()))
+	for _, s := range m.GetSteps() {
+		steps = append(steps, s.API())
+	}
+	return api.DryRunRequest{
+		MigrationId: m.GetMigrationId(),
+		Candidate:   m.GetCandidate().API(),
+		Steps:       steps,
+	}
+}
+
+// NewDryRunResult converts result to its message.
+func NewDryRunResult(result api.DryRunResult) *DryRunResult {
+	steps := make([]*StepDryRunResult, 0, len(result.Steps))
+	for _, s := range result.Steps {
+		var files []*FileDiff
+		if s.Files != nil {
+			files = convertAll(*s.Files, newFileDiff)
+		}
+		steps = append(steps, &StepDryRunResult{
+			StepName: s.StepName,
+			Skipped:  s.Skipped,
+			Files:    files,
+			Error:    s.Error,
+		})
+	}
+	return &DryRunResult{Steps: steps}
+}
+
+// API converts m to the result it mirrors.
+func (m *DryRunResult) API() api.DryRunResult {
+	steps := make([]api.StepDryRunResult, 0, len(m.GetSteps()))
+	for _, s := range m.GetSteps() {
+		step := api.StepDryRunResult{StepName: s.GetStepName(), Skipped: s.GetSkipped(), Error: s.Error}
+		if len(s.GetFiles()) > 0 {
+			files := make([]api.FileDiff, 0, len(s.GetFiles()))
+			for _, f := range s.GetFiles() {
+				files = append(files, api.FileDiff{
+					Repo:   f.GetRepo(),
+					Path:   f.GetPath(),
+					Status: fileDiffStatuses.toAPI(f.GetStatus()),
+					Before: f.Before,
+					After:  f.GetAfter(),
+				})
+			}
+			step.Files = &files
+		}
+		steps = append(steps, step)
+	}
+	return api.DryRunResult{Steps: steps}
+}
+
+func newFileDiff(f api.FileDiff) *FileDiff {
+	return &FileDiff{
+		Repo:   f.Repo,
+		Path:   f.Path,
+		Status: fileDiffStatuses.fromAPI(f.Status),
+		Before: f.Before,
+		After:  f.After,
+	}
+}
+
+// ─── Callbacks ───────────────────────────────────────────────────────────────
+
+// NewStepStatusEvent converts event to its message.
+func NewStepStatusEvent(event api.StepStatusEvent) *StepStatusEvent {
+	return &StepStatusEvent{
+		StepName:    event.StepName,
+		CandidateId: event.CandidateId,
+		Status:      stepStatuses.fromAPI(event.Status),
+		Metadata:    fromMap(event.Metadata),
+		Attempt:     fromInt(event.Attempt),
+		EventId:     event.EventId,
+	}
+}
+
+// API converts m to the event it mirrors.
+func (m *StepStatusEvent) API() api.StepStatusEvent {
+	return api.StepStatusEvent{
+		StepName:    m.GetStepName(),
+		CandidateId: m.GetCandidateId(),
+		Status:      stepStatuses.toAPI(m.GetStatus()),
+		Metadata:    toMap(m.GetMetadata()),
+		Attempt:     toInt(m.Attempt),
+		EventId:     m.EventId,
+	}
+}
+
+// ─── Registry ────────────────────────────────────────────────────────────────
+
+// NewMigrationAnnouncement converts a to its message.
+func NewMigrationAnnouncement(a api.MigrationAnnouncement) *MigrationAnnouncement {
+	m := &MigrationAnnouncement{
+		Id:          a.Id,
+		Name:        a.Name,
+		Description: a.Description,
+		Steps:       convertAll(a.Steps, NewStepDefinition),
+		Candidates:  convertAll(a.Candidates, NewCandidate),
+		MigratorUrl: a.MigratorUrl,
+	}
+	if a.Overview != nil {
+		m.Overview = *a.Overview
+	}
+	if a.RequiredInputs != nil {
+		m.RequiredInputs = convertAll(*a.RequiredInputs, func(in api.InputDefinition) *InputDefinition {
+			return &InputDefinition{Name: in.Name, Label: in.Label, Description: in.Description}
+		})
+	}
+	if a.DispatchLimits != nil {
+		m.DispatchLimits = convertAll(*a.DispatchLimits, func(l api.DispatchLimit) *DispatchLimit {
+			return &DispatchLimit{
+				MigratorApp: l.MigratorApp,
+				MaxInFlight: fromInt(l.MaxInFlight),
+				PerMinute:   fromInt(l.PerMinute),
+			}
+		})
+	}
+	return m
+}
+
+// API converts m to the announcement it mirrors.
+func (m *MigrationAnnouncement) API() api.MigrationAnnouncement {
+	a := api.MigrationAnnouncement{
+		Id:          m.GetId(),
+		Name:        m.GetName(),
+		Description: m.GetDescription(),
+		Steps:       make([]api.StepDefinition, 0, len(m.GetSteps())),
+		Candidates:  make([]api.Candidate, 0, len(m.GetCandidates())),
+		MigratorUrl: m.GetMigratorUrl(),
+	}
+	for _, s := range m.GetSteps() {
+		a.Steps = append(a.Steps, s.API())
+	}
+	for _, c := range m.GetCandidates() {
+		a.Candidates = append(a.Candidates, c.API())
+	}
+	if len(m.GetOverview()) > 0 {
+		overview := m.GetOverview()
+		a.Overview = &overview
+	}
+	if len(m.GetRequiredInputs()) > 0 {
+		inputs := make([]api.InputDefinition, 0, len(m.GetRequiredInputs()))
+		for _, in := range m.GetRequiredInputs() {
+			inputs = append(inputs, api.InputDefinition{
+				Name:        in.GetName(),
+				Label:       in.GetLabel(),
+				Description: in.Description,
+			})
+		}
+		a.RequiredInputs = &inputs
+	}
+	if len(m.GetDispatchLimits()) > 0 {
+		limits := make([]api.DispatchLimit, 0, len(m.GetDispatchLimits()))
+		for _, l := range m.GetDispatchLimits() {
+			limits = append(limits, api.DispatchLimit{
+				MigratorApp: l.GetMigratorApp(),
+				MaxInFlight: toInt(l.MaxInFlight),
+				PerMinute:   toInt(l.PerMinute),
+			})
+		}
+		a.DispatchLimits = &limits
+	}
+	return a
+}
+
+// NewMigratorRegistration converts r to its message.
+func NewMigratorRegistration(r api.MigratorRegistration) *MigratorRegistration {
+	return &MigratorRegistration{MigratorApp: r.MigratorApp, Url: r.Url, HealthUrl: r.HealthUrl}
+}
+
+// API converts m to the registration it mirrors.
+func (m *MigratorRegistration) API() api.MigratorRegistration {
+	return api.MigratorRegistration{MigratorApp: m.GetMigratorApp(), Url: m.GetUrl(), HealthUrl: m.HealthUrl}
+}
+
+// NewRegisteredMigrator converts r to its message.
+func NewRegisteredMigrator(r api.RegisteredMigrator) *RegisteredMigrator {
+	return &RegisteredMigrator{
+		MigratorApp:  r.MigratorApp,
+		Url:          r.Url,
+		HealthUrl:    r.HealthUrl,
+		RegisteredAt: timestamppb.New(r.RegisteredAt),
+	}
+}
+
+// API converts m to the migrator it mirrors.
+func (m *RegisteredMigrator) API() api.RegisteredMigrator {
+	return api.RegisteredMigrator{
+		MigratorApp:  m.GetMigratorApp(),
+		Url:          m.GetUrl(),
+		HealthUrl:    m.GetHealthUrl(),
+		RegisteredAt: m.GetRegisteredAt().AsTime(),
+	}
+}
+
+// ─── Shared ──────────────────────────────────────────────────────────────────
+
+// NewCandidate converts c to its message.
+func NewCandidate(c api.Candidate) *Candidate {
+	m := &Candidate{
+		Id:       c.Id,
+		Kind:     c.Kind,
+		Status:   candidateStatuses.fromAPI(c.Status),
+		Metadata: fromMap(c.Metadata),
+	}
+	if c.Files != nil {
+		m.Files = convertAll(*c.Files, func(g api.FileGroup) *FileGroup {
+			return &FileGroup{
+				Name: g.Name,
+				Repo: g.Repo,
+				Files: convertAll(g.Files, func(f api.FileRef) *FileRef {
+					return &FileRef{Path: f.Path, Url: f.Url}
+				}),
+			}
+		})
+	}
+	if c.Steps != nil {
+		m.Steps = convertAll(*c.Steps, NewStepDefinition)
+	}
+	return m
+}
+
+// API converts m to the candidate it mirrors.
+func (m *Candidate) API() api.Candidate {
+	c := api.Candidate{
+		Id:       m.GetId(),
+		Kind:     m.GetKind(),
+		Status:   candidateStatuses.toAPI(m.GetStatus()),
+		Metadata: toMap(m.GetMetadata()),
+	}
+	if len(m.GetFiles()) > 0 {
+		groups := make([]api.FileGroup, 0, len(m.GetFiles()))
+		for _, g := range m.GetFiles() {
+			files := make([]api.FileRef, 0, len(g.GetFiles()))
+			for _, f := range g.GetFiles() {
+				files = append(files, api.FileRef{Path: f.GetPath(), Url: f.GetUrl()})
+			}
+			groups = append(groups, api.FileGroup{Name: g.GetName(), Repo: g.GetRepo(), Files: files})
+		}
+		c.Files = &groups
+	}
+	if len(m.GetSteps()) > 0 {
+		steps := make([]api.StepDefinition, 0, len(m.GetSteps()))
+		for _, s := range m.GetSteps() {
+			steps = append(steps, s.API())
+		}
+		c.Steps = &steps
+	}
+	return c
+}
+
+// NewStepDefinition converts s to its message.
+func NewStepDefinition(s api.StepDefinition) *StepDefinition {
+	m := &StepDefinition{
+		Name:             s.Name,
+		MigratorApp:      s.MigratorApp,
+		Description:      s.Description,
+		Type:             s.Type,
+		Config:           fromMap(s.Config),
+		When:             s.When,
+		TimeoutSeconds:   fromInt(s.TimeoutSeconds),
+		CompensationType: s.CompensationType,
+	}
+	if s.DependsOn != nil {
+		m.DependsOn = *s.DependsOn
+	}
+	if p := s.RetryPolicy; p != nil {
+		m.RetryPolicy = &RetryPolicy{
+			MaxAttempts:           int32(p.MaxAttempts), //nolint:gosec // bounded by the OpenAPI schema
+			InitialBackoffSeconds: fromInt(p.InitialBackoffSeconds),
+			BackoffMultiplier:     p.BackoffMultiplier,
+		}
+	}
+	if p := s.DispatchRetry; p != nil {
+		m.DispatchRetry = &DispatchRetryPolicy{
+			MaxAttempts:           fromInt(p.MaxAttempts),
+			InitialBackoffSeconds: fromInt(p.InitialBackoffSeconds),
+			BackoffMultiplier:     p.BackoffMultiplier,
+			MaxBackoffSeconds:     fromInt(p.MaxBackoffSeconds),
+		}
+	}
+	return m
+}
+
+// API converts m to the step it mirrors.
+func (m *StepDefinition) API() api.StepDefinition {
+	s := api.StepDefinition{
+		Name:             m.GetName(),
+		MigratorApp:      m.GetMigratorApp(),
+		Description:      m.Description,
+		Type:             m.Type,
+		Config:           toMap(m.GetConfig()),
+		When:             m.When,
+		TimeoutSeconds:   toInt(m.TimeoutSeconds),
+		CompensationType: m.CompensationType,
+	}
+	if len(m.GetDependsOn()) > 0 {
+		dependsOn := m.GetDependsOn()
+		s.DependsOn = &dependsOn
+	}
+	if p := m.GetRetryPolicy(); p != nil {
+		s.RetryPolicy = &api.RetryPolicy{
+			MaxAttempts:           int(p.GetMaxAttempts()),
+			InitialBackoffSeconds: toInt(p.InitialBackoffSeconds),
+			BackoffMultiplier:     p.BackoffMultiplier,
+		}
+	}
+	if p := m.GetDispatchRetry(); p != nil {
+		s.DispatchRetry = &api.DispatchRetryPolicy{
+			MaxAttempts:           toInt(p.MaxAttempts),
+			InitialBackoffSeconds: toInt(p.InitialBackoffSeconds),
+			BackoffMultiplier:     p.BackoffMultiplier,
+			MaxBackoffSeconds:     toInt(p.MaxBackoffSeconds),
+		}
+	}
+	return s
+}
+
+// enumMap pairs the values of a proto enum with the OpenAPI enum they mirror.
+// The zero value of the proto enum stands for the OpenAPI field left out.
+type enumMap[P comparable, A comparable] map[P]A
+
+func (e enumMap[P, A]) toAPI(p P) A {
+	return e[p]
+}
+
+func (e enumMap[P, A]) fromAPI(a A) P {
+	for p, v := range e {
+		if v == a {
+			return p
+		}
+	}
+	var zero P
+	return zero
+}
+
+var (
+	candidateStatuses = enumMap[CandidateStatus, api.CandidateStatus]{
+		CandidateStatus_CANDIDATE_STATUS_NOT_STARTED: api.CandidateStatusNotStarted,
+		CandidateStatus_CANDIDATE_STATUS_RUNNING:     api.CandidateStatusRunning,
+		CandidateStatus_CANDIDATE_STATUS_COMPLETED:   api.CandidateStatusCompleted,
+	}
+	stepStatuses = enumMap[StepStatus, api.StepStatusEventStatus]{
+		StepStatus_STEP_STATUS_SUCCEEDED: api.StepStatusEventStatusSucceeded,
+		StepStatus_STEP_STATUS_FAILED:    api.StepStatusEventStatusFailed,
+		StepStatus_STEP_STATUS_PENDING:   api.StepStatusEventStatusPending,
+		StepStatus_STEP_STATUS_MERGED:    api.StepStatusEventStatusMerged,
+	}
+	fileDiffStatuses = enumMap[FileDiffStatus, api.FileDiffStatus]{
+		FileDiffStatus_FILE_DIFF_STATUS_NEW:      api.New,
+		FileDiffStatus_FILE_DIFF_STATUS_MODIFIED: api.Modified,
+		FileDiffStatus_FILE_DIFF_STATUS_DELETED:  api.Deleted,
+	}
+)
+
+func convertAll[A any, M any](items []A, convert func(A) M) []M {
+	if len(items) == 0 {
+		return nil
+	}
+	out := make([]M, 0, len(items))
+	for _, item := range items {
+		out = append(out, convert(item))
+	}
+	return out
+}
+
+func fromMap(m *map[string]string) map[string]string {
+	if m == nil || len(*m) == 0 {
+		return nil
+	}
+	return *m
+}
+
+func toMap(m map[string]string) *map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	return &m
+}
+
+func fromInt(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	i := int32(*v) //nolint:gosec // bounded by the OpenAPI schema
+	return &i
+}
+
+func toInt(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
@@ -0,0 +1,153 @@
+package migratorpb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tilsley/loom/pkg/api"
+	"github.com/tilsley/loom/pkg/migratorpb"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+// fullStep sets every field of a StepDefinition.
+var fullStep = api.StepDefinition{
+	Name:             "update-chart",
+	MigratorApp:      "app-chart-migrator",
+	Description:      ptr("Bump the chart"),
+	Type:             ptr("chart-bump"),
+	Config:           &map[string]string{"chart": "loom"},
+	DependsOn:        &[]string{"open-pr"},
+	When:             ptr(`inputs.env == "prod"`),
+	TimeoutSeconds:   ptr(600),
+	RetryPolicy:      &api.RetryPolicy{MaxAttempts: 3, InitialBackoffSeconds: ptr(30), BackoffMultiplier: ptr(2.0)},
+	DispatchRetry:    &api.DispatchRetryPolicy{MaxAttempts: ptr(5), MaxBackoffSeconds: ptr(60)},
+	CompensationType: ptr("revert-chart"),
+}
+
+// fullCandidate sets every field of a Candidate.
+var fullCandidate = api.Candidate{
+	Id:       "billing-api",
+	Kind:     "application",
+	Status:   api.CandidateStatusRunning,
+	Metadata: &map[string]string{"team": "payments"},
+	Files: &[]api.FileGroup{{
+		Name:  "chart",
+		Repo:  "acme/billing-api",
+		Files: []api.FileRef{{Path: "Chart.yaml", Url: "https://github.com/acme/billing-api/blob/main/Chart.yaml"}},
+	}},
+	Steps: &[]api.StepDefinition{fullStep},
+}
+
+// roundTrip sends m over the wire and back.
+func roundTrip[M proto.Message](t *testing.T, m M) M {
+	t.Helper()
+	b, err := proto.Marshal(m)
+	require.NoError(t, err)
+	out := m.ProtoReflect().New().Interface().(M) //nolint:forcetypeassert // same message type
+	require.NoError(t, proto.Unmarshal(b, out))
+	return out
+}
+
+// ─── Conversions ─────────────────────────────────────────────────────────────
+
+func TestDispatchStepRequest_RoundTrip(t *testing.T) {
+	req := api.DispatchStepRequest{
+		MigrationId: "mig-abc",
+		StepName:    "update-chart",
+		Candidate:   fullCandidate,
+		Config:      &map[string]string{"chart": "loom"},
+		Type:        ptr("chart-bump"),
+		CallbackId:  "mig-abc__billing-api",
+		EventName:   "step-completed",
+		MigratorApp: "app-chart-migrator",
+		MigratorUrl: "grpc://app-chart-migrator:9090",
+		Attempt:     ptr(2),
+	}
+
+	assert.Equal(t, req, roundTrip(t, migratorpb.NewDispatchStepRequest(req)).API())
+}
+
+func TestMigrationAnnouncement_RoundTrip(t *testing.T) {
+	ann := api.MigrationAnnouncement{
+		Id:             "mig-abc",
+		Name:           "Migrate charts",
+		Description:    "Moves every app to the new chart",
+		Overview:       &[]string{"Bump the chart", "Merge"},
+		RequiredInputs: &[]api.InputDefinition{{Name: "env", Label: "Environment", Description: ptr("Target env")}},
+		Steps:          []api.StepDefinition{fullStep},
+		Candidates:     []api.Candidate{fullCandidate},
+		MigratorUrl:    "grpc://app-chart-migrator:9090",
+		DispatchLimits: &[]api.DispatchLimit{{MigratorApp: "app-chart-migrator", MaxInFlight: ptr(5), PerMinute: ptr(0)}},
+	}
+
+	assert.Equal(t, ann, roundTrip(t, migratorpb.NewMigrationAnnouncement(ann)).API())
+}
+
+func TestDryRun_RoundTrip(t *testing.T) {
+	req := api.DryRunRequest{MigrationId: "mig-abc", Candidate: fullCandidate, Steps: []api.StepDefinition{fullStep}}
+	result := api.DryRunResult{Steps: []api.StepDryRunResult{
+		{StepName: "update-chart", Files: &[]api.FileDiff{
+			{Repo: "acme/billing-api", Path: "Chart.yaml", Status: api.Modified, Before: ptr("v1"), After: "v2"},
+			{Repo: "acme/billing-api", Path: "values.yaml", Status: api.New, After: "replicas: 2"},
+		}},
+		{StepName: "merge", Skipped: true},
+		{StepName: "cleanup", Error: ptr("repo not found")},
+	}}
+
+	assert.Equal(t, req, roundTrip(t, migratorpb.NewDryRunRequest(req)).API())
+	assert.Equal(t, result, roundTrip(t, migratorpb.NewDryRunResult(result)).API())
+}
+
+func TestStepStatusEvent_RoundTrip(t *testing.T) {
+	for _, s := range []api.StepStatusEventStatus{
+		api.StepStatusEventStatusSucceeded,
+		api.StepStatusEventStatusFailed,
+		api.StepStatusEventStatusPending,
+		api.StepStatusEventStatusMerged,
+	} {
+		event := api.StepStatusEvent{
+			StepName:    "update-chart",
+			CandidateId: "billing-api",
+			Status:      s,
+			Metadata:    &map[string]string{"prUrl": "https://github.com/acme/billing-api/pull/1"},
+			Attempt:     ptr(1),
+			EventId:     ptr("evt-1"),
+		}
+		assert.Equal(t, event, roundTrip(t, migratorpb.NewStepStatusEvent(event)).API(), s)
+	}
+}
+
+func TestRegisteredMigrator_RoundTrip(t *testing.T) {
+	m := api.RegisteredMigrator{
+		MigratorApp:  "app-chart-migrator",
+		Url:          "grpc://app-chart-migrator:9090",
+		HealthUrl:    "grpc://app-chart-migrator:9090",
+		RegisteredAt: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+	}
+
+	assert.Equal(t, m, roundTrip(t, migratorpb.NewRegisteredMigrator(m)).API())
+}
+
+// ─── Callback payload ────────────────────────────────────────────────────────
+
+func TestCallbackPayload_SameForEqualEvents(t *testing.T) {
+	md := map[string]string{}
+	for _, k := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		md[k] = k
+	}
+	event := api.StepStatusEvent{StepName: "update-chart", CandidateId: "billing-api", Metadata: &md}
+
+	first, err := migratorpb.CallbackPayload(migratorpb.NewStepStatusEvent(event))
+	require.NoError(t, err)
+	for range 10 {
+		received := roundTrip(t, migratorpb.NewStepStatusEvent(event))
+		again, err := migratorpb.CallbackPayload(received)
+		require.NoError(t, err)
+		require.Equal(t, first, again, "map order does not change the payload")
+	}
+}
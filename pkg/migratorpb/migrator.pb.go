@@ -0,0 +1,1967 @@
+// gRPC transport for the migrator contract. The messages mirror the OpenAPI
+// schemas of the JSON-over-HTTP routes in openapi.yaml field for field, under
+// the same names, so a migrator can switch transports without changing what
+// it sends. Optional OpenAPI fields are proto3 optional fields, or empty
+// repeated and map fields; an unset enum is the OpenAPI field left out.
+//
+// The server uses it for a migrator whose URL has the grpc:// or grpcs://
+// scheme. Deadlines travel with each call; failures are gRPC status codes.
+//
+// Streaming step logs is not part of the contract yet: migrators report on a
+// step through SendCallback only, and Loom has nowhere to keep or show log
+// lines.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: migrator.proto
+
+package migratorpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CandidateStatus int32
+
+const (
+	CandidateStatus_CANDIDATE_STATUS_UNSPECIFIED CandidateStatus = 0
+	CandidateStatus_CANDIDATE_STATUS_NOT_STARTED CandidateStatus = 1
+	CandidateStatus_CANDIDATE_STATUS_RUNNING     CandidateStatus = 2
+	CandidateStatus_CANDIDATE_STATUS_COMPLETED   CandidateStatus = 3
+)
+
+// Enum value maps for CandidateStatus.
+var (
+	CandidateStatus_name = map[int32]string{
+		0: "CANDIDATE_STATUS_UNSPECIFIED",
+		1: "CANDIDATE_STATUS_NOT_STARTED",
+		2: "CANDIDATE_STATUS_RUNNING",
+		3: "CANDIDATE_STATUS_COMPLETED",
+	}
+	CandidateStatus_value = map[string]int32{
+		"CANDIDATE_STATUS_UNSPECIFIED": 0,
+		"CANDIDATE_STATUS_NOT_STARTED": 1,
+		"CANDIDATE_STATUS_RUNNING":     2,
+		"CANDIDATE_STATUS_COMPLETED":   3,
+	}
+)
+
+func (x CandidateStatus) Enum() *CandidateStatus {
+	p := new(CandidateStatus)
+	*p = x
+	return p
+}
+
+func (x CandidateStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CandidateStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_migrator_proto_enumTypes[0].Descriptor()
+}
+
+func (CandidateStatus) Type() protoreflect.EnumType {
+	return &file_migrator_proto_enumTypes[0]
+}
+
+func (x CandidateStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CandidateStatus.Descriptor instead.
+func (CandidateStatus) EnumDescriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{0}
+}
+
+type FileDiffStatus int32
+
+const (
+	FileDiffStatus_FILE_DIFF_STATUS_UNSPECIFIED FileDiffStatus = 0
+	FileDiffStatus_FILE_DIFF_STATUS_NEW         FileDiffStatus = 1
+	FileDiffStatus_FILE_DIFF_STATUS_MODIFIED    FileDiffStatus = 2
+	FileDiffStatus_FILE_DIFF_STATUS_DELETED     FileDiffStatus = 3
+)
+
+// Enum value maps for FileDiffStatus.
+var (
+	FileDiffStatus_name = map[int32]string{
+		0: "FILE_DIFF_STATUS_UNSPECIFIED",
+		1: "FILE_DIFF_STATUS_NEW",
+		2: "FILE_DIFF_STATUS_MODIFIED",
+		3: "FILE_DIFF_STATUS_DELETED",
+	}
+	FileDiffStatus_value = map[string]int32{
+		"FILE_DIFF_STATUS_UNSPECIFIED": 0,
+		"FILE_DIFF_STATUS_NEW":         1,
+		"FILE_DIFF_STATUS_MODIFIED":    2,
+		"FILE_DIFF_STATUS_DELETED":     3,
+	}
+)
+
+func (x FileDiffStatus) Enum() *FileDiffStatus {
+	p := new(FileDiffStatus)
+	*p = x
+	return p
+}
+
+func (x FileDiffStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FileDiffStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_migrator_proto_enumTypes[1].Descriptor()
+}
+
+func (FileDiffStatus) Type() protoreflect.EnumType {
+	return &file_migrator_proto_enumTypes[1]
+}
+
+func (x FileDiffStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FileDiffStatus.Descriptor instead.
+func (FileDiffStatus) EnumDescriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{1}
+}
+
+type StepStatus int32
+
+const (
+	StepStatus_STEP_STATUS_UNSPECIFIED StepStatus = 0
+	StepStatus_STEP_STATUS_SUCCEEDED   StepStatus = 1
+	StepStatus_STEP_STATUS_FAILED      StepStatus = 2
+	StepStatus_STEP_STATUS_PENDING     StepStatus = 3
+	StepStatus_STEP_STATUS_MERGED      StepStatus = 4
+)
+
+// Enum value maps for StepStatus.
+var (
+	StepStatus_name = map[int32]string{
+		0: "STEP_STATUS_UNSPECIFIED",
+		1: "STEP_STATUS_SUCCEEDED",
+		2: "STEP_STATUS_FAILED",
+		3: "STEP_STATUS_PENDING",
+		4: "STEP_STATUS_MERGED",
+	}
+	StepStatus_value = map[string]int32{
+		"STEP_STATUS_UNSPECIFIED": 0,
+		"STEP_STATUS_SUCCEEDED":   1,
+		"STEP_STATUS_FAILED":      2,
+		"STEP_STATUS_PENDING":     3,
+		"STEP_STATUS_MERGED":      4,
+	}
+)
+
+func (x StepStatus) Enum() *StepStatus {
+	p := new(StepStatus)
+	*p = x
+	return p
+}
+
+func (x StepStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StepStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_migrator_proto_enumTypes[2].Descriptor()
+}
+
+func (StepStatus) Type() protoreflect.EnumType {
+	return &file_migrator_proto_enumTypes[2]
+}
+
+func (x StepStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StepStatus.Descriptor instead.
+func (StepStatus) EnumDescriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{2}
+}
+
+type Candidate struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kind     string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Status   CandidateStatus        `protobuf:"varint,3,opt,name=status,proto3,enum=loom.migrator.v1.CandidateStatus" json:"status,omitempty"`
+	Metadata map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Files    []*FileGroup           `protobuf:"bytes,5,rep,name=files,proto3" json:"files,omitempty"`
+	// Overrides the migration's steps for this candidate when set.
+	Steps         []*StepDefinition `protobuf:"bytes,6,rep,name=steps,proto3" json:"steps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Candidate) Reset() {
+	*x = Candidate{}
+	mi := &file_migrator_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Candidate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Candidate) ProtoMessage() {}
+
+func (x *Candidate) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Candidate.ProtoReflect.Descriptor instead.
+func (*Candidate) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Candidate) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Candidate) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Candidate) GetStatus() CandidateStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CandidateStatus_CANDIDATE_STATUS_UNSPECIFIED
+}
+
+func (x *Candidate) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Candidate) GetFiles() []*FileGroup {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *Candidate) GetSteps() []*StepDefinition {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+type FileGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Repo          string                 `protobuf:"bytes,2,opt,name=repo,proto3" json:"repo,omitempty"`
+	Files         []*FileRef             `protobuf:"bytes,3,rep,name=files,proto3" json:"files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileGroup) Reset() {
+	*x = FileGroup{}
+	mi := &file_migrator_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileGroup) ProtoMessage() {}
+
+func (x *FileGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileGroup.ProtoReflect.Descriptor instead.
+func (*FileGroup) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FileGroup) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FileGroup) GetRepo() string {
+	if x != nil {
+		return x.Repo
+	}
+	return ""
+}
+
+func (x *FileGroup) GetFiles() []*FileRef {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+type FileRef struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileRef) Reset() {
+	*x = FileRef{}
+	mi := &file_migrator_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileRef) ProtoMessage() {}
+
+func (x *FileRef) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileRef.ProtoReflect.Descriptor instead.
+func (*FileRef) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FileRef) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileRef) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type StepDefinition struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Name             string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	MigratorApp      string                 `protobuf:"bytes,2,opt,name=migrator_app,json=migratorApp,proto3" json:"migrator_app,omitempty"`
+	Description      *string                `protobuf:"bytes,3,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	Type             *string                `protobuf:"bytes,4,opt,name=type,proto3,oneof" json:"type,omitempty"`
+	Config           map[string]string      `protobuf:"bytes,5,rep,name=config,proto3" json:"config,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	DependsOn        []string               `protobuf:"bytes,6,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	When             *string                `protobuf:"bytes,7,opt,name=when,proto3,oneof" json:"when,omitempty"`
+	TimeoutSeconds   *int32                 `protobuf:"varint,8,opt,name=timeout_seconds,json=timeoutSeconds,proto3,oneof" json:"timeout_seconds,omitempty"`
+	RetryPolicy      *RetryPolicy           `protobuf:"bytes,9,opt,name=retry_policy,json=retryPolicy,proto3" json:"retry_policy,omitempty"`
+	DispatchRetry    *DispatchRetryPolicy   `protobuf:"bytes,10,opt,name=dispatch_retry,json=dispatchRetry,proto3" json:"dispatch_retry,omitempty"`
+	CompensationType *string                `protobuf:"bytes,11,opt,name=compensation_type,json=compensationType,proto3,oneof" json:"compensation_type,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *StepDefinition) Reset() {
+	*x = StepDefinition{}
+	mi := &file_migrator_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StepDefinition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StepDefinition) ProtoMessage() {}
+
+func (x *StepDefinition) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StepDefinition.ProtoReflect.Descriptor instead.
+func (*StepDefinition) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StepDefinition) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StepDefinition) GetMigratorApp() string {
+	if x != nil {
+		return x.MigratorApp
+	}
+	return ""
+}
+
+func (x *StepDefinition) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *StepDefinition) GetType() string {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return ""
+}
+
+func (x *StepDefinition) GetConfig() map[string]string {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *StepDefinition) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *StepDefinition) GetWhen() string {
+	if x != nil && x.When != nil {
+		return *x.When
+	}
+	return ""
+}
+
+func (x *StepDefinition) GetTimeoutSeconds() int32 {
+	if x != nil && x.TimeoutSeconds != nil {
+		return *x.TimeoutSeconds
+	}
+	return 0
+}
+
+func (x *StepDefinition) GetRetryPolicy() *RetryPolicy {
+	if x != nil {
+		return x.RetryPolicy
+	}
+	return nil
+}
+
+func (x *StepDefinition) GetDispatchRetry() *DispatchRetryPolicy {
+	if x != nil {
+		return x.DispatchRetry
+	}
+	return nil
+}
+
+func (x *StepDefinition) GetCompensationType() string {
+	if x != nil && x.CompensationType != nil {
+		return *x.CompensationType
+	}
+	return ""
+}
+
+type RetryPolicy struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	MaxAttempts           int32                  `protobuf:"varint,1,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`
+	InitialBackoffSeconds *int32                 `protobuf:"varint,2,opt,name=initial_backoff_seconds,json=initialBackoffSeconds,proto3,oneof" json:"initial_backoff_seconds,omitempty"`
+	BackoffMultiplier     *float64               `protobuf:"fixed64,3,opt,name=backoff_multiplier,json=backoffMultiplier,proto3,oneof" json:"backoff_multiplier,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *RetryPolicy) Reset() {
+	*x = RetryPolicy{}
+	mi := &file_migrator_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryPolicy) ProtoMessage() {}
+
+func (x *RetryPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryPolicy.ProtoReflect.Descriptor instead.
+func (*RetryPolicy) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RetryPolicy) GetMaxAttempts() int32 {
+	if x != nil {
+		return x.MaxAttempts
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetInitialBackoffSeconds() int32 {
+	if x != nil && x.InitialBackoffSeconds != nil {
+		return *x.InitialBackoffSeconds
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetBackoffMultiplier() float64 {
+	if x != nil && x.BackoffMultiplier != nil {
+		return *x.BackoffMultiplier
+	}
+	return 0
+}
+
+type DispatchRetryPolicy struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	MaxAttempts           *int32                 `protobuf:"varint,1,opt,name=max_attempts,json=maxAttempts,proto3,oneof" json:"max_attempts,omitempty"`
+	InitialBackoffSeconds *int32                 `protobuf:"varint,2,opt,name=initial_backoff_seconds,json=initialBackoffSeconds,proto3,oneof" json:"initial_backoff_seconds,omitempty"`
+	BackoffMultiplier     *float64               `protobuf:"fixed64,3,opt,name=backoff_multiplier,json=backoffMultiplier,proto3,oneof" json:"backoff_multiplier,omitempty"`
+	MaxBackoffSeconds     *int32                 `protobuf:"varint,4,opt,name=max_backoff_seconds,json=maxBackoffSeconds,proto3,oneof" json:"max_backoff_seconds,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *DispatchRetryPolicy) Reset() {
+	*x = DispatchRetryPolicy{}
+	mi := &file_migrator_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DispatchRetryPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DispatchRetryPolicy) ProtoMessage() {}
+
+func (x *DispatchRetryPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DispatchRetryPolicy.ProtoReflect.Descriptor instead.
+func (*DispatchRetryPolicy) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DispatchRetryPolicy) GetMaxAttempts() int32 {
+	if x != nil && x.MaxAttempts != nil {
+		return *x.MaxAttempts
+	}
+	return 0
+}
+
+func (x *DispatchRetryPolicy) GetInitialBackoffSeconds() int32 {
+	if x != nil && x.InitialBackoffSeconds != nil {
+		return *x.InitialBackoffSeconds
+	}
+	return 0
+}
+
+func (x *DispatchRetryPolicy) GetBackoffMultiplier() float64 {
+	if x != nil && x.BackoffMultiplier != nil {
+		return *x.BackoffMultiplier
+	}
+	return 0
+}
+
+func (x *DispatchRetryPolicy) GetMaxBackoffSeconds() int32 {
+	if x != nil && x.MaxBackoffSeconds != nil {
+		return *x.MaxBackoffSeconds
+	}
+	return 0
+}
+
+type DispatchStepRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MigrationId   string                 `protobuf:"bytes,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"`
+	StepName      string                 `protobuf:"bytes,2,opt,name=step_name,json=stepName,proto3" json:"step_name,omitempty"`
+	Candidate     *Candidate             `protobuf:"bytes,3,opt,name=candidate,proto3" json:"candidate,omitempty"`
+	Config        map[string]string      `protobuf:"bytes,4,rep,name=config,proto3" json:"config,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Type          *string                `protobuf:"bytes,5,opt,name=type,proto3,oneof" json:"type,omitempty"`
+	CallbackId    string                 `protobuf:"bytes,6,opt,name=callback_id,json=callbackId,proto3" json:"callback_id,omitempty"`
+	EventName     string                 `protobuf:"bytes,7,opt,name=event_name,json=eventName,proto3" json:"event_name,omitempty"`
+	MigratorApp   string                 `protobuf:"bytes,8,opt,name=migrator_app,json=migratorApp,proto3" json:"migrator_app,omitempty"`
+	MigratorUrl   string                 `protobuf:"bytes,9,opt,name=migrator_url,json=migratorUrl,proto3" json:"migrator_url,omitempty"`
+	Attempt       *int32                 `protobuf:"varint,10,opt,name=attempt,proto3,oneof" json:"attempt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DispatchStepRequest) Reset() {
+	*x = DispatchStepRequest{}
+	mi := &file_migrator_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DispatchStepRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DispatchStepRequest) ProtoMessage() {}
+
+func (x *DispatchStepRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DispatchStepRequest.ProtoReflect.Descriptor instead.
+func (*DispatchStepRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DispatchStepRequest) GetMigrationId() string {
+	if x != nil {
+		return x.MigrationId
+	}
+	return ""
+}
+
+func (x *DispatchStepRequest) GetStepName() string {
+	if x != nil {
+		return x.StepName
+	}
+	return ""
+}
+
+func (x *DispatchStepRequest) GetCandidate() *Candidate {
+	if x != nil {
+		return x.Candidate
+	}
+	return nil
+}
+
+func (x *DispatchStepRequest) GetConfig() map[string]string {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *DispatchStepRequest) GetType() string {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return ""
+}
+
+func (x *DispatchStepRequest) GetCallbackId() string {
+	if x != nil {
+		return x.CallbackId
+	}
+	return ""
+}
+
+func (x *DispatchStepRequest) GetEventName() string {
+	if x != nil {
+		return x.EventName
+	}
+	return ""
+}
+
+func (x *DispatchStepRequest) GetMigratorApp() string {
+	if x != nil {
+		return x.MigratorApp
+	}
+	return ""
+}
+
+func (x *DispatchStepRequest) GetMigratorUrl() string {
+	if x != nil {
+		return x.MigratorUrl
+	}
+	return ""
+}
+
+func (x *DispatchStepRequest) GetAttempt() int32 {
+	if x != nil && x.Attempt != nil {
+		return *x.Attempt
+	}
+	return 0
+}
+
+type DispatchStepResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DispatchStepResponse) Reset() {
+	*x = DispatchStepResponse{}
+	mi := &file_migrator_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DispatchStepResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DispatchStepResponse) ProtoMessage() {}
+
+func (x *DispatchStepResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DispatchStepResponse.ProtoReflect.Descriptor instead.
+func (*DispatchStepResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{7}
+}
+
+type DryRunRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MigrationId   string                 `protobuf:"bytes,1,opt,name=migration_id,json=migrationId,proto3" json:"migration_id,omitempty"`
+	Candidate     *Candidate             `protobuf:"bytes,2,opt,name=candidate,proto3" json:"candidate,omitempty"`
+	Steps         []*StepDefinition      `protobuf:"bytes,3,rep,name=steps,proto3" json:"steps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DryRunRequest) Reset() {
+	*x = DryRunRequest{}
+	mi := &file_migrator_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DryRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DryRunRequest) ProtoMessage() {}
+
+func (x *DryRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DryRunRequest.ProtoReflect.Descriptor instead.
+func (*DryRunRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DryRunRequest) GetMigrationId() string {
+	if x != nil {
+		return x.MigrationId
+	}
+	return ""
+}
+
+func (x *DryRunRequest) GetCandidate() *Candidate {
+	if x != nil {
+		return x.Candidate
+	}
+	return nil
+}
+
+func (x *DryRunRequest) GetSteps() []*StepDefinition {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+type DryRunResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Steps         []*StepDryRunResult    `protobuf:"bytes,1,rep,name=steps,proto3" json:"steps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DryRunResult) Reset() {
+	*x = DryRunResult{}
+	mi := &file_migrator_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DryRunResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DryRunResult) ProtoMessage() {}
+
+func (x *DryRunResult) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DryRunResult.ProtoReflect.Descriptor instead.
+func (*DryRunResult) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DryRunResult) GetSteps() []*StepDryRunResult {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+type StepDryRunResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StepName      string                 `protobuf:"bytes,1,opt,name=step_name,json=stepName,proto3" json:"step_name,omitempty"`
+	Skipped       bool                   `protobuf:"varint,2,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	Files         []*FileDiff            `protobuf:"bytes,3,rep,name=files,proto3" json:"files,omitempty"`
+	Error         *string                `protobuf:"bytes,4,opt,name=error,proto3,oneof" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StepDryRunResult) Reset() {
+	*x = StepDryRunResult{}
+	mi := &file_migrator_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StepDryRunResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StepDryRunResult) ProtoMessage() {}
+
+func (x *StepDryRunResult) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StepDryRunResult.ProtoReflect.Descriptor instead.
+func (*StepDryRunResult) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StepDryRunResult) GetStepName() string {
+	if x != nil {
+		return x.StepName
+	}
+	return ""
+}
+
+func (x *StepDryRunResult) GetSkipped() bool {
+	if x != nil {
+		return x.Skipped
+	}
+	return false
+}
+
+func (x *StepDryRunResult) GetFiles() []*FileDiff {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *StepDryRunResult) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+type FileDiff struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Repo          string                 `protobuf:"bytes,1,opt,name=repo,proto3" json:"repo,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Status        FileDiffStatus         `protobuf:"varint,3,opt,name=status,proto3,enum=loom.migrator.v1.FileDiffStatus" json:"status,omitempty"`
+	Before        *string                `protobuf:"bytes,4,opt,name=before,proto3,oneof" json:"before,omitempty"`
+	After         string                 `protobuf:"bytes,5,opt,name=after,proto3" json:"after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileDiff) Reset() {
+	*x = FileDiff{}
+	mi := &file_migrator_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileDiff) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileDiff) ProtoMessage() {}
+
+func (x *FileDiff) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileDiff.ProtoReflect.Descriptor instead.
+func (*FileDiff) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *FileDiff) GetRepo() string {
+	if x != nil {
+		return x.Repo
+	}
+	return ""
+}
+
+func (x *FileDiff) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileDiff) GetStatus() FileDiffStatus {
+	if x != nil {
+		return x.Status
+	}
+	return FileDiffStatus_FILE_DIFF_STATUS_UNSPECIFIED
+}
+
+func (x *FileDiff) GetBefore() string {
+	if x != nil && x.Before != nil {
+		return *x.Before
+	}
+	return ""
+}
+
+func (x *FileDiff) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+type StepStatusEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StepName      string                 `protobuf:"bytes,1,opt,name=step_name,json=stepName,proto3" json:"step_name,omitempty"`
+	CandidateId   string                 `protobuf:"bytes,2,opt,name=candidate_id,json=candidateId,proto3" json:"candidate_id,omitempty"`
+	Status        StepStatus             `protobuf:"varint,3,opt,name=status,proto3,enum=loom.migrator.v1.StepStatus" json:"status,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Attempt       *int32                 `protobuf:"varint,5,opt,name=attempt,proto3,oneof" json:"attempt,omitempty"`
+	EventId       *string                `protobuf:"bytes,6,opt,name=event_id,json=eventId,proto3,oneof" json:"event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StepStatusEvent) Reset() {
+	*x = StepStatusEvent{}
+	mi := &file_migrator_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StepStatusEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StepStatusEvent) ProtoMessage() {}
+
+func (x *StepStatusEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StepStatusEvent.ProtoReflect.Descriptor instead.
+func (*StepStatusEvent) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *StepStatusEvent) GetStepName() string {
+	if x != nil {
+		return x.StepName
+	}
+	return ""
+}
+
+func (x *StepStatusEvent) GetCandidateId() string {
+	if x != nil {
+		return x.CandidateId
+	}
+	return ""
+}
+
+func (x *StepStatusEvent) GetStatus() StepStatus {
+	if x != nil {
+		return x.Status
+	}
+	return StepStatus_STEP_STATUS_UNSPECIFIED
+}
+
+func (x *StepStatusEvent) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *StepStatusEvent) GetAttempt() int32 {
+	if x != nil && x.Attempt != nil {
+		return *x.Attempt
+	}
+	return 0
+}
+
+func (x *StepStatusEvent) GetEventId() string {
+	if x != nil && x.EventId != nil {
+		return *x.EventId
+	}
+	return ""
+}
+
+type SendCallbackRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The callbackId of the DispatchStepRequest being answered.
+	CallbackId    string           `protobuf:"bytes,1,opt,name=callback_id,json=callbackId,proto3" json:"callback_id,omitempty"`
+	Event         *StepStatusEvent `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendCallbackRequest) Reset() {
+	*x = SendCallbackRequest{}
+	mi := &file_migrator_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendCallbackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendCallbackRequest) ProtoMessage() {}
+
+func (x *SendCallbackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendCallbackRequest.ProtoReflect.Descriptor instead.
+func (*SendCallbackRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SendCallbackRequest) GetCallbackId() string {
+	if x != nil {
+		return x.CallbackId
+	}
+	return ""
+}
+
+func (x *SendCallbackRequest) GetEvent() *StepStatusEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+type SendCallbackResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Set when the event was already applied under the same event_id.
+	Duplicate     bool `protobuf:"varint,1,opt,name=duplicate,proto3" json:"duplicate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendCallbackResponse) Reset() {
+	*x = SendCallbackResponse{}
+	mi := &file_migrator_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendCallbackResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendCallbackResponse) ProtoMessage() {}
+
+func (x *SendCallbackResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendCallbackResponse.ProtoReflect.Descriptor instead.
+func (*SendCallbackResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SendCallbackResponse) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
+type InputDefinition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Label         string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	Description   *string                `protobuf:"bytes,3,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InputDefinition) Reset() {
+	*x = InputDefinition{}
+	mi := &file_migrator_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InputDefinition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InputDefinition) ProtoMessage() {}
+
+func (x *InputDefinition) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InputDefinition.ProtoReflect.Descriptor instead.
+func (*InputDefinition) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *InputDefinition) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *InputDefinition) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *InputDefinition) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+type DispatchLimit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MigratorApp   string                 `protobuf:"bytes,1,opt,name=migrator_app,json=migratorApp,proto3" json:"migrator_app,omitempty"`
+	MaxInFlight   *int32                 `protobuf:"varint,2,opt,name=max_in_flight,json=maxInFlight,proto3,oneof" json:"max_in_flight,omitempty"`
+	PerMinute     *int32                 `protobuf:"varint,3,opt,name=per_minute,json=perMinute,proto3,oneof" json:"per_minute,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DispatchLimit) Reset() {
+	*x = DispatchLimit{}
+	mi := &file_migrator_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DispatchLimit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DispatchLimit) ProtoMessage() {}
+
+func (x *DispatchLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DispatchLimit.ProtoReflect.Descriptor instead.
+func (*DispatchLimit) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DispatchLimit) GetMigratorApp() string {
+	if x != nil {
+		return x.MigratorApp
+	}
+	return ""
+}
+
+func (x *DispatchLimit) GetMaxInFlight() int32 {
+	if x != nil && x.MaxInFlight != nil {
+		return *x.MaxInFlight
+	}
+	return 0
+}
+
+func (x *DispatchLimit) GetPerMinute() int32 {
+	if x != nil && x.PerMinute != nil {
+		return *x.PerMinute
+	}
+	return 0
+}
+
+type MigrationAnnouncement struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description    string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Overview       []string               `protobuf:"bytes,4,rep,name=overview,proto3" json:"overview,omitempty"`
+	RequiredInputs []*InputDefinition     `protobuf:"bytes,5,rep,name=required_inputs,json=requiredInputs,proto3" json:"required_inputs,omitempty"`
+	Steps          []*StepDefinition      `protobuf:"bytes,6,rep,name=steps,proto3" json:"steps,omitempty"`
+	Candidates     []*Candidate           `protobuf:"bytes,7,rep,name=candidates,proto3" json:"candidates,omitempty"`
+	MigratorUrl    string                 `protobuf:"bytes,8,opt,name=migrator_url,json=migratorUrl,proto3" json:"migrator_url,omitempty"`
+	DispatchLimits []*DispatchLimit       `protobuf:"bytes,9,rep,name=dispatch_limits,json=dispatchLimits,proto3" json:"dispatch_limits,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *MigrationAnnouncement) Reset() {
+	*x = MigrationAnnouncement{}
+	mi := &file_migrator_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MigrationAnnouncement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MigrationAnnouncement) ProtoMessage() {}
+
+func (x *MigrationAnnouncement) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MigrationAnnouncement.ProtoReflect.Descriptor instead.
+func (*MigrationAnnouncement) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *MigrationAnnouncement) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MigrationAnnouncement) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MigrationAnnouncement) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *MigrationAnnouncement) GetOverview() []string {
+	if x != nil {
+		return x.Overview
+	}
+	return nil
+}
+
+func (x *MigrationAnnouncement) GetRequiredInputs() []*InputDefinition {
+	if x != nil {
+		return x.RequiredInputs
+	}
+	return nil
+}
+
+func (x *MigrationAnnouncement) GetSteps() []*StepDefinition {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+func (x *MigrationAnnouncement) GetCandidates() []*Candidate {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
+func (x *MigrationAnnouncement) GetMigratorUrl() string {
+	if x != nil {
+		return x.MigratorUrl
+	}
+	return ""
+}
+
+func (x *MigrationAnnouncement) GetDispatchLimits() []*DispatchLimit {
+	if x != nil {
+		return x.DispatchLimits
+	}
+	return nil
+}
+
+type AnnounceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Announcement  *MigrationAnnouncement `protobuf:"bytes,1,opt,name=announcement,proto3" json:"announcement,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnnounceRequest) Reset() {
+	*x = AnnounceRequest{}
+	mi := &file_migrator_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnnounceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnnounceRequest) ProtoMessage() {}
+
+func (x *AnnounceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnnounceRequest.ProtoReflect.Descriptor instead.
+func (*AnnounceRequest) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AnnounceRequest) GetAnnouncement() *MigrationAnnouncement {
+	if x != nil {
+		return x.Announcement
+	}
+	return nil
+}
+
+type AnnounceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnnounceResponse) Reset() {
+	*x = AnnounceResponse{}
+	mi := &file_migrator_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnnounceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnnounceResponse) ProtoMessage() {}
+
+func (x *AnnounceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnnounceResponse.ProtoReflect.Descriptor instead.
+func (*AnnounceResponse) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{19}
+}
+
+type MigratorRegistration struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MigratorApp   string                 `protobuf:"bytes,1,opt,name=migrator_app,json=migratorApp,proto3" json:"migrator_app,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	HealthUrl     *string                `protobuf:"bytes,3,opt,name=health_url,json=healthUrl,proto3,oneof" json:"health_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MigratorRegistration) Reset() {
+	*x = MigratorRegistration{}
+	mi := &file_migrator_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MigratorRegistration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MigratorRegistration) ProtoMessage() {}
+
+func (x *MigratorRegistration) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MigratorRegistration.ProtoReflect.Descriptor instead.
+func (*MigratorRegistration) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *MigratorRegistration) GetMigratorApp() string {
+	if x != nil {
+		return x.MigratorApp
+	}
+	return ""
+}
+
+func (x *MigratorRegistration) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *MigratorRegistration) GetHealthUrl() string {
+	if x != nil && x.HealthUrl != nil {
+		return *x.HealthUrl
+	}
+	return ""
+}
+
+type RegisteredMigrator struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MigratorApp   string                 `protobuf:"bytes,1,opt,name=migrator_app,json=migratorApp,proto3" json:"migrator_app,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	HealthUrl     string                 `protobuf:"bytes,3,opt,name=health_url,json=healthUrl,proto3" json:"health_url,omitempty"`
+	RegisteredAt  *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=registered_at,json=registeredAt,proto3" json:"registered_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisteredMigrator) Reset() {
+	*x = RegisteredMigrator{}
+	mi := &file_migrator_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisteredMigrator) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisteredMigrator) ProtoMessage() {}
+
+func (x *RegisteredMigrator) ProtoReflect() protoreflect.Message {
+	mi := &file_migrator_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisteredMigrator.ProtoReflect.Descriptor instead.
+func (*RegisteredMigrator) Descriptor() ([]byte, []int) {
+	return file_migrator_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RegisteredMigrator) GetMigratorApp() string {
+	if x != nil {
+		return x.MigratorApp
+	}
+	return ""
+}
+
+func (x *RegisteredMigrator) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *RegisteredMigrator) GetHealthUrl() string {
+	if x != nil {
+		return x.HealthUrl
+	}
+	return ""
+}
+
+func (x *RegisteredMigrator) GetRegisteredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RegisteredAt
+	}
+	return nil
+}
+
+var File_migrator_proto protoreflect.FileDescriptor
+
+const file_migrator_proto_rawDesc = "" +
+	"\n" +
+	"\x0emigrator.proto\x12\x10loom.migrator.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd9\x02\n" +
+	"\tCandidate\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x129\n" +
+	"\x06status\x18\x03 \x01(\x0e2!.loom.migrator.v1.CandidateStatusR\x06status\x12E\n" +
+	"\bmetadata\x18\x04 \x03(\v2).loom.migrator.v1.Candidate.MetadataEntryR\bmetadata\x121\n" +
+	"\x05files\x18\x05 \x03(\v2\x1b.loom.migrator.v1.FileGroupR\x05files\x126\n" +
+	"\x05steps\x18\x06 \x03(\v2 .loom.migrator.v1.StepDefinitionR\x05steps\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"d\n" +
+	"\tFileGroup\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04repo\x18\x02 \x01(\tR\x04repo\x12/\n" +
+	"\x05files\x18\x03 \x03(\v2\x19.loom.migrator.v1.FileRefR\x05files\"/\n" +
+	"\aFileRef\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\"\xfc\x04\n" +
+	"\x0eStepDefinition\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12!\n" +
+	"\fmigrator_app\x18\x02 \x01(\tR\vmigratorApp\x12%\n" +
+	"\vdescription\x18\x03 \x01(\tH\x00R\vdescription\x88\x01\x01\x12\x17\n" +
+	"\x04type\x18\x04 \x01(\tH\x01R\x04type\x88\x01\x01\x12D\n" +
+	"\x06config\x18\x05 \x03(\v2,.loom.migrator.v1.StepDefinition.ConfigEntryR\x06config\x12\x1d\n" +
+	"\n" +
+	"depends_on\x18\x06 \x03(\tR\tdependsOn\x12\x17\n" +
+	"\x04when\x18\a \x01(\tH\x02R\x04when\x88\x01\x01\x12,\n" +
+	"\x0ftimeout_seconds\x18\b \x01(\x05H\x03R\x0etimeoutSeconds\x88\x01\x01\x12@\n" +
+	"\fretry_policy\x18\t \x01(\v2\x1d.loom.migrator.v1.RetryPolicyR\vretryPolicy\x12L\n" +
+	"\x0edispatch_retry\x18\n" +
+	" \x01(\v2%.loom.migrator.v1.DispatchRetryPolicyR\rdispatchRetry\x120\n" +
+	"\x11compensation_type\x18\v \x01(\tH\x04R\x10compensationType\x88\x01\x01\x1a9\n" +
+	"\vConfigEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x0e\n" +
+	"\f_descriptionB\a\n" +
+	"\x05_typeB\a\n" +
+	"\x05_whenB\x12\n" +
+	"\x10_timeout_secondsB\x14\n" +
+	"\x12_compensation_type\"\xd4\x01\n" +
+	"\vRetryPolicy\x12!\n" +
+	"\fmax_attempts\x18\x01 \x01(\x05R\vmaxAttempts\x12;\n" +
+	"\x17initial_backoff_seconds\x18\x02 \x01(\x05H\x00R\x15initialBackoffSeconds\x88\x01\x01\x122\n" +
+	"\x12backoff_multiplier\x18\x03 \x01(\x01H\x01R\x11backoffMultiplier\x88\x01\x01B\x1a\n" +
+	"\x18_initial_backoff_secondsB\x15\n" +
+	"\x13_backoff_multiplier\"\xbf\x02\n" +
+	"\x13DispatchRetryPolicy\x12&\n" +
+	"\fmax_attempts\x18\x01 \x01(\x05H\x00R\vmaxAttempts\x88\x01\x01\x12;\n" +
+	"\x17initial_backoff_seconds\x18\x02 \x01(\x05H\x01R\x15initialBackoffSeconds\x88\x01\x01\x122\n" +
+	"\x12backoff_multiplier\x18\x03 \x01(\x01H\x02R\x11backoffMultiplier\x88\x01\x01\x123\n" +
+	"\x13max_backoff_seconds\x18\x04 \x01(\x05H\x03R\x11maxBackoffSeconds\x88\x01\x01B\x0f\n" +
+	"\r_max_attemptsB\x1a\n" +
+	"\x18_initial_backoff_secondsB\x15\n" +
+	"\x13_backoff_multiplierB\x16\n" +
+	"\x14_max_backoff_seconds\"\xe9\x03\n" +
+	"\x13DispatchStepRequest\x12!\n" +
+	"\fmigration_id\x18\x01 \x01(\tR\vmigrationId\x12\x1b\n" +
+	"\tstep_name\x18\x02 \x01(\tR\bstepName\x129\n" +
+	"\tcandidate\x18\x03 \x01(\v2\x1b.loom.migrator.v1.CandidateR\tcandidate\x12I\n" +
+	"\x06config\x18\x04 \x03(\v21.loom.migrator.v1.DispatchStepRequest.ConfigEntryR\x06config\x12\x17\n" +
+	"\x04type\x18\x05 \x01(\tH\x00R\x04type\x88\x01\x01\x12\x1f\n" +
+	"\vcallback_id\x18\x06 \x01(\tR\n" +
+	"callbackId\x12\x1d\n" +
+	"\n" +
+	"event_name\x18\a \x01(\tR\teventName\x12!\n" +
+	"\fmigrator_app\x18\b \x01(\tR\vmigratorApp\x12!\n" +
+	"\fmigrator_url\x18\t \x01(\tR\vmigratorUrl\x12\x1d\n" +
+	"\aattempt\x18\n" +
+	" \x01(\x05H\x01R\aattempt\x88\x01\x01\x1a9\n" +
+	"\vConfigEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\a\n" +
+	"\x05_typeB\n" +
+	"\n" +
+	"\b_attempt\"\x16\n" +
+	"\x14DispatchStepResponse\"\xa5\x01\n" +
+	"\rDryRunRequest\x12!\n" +
+	"\fmigration_id\x18\x01 \x01(\tR\vmigrationId\x129\n" +
+	"\tcandidate\x18\x02 \x01(\v2\x1b.loom.migrator.v1.CandidateR\tcandidate\x126\n" +
+	"\x05steps\x18\x03 \x03(\v2 .loom.migrator.v1.StepDefinitionR\x05steps\"H\n" +
+	"\fDryRunResult\x128\n" +
+	"\x05steps\x18\x01 \x03(\v2\".loom.migrator.v1.StepDryRunResultR\x05steps\"\xa0\x01\n" +
+	"\x10StepDryRunResult\x12\x1b\n" +
+	"\tstep_name\x18\x01 \x01(\tR\bstepName\x12\x18\n" +
+	"\askipped\x18\x02 \x01(\bR\askipped\x120\n" +
+	"\x05files\x18\x03 \x03(\v2\x1a.loom.migrator.v1.FileDiffR\x05files\x12\x19\n" +
+	"\x05error\x18\x04 \x01(\tH\x00R\x05error\x88\x01\x01B\b\n" +
+	"\x06_error\"\xaa\x01\n" +
+	"\bFileDiff\x12\x12\n" +
+	"\x04repo\x18\x01 \x01(\tR\x04repo\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x128\n" +
+	"\x06status\x18\x03 \x01(\x0e2 .loom.migrator.v1.FileDiffStatusR\x06status\x12\x1b\n" +
+	"\x06before\x18\x04 \x01(\tH\x00R\x06before\x88\x01\x01\x12\x14\n" +
+	"\x05after\x18\x05 \x01(\tR\x05afterB\t\n" +
+	"\a_before\"\xe9\x02\n" +
+	"\x0fStepStatusEvent\x12\x1b\n" +
+	"\tstep_name\x18\x01 \x01(\tR\bstepName\x12!\n" +
+	"\fcandidate_id\x18\x02 \x01(\tR\vcandidateId\x124\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x1c.loom.migrator.v1.StepStatusR\x06status\x12K\n" +
+	"\bmetadata\x18\x04 \x03(\v2/.loom.migrator.v1.StepStatusEvent.MetadataEntryR\bmetadata\x12\x1d\n" +
+	"\aattempt\x18\x05 \x01(\x05H\x00R\aattempt\x88\x01\x01\x12\x1e\n" +
+	"\bevent_id\x18\x06 \x01(\tH\x01R\aeventId\x88\x01\x01\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\n" +
+	"\n" +
+	"\b_attemptB\v\n" +
+	"\t_event_id\"o\n" +
+	"\x13SendCallbackRequest\x12\x1f\n" +
+	"\vcallback_id\x18\x01 \x01(\tR\n" +
+	"callbackId\x127\n" +
+	"\x05event\x18\x02 \x01(\v2!.loom.migrator.v1.StepStatusEventR\x05event\"4\n" +
+	"\x14SendCallbackResponse\x12\x1c\n" +
+	"\tduplicate\x18\x01 \x01(\bR\tduplicate\"r\n" +
+	"\x0fInputDefinition\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\x12%\n" +
+	"\vdescription\x18\x03 \x01(\tH\x00R\vdescription\x88\x01\x01B\x0e\n" +
+	"\f_description\"\xa0\x01\n" +
+	"\rDispatchLimit\x12!\n" +
+	"\fmigrator_app\x18\x01 \x01(\tR\vmigratorApp\x12'\n" +
+	"\rmax_in_flight\x18\x02 \x01(\x05H\x00R\vmaxInFlight\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"per_minute\x18\x03 \x01(\x05H\x01R\tperMinute\x88\x01\x01B\x10\n" +
+	"\x0e_max_in_flightB\r\n" +
+	"\v_per_minute\"\xa7\x03\n" +
+	"\x15MigrationAnnouncement\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1a\n" +
+	"\boverview\x18\x04 \x03(\tR\boverview\x12J\n" +
+	"\x0frequired_inputs\x18\x05 \x03(\v2!.loom.migrator.v1.InputDefinitionR\x0erequiredInputs\x126\n" +
+	"\x05steps\x18\x06 \x03(\v2 .loom.migrator.v1.StepDefinitionR\x05steps\x12;\n" +
+	"\n" +
+	"candidates\x18\a \x03(\v2\x1b.loom.migrator.v1.CandidateR\n" +
+	"candidates\x12!\n" +
+	"\fmigrator_url\x18\b \x01(\tR\vmigratorUrl\x12H\n" +
+	"\x0fdispatch_limits\x18\t \x03(\v2\x1f.loom.migrator.v1.DispatchLimitR\x0edispatchLimits\"^\n" +
+	"\x0fAnnounceRequest\x12K\n" +
+	"\fannouncement\x18\x01 \x01(\v2'.loom.migrator.v1.MigrationAnnouncementR\fannouncement\"\x12\n" +
+	"\x10AnnounceResponse\"~\n" +
+	"\x14MigratorRegistration\x12!\n" +
+	"\fmigrator_app\x18\x01 \x01(\tR\vmigratorApp\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\"\n" +
+	"\n" +
+	"health_url\x18\x03 \x01(\tH\x00R\thealthUrl\x88\x01\x01B\r\n" +
+	"\v_health_url\"\xa9\x01\n" +
+	"\x12RegisteredMigrator\x12!\n" +
+	"\fmigrator_app\x18\x01 \x01(\tR\vmigratorApp\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x1d\n" +
+	"\n" +
+	"health_url\x18\x03 \x01(\tR\thealthUrl\x12?\n" +
+	"\rregistered_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\fregisteredAt*\x93\x01\n" +
+	"\x0fCandidateStatus\x12 \n" +
+	"\x1cCANDIDATE_STATUS_UNSPECIFIED\x10\x00\x12 \n" +
+	"\x1cCANDIDATE_STATUS_NOT_STARTED\x10\x01\x12\x1c\n" +
+	"\x18CANDIDATE_STATUS_RUNNING\x10\x02\x12\x1e\n" +
+	"\x1aCANDIDATE_STATUS_COMPLETED\x10\x03*\x89\x01\n" +
+	"\x0eFileDiffStatus\x12 \n" +
+	"\x1cFILE_DIFF_STATUS_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14FILE_DIFF_STATUS_NEW\x10\x01\x12\x1d\n" +
+	"\x19FILE_DIFF_STATUS_MODIFIED\x10\x02\x12\x1c\n" +
+	"\x18FILE_DIFF_STATUS_DELETED\x10\x03*\x8d\x01\n" +
+	"\n" +
+	"StepStatus\x12\x1b\n" +
+	"\x17STEP_STATUS_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15STEP_STATUS_SUCCEEDED\x10\x01\x12\x16\n" +
+	"\x12STEP_STATUS_FAILED\x10\x02\x12\x17\n" +
+	"\x13STEP_STATUS_PENDING\x10\x03\x12\x16\n" +
+	"\x12STEP_STATUS_MERGED\x10\x042\xb4\x01\n" +
+	"\bMigrator\x12]\n" +
+	"\fDispatchStep\x12%.loom.migrator.v1.DispatchStepRequest\x1a&.loom.migrator.v1.DispatchStepResponse\x12I\n" +
+	"\x06DryRun\x12\x1f.loom.migrator.v1.DryRunRequest\x1a\x1e.loom.migrator.v1.DryRunResult2\x9a\x02\n" +
+	"\x04Loom\x12Q\n" +
+	"\bAnnounce\x12!.loom.migrator.v1.AnnounceRequest\x1a\".loom.migrator.v1.AnnounceResponse\x12`\n" +
+	"\x10RegisterMigrator\x12&.loom.migrator.v1.MigratorRegistration\x1a$.loom.migrator.v1.RegisteredMigrator\x12]\n" +
+	"\fSendCallback\x12%.loom.migrator.v1.SendCallbackRequest\x1a&.loom.migrator.v1.SendCallbackResponseB(Z&github.com/tilsley/loom/pkg/migratorpbb\x06proto3"
+
+var (
+	file_migrator_proto_rawDescOnce sync.Once
+	file_migrator_proto_rawDescData []byte
+)
+
+func file_migrator_proto_rawDescGZIP() []byte {
+	file_migrator_proto_rawDescOnce.Do(func() {
+		file_migrator_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_migrator_proto_rawDesc), len(file_migrator_proto_rawDesc)))
+	})
+	return file_migrator_proto_rawDescData
+}
+
+var file_migrator_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_migrator_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
+var file_migrator_proto_goTypes = []any{
+	(CandidateStatus)(0),          // 0: loom.migrator.v1.CandidateStatus
+	(FileDiffStatus)(0),           // 1: loom.migrator.v1.FileDiffStatus
+	(StepStatus)(0),               // 2: loom.migrator.v1.StepStatus
+	(*Candidate)(nil),             // 3: loom.migrator.v1.Candidate
+	(*FileGroup)(nil),             // 4: loom.migrator.v1.FileGroup
+	(*FileRef)(nil),               // 5: loom.migrator.v1.FileRef
+	(*StepDefinition)(nil),        // 6: loom.migrator.v1.StepDefinition
+	(*RetryPolicy)(nil),           // 7: loom.migrator.v1.RetryPolicy
+	(*DispatchRetryPolicy)(nil),   // 8: loom.migrator.v1.DispatchRetryPolicy
+	(*DispatchStepRequest)(nil),   // 9: loom.migrator.v1.DispatchStepRequest
+	(*DispatchStepResponse)(nil),  // 10: loom.migrator.v1.DispatchStepResponse
+	(*DryRunRequest)(nil),         // 11: loom.migrator.v1.DryRunRequest
+	(*DryRunResult)(nil),          // 12: loom.migrator.v1.DryRunResult
+	(*StepDryRunResult)(nil),      // 13: loom.migrator.v1.StepDryRunResult
+	(*FileDiff)(nil),              // 14: loom.migrator.v1.FileDiff
+	(*StepStatusEvent)(nil),       // 15: loom.migrator.v1.StepStatusEvent
+	(*SendCallbackRequest)(nil),   // 16: loom.migrator.v1.SendCallbackRequest
+	(*SendCallbackResponse)(nil),  // 17: loom.migrator.v1.SendCallbackResponse
+	(*InputDefinition)(nil),       // 18: loom.migrator.v1.InputDefinition
+	(*DispatchLimit)(nil),         // 19: loom.migrator.v1.DispatchLimit
+	(*MigrationAnnouncement)(nil), // 20: loom.migrator.v1.MigrationAnnouncement
+	(*AnnounceRequest)(nil),       // 21: loom.migrator.v1.AnnounceRequest
+	(*AnnounceResponse)(nil),      // 22: loom.migrator.v1.AnnounceResponse
+	(*MigratorRegistration)(nil),  // 23: loom.migrator.v1.MigratorRegistration
+	(*RegisteredMigrator)(nil),    // 24: loom.migrator.v1.RegisteredMigrator
+	nil,                           // 25: loom.migrator.v1.Candidate.MetadataEntry
+	nil,                           // 26: loom.migrator.v1.StepDefinition.ConfigEntry
+	nil,                           // 27: loom.migrator.v1.DispatchStepRequest.ConfigEntry
+	nil,                           // 28: loom.migrator.v1.StepStatusEvent.MetadataEntry
+	(*timestamppb.Timestamp)(nil), // 29: google.protobuf.Timestamp
+}
+var file_migrator_proto_depIdxs = []int32{
+	0,  // 0: loom.migrator.v1.Candidate.status:type_name -> loom.migrator.v1.CandidateStatus
+	25, // 1: loom.migrator.v1.Candidate.metadata:type_name -> loom.migrator.v1.Candidate.MetadataEntry
+	4,  // 2: loom.migrator.v1.Candidate.files:type_name -> loom.migrator.v1.FileGroup
+	6,  // 3: loom.migrator.v1.Candidate.steps:type_name -> loom.migrator.v1.StepDefinition
+	5,  // 4: loom.migrator.v1.FileGroup.files:type_name -> loom.migrator.v1.FileRef
+	26, // 5: loom.migrator.v1.StepDefinition.config:type_name -> loom.migrator.v1.StepDefinition.ConfigEntry
+	7,  // 6: loom.migrator.v1.StepDefinition.retry_policy:type_name -> loom.migrator.v1.RetryPolicy
+	8,  // 7: loom.migrator.v1.StepDefinition.dispatch_retry:type_name -> loom.migrator.v1.DispatchRetryPolicy
+	3,  // 8: loom.migrator.v1.DispatchStepRequest.candidate:type_name -> loom.migrator.v1.Candidate
+	27, // 9: loom.migrator.v1.DispatchStepRequest.config:type_name -> loom.migrator.v1.DispatchStepRequest.ConfigEntry
+	3,  // 10: loom.migrator.v1.DryRunRequest.candidate:type_name -> loom.migrator.v1.Candidate
+	6,  // 11: loom.migrator.v1.DryRunRequest.steps:type_name -> loom.migrator.v1.StepDefinition
+	13, // 12: loom.migrator.v1.DryRunResult.steps:type_name -> loom.migrator.v1.StepDryRunResult
+	14, // 13: loom.migrator.v1.StepDryRunResult.files:type_name -> loom.migrator.v1.FileDiff
+	1,  // 14: loom.migrator.v1.FileDiff.status:type_name -> loom.migrator.v1.FileDiffStatus
+	2,  // 15: loom.migrator.v1.StepStatusEvent.status:type_name -> loom.migrator.v1.StepStatus
+	28, // 16: loom.migrator.v1.StepStatusEvent.metadata:type_name -> loom.migrator.v1.StepStatusEvent.MetadataEntry
+	15, // 17: loom.migrator.v1.SendCallbackRequest.event:type_name -> loom.migrator.v1.StepStatusEvent
+	18, // 18: loom.migrator.v1.MigrationAnnouncement.required_inputs:type_name -> loom.migrator.v1.InputDefinition
+	6,  // 19: loom.migrator.v1.MigrationAnnouncement.steps:type_name -> loom.migrator.v1.StepDefinition
+	3,  // 20: loom.migrator.v1.MigrationAnnouncement.candidates:type_name -> loom.migrator.v1.Candidate
+	19, // 21: loom.migrator.v1.MigrationAnnouncement.dispatch_limits:type_name -> loom.migrator.v1.DispatchLimit
+	20, // 22: loom.migrator.v1.AnnounceRequest.announcement:type_name -> loom.migrator.v1.MigrationAnnouncement
+	29, // 23: loom.migrator.v1.RegisteredMigrator.registered_at:type_name -> google.protobuf.Timestamp
+	9,  // 24: loom.migrator.v1.Migrator.DispatchStep:input_type -> loom.migrator.v1.DispatchStepRequest
+	11, // 25: loom.migrator.v1.Migrator.DryRun:input_type -> loom.migrator.v1.DryRunRequest
+	21, // 26: loom.migrator.v1.Loom.Announce:input_type -> loom.migrator.v1.AnnounceRequest
+	23, // 27: loom.migrator.v1.Loom.RegisterMigrator:input_type -> loom.migrator.v1.MigratorRegistration
+	16, // 28: loom.migrator.v1.Loom.SendCallback:input_type -> loom.migrator.v1.SendCallbackRequest
+	10, // 29: loom.migrator.v1.Migrator.DispatchStep:output_type -> loom.migrator.v1.DispatchStepResponse
+	12, // 30: loom.migrator.v1.Migrator.DryRun:output_type -> loom.migrator.v1.DryRunResult
+	22, // 31: loom.migrator.v1.Loom.Announce:output_type -> loom.migrator.v1.AnnounceResponse
+	24, // 32: loom.migrator.v1.Loom.RegisterMigrator:output_type -> loom.migrator.v1.RegisteredMigrator
+	17, // 33: loom.migrator.v1.Loom.SendCallback:output_type -> loom.migrator.v1.SendCallbackResponse
+	29, // [29:34] is the sub-list for method output_type
+	24, // [24:29] is the sub-list for method input_type
+	24, // [24:24] is the sub-list for extension type_name
+	24, // [24:24] is the sub-list for extension extendee
+	0,  // [0:24] is the sub-list for field type_name
+}
+
+func init() { file_migrator_proto_init() }
+func file_migrator_proto_init() {
+	if File_migrator_proto != nil {
+		return
+	}
+	file_migrator_proto_msgTypes[3].OneofWrappers = []any{}
+	file_migrator_proto_msgTypes[4].OneofWrappers = []any{}
+	file_migrator_proto_msgTypes[5].OneofWrappers = []any{}
+	file_migrator_proto_msgTypes[6].OneofWrappers = []any{}
+	file_migrator_proto_msgTypes[10].OneofWrappers = []any{}
+	file_migrator_proto_msgTypes[11].OneofWrappers = []any{}
+	file_migrator_proto_msgTypes[12].OneofWrappers = []any{}
+	file_migrator_proto_msgTypes[15].OneofWrappers = []any{}
+	file_migrator_proto_msgTypes[16].OneofWrappers = []any{}
+	file_migrator_proto_msgTypes[20].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_migrator_proto_rawDesc), len(file_migrator_proto_rawDesc)),
+			NumEnums:      3,
+			NumMessages:   26,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_migrator_proto_goTypes,
+		DependencyIndexes: file_migrator_proto_depIdxs,
+		EnumInfos:         file_migrator_proto_enumTypes,
+		MessageInfos:      file_migrator_proto_msgTypes,
+	}.Build()
+	File_migrator_proto = out.File
+	file_migrator_proto_goTypes = nil
+	file_migrator_proto_depIdxs = nil
+}
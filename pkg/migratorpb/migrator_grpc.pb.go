@@ -0,0 +1,394 @@
+// gRPC transport for the migrator contract. The messages mirror the OpenAPI
+// schemas of the JSON-over-HTTP routes in openapi.yaml field for field, under
+// the same names, so a migrator can switch transports without changing what
+// it sends. Optional OpenAPI fields are proto3 optional fields, or empty
+// repeated and map fields; an unset enum is the OpenAPI field left out.
+//
+// The server uses it for a migrator whose URL has the grpc:// or grpcs://
+// scheme. Deadlines travel with each call; failures are gRPC status codes.
+//
+// Streaming step logs is not part of the contract yet: migrators report on a
+// step through SendCallback only, and Loom has nowhere to keep or show log
+// lines.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: migrator.proto
+
+package migratorpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Migrator_DispatchStep_FullMethodName = "/loom.migrator.v1.Migrator/DispatchStep"
+	Migrator_DryRun_FullMethodName       = "/loom.migrator.v1.Migrator/DryRun"
+)
+
+// MigratorClient is the client API for Migrator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Migrator is served by a migrator and called by the Loom server.
+type MigratorClient interface {
+	// DispatchStep hands a step to the migrator. The migrator answers once it
+	// has taken the step and reports the outcome through Loom.SendCallback.
+	//
+	// UNAVAILABLE, DEADLINE_EXCEEDED, RESOURCE_EXHAUSTED, ABORTED, INTERNAL and
+	// UNKNOWN are retried. UNIMPLEMENTED fails the step as unsupported_step;
+	// every other code fails it as invalid_request.
+	DispatchStep(ctx context.Context, in *DispatchStepRequest, opts ...grpc.CallOption) (*DispatchStepResponse, error)
+	// DryRun simulates the steps of a candidate without side effects.
+	DryRun(ctx context.Context, in *DryRunRequest, opts ...grpc.CallOption) (*DryRunResult, error)
+}
+
+type migratorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMigratorClient(cc grpc.ClientConnInterface) MigratorClient {
+	return &migratorClient{cc}
+}
+
+func (c *migratorClient) DispatchStep(ctx context.Context, in *DispatchStepRequest, opts ...grpc.CallOption) (*DispatchStepResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DispatchStepResponse)
+	err := c.cc.Invoke(ctx, Migrator_DispatchStep_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *migratorClient) DryRun(ctx context.Context, in *DryRunRequest, opts ...grpc.CallOption) (*DryRunResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DryRunResult)
+	err := c.cc.Invoke(ctx, Migrator_DryRun_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MigratorServer is the server API for Migrator service.
+// All implementations must embed UnimplementedMigratorServer
+// for forward compatibility.
+//
+// Migrator is served by a migrator and called by the Loom server.
+type MigratorServer interface {
+	// DispatchStep hands a step to the migrator. The migrator answers once it
+	// has taken the step and reports the outcome through Loom.SendCallback.
+	//
+	// UNAVAILABLE, DEADLINE_EXCEEDED, RESOURCE_EXHAUSTED, ABORTED, INTERNAL and
+	// UNKNOWN are retried. UNIMPLEMENTED fails the step as unsupported_step;
+	// every other code fails it as invalid_request.
+	DispatchStep(context.Context, *DispatchStepRequest) (*DispatchStepResponse, error)
+	// DryRun simulates the steps of a candidate without side effects.
+	DryRun(context.Context, *DryRunRequest) (*DryRunResult, error)
+	mustEmbedUnimplementedMigratorServer()
+}
+
+// UnimplementedMigratorServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMigratorServer struct{}
+
+func (UnimplementedMigratorServer) DispatchStep(context.Context, *DispatchStepRequest) (*DispatchStepResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DispatchStep not implemented")
+}
+func (UnimplementedMigratorServer) DryRun(context.Context, *DryRunRequest) (*DryRunResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DryRun not implemented")
+}
+func (UnimplementedMigratorServer) mustEmbedUnimplementedMigratorServer() {}
+func (UnimplementedMigratorServer) testEmbeddedByValue()                  {}
+
+// UnsafeMigratorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MigratorServer will
+// result in compilation errors.
+type UnsafeMigratorServer interface {
+	mustEmbedUnimplementedMigratorServer()
+}
+
+func RegisterMigratorServer(s grpc.ServiceRegistrar, srv MigratorServer) {
+	// If the following call pancis, it indicates UnimplementedMigratorServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Migrator_ServiceDesc, srv)
+}
+
+func _Migrator_DispatchStep_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DispatchStepRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigratorServer).DispatchStep(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Migrator_DispatchStep_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigratorServer).DispatchStep(ctx, req.(*DispatchStepRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Migrator_DryRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DryRunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MigratorServer).DryRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Migrator_DryRun_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MigratorServer).DryRun(ctx, req.(*DryRunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Migrator_ServiceDesc is the grpc.ServiceDesc for Migrator service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Migrator_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "loom.migrator.v1.Migrator",
+	HandlerType: (*MigratorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "DispatchStep",
+			Handler:    _Migrator_DispatchStep_Handler,
+		},
+		{
+			MethodName: "DryRun",
+			Handler:    _Migrator_DryRun_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "migrator.proto",
+}
+
+const (
+	Loom_Announce_FullMethodName         = "/loom.migrator.v1.Loom/Announce"
+	Loom_RegisterMigrator_FullMethodName = "/loom.migrator.v1.Loom/RegisterMigrator"
+	Loom_SendCallback_FullMethodName     = "/loom.migrator.v1.Loom/SendCallback"
+)
+
+// LoomClient is the client API for Loom service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Loom is served by the Loom server and called by migrators. Calls are
+// authorized like the HTTP routes they mirror: send
+// "authorization: Bearer <token>" when the server has auth enabled, and sign
+// callbacks with the "x-loom-timestamp" and "x-loom-signature" metadata.
+// Announce and RegisterMigrator are recorded in the audit log.
+type LoomClient interface {
+	// Announce mirrors POST /registry/announce. Conflicting dispatch limits
+	// are refused with FAILED_PRECONDITION.
+	Announce(ctx context.Context, in *AnnounceRequest, opts ...grpc.CallOption) (*AnnounceResponse, error)
+	// RegisterMigrator mirrors POST /registry/migrators.
+	RegisterMigrator(ctx context.Context, in *MigratorRegistration, opts ...grpc.CallOption) (*RegisteredMigrator, error)
+	// SendCallback mirrors POST /event/{callback_id}. The signature covers
+	// callback_id and the event serialized deterministically (in Go,
+	// proto.MarshalOptions{Deterministic: true}); the server serializes the
+	// event it received the same way to check it.
+	SendCallback(ctx context.Context, in *SendCallbackRequest, opts ...grpc.CallOption) (*SendCallbackResponse, error)
+}
+
+type loomClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLoomClient(cc grpc.ClientConnInterface) LoomClient {
+	return &loomClient{cc}
+}
+
+func (c *loomClient) Announce(ctx context.Context, in *AnnounceRequest, opts ...grpc.CallOption) (*AnnounceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnnounceResponse)
+	err := c.cc.Invoke(ctx, Loom_Announce_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loomClient) RegisterMigrator(ctx context.Context, in *MigratorRegistration, opts ...grpc.CallOption) (*RegisteredMigrator, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisteredMigrator)
+	err := c.cc.Invoke(ctx, Loom_RegisterMigrator_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loomClient) SendCallback(ctx context.Context, in *SendCallbackRequest, opts ...grpc.CallOption) (*SendCallbackResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendCallbackResponse)
+	err := c.cc.Invoke(ctx, Loom_SendCallback_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LoomServer is the server API for Loom service.
+// All implementations must embed UnimplementedLoomServer
+// for forward compatibility.
+//
+// Loom is served by the Loom server and called by migrators. Calls are
+// authorized like the HTTP routes they mirror: send
+// "authorization: Bearer <token>" when the server has auth enabled, and sign
+// callbacks with the "x-loom-timestamp" and "x-loom-signature" metadata.
+// Announce and RegisterMigrator are recorded in the audit log.
+type LoomServer interface {
+	// Announce mirrors POST /registry/announce. Conflicting dispatch limits
+	// are refused with FAILED_PRECONDITION.
+	Announce(context.Context, *AnnounceRequest) (*AnnounceResponse, error)
+	// RegisterMigrator mirrors POST /registry/migrators.
+	RegisterMigrator(context.Context, *MigratorRegistration) (*RegisteredMigrator, error)
+	// SendCallback mirrors POST /event/{callback_id}. The signature covers
+	// callback_id and the event serialized deterministically (in Go,
+	// proto.MarshalOptions{Deterministic: true}); the server serializes the
+	// event it received the same way to check it.
+	SendCallback(context.Context, *SendCallbackRequest) (*SendCallbackResponse, error)
+	mustEmbedUnimplementedLoomServer()
+}
+
+// UnimplementedLoomServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLoomServer struct{}
+
+func (UnimplementedLoomServer) Announce(context.Context, *AnnounceRequest) (*AnnounceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Announce not implemented")
+}
+func (UnimplementedLoomServer) RegisterMigrator(context.Context, *MigratorRegistration) (*RegisteredMigrator, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterMigrator not implemented")
+}
+func (UnimplementedLoomServer) SendCallback(context.Context, *SendCallbackRequest) (*SendCallbackResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendCallback not implemented")
+}
+func (UnimplementedLoomServer) mustEmbedUnimplementedLoomServer() {}
+func (UnimplementedLoomServer) testEmbeddedByValue()              {}
+
+// UnsafeLoomServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LoomServer will
+// result in compilation errors.
+type UnsafeLoomServer interface {
+	mustEmbedUnimplementedLoomServer()
+}
+
+func RegisterLoomServer(s grpc.ServiceRegistrar, srv LoomServer) {
+	// If the following call pancis, it indicates UnimplementedLoomServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Loom_ServiceDesc, srv)
+}
+
+func _Loom_Announce_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnnounceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoomServer).Announce(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Loom_Announce_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoomServer).Announce(ctx, req.(*AnnounceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Loom_RegisterMigrator_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MigratorRegistration)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoomServer).RegisterMigrator(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Loom_RegisterMigrator_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoomServer).RegisterMigrator(ctx, req.(*MigratorRegistration))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Loom_SendCallback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendCallbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoomServer).SendCallback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Loom_SendCallback_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoomServer).SendCallback(ctx, req.(*SendCallbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Loom_ServiceDesc is the grpc.ServiceDesc for Loom service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Loom_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "loom.migrator.v1.Loom",
+	HandlerType: (*LoomServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Announce",
+			Handler:    _Loom_Announce_Handler,
+		},
+		{
+			MethodName: "RegisterMigrator",
+			Handler:    _Loom_RegisterMigrator_Handler,
+		},
+		{
+			MethodName: "SendCallback",
+			Handler:    _Loom_SendCallback_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "migrator.proto",
+}
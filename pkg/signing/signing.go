@@ -0,0 +1,88 @@
+// Package signing signs and verifies requests exchanged between Loom and
+// migrators, and the webhooks Loom delivers, with HMAC-SHA256 over a shared
+// secret.
+//
+// The signature covers the request timestamp, a target naming what the request
+// is for (for step callbacks, the run ID; for webhooks, the webhook ID) and the
+// raw body, so a signed body cannot be replayed later or against another
+// target:
+//
+//	X-Loom-Timestamp: 1760620800
+//	X-Loom-Signature: sha256=hex(HMAC(secret, "<timestamp>\n<target>\n<body>"))
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// TimestampHeader carries the Unix time (seconds) the request was signed at.
+	TimestampHeader = "X-Loom-Timestamp"
+	// SignatureHeader carries "sha256=" followed by the hex-encoded HMAC.
+	SignatureHeader = "X-Loom-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+// DefaultTolerance is how far a request's timestamp may be from the verifier's clock.
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	// ErrMissingSignature is returned when either header is absent.
+	ErrMissingSignature = errors.New("missing signature headers")
+	// ErrStaleTimestamp is returned when the timestamp is outside the tolerance.
+	ErrStaleTimestamp = errors.New("signature timestamp outside tolerance")
+	// ErrBadSignature is returned when the signature does not match.
+	ErrBadSignature = errors.New("signature mismatch")
+)
+
+// Sign returns the SignatureHeader value for body sent to target at ts.
+func Sign(secret string, ts time.Time, target string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d\n%s\n", ts.Unix(), target)
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignRequest sets the timestamp and signature headers on req, whose body is body.
+func SignRequest(req *http.Request, secret, target string, body []byte, now time.Time) {
+	req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(SignatureHeader, Sign(secret, now, target, body))
+}
+
+// Verify checks the timestamp and signature header values of a request with
+// body sent to target. The timestamp must be within tolerance of now.
+func Verify(
+	secret, target string,
+	body []byte,
+	timestamp, signature string,
+	now time.Time,
+	tolerance time.Duration,
+) error {
+	if timestamp == "" || signature == "" {
+		return ErrMissingSignature
+	}
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", TimestampHeader, err)
+	}
+	ts := time.Unix(sec, 0)
+	if d := now.Sub(ts); d > tolerance || d < -tolerance {
+		return ErrStaleTimestamp
+	}
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return ErrBadSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, ts, target, body))) {
+		return ErrBadSignature
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package signing_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilsley/loom/pkg/signing"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1760620800, 0)
+	body := []byte(`{"stepName":"update-chart","status":"succeeded"}`)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	sig := signing.Sign("s3cret", now, "mig__billing-api", body)
+
+	t.Run("accepts a valid signature", func(t *testing.T) {
+		require.NoError(t, signing.Verify("s3cret", "mig__billing-api", body, ts, sig, now, time.Minute))
+	})
+
+	t.Run("rejects missing headers", func(t *testing.T) {
+		err := signing.Verify("s3cret", "mig__billing-api", body, "", "", now, time.Minute)
+		require.ErrorIs(t, err, signing.ErrMissingSignature)
+	})
+
+	t.Run("rejects another secret, target or body", func(t *testing.T) {
+		for _, err := range []error{
+			signing.Verify("other", "mig__billing-api", body, ts, sig, now, time.Minute),
+			signing.Verify("s3cret", "mig__payments-api", body, ts, sig, now, time.Minute),
+			signing.Verify("s3cret", "mig__billing-api", []byte(`{}`), ts, sig, now, time.Minute),
+		} {
+			require.ErrorIs(t, err, signing.ErrBadSignature)
+		}
+	})
+
+	t.Run("rejects a timestamp outside the tolerance", func(t *testing.T) {
+		err := signing.Verify("s3cret", "mig__billing-api", body, ts, sig, now.Add(2*time.Minute), time.Minute)
+		require.ErrorIs(t, err, signing.ErrStaleTimestamp)
+	})
+}
+
+func TestSignRequest(t *testing.T) {
+	now := time.Unix(1760620800, 0)
+	req, err := http.NewRequest(http.MethodPost, "http://loom/event/mig__billing-api", nil)
+	require.NoError(t, err)
+
+	signing.SignRequest(req, "s3cret", "mig__billing-api", []byte(`{}`), now)
+
+	assert.Equal(t, "1760620800", req.Header.Get(signing.TimestampHeader))
+	require.NoError(t, signing.Verify("s3cret", "mig__billing-api", []byte(`{}`),
+		req.Header.Get(signing.TimestampHeader), req.Header.Get(signing.SignatureHeader), now, time.Minute))
+}